@@ -1,17 +1,28 @@
 package provider
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"math/rand"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/boz/go-lifecycle"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/tendermint/tendermint/libs/log"
 	tmrpc "github.com/tendermint/tendermint/rpc/core/types"
 
 	aclient "github.com/akash-network/akash-api/go/node/client/v1beta2"
 	dtypes "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
+	etypes "github.com/akash-network/akash-api/go/node/escrow/v1beta3"
 	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
 
 	"github.com/akash-network/node/pubsub"
@@ -20,13 +31,16 @@ import (
 	"github.com/akash-network/node/x/escrow/client/util"
 
 	"github.com/akash-network/provider/event"
+	"github.com/akash-network/provider/ledger"
 	"github.com/akash-network/provider/session"
+	"github.com/akash-network/provider/tools/coin"
 )
 
 type respState int
 
 const (
-	withdrawTimeout = 30 * time.Second
+	withdrawTimeout          = 30 * time.Second
+	lowBalanceWebhookTimeout = 10 * time.Second
 )
 
 const (
@@ -35,14 +49,64 @@ const (
 	respStateScheduledWithdraw
 )
 
+var (
+	// matchFailedMsgIndex extracts index of the message which failed transaction
+	matchFailedMsgIndex = regexp.MustCompile(`message index: (\d+)`)
+
+	leaseLabels = []string{"owner", "dseq", "gseq", "oseq", "denom"}
+
+	leaseWithdrawnGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "provider_lease_earnings_withdrawn",
+		Help: "Total amount withdrawn from the lease",
+	}, leaseLabels)
+
+	leaseAccruedGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "provider_lease_earnings_accrued",
+		Help: "Amount the lease has earned and provider has not withdrawn yet",
+	}, leaseLabels)
+
+	withdrawnCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "provider_earnings_withdrawn",
+		Help: "Total amount provider has withdrawn from leases",
+	}, []string{"denom"})
+)
+
 type BalanceCheckerConfig struct {
 	WithdrawalPeriod        time.Duration
 	LeaseFundsCheckInterval time.Duration
+	// WithdrawalBatchSize is max number of leases withdrawn from in single transaction
+	WithdrawalBatchSize int
+	// WithdrawalBatchWindow is how long withdrawal waits for other leases to join the batch
+	WithdrawalBatchWindow time.Duration
+	// LowBalanceThreshold and LowBalanceBlocks set runway of the lease below which tenant is warned
+	// about deployment escrow running out of funds. the larger of the two is in effect, zero disables warnings
+	LowBalanceThreshold time.Duration
+	LowBalanceBlocks    int64
+	// LowBalanceWebhook is url warnings are POSTed to. optional
+	LowBalanceWebhook string
+}
+
+// lowBalanceBlocks returns runway in blocks below which lease is low on funds
+func (cfg BalanceCheckerConfig) lowBalanceBlocks() int64 {
+	blocks := cfg.LowBalanceBlocks
+	if byTime := int64(cfg.LowBalanceThreshold / netutil.AverageBlockTime); byTime > blocks {
+		blocks = byTime
+	}
+
+	return blocks
+}
+
+// lowBalanceWebhookPayload is the document POSTed to low balance webhook
+type lowBalanceWebhookPayload struct {
+	LeaseID         mtypes.LeaseID `json:"lease_id"`
+	BlocksRemaining int64          `json:"blocks_remaining"`
+	Runway          string         `json:"runway"`
 }
 
 type leaseState struct {
 	tm                  *time.Timer
 	scheduledWithdrawAt time.Time
+	lowBalance          bool
 }
 
 type balanceChecker struct {
@@ -55,13 +119,23 @@ type balanceChecker struct {
 	aqc     aclient.QueryClient
 	leases  map[mtypes.LeaseID]*leaseState
 	cfg     BalanceCheckerConfig
+	client  *http.Client
+	ledger  *ledger.Ledger
+
+	lock   sync.RWMutex
+	runway map[mtypes.LeaseID]LeaseRunway
 }
 
 type leaseCheckResponse struct {
-	lid        mtypes.LeaseID
-	checkAfter time.Duration
-	state      respState
-	err        error
+	lid          mtypes.LeaseID
+	checkAfter   time.Duration
+	blocksRemain int64
+	height       int64
+	// payment is escrow payment of the lease, nil if lease is not active
+	payment *etypes.FractionalPayment
+	accrued sdk.DecCoin
+	state   respState
+	err     error
 }
 
 func newBalanceChecker(
@@ -71,6 +145,7 @@ func newBalanceChecker(
 	clientSession session.Session,
 	bus pubsub.Bus,
 	cfg BalanceCheckerConfig,
+	earnings *ledger.Ledger,
 ) (*balanceChecker, error) {
 
 	bc := &balanceChecker{
@@ -83,6 +158,11 @@ func newBalanceChecker(
 		aqc:     aqc,
 		leases:  make(map[mtypes.LeaseID]*leaseState),
 		cfg:     cfg,
+		client: &http.Client{
+			Timeout: lowBalanceWebhookTimeout,
+		},
+		runway: make(map[mtypes.LeaseID]LeaseRunway),
+		ledger: earnings,
 	}
 
 	startCh := make(chan error, 1)
@@ -156,8 +236,17 @@ func (bc *balanceChecker) doEscrowCheck(ctx context.Context, lid mtypes.LeaseID,
 	}
 
 	totalLeaseAmount := sdk.NewDec(0)
-	for _, lease := range lResp.Leases {
+	for idx, lease := range lResp.Leases {
 		totalLeaseAmount = totalLeaseAmount.Add(lease.Lease.Price.Amount)
+
+		if lease.Lease.LeaseID.Equals(lid) {
+			resp.payment = &lResp.Leases[idx].EscrowPayment
+		}
+	}
+
+	resp.height = syncInfo.LatestBlockHeight
+	if resp.payment != nil {
+		resp.accrued = leaseAccrued(*resp.payment, dResp.EscrowAccount, resp.height, totalLeaseAmount)
 	}
 
 	balanceRemain := util.LeaseCalcBalanceRemain(dResp.EscrowAccount.TotalBalance().Amount,
@@ -166,12 +255,18 @@ func (bc *balanceChecker) doEscrowCheck(ctx context.Context, lid mtypes.LeaseID,
 		totalLeaseAmount)
 
 	blocksRemain := util.LeaseCalcBlocksRemain(balanceRemain, totalLeaseAmount)
+	resp.blocksRemain = blocksRemain
 
 	// lease is out of funds
 	if blocksRemain <= 0 {
 		resp.state = respStateOutOfFunds
 		resp.checkAfter = time.Minute * 10
 	} else {
+		// check again as soon as lease runway drops below warning threshold
+		if threshold := bc.cfg.lowBalanceBlocks(); threshold > 0 && blocksRemain > threshold {
+			blocksRemain -= threshold
+		}
+
 		blocksPerCheckInterval := int64(bc.cfg.LeaseFundsCheckInterval / netutil.AverageBlockTime)
 		if blocksRemain > blocksPerCheckInterval {
 			blocksRemain = blocksPerCheckInterval
@@ -183,16 +278,271 @@ func (bc *balanceChecker) doEscrowCheck(ctx context.Context, lid mtypes.LeaseID,
 	return resp
 }
 
-func (bc *balanceChecker) startWithdraw(ctx context.Context, lid mtypes.LeaseID) error {
+// leaseAccrued estimates amount lease has earned and provider has not withdrawn yet:
+// settled balance of the payment plus its share of what deployment escrow owes to all leases since last settlement
+func leaseAccrued(payment etypes.FractionalPayment, account etypes.Account, height int64, totalRate sdk.Dec) sdk.DecCoin {
+	accrued := payment.Balance.Amount
+
+	blocks := height - account.SettledAt
+	if blocks > 0 && payment.State == etypes.PaymentOpen && totalRate.IsPositive() {
+		unsettled := payment.Rate.Amount.MulInt64(blocks)
+
+		// escrow which has run out of funds pays leases proportionally to their rates
+		owed := totalRate.MulInt64(blocks)
+		if available := account.TotalBalance().Amount; owed.GT(available) {
+			if !available.IsPositive() {
+				available = sdk.ZeroDec()
+			}
+
+			unsettled = unsettled.Mul(available).Quo(owed)
+		}
+
+		accrued = accrued.Add(unsettled)
+	}
+
+	return sdk.NewDecCoinFromDec(payment.Balance.Denom, accrued)
+}
+
+func (bc *balanceChecker) startWithdraw(ctx context.Context, lids ...mtypes.LeaseID) (*sdk.TxResponse, error) {
 	ctx, cancel := context.WithTimeout(ctx, withdrawTimeout)
 	defer cancel()
 
-	msg := &mtypes.MsgWithdrawLease{
-		LeaseID: lid,
+	msgs := make([]sdk.Msg, 0, len(lids))
+	for _, lid := range lids {
+		msgs = append(msgs, &mtypes.MsgWithdrawLease{
+			LeaseID: lid,
+		})
+	}
+
+	resp, err := bc.session.Client().Tx().Broadcast(ctx, msgs, aclient.WithResultCodeAsError())
+	if err != nil {
+		return nil, err
+	}
+
+	txResp, _ := resp.(*sdk.TxResponse)
+
+	return txResp, nil
+}
+
+// withdrawBatch withdraws from all leases of the batch in single transaction.
+// when transaction fails on one of the messages, lease of that message gets the error
+// and the rest of the batch is retried without it
+func (bc *balanceChecker) withdrawBatch(ctx context.Context, lids []mtypes.LeaseID) []runner.Result {
+	results := make([]runner.Result, 0, len(lids))
+
+	for len(lids) > 0 {
+		txResp, err := bc.startWithdraw(ctx, lids...)
+		if err == nil {
+			for _, lid := range lids {
+				bc.recordWithdrawal(ctx, lid, txResp)
+				results = append(results, runner.NewResult(lid, nil))
+			}
+
+			break
+		}
+
+		idx, attributed := failedMsgIndex(err, len(lids))
+		if !attributed {
+			for _, lid := range lids {
+				results = append(results, runner.NewResult(lid, err))
+			}
+
+			break
+		}
+
+		results = append(results, runner.NewResult(lids[idx], err))
+
+		remaining := make([]mtypes.LeaseID, 0, len(lids)-1)
+		remaining = append(remaining, lids[:idx]...)
+		lids = append(remaining, lids[idx+1:]...)
+	}
+
+	return results
+}
+
+// recordWithdrawal records amount withdrawn from the lease by the transaction into the earnings ledger
+func (bc *balanceChecker) recordWithdrawal(ctx context.Context, lid mtypes.LeaseID, txResp *sdk.TxResponse) {
+	var height int64
+	var txHash string
+
+	if txResp != nil {
+		height = txResp.Height
+		txHash = txResp.TxHash
+	}
+
+	res, err := bc.aqc.Lease(ctx, &mtypes.QueryLeaseRequest{ID: lid})
+	if err != nil {
+		bc.log.Error("querying lease after withdrawal", "lease", lid, "tx", txHash, "err", err)
+		return
+	}
+
+	// withdrawal settles escrow payment, balance of the payment is what withdrawal has left over
+	payment := res.EscrowPayment
+
+	if height == 0 {
+		// transaction height is unknown, record state as of the latest block
+		if syncInfo, err := bc.session.Client().Node().SyncInfo(ctx); err == nil {
+			height = syncInfo.LatestBlockHeight
+		}
+	}
+
+	withdrawal, err := bc.ledger.Withdrawn(lid, payment.Withdrawn, payment.Balance, height, txHash)
+	if err != nil {
+		bc.log.Error("recording withdrawal", "lease", lid, "tx", txHash, "err", err)
+	}
+
+	setLeaseEarnings(lid, payment.Withdrawn, payment.Balance)
+
+	if withdrawal != nil {
+		withdrawnCounter.WithLabelValues(withdrawal.Amount.Denom).Add(coin.Float64(withdrawal.Amount))
+		bc.log.Debug("withdrawn from lease", "lease", lid, "amount", withdrawal.Amount, "tx", txHash)
+	}
+}
+
+// recordAccrued records withdrawn and accrued amounts of the lease as of its balance check
+func (bc *balanceChecker) recordAccrued(res leaseCheckResponse) {
+	if res.payment == nil {
+		return
+	}
+
+	if err := bc.ledger.Accrued(res.lid, res.payment.Withdrawn, res.accrued, res.height); err != nil {
+		bc.log.Error("recording accrued earnings", "lease", res.lid, "err", err)
+	}
+
+	setLeaseEarnings(res.lid, res.payment.Withdrawn, res.accrued)
+}
+
+func setLeaseEarnings(lid mtypes.LeaseID, withdrawn sdk.Coin, accrued sdk.DecCoin) {
+	labels := leaseMetricLabels(lid)
+
+	labels["denom"] = withdrawn.Denom
+	leaseWithdrawnGauge.With(labels).Set(coin.Float64(withdrawn))
+
+	labels["denom"] = accrued.Denom
+	leaseAccruedGauge.With(labels).Set(coin.DecFloat64(accrued))
+}
+
+func deleteLeaseEarnings(lid mtypes.LeaseID) {
+	leaseWithdrawnGauge.DeletePartialMatch(leaseMetricLabels(lid))
+	leaseAccruedGauge.DeletePartialMatch(leaseMetricLabels(lid))
+}
+
+func leaseMetricLabels(lid mtypes.LeaseID) prometheus.Labels {
+	return prometheus.Labels{
+		"owner": lid.Owner,
+		"dseq":  strconv.FormatUint(lid.DSeq, 10),
+		"gseq":  strconv.FormatUint(uint64(lid.GSeq), 10),
+		"oseq":  strconv.FormatUint(uint64(lid.OSeq), 10),
+	}
+}
+
+// LeaseRunway returns funds runway of the lease as of its last check
+func (bc *balanceChecker) LeaseRunway(lid mtypes.LeaseID) (LeaseRunway, bool) {
+	bc.lock.RLock()
+	defer bc.lock.RUnlock()
+
+	runway, exists := bc.runway[lid]
+
+	return runway, exists
+}
+
+// updateRunway records lease runway and warns tenant once lease runway drops below threshold.
+// warning is repeated only after lease has been topped up above the threshold
+func (bc *balanceChecker) updateRunway(ctx context.Context, lState *leaseState, lid mtypes.LeaseID, blocksRemain int64) {
+	if blocksRemain < 0 {
+		blocksRemain = 0
+	}
+
+	threshold := bc.cfg.lowBalanceBlocks()
+	lowBalance := threshold > 0 && blocksRemain <= threshold
+	runway := time.Duration(blocksRemain) * netutil.AverageBlockTime
+
+	bc.lock.Lock()
+	bc.runway[lid] = LeaseRunway{
+		BlocksRemaining: blocksRemain,
+		Runway:          runway.String(),
+		LowBalance:      lowBalance,
+		CheckedAt:       time.Now().UTC(),
+	}
+	bc.lock.Unlock()
+
+	notify := lowBalance && !lState.lowBalance
+	lState.lowBalance = lowBalance
+
+	if !notify {
+		return
+	}
+
+	bc.log.Info("lease is low on funds", "lease", lid, "blocks-remaining", blocksRemain, "runway", runway)
+
+	ev := event.LeaseLowBalance{
+		LeaseID:         lid,
+		BlocksRemaining: blocksRemain,
+		Runway:          runway,
+	}
+
+	if err := bc.bus.Publish(ev); err != nil {
+		bc.log.Error("publishing low balance event", "lease", lid, "err", err)
+	}
+
+	if bc.cfg.LowBalanceWebhook != "" {
+		go func() {
+			if err := bc.notifyLowBalance(ctx, ev); err != nil {
+				bc.log.Error("sending low balance webhook", "lease", lid, "err", err)
+			}
+		}()
+	}
+}
+
+// notifyLowBalance POSTs low balance warning to the webhook
+func (bc *balanceChecker) notifyLowBalance(ctx context.Context, ev event.LeaseLowBalance) error {
+	body, err := json.Marshal(lowBalanceWebhookPayload{
+		LeaseID:         ev.LeaseID,
+		BlocksRemaining: ev.BlocksRemaining,
+		Runway:          ev.Runway.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, bc.cfg.LowBalanceWebhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := bc.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode) // nolint: err113
+	}
+
+	return nil
+}
+
+// failedMsgIndex returns index of the message transaction has failed on
+func failedMsgIndex(err error, count int) (int, bool) {
+	match := matchFailedMsgIndex.FindStringSubmatch(err.Error())
+	if match == nil {
+		return 0, false
+	}
+
+	idx, perr := strconv.Atoi(match[1])
+	if perr != nil || idx >= count {
+		return 0, false
 	}
 
-	_, err := bc.session.Client().Tx().Broadcast(ctx, []sdk.Msg{msg}, aclient.WithResultCodeAsError())
-	return err
+	return idx, true
 }
 
 func (bc *balanceChecker) run(startCh chan<- error) {
@@ -214,6 +564,43 @@ func (bc *balanceChecker) run(startCh chan<- error) {
 	leaseCheckCh := make(chan leaseCheckResponse, 1)
 	var resultch chan runner.Result
 
+	batchSize := bc.cfg.WithdrawalBatchSize
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	// leases waiting for withdrawal batch to be sent
+	var pending []mtypes.LeaseID
+	var flushTimer *time.Timer
+	var flushch <-chan time.Time
+
+	flush := func() {
+		if flushTimer != nil {
+			flushTimer.Stop()
+			flushTimer = nil
+			flushch = nil
+		}
+
+		if len(pending) == 0 {
+			return
+		}
+
+		batch := pending
+		pending = nil
+
+		bc.log.Debug("sending withdraw", "leases", len(batch))
+
+		go func() {
+			for _, res := range bc.withdrawBatch(ctx, batch) {
+				select {
+				case <-ctx.Done():
+					return
+				case resultch <- res:
+				}
+			}
+		}()
+	}
+
 	subscriber, err := bc.bus.Subscribe()
 	startCh <- err
 	if err != nil {
@@ -266,6 +653,14 @@ loop:
 				}
 
 				delete(bc.leases, ev.LeaseID)
+				// closed lease must not be withdrawn from with the next batch
+				pending = removeLease(pending, ev.LeaseID)
+
+				bc.lock.Lock()
+				delete(bc.runway, ev.LeaseID)
+				bc.lock.Unlock()
+
+				deleteLeaseEarnings(ev.LeaseID)
 			}
 		case res := <-leaseCheckCh:
 			// we may have timer fired just a heart beat ahead of lease remove event.
@@ -276,6 +671,11 @@ loop:
 
 			withdraw := false
 
+			if res.err == nil {
+				bc.updateRunway(ctx, lState, res.lid, res.blocksRemain)
+				bc.recordAccrued(res)
+			}
+
 			switch res.state {
 			case respStateOutOfFunds:
 				bc.log.Debug("lease is out of funds", "lease", res.lid)
@@ -283,7 +683,7 @@ loop:
 				fallthrough
 			case respStateScheduledWithdraw:
 				withdraw = true
-				bc.log.Debug("sending withdraw", "lease", res.lid)
+				bc.log.Debug("queueing withdraw", "lease", res.lid)
 				// reschedule periodic withdraw if configured
 				if bc.cfg.WithdrawalPeriod > 0 {
 					lState.scheduledWithdrawAt = time.Now().Add(bc.cfg.WithdrawalPeriod)
@@ -308,20 +708,31 @@ loop:
 				lState.tm = bc.timerFunc(ctx, timerPeriod, res.lid, scheduledWithdraw, leaseCheckCh)
 			}
 
-			if withdraw {
-				go func() {
-					select {
-					case <-ctx.Done():
-					case resultch <- runner.NewResult(res.lid, bc.startWithdraw(ctx, res.lid)):
-					}
-				}()
+			if withdraw && !containsLease(pending, res.lid) {
+				pending = append(pending, res.lid)
+
+				if len(pending) >= batchSize || bc.cfg.WithdrawalBatchWindow <= 0 {
+					flush()
+				} else if flushTimer == nil {
+					flushTimer = time.NewTimer(bc.cfg.WithdrawalBatchWindow)
+					flushch = flushTimer.C
+				}
 			}
+		case <-flushch:
+			flushTimer = nil
+			flushch = nil
+			flush()
 		case res := <-resultch:
 			if err := res.Error(); err != nil {
 				bc.log.Error("failed to do lease withdrawal", "err", err, "LeaseID", res.Value().(mtypes.LeaseID))
 			}
 		}
 	}
+
+	// leases of the batch not sent yet are withdrawn from on their next schedule
+	if flushTimer != nil {
+		flushTimer.Stop()
+	}
 }
 
 func (bc *balanceChecker) timerFunc(ctx context.Context, d time.Duration, lid mtypes.LeaseID, scheduledWithdraw bool, ch chan<- leaseCheckResponse) *time.Timer {
@@ -332,3 +743,24 @@ func (bc *balanceChecker) timerFunc(ctx context.Context, d time.Duration, lid mt
 		}
 	})
 }
+
+func removeLease(lids []mtypes.LeaseID, lid mtypes.LeaseID) []mtypes.LeaseID {
+	res := lids[:0]
+	for _, val := range lids {
+		if !val.Equals(lid) {
+			res = append(res, val)
+		}
+	}
+
+	return res
+}
+
+func containsLease(lids []mtypes.LeaseID, lid mtypes.LeaseID) bool {
+	for _, val := range lids {
+		if val.Equals(lid) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,373 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+
+	clientmocks "github.com/akash-network/akash-api/go/node/client/v1beta2/mocks"
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
+
+	"github.com/akash-network/node/pubsub"
+	"github.com/akash-network/node/testutil"
+	netutil "github.com/akash-network/node/util/network"
+
+	"github.com/akash-network/provider/event"
+	"github.com/akash-network/provider/session"
+)
+
+func TestFailedMsgIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		count    int
+		expIdx   int
+		expFound bool
+	}{
+		{
+			name:     "first message",
+			err:      errors.New("failed to execute message; message index: 0: payment closed"),
+			count:    3,
+			expIdx:   0,
+			expFound: true,
+		},
+		{
+			name:     "abci error",
+			err:      sdkerrors.ABCIError("escrow", 4, "failed to execute message; message index: 2: payment closed"),
+			count:    3,
+			expIdx:   2,
+			expFound: true,
+		},
+		{
+			name:     "unrecognized route",
+			err:      errors.New("unrecognized message route: market; message index: 1"),
+			count:    2,
+			expIdx:   1,
+			expFound: true,
+		},
+		{
+			name:  "index out of batch",
+			err:   errors.New("failed to execute message; message index: 3: payment closed"),
+			count: 3,
+		},
+		{
+			name:  "index overflow",
+			err:   errors.New("failed to execute message; message index: 99999999999999999999: payment closed"),
+			count: 3,
+		},
+		{
+			name:  "no index",
+			err:   errors.New("insufficient fees"),
+			count: 3,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			idx, found := failedMsgIndex(test.err, test.count)
+			require.Equal(t, test.expFound, found)
+			require.Equal(t, test.expIdx, idx)
+		})
+	}
+}
+
+func withdrawLeases(msgs []sdk.Msg) []mtypes.LeaseID {
+	lids := make([]mtypes.LeaseID, 0, len(msgs))
+	for _, msg := range msgs {
+		lids = append(lids, msg.(*mtypes.MsgWithdrawLease).LeaseID)
+	}
+
+	return lids
+}
+
+func makeWithdrawBalanceChecker(t *testing.T, txClient *clientmocks.TxClient) *balanceChecker {
+	t.Helper()
+
+	provider := testutil.Provider(t)
+
+	client := &clientmocks.Client{}
+	client.On("Tx").Return(txClient)
+
+	// withdrawals are not recorded into earnings ledger when lease can't be queried
+	qc := &clientmocks.QueryClient{}
+	qc.On("Lease", mock.Anything, mock.Anything).Return(nil, errors.New("lease query failed"))
+
+	return &balanceChecker{
+		session: session.New(testutil.Logger(t), client, &provider, -1),
+		log:     testutil.Logger(t),
+		aqc:     qc,
+	}
+}
+
+func TestWithdrawBatchRetriesWithoutFailedLease(t *testing.T) {
+	lids := []mtypes.LeaseID{testutil.LeaseID(t), testutil.LeaseID(t), testutil.LeaseID(t)}
+	failure := sdkerrors.ABCIError("escrow", 4, "failed to execute message; message index: 1: payment closed")
+
+	var broadcasts [][]mtypes.LeaseID
+
+	txClient := &clientmocks.TxClient{}
+	txClient.On("Broadcast", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		broadcasts = append(broadcasts, withdrawLeases(args.Get(1).([]sdk.Msg)))
+	}).Return(nil, failure).Once()
+	txClient.On("Broadcast", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		broadcasts = append(broadcasts, withdrawLeases(args.Get(1).([]sdk.Msg)))
+	}).Return(&sdk.TxResponse{Height: 10, TxHash: "hash"}, nil).Once()
+
+	bc := makeWithdrawBalanceChecker(t, txClient)
+
+	results := bc.withdrawBatch(context.Background(), lids)
+
+	txClient.AssertExpectations(t)
+	require.Equal(t, [][]mtypes.LeaseID{lids, {lids[0], lids[2]}}, broadcasts)
+
+	require.Len(t, results, 3)
+
+	require.Equal(t, lids[1], results[0].Value())
+	require.ErrorIs(t, results[0].Error(), failure)
+
+	require.Equal(t, lids[0], results[1].Value())
+	require.NoError(t, results[1].Error())
+
+	require.Equal(t, lids[2], results[2].Value())
+	require.NoError(t, results[2].Error())
+}
+
+func TestWithdrawBatchUnattributedFailure(t *testing.T) {
+	lids := []mtypes.LeaseID{testutil.LeaseID(t), testutil.LeaseID(t)}
+	failure := errors.New("insufficient fees")
+
+	txClient := &clientmocks.TxClient{}
+	txClient.On("Broadcast", mock.Anything, mock.Anything, mock.Anything).Return(nil, failure).Once()
+
+	bc := makeWithdrawBalanceChecker(t, txClient)
+
+	results := bc.withdrawBatch(context.Background(), lids)
+
+	txClient.AssertExpectations(t)
+	require.Len(t, results, 2)
+
+	for idx, res := range results {
+		require.Equal(t, lids[idx], res.Value())
+		require.ErrorIs(t, res.Error(), failure)
+	}
+}
+
+func TestLowBalanceBlocks(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  BalanceCheckerConfig
+		exp  int64
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name: "blocks",
+			cfg:  BalanceCheckerConfig{LowBalanceBlocks: 100},
+			exp:  100,
+		},
+		{
+			name: "threshold",
+			cfg:  BalanceCheckerConfig{LowBalanceThreshold: 200 * netutil.AverageBlockTime},
+			exp:  200,
+		},
+		{
+			name: "threshold rounded down",
+			cfg:  BalanceCheckerConfig{LowBalanceThreshold: 200*netutil.AverageBlockTime + netutil.AverageBlockTime/2},
+			exp:  200,
+		},
+		{
+			name: "threshold shorter than block",
+			cfg:  BalanceCheckerConfig{LowBalanceThreshold: netutil.AverageBlockTime - 1},
+		},
+		{
+			name: "larger blocks",
+			cfg:  BalanceCheckerConfig{LowBalanceThreshold: 200 * netutil.AverageBlockTime, LowBalanceBlocks: 300},
+			exp:  300,
+		},
+		{
+			name: "larger threshold",
+			cfg:  BalanceCheckerConfig{LowBalanceThreshold: 200 * netutil.AverageBlockTime, LowBalanceBlocks: 100},
+			exp:  200,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.exp, test.cfg.lowBalanceBlocks())
+		})
+	}
+}
+
+func newLowBalanceWebhook(t *testing.T, status int) (*httptest.Server, <-chan lowBalanceWebhookPayload) {
+	t.Helper()
+
+	payloads := make(chan lowBalanceWebhookPayload, 4)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		payload := lowBalanceWebhookPayload{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+
+		payloads <- payload
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, payloads
+}
+
+func TestUpdateRunwayWarnsOnce(t *testing.T) {
+	server, payloads := newLowBalanceWebhook(t, http.StatusOK)
+
+	bus := pubsub.NewBus()
+	t.Cleanup(bus.Close)
+
+	sub, err := bus.Subscribe()
+	require.NoError(t, err)
+	t.Cleanup(sub.Close)
+
+	bc := &balanceChecker{
+		log: testutil.Logger(t),
+		bus: bus,
+		cfg: BalanceCheckerConfig{
+			LowBalanceBlocks:  100,
+			LowBalanceWebhook: server.URL,
+		},
+		client: server.Client(),
+		runway: make(map[mtypes.LeaseID]LeaseRunway),
+	}
+
+	lid := testutil.LeaseID(t)
+	lState := &leaseState{}
+
+	// next warning sent by test must be the one expected, nothing is published in between
+	expectWarning := func(blocks int64) {
+		t.Helper()
+
+		select {
+		case ev := <-sub.Events():
+			require.Equal(t, event.LeaseLowBalance{
+				LeaseID:         lid,
+				BlocksRemaining: blocks,
+				Runway:          time.Duration(blocks) * netutil.AverageBlockTime,
+			}, ev)
+		case <-time.After(5 * time.Second):
+			t.Fatal("low balance event has not been published")
+		}
+
+		select {
+		case payload := <-payloads:
+			require.Equal(t, lowBalanceWebhookPayload{
+				LeaseID:         lid,
+				BlocksRemaining: blocks,
+				Runway:          (time.Duration(blocks) * netutil.AverageBlockTime).String(),
+			}, payload)
+		case <-time.After(5 * time.Second):
+			t.Fatal("low balance webhook has not been called")
+		}
+	}
+
+	expectRunway := func(blocks int64, low bool) {
+		t.Helper()
+
+		runway, exists := bc.LeaseRunway(lid)
+		require.True(t, exists)
+		require.Equal(t, blocks, runway.BlocksRemaining)
+		require.Equal(t, low, runway.LowBalance)
+	}
+
+	ctx := context.Background()
+
+	bc.updateRunway(ctx, lState, lid, 101)
+	expectRunway(101, false)
+
+	// threshold is inclusive
+	bc.updateRunway(ctx, lState, lid, 100)
+	expectRunway(100, true)
+	expectWarning(100)
+
+	// lease stays low on funds, tenant has already been warned
+	bc.updateRunway(ctx, lState, lid, 50)
+	expectRunway(50, true)
+
+	// overdrawn lease has no runway left
+	bc.updateRunway(ctx, lState, lid, -10)
+	expectRunway(0, true)
+
+	// top up re-arms warning
+	bc.updateRunway(ctx, lState, lid, 1000)
+	expectRunway(1000, false)
+	require.False(t, lState.lowBalance)
+
+	bc.updateRunway(ctx, lState, lid, 90)
+	expectRunway(90, true)
+	expectWarning(90)
+}
+
+func TestUpdateRunwayDisabled(t *testing.T) {
+	bc := &balanceChecker{
+		log:    testutil.Logger(t),
+		runway: make(map[mtypes.LeaseID]LeaseRunway),
+	}
+
+	lid := testutil.LeaseID(t)
+	lState := &leaseState{}
+
+	// bus is not set, publishing warning would panic
+	bc.updateRunway(context.Background(), lState, lid, 0)
+
+	runway, exists := bc.LeaseRunway(lid)
+	require.True(t, exists)
+	require.False(t, runway.LowBalance)
+	require.False(t, lState.lowBalance)
+}
+
+func TestNotifyLowBalance(t *testing.T) {
+	ev := event.LeaseLowBalance{
+		LeaseID:         testutil.LeaseID(t),
+		BlocksRemaining: 42,
+		Runway:          42 * netutil.AverageBlockTime,
+	}
+
+	server, payloads := newLowBalanceWebhook(t, http.StatusNoContent)
+
+	bc := &balanceChecker{
+		cfg:    BalanceCheckerConfig{LowBalanceWebhook: server.URL},
+		client: server.Client(),
+	}
+
+	require.NoError(t, bc.notifyLowBalance(context.Background(), ev))
+	require.Equal(t, lowBalanceWebhookPayload{
+		LeaseID:         ev.LeaseID,
+		BlocksRemaining: 42,
+		Runway:          ev.Runway.String(),
+	}, <-payloads)
+
+	// webhook rejecting warning
+	server, payloads = newLowBalanceWebhook(t, http.StatusInternalServerError)
+	bc.cfg.LowBalanceWebhook = server.URL
+
+	err := bc.notifyLowBalance(context.Background(), ev)
+	require.ErrorContains(t, err, "unexpected status 500")
+	require.Len(t, payloads, 1)
+
+	// webhook not reachable
+	server.Close()
+
+	err = bc.notifyLowBalance(context.Background(), ev)
+	require.Error(t, err)
+}
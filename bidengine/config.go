@@ -6,6 +6,9 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
 	types "github.com/akash-network/akash-api/go/node/types/v1beta3"
+
+	"github.com/akash-network/provider/bidengine/journal"
+	"github.com/akash-network/provider/bidengine/policy"
 )
 
 type Config struct {
@@ -14,4 +17,10 @@ type Config struct {
 	BidTimeout      time.Duration
 	Attributes      types.Attributes
 	MaxGroupVolumes int
+	Policy          policy.Source
+	Journal         *journal.Journal
+	// JournalRetention is how long closed orders are kept in the journal
+	JournalRetention time.Duration
+	// Wallet enables pausing of bidding while provider account balance is low. nil disables it
+	Wallet *WalletConfig
 }
@@ -0,0 +1,292 @@
+package bidengine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	atypes "github.com/akash-network/akash-api/go/node/audit/v1beta3"
+	dtypes "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
+	ptypes "github.com/akash-network/akash-api/go/node/provider/v1beta3"
+	types "github.com/akash-network/akash-api/go/node/types/v1beta3"
+
+	"github.com/akash-network/provider/bidengine/policy"
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+// Codes of the reasons provider declines to bid on the group
+const (
+	DeclineProviderAttributes   = "provider-attributes"
+	DeclineOrderAttributes      = "order-attributes"
+	DeclineResourceCapabilities = "resource-capabilities"
+	DeclineVolumeLimit          = "volume-limit"
+	DeclineAuditorSignatures    = "auditor-signatures"
+	DeclineInvalidGroup         = "invalid-group"
+	DeclineDenomination         = "denomination"
+	DeclineWalletBalance        = "wallet-balance"
+	DeclinePolicy               = "policy"
+	DeclineInsufficientCapacity = "insufficient-capacity"
+	DeclinePricing              = "pricing"
+	DeclinePriceAboveMax        = "price-above-max"
+)
+
+// DeclineReason explains why provider would not bid on the group
+type DeclineReason struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (r DeclineReason) String() string {
+	return r.Code + ": " + r.Message
+}
+
+// ValidateResult is the outcome of checking group spec without bidding on it
+type ValidateResult struct {
+	Price         sdk.DecCoin
+	Declines      []DeclineReason
+	PolicyDecline *policy.Decline
+}
+
+// Validator checks group spec the same way bidding on order does
+type Validator interface {
+	Validate(ctx context.Context, owner string, gspec dtypes.GroupSpec) (ValidateResult, error)
+}
+
+// groupChecker evaluates group spec against provider attributes and configuration
+type groupChecker struct {
+	provider *ptypes.Provider
+	cfg      Config
+	pass     ProviderAttrSignatureService
+	wallet   *walletMonitor
+}
+
+type groupCheck func(*dtypes.GroupSpec) (*DeclineReason, error)
+
+// check returns reasons provider declines the group.
+// bidding only needs to know group is declined, so checking stops at first reason unless all is set
+func (c groupChecker) check(gspec *dtypes.GroupSpec, all bool) ([]DeclineReason, error) {
+	checks := []groupCheck{
+		c.checkProviderAttributes,
+		c.checkOrderAttributes,
+		c.checkResourceCapabilities,
+		c.checkVolumes,
+		c.checkAuditorSignatures,
+		c.checkValidGroup,
+		c.checkDenomination,
+		c.checkWalletBalance,
+	}
+
+	var reasons []DeclineReason
+
+	for _, check := range checks {
+		reason, err := check(gspec)
+		if err != nil {
+			return nil, err
+		}
+
+		if reason == nil {
+			continue
+		}
+
+		reasons = append(reasons, *reason)
+		if !all {
+			break
+		}
+	}
+
+	return reasons, nil
+}
+
+// can provider account pay for the bid?
+func (c groupChecker) checkWalletBalance(_ *dtypes.GroupSpec) (*DeclineReason, error) {
+	return c.wallet.declineReason(), nil
+}
+
+// does provider have required attributes?
+func (c groupChecker) checkProviderAttributes(gspec *dtypes.GroupSpec) (*DeclineReason, error) {
+	if gspec.MatchAttributes(c.provider.Attributes) {
+		return nil, nil
+	}
+
+	return &DeclineReason{
+		Code:    DeclineProviderAttributes,
+		Message: fmt.Sprintf("provider does not have required attributes: %s", formatAttributes(missingAttributes(gspec.Requirements.Attributes, c.provider.Attributes))),
+	}, nil
+}
+
+// does order have required attributes?
+func (c groupChecker) checkOrderAttributes(gspec *dtypes.GroupSpec) (*DeclineReason, error) {
+	if c.cfg.Attributes.SubsetOf(gspec.Requirements.Attributes) {
+		return nil, nil
+	}
+
+	return &DeclineReason{
+		Code:    DeclineOrderAttributes,
+		Message: fmt.Sprintf("order does not have attributes required by provider: %s", formatAttributes(missingAttributes(c.cfg.Attributes, gspec.Requirements.Attributes))),
+	}, nil
+}
+
+// does provider have required capabilities?
+func (c groupChecker) checkResourceCapabilities(gspec *dtypes.GroupSpec) (*DeclineReason, error) {
+	attr, err := c.pass.GetAttributes()
+	if err != nil {
+		return nil, err
+	}
+
+	if gspec.MatchResourcesRequirements(attr) {
+		return nil, nil
+	}
+
+	return &DeclineReason{
+		Code:    DeclineResourceCapabilities,
+		Message: "provider does not have storage or gpu capabilities requested by resources",
+	}, nil
+}
+
+func (c groupChecker) checkVolumes(gspec *dtypes.GroupSpec) (*DeclineReason, error) {
+	for _, resources := range gspec.GetResourceUnits() {
+		if len(resources.Resources.Storage) > c.cfg.MaxGroupVolumes {
+			return &DeclineReason{
+				Code:    DeclineVolumeLimit,
+				Message: fmt.Sprintf("group volumes count exceeds (%d > %d)", len(resources.Resources.Storage), c.cfg.MaxGroupVolumes),
+			}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (c groupChecker) checkAuditorSignatures(gspec *dtypes.GroupSpec) (*DeclineReason, error) {
+	signatureRequirements := gspec.Requirements.SignedBy
+	if signatureRequirements.Size() == 0 {
+		return nil, nil
+	}
+
+	// Check that the signature requirements are met for each attribute
+	var provAttr []atypes.Provider
+	ownAttrs := atypes.Provider{
+		Owner:      c.provider.Owner,
+		Auditor:    "",
+		Attributes: c.provider.Attributes,
+	}
+	provAttr = append(provAttr, ownAttrs)
+	auditors := make([]string, 0)
+	auditors = append(auditors, signatureRequirements.AllOf...)
+	auditors = append(auditors, signatureRequirements.AnyOf...)
+
+	gotten := make(map[string]struct{})
+	for _, auditor := range auditors {
+		_, done := gotten[auditor]
+		if done {
+			continue
+		}
+		result, err := c.pass.GetAuditorAttributeSignatures(auditor)
+		if err != nil {
+			return nil, err
+		}
+		provAttr = append(provAttr, result...)
+		gotten[auditor] = struct{}{}
+	}
+
+	if gspec.MatchRequirements(provAttr) {
+		return nil, nil
+	}
+
+	return &DeclineReason{
+		Code:    DeclineAuditorSignatures,
+		Message: fmt.Sprintf("required attributes are not signed by auditors (all of: %v, any of: %v)", signatureRequirements.AllOf, signatureRequirements.AnyOf),
+	}, nil
+}
+
+func (c groupChecker) checkValidGroup(gspec *dtypes.GroupSpec) (*DeclineReason, error) {
+	if err := gspec.ValidateBasic(); err != nil {
+		return &DeclineReason{
+			Code:    DeclineInvalidGroup,
+			Message: err.Error(),
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// can pricing strategy price orders in the denomination of the group?
+func (c groupChecker) checkDenomination(gspec *dtypes.GroupSpec) (*DeclineReason, error) {
+	denom := gspec.Price().Denom
+	if c.cfg.PricingStrategy == nil || acceptsDenom(c.cfg.PricingStrategy, denom) {
+		return nil, nil
+	}
+
+	return &DeclineReason{
+		Code:    DeclineDenomination,
+		Message: fmt.Sprintf("provider does not accept payments in %s", denom),
+	}, nil
+}
+
+func policyDeclineReason(decline *policy.Decline) DeclineReason {
+	return DeclineReason{
+		Code:    DeclinePolicy,
+		Message: decline.String(),
+	}
+}
+
+// capacityDeclineReason converts inventory dry run error into decline reason.
+// errors other than lack of capacity are not related to the group and returned as is
+func capacityDeclineReason(err error) (*DeclineReason, error) {
+	if err == nil {
+		return nil, nil
+	}
+
+	if errors.Is(err, ctypes.ErrInsufficientCapacity) || errors.Is(err, ctypes.ErrTEEProfileUnknown) {
+		return &DeclineReason{
+			Code:    DeclineInsufficientCapacity,
+			Message: err.Error(),
+		}, nil
+	}
+
+	return nil, err
+}
+
+// priceDeclineReason checks calculated price fits into max price of the group
+func priceDeclineReason(price sdk.DecCoin, gspec *dtypes.GroupSpec) *DeclineReason {
+	maxPrice := gspec.Price()
+
+	if maxPrice.GetDenom() != price.GetDenom() {
+		return &DeclineReason{
+			Code:    DeclinePricing,
+			Message: fmt.Sprintf("unsupported denomination: calculated %s, max price %s", price, maxPrice),
+		}
+	}
+
+	if maxPrice.IsLT(price) {
+		return &DeclineReason{
+			Code:    DeclinePriceAboveMax,
+			Message: fmt.Sprintf("price %s is above max price %s", price, maxPrice),
+		}
+	}
+
+	return nil
+}
+
+func missingAttributes(wanted, have types.Attributes) types.Attributes {
+	var missing types.Attributes
+
+	for _, attr := range wanted {
+		if !types.AttributesAnyOf(types.Attributes{attr}, have) {
+			missing = append(missing, attr)
+		}
+	}
+
+	return missing
+}
+
+func formatAttributes(attrs types.Attributes) string {
+	res := make([]string, 0, len(attrs))
+	for _, attr := range attrs {
+		res = append(res, attr.Key+"="+attr.Value)
+	}
+
+	return strings.Join(res, ", ")
+}
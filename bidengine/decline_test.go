@@ -0,0 +1,121 @@
+package bidengine
+
+import (
+	"fmt"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	dtypes "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
+	ptypes "github.com/akash-network/akash-api/go/node/provider/v1beta3"
+	atypes "github.com/akash-network/akash-api/go/node/types/v1beta3"
+
+	"github.com/akash-network/node/testutil"
+
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+func declineCodes(reasons []DeclineReason) []string {
+	codes := make([]string, 0, len(reasons))
+	for _, reason := range reasons {
+		codes = append(codes, reason.Code)
+	}
+
+	return codes
+}
+
+func validGroupSpec() *dtypes.GroupSpec {
+	vcfg := dtypes.GetValidationConfig()
+
+	return &dtypes.GroupSpec{
+		Name: "test",
+		Resources: dtypes.ResourceUnits{
+			{
+				Resources: atypes.Resources{
+					ID:     1,
+					CPU:    &atypes.CPU{Units: atypes.NewResourceValue(uint64(vcfg.Unit.Min.CPU))},
+					GPU:    &atypes.GPU{Units: atypes.NewResourceValue(uint64(vcfg.Unit.Min.GPU))},
+					Memory: &atypes.Memory{Quantity: atypes.NewResourceValue(vcfg.Unit.Min.Memory)},
+					Storage: atypes.Volumes{
+						{Quantity: atypes.NewResourceValue(vcfg.Unit.Min.Storage)},
+					},
+				},
+				Count: 1,
+				Price: sdk.NewInt64DecCoin(testutil.CoinDenom, 23),
+			},
+		},
+	}
+}
+
+func Test_GroupCheckerReportsAllDeclines(t *testing.T) {
+	checker := groupChecker{
+		provider: &ptypes.Provider{
+			Attributes: atypes.Attributes{{Key: "region", Value: "us-west"}},
+		},
+		cfg: Config{
+			Attributes:      atypes.Attributes{{Key: "tier", Value: "community"}},
+			MaxGroupVolumes: 0,
+		},
+		pass: nullProviderAttrSignatureService{},
+	}
+
+	gspec := validGroupSpec()
+	gspec.Requirements.Attributes = atypes.Attributes{{Key: "region", Value: "eu-central"}}
+	gspec.Requirements.SignedBy.AllOf = []string{testutil.AccAddress(t).String()}
+
+	reasons, err := checker.check(gspec, true)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		DeclineProviderAttributes,
+		DeclineOrderAttributes,
+		DeclineVolumeLimit,
+		DeclineAuditorSignatures,
+	}, declineCodes(reasons))
+	require.Contains(t, reasons[0].Message, "region=eu-central")
+	require.Contains(t, reasons[1].Message, "tier=community")
+
+	// bidding stops at first decline
+	reasons, err = checker.check(gspec, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{DeclineProviderAttributes}, declineCodes(reasons))
+
+	gspec.Requirements = atypes.PlacementRequirements{
+		Attributes: atypes.Attributes{{Key: "tier", Value: "community"}},
+	}
+	checker.provider.Attributes = append(checker.provider.Attributes, atypes.Attribute{Key: "tier", Value: "community"})
+	checker.cfg.MaxGroupVolumes = 1
+
+	reasons, err = checker.check(gspec, true)
+	require.NoError(t, err)
+	require.Empty(t, reasons)
+}
+
+func Test_PriceDeclineReason(t *testing.T) {
+	gspec := defaultGroupSpec()
+	maxPrice := gspec.Price()
+
+	require.Nil(t, priceDeclineReason(maxPrice, gspec))
+
+	reason := priceDeclineReason(sdk.NewDecCoinFromDec(maxPrice.Denom, maxPrice.Amount.Add(sdk.NewDec(1))), gspec)
+	require.NotNil(t, reason)
+	require.Equal(t, DeclinePriceAboveMax, reason.Code)
+
+	reason = priceDeclineReason(sdk.NewDecCoinFromDec("uusdc", maxPrice.Amount), gspec)
+	require.NotNil(t, reason)
+	require.Equal(t, DeclinePricing, reason.Code)
+}
+
+func Test_CapacityDeclineReason(t *testing.T) {
+	reason, err := capacityDeclineReason(nil)
+	require.NoError(t, err)
+	require.Nil(t, reason)
+
+	reason, err = capacityDeclineReason(fmt.Errorf("%w: no nodes", ctypes.ErrInsufficientCapacity))
+	require.NoError(t, err)
+	require.NotNil(t, reason)
+	require.Equal(t, DeclineInsufficientCapacity, reason.Code)
+
+	_, err = capacityDeclineReason(ErrNotRunning)
+	require.ErrorIs(t, err, ErrNotRunning)
+}
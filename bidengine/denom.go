@@ -0,0 +1,134 @@
+package bidengine
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+var (
+	ErrDenomNotSupported = errors.New("bid pricing: denomination not supported")
+
+	errDenomPricingBase  = errors.New("denom pricing: base pricing strategy and its denomination required")
+	errDenomPricingDenom = errors.New("denom pricing: invalid denomination")
+)
+
+// PriceOracle provides exchange rates between denominations
+type PriceOracle interface {
+	// Supports reports whether oracle is able to quote rate of the pair without querying it
+	Supports(base, quote string) bool
+	// Rate returns amount of quote denomination single unit of base denomination is worth
+	Rate(ctx context.Context, base, quote string) (sdk.Dec, error)
+}
+
+// DenomFilter is implemented by pricing strategies which price orders of some denominations only
+type DenomFilter interface {
+	AcceptsDenom(denom string) bool
+}
+
+// acceptsDenom reports whether strategy is able to price orders in given denomination.
+// strategies not implementing DenomFilter price any denomination
+func acceptsDenom(strategy BidPricingStrategy, denom string) bool {
+	if filter, valid := strategy.(DenomFilter); valid {
+		return filter.AcceptsDenom(denom)
+	}
+
+	return true
+}
+
+// DenomPricingConfig configures pricing of orders in multiple denominations
+type DenomPricingConfig struct {
+	// Denom is denomination Base strategy prices in
+	Denom string
+	Base  BidPricingStrategy
+	// Strategies price orders of their denominations in those denominations, e.g. per denom scale tables
+	Strategies map[string]BidPricingStrategy
+	// Oracle converts price of Base strategy into denomination of the order which has no strategy of its own.
+	// orders of denominations oracle does not support are not accepted
+	Oracle PriceOracle
+}
+
+type denomPricing struct {
+	cfg DenomPricingConfig
+}
+
+// MakeDenomPricing creates pricing strategy which bids on orders in any configured denomination
+func MakeDenomPricing(cfg DenomPricingConfig) (BidPricingStrategy, error) {
+	if cfg.Base == nil || cfg.Denom == "" {
+		return nil, errDenomPricingBase
+	}
+
+	if err := sdk.ValidateDenom(cfg.Denom); err != nil {
+		return nil, fmt.Errorf("%w: %w", errDenomPricingDenom, err)
+	}
+
+	for denom, strategy := range cfg.Strategies {
+		if err := sdk.ValidateDenom(denom); err != nil {
+			return nil, fmt.Errorf("%w: %w", errDenomPricingDenom, err)
+		}
+
+		if strategy == nil {
+			return nil, fmt.Errorf("%w: no pricing strategy for %s", errDenomPricingDenom, denom)
+		}
+	}
+
+	return denomPricing{cfg: cfg}, nil
+}
+
+func (dp denomPricing) AcceptsDenom(denom string) bool {
+	if denom == dp.cfg.Denom {
+		return true
+	}
+
+	if _, exists := dp.cfg.Strategies[denom]; exists {
+		return true
+	}
+
+	return dp.convertible(denom)
+}
+
+// convertible reports whether price of Base strategy can be converted into given denomination
+func (dp denomPricing) convertible(denom string) bool {
+	return dp.cfg.Oracle != nil && dp.cfg.Oracle.Supports(dp.cfg.Denom, denom)
+}
+
+func (dp denomPricing) CalculatePrice(ctx context.Context, req Request) (sdk.DecCoin, error) {
+	denom := req.GSpec.Price().Denom
+
+	if strategy, exists := dp.cfg.Strategies[denom]; exists {
+		price, err := strategy.CalculatePrice(ctx, req)
+		if err != nil {
+			return sdk.DecCoin{}, err
+		}
+
+		return sdk.NewDecCoinFromDec(denom, price.Amount), nil
+	}
+
+	if denom != dp.cfg.Denom && !dp.convertible(denom) {
+		return sdk.DecCoin{}, errors.Wrapf(ErrDenomNotSupported, "%s", denom)
+	}
+
+	price, err := dp.cfg.Base.CalculatePrice(ctx, req)
+	if err != nil {
+		return sdk.DecCoin{}, err
+	}
+
+	if denom == dp.cfg.Denom {
+		return sdk.NewDecCoinFromDec(denom, price.Amount), nil
+	}
+
+	rate, err := dp.cfg.Oracle.Rate(ctx, dp.cfg.Denom, denom)
+	if err != nil {
+		return sdk.DecCoin{}, fmt.Errorf("%w: %s: %w", ErrDenomNotSupported, denom, err)
+	}
+
+	result := sdk.NewDecCoinFromDec(denom, price.Amount.Mul(rate))
+	if result.IsZero() {
+		return sdk.DecCoin{}, ErrBidZero
+	}
+
+	return result, nil
+}
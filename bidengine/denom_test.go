@@ -0,0 +1,133 @@
+package bidengine
+
+import (
+	"context"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	ptypes "github.com/akash-network/akash-api/go/node/provider/v1beta3"
+
+	"github.com/akash-network/node/testutil"
+)
+
+const testUSDCDenom = "ibc/170C677610AC31DF0904FFE09CD3B5C657492170E7E52372E48756B71E56F2F1"
+
+type fixedOracle map[string]sdk.Dec
+
+func (fo fixedOracle) Supports(base, quote string) bool {
+	_, exists := fo[base+":"+quote]
+	return exists
+}
+
+func (fo fixedOracle) Rate(_ context.Context, base, quote string) (sdk.Dec, error) {
+	if rate, exists := fo[base+":"+quote]; exists {
+		return rate, nil
+	}
+
+	return sdk.Dec{}, errors.New("no rate")
+}
+
+func groupSpecInDenom(denom string) Request {
+	gspec := defaultGroupSpec()
+	gspec.Resources[0].Price = sdk.NewInt64DecCoin(denom, 1000)
+
+	return Request{GSpec: gspec}
+}
+
+func Test_DenomPricingRejectsInvalidConfig(t *testing.T) {
+	base := fixedPricing(sdk.NewInt64DecCoin(testutil.CoinDenom, 10))
+
+	_, err := MakeDenomPricing(DenomPricingConfig{Denom: testutil.CoinDenom})
+	require.ErrorIs(t, err, errDenomPricingBase)
+
+	_, err = MakeDenomPricing(DenomPricingConfig{Denom: "1", Base: base})
+	require.ErrorIs(t, err, errDenomPricingDenom)
+
+	_, err = MakeDenomPricing(DenomPricingConfig{
+		Denom:      testutil.CoinDenom,
+		Base:       base,
+		Strategies: map[string]BidPricingStrategy{testUSDCDenom: nil},
+	})
+	require.ErrorIs(t, err, errDenomPricingDenom)
+}
+
+func Test_DenomPricingUsesDenomStrategy(t *testing.T) {
+	pricing, err := MakeDenomPricing(DenomPricingConfig{
+		Denom: testutil.CoinDenom,
+		Base:  fixedPricing(sdk.NewInt64DecCoin(testutil.CoinDenom, 10)),
+		Strategies: map[string]BidPricingStrategy{
+			// strategies price in denomination of the order regardless of what they report
+			testUSDCDenom: fixedPricing(sdk.NewInt64DecCoin(testutil.CoinDenom, 3)),
+		},
+	})
+	require.NoError(t, err)
+
+	price, err := pricing.CalculatePrice(context.Background(), groupSpecInDenom(testutil.CoinDenom))
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewInt64DecCoin(testutil.CoinDenom, 10), price)
+
+	price, err = pricing.CalculatePrice(context.Background(), groupSpecInDenom(testUSDCDenom))
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewInt64DecCoin(testUSDCDenom, 3), price)
+
+	_, err = pricing.CalculatePrice(context.Background(), groupSpecInDenom("uatom"))
+	require.ErrorIs(t, err, ErrDenomNotSupported)
+
+	require.True(t, acceptsDenom(pricing, testutil.CoinDenom))
+	require.True(t, acceptsDenom(pricing, testUSDCDenom))
+	require.False(t, acceptsDenom(pricing, "uatom"))
+}
+
+func Test_DenomPricingConvertsWithOracle(t *testing.T) {
+	pricing, err := MakeDenomPricing(DenomPricingConfig{
+		Denom: testutil.CoinDenom,
+		Base:  fixedPricing(sdk.NewInt64DecCoin(testutil.CoinDenom, 10)),
+		Oracle: fixedOracle{
+			testutil.CoinDenom + ":" + testUSDCDenom: sdk.MustNewDecFromStr("2.5"),
+		},
+	})
+	require.NoError(t, err)
+
+	price, err := pricing.CalculatePrice(context.Background(), groupSpecInDenom(testUSDCDenom))
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewInt64DecCoin(testUSDCDenom, 25), price)
+
+	require.True(t, acceptsDenom(pricing, testUSDCDenom))
+
+	// oracle has no rate
+	require.False(t, acceptsDenom(pricing, "uatom"))
+	_, err = pricing.CalculatePrice(context.Background(), groupSpecInDenom("uatom"))
+	require.ErrorIs(t, err, ErrDenomNotSupported)
+}
+
+func Test_GroupCheckerDeclinesDenomination(t *testing.T) {
+	pricing, err := MakeDenomPricing(DenomPricingConfig{
+		Denom: testutil.CoinDenom,
+		Base:  fixedPricing(sdk.NewInt64DecCoin(testutil.CoinDenom, 10)),
+	})
+	require.NoError(t, err)
+
+	checker := groupChecker{
+		provider: &ptypes.Provider{},
+		cfg: Config{
+			PricingStrategy: pricing,
+			MaxGroupVolumes: 1,
+		},
+		pass: nullProviderAttrSignatureService{},
+	}
+
+	gspec := validGroupSpec()
+
+	reasons, err := checker.check(gspec, true)
+	require.NoError(t, err)
+	require.Empty(t, reasons)
+
+	gspec.Resources[0].Price = sdk.NewInt64DecCoin("uatom", 23)
+
+	reasons, err = checker.check(gspec, true)
+	require.NoError(t, err)
+	require.Equal(t, []string{DeclineDenomination}, declineCodes(reasons))
+}
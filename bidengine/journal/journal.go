@@ -0,0 +1,297 @@
+package journal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	bolt "go.etcd.io/bbolt"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
+
+	"github.com/akash-network/provider/tools/boltdb"
+)
+
+const (
+	EventReserved = "reserved"
+	EventPriced   = "priced"
+	EventBid      = "bid"
+	EventResumed  = "resumed"
+	EventClosed   = "closed"
+)
+
+var (
+	ErrNotFound = errors.New("bid journal: entry not found")
+
+	ordersBucket = []byte("orders")
+)
+
+// Event is a single step of order lifecycle
+type Event struct {
+	Type   string    `json:"type"`
+	Time   time.Time `json:"time"`
+	Detail string    `json:"detail,omitempty"`
+}
+
+// Entry is the journal record of the order provider has reserved resources for
+type Entry struct {
+	OrderID mtypes.OrderID `json:"order_id"`
+	// Price is the last price calculated for the order
+	Price *sdk.DecCoin `json:"price,omitempty"`
+	// BidTx is hash of the transaction bid has been created with
+	BidTx string `json:"bid_tx,omitempty"`
+	// BidDeadline is the time bid is closed at unless lease is created. zero when bid timeout is disabled
+	BidDeadline time.Time `json:"bid_deadline,omitempty"`
+	Closed      bool      `json:"closed"`
+	CloseReason string    `json:"close_reason,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Events      []Event   `json:"events"`
+}
+
+// BidPlaced returns true when bid has been broadcast for the order
+func (e Entry) BidPlaced() bool {
+	return e.BidTx != ""
+}
+
+// ListOptions filters journal entries
+type ListOptions struct {
+	// Owner limits entries to orders of given deployment owner
+	Owner string
+	// InFlight limits entries to orders not closed yet
+	InFlight bool
+	// Limit is max number of most recently updated entries to return. 0 returns all
+	Limit int
+}
+
+// Journal keeps lifecycle of orders provider reserves resources and bids for on disk,
+// so in-flight bids survive provider restarts.
+// all methods of nil Journal are no-op
+type Journal struct {
+	db  *bolt.DB
+	now func() time.Time
+}
+
+// Open opens journal file, creating it if does not exist
+func Open(path string) (*Journal, error) {
+	db, err := boltdb.Open(path, ordersBucket)
+	if err != nil {
+		return nil, fmt.Errorf("bid journal: %w", err)
+	}
+
+	return &Journal{
+		db:  db,
+		now: time.Now,
+	}, nil
+}
+
+func (j *Journal) Close() error {
+	if j == nil {
+		return nil
+	}
+
+	return j.db.Close()
+}
+
+// Reserved records resources have been reserved for the order, creating the entry if needed
+func (j *Journal) Reserved(oid mtypes.OrderID) error {
+	return j.update(oid, true, func(e *Entry) {
+		e.Closed = false
+		e.CloseReason = ""
+		e.addEvent(EventReserved, "", j.now())
+	})
+}
+
+// Priced records price calculated for the order
+func (j *Journal) Priced(oid mtypes.OrderID, price sdk.DecCoin) error {
+	return j.update(oid, false, func(e *Entry) {
+		e.Price = &price
+		e.addEvent(EventPriced, price.String(), j.now())
+	})
+}
+
+// BidPlaced records bid transaction and the time bid times out at
+func (j *Journal) BidPlaced(oid mtypes.OrderID, txHash string, deadline time.Time) error {
+	return j.update(oid, false, func(e *Entry) {
+		e.BidTx = txHash
+		e.BidDeadline = deadline
+		e.addEvent(EventBid, txHash, j.now())
+	})
+}
+
+// Resumed records order has been picked up after provider restart
+func (j *Journal) Resumed(oid mtypes.OrderID) error {
+	return j.update(oid, false, func(e *Entry) {
+		e.addEvent(EventResumed, "", j.now())
+	})
+}
+
+// Closed records the order is done with. orders without entry are ignored
+func (j *Journal) Closed(oid mtypes.OrderID, reason string) error {
+	return j.update(oid, false, func(e *Entry) {
+		e.Closed = true
+		e.CloseReason = reason
+		e.addEvent(EventClosed, reason, j.now())
+	})
+}
+
+// Get returns entry of the order
+func (j *Journal) Get(oid mtypes.OrderID) (Entry, error) {
+	if j == nil {
+		return Entry{}, ErrNotFound
+	}
+
+	var entry Entry
+
+	err := j.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(ordersBucket).Get(entryKey(oid))
+		if data == nil {
+			return ErrNotFound
+		}
+
+		return json.Unmarshal(data, &entry)
+	})
+
+	return entry, err
+}
+
+// List returns entries matching options, most recently updated first
+func (j *Journal) List(opts ListOptions) ([]Entry, error) {
+	if j == nil {
+		return nil, nil
+	}
+
+	var prefix []byte
+	if opts.Owner != "" {
+		prefix = []byte(opts.Owner + "/")
+	}
+
+	var entries []Entry
+
+	err := j.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(ordersBucket).Cursor()
+
+		for key, data := c.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, data = c.Next() {
+			var entry Entry
+			if err := json.Unmarshal(data, &entry); err != nil {
+				return fmt.Errorf("bid journal: decoding %s: %w", key, err)
+			}
+
+			if opts.InFlight && entry.Closed {
+				continue
+			}
+
+			entries = append(entries, entry)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(entries, func(i, k int) bool {
+		return entries[i].UpdatedAt.After(entries[k].UpdatedAt)
+	})
+
+	if opts.Limit > 0 && len(entries) > opts.Limit {
+		entries = entries[:opts.Limit]
+	}
+
+	return entries, nil
+}
+
+// Prune removes entries closed before given time
+func (j *Journal) Prune(before time.Time) (int, error) {
+	if j == nil {
+		return 0, nil
+	}
+
+	var pruned [][]byte
+
+	err := j.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(ordersBucket)
+
+		err := bucket.ForEach(func(key, data []byte) error {
+			var entry Entry
+			if err := json.Unmarshal(data, &entry); err != nil {
+				return fmt.Errorf("bid journal: decoding %s: %w", key, err)
+			}
+
+			if entry.Closed && entry.UpdatedAt.Before(before) {
+				pruned = append(pruned, key)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// bucket must not be modified while iterating over it
+		for _, key := range pruned {
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(pruned), nil
+}
+
+func (j *Journal) update(oid mtypes.OrderID, create bool, fn func(*Entry)) error {
+	if j == nil {
+		return nil
+	}
+
+	return j.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(ordersBucket)
+		key := entryKey(oid)
+
+		var entry Entry
+
+		if data := bucket.Get(key); data != nil {
+			if err := json.Unmarshal(data, &entry); err != nil {
+				return fmt.Errorf("bid journal: decoding %s: %w", key, err)
+			}
+		} else if !create {
+			return nil
+		} else {
+			entry = Entry{
+				OrderID:   oid,
+				CreatedAt: j.now(),
+			}
+		}
+
+		fn(&entry)
+		entry.UpdatedAt = j.now()
+
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(key, data)
+	})
+}
+
+func (e *Entry) addEvent(kind string, detail string, at time.Time) {
+	e.Events = append(e.Events, Event{
+		Type:   kind,
+		Time:   at,
+		Detail: detail,
+	})
+}
+
+func entryKey(oid mtypes.OrderID) []byte {
+	return []byte(fmt.Sprintf("%s/%d/%d/%d", oid.Owner, oid.DSeq, oid.GSeq, oid.OSeq))
+}
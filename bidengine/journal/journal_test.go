@@ -0,0 +1,191 @@
+package journal
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
+
+	"github.com/akash-network/node/testutil"
+)
+
+func openTestJournal(t *testing.T) (*Journal, *time.Time) {
+	t.Helper()
+
+	j, err := Open(filepath.Join(t.TempDir(), "journal", "bid-journal.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = j.Close()
+	})
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	j.now = func() time.Time {
+		return now
+	}
+
+	return j, &now
+}
+
+func TestJournalOrderLifecycle(t *testing.T) {
+	j, now := openTestJournal(t)
+
+	oid := testutil.OrderID(t)
+
+	// orders without entry are ignored
+	require.NoError(t, j.Closed(oid, "bid-timeout"))
+	_, err := j.Get(oid)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, j.Reserved(oid))
+
+	price := sdk.NewInt64DecCoin(testutil.CoinDenom, 10)
+	require.NoError(t, j.Priced(oid, price))
+
+	deadline := now.Add(5 * time.Minute)
+	require.NoError(t, j.BidPlaced(oid, "ABCDEF", deadline))
+
+	entry, err := j.Get(oid)
+	require.NoError(t, err)
+	require.Equal(t, oid, entry.OrderID)
+	require.NotNil(t, entry.Price)
+	require.True(t, price.IsEqual(*entry.Price))
+	require.True(t, entry.BidPlaced())
+	require.Equal(t, "ABCDEF", entry.BidTx)
+	require.True(t, deadline.Equal(entry.BidDeadline))
+	require.False(t, entry.Closed)
+
+	*now = now.Add(time.Minute)
+	require.NoError(t, j.Resumed(oid))
+	require.NoError(t, j.Closed(oid, "lease-won"))
+
+	entry, err = j.Get(oid)
+	require.NoError(t, err)
+	require.True(t, entry.Closed)
+	require.Equal(t, "lease-won", entry.CloseReason)
+	require.True(t, now.Equal(entry.UpdatedAt))
+
+	types := make([]string, 0, len(entry.Events))
+	for _, ev := range entry.Events {
+		types = append(types, ev.Type)
+	}
+	require.Equal(t, []string{EventReserved, EventPriced, EventBid, EventResumed, EventClosed}, types)
+}
+
+func TestJournalList(t *testing.T) {
+	j, now := openTestJournal(t)
+
+	first := testutil.OrderID(t)
+	second := testutil.OrderID(t)
+	third := mtypes.MakeOrderID(second.GroupID(), second.OSeq+1)
+
+	for _, oid := range []mtypes.OrderID{first, second, third} {
+		*now = now.Add(time.Second)
+		require.NoError(t, j.Reserved(oid))
+	}
+
+	*now = now.Add(time.Second)
+	require.NoError(t, j.Closed(second, "bid-timeout"))
+
+	entries, err := j.List(ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	// most recently updated first
+	require.Equal(t, second, entries[0].OrderID)
+	require.Equal(t, third, entries[1].OrderID)
+	require.Equal(t, first, entries[2].OrderID)
+
+	entries, err = j.List(ListOptions{InFlight: true})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, third, entries[0].OrderID)
+	require.Equal(t, first, entries[1].OrderID)
+
+	entries, err = j.List(ListOptions{Owner: second.Owner})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, second, entries[0].OrderID)
+	require.Equal(t, third, entries[1].OrderID)
+
+	entries, err = j.List(ListOptions{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, second, entries[0].OrderID)
+}
+
+func TestJournalPrune(t *testing.T) {
+	j, now := openTestJournal(t)
+
+	closed := testutil.OrderID(t)
+	inFlight := testutil.OrderID(t)
+
+	require.NoError(t, j.Reserved(closed))
+	require.NoError(t, j.Reserved(inFlight))
+	require.NoError(t, j.Closed(closed, "order-closed"))
+
+	pruned, err := j.Prune(now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, pruned)
+
+	*now = now.Add(2 * time.Hour)
+
+	pruned, err = j.Prune(now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, pruned)
+
+	_, err = j.Get(closed)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = j.Get(inFlight)
+	require.NoError(t, err)
+}
+
+func TestJournalReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bid-journal.db")
+
+	j, err := Open(path)
+	require.NoError(t, err)
+
+	oid := testutil.OrderID(t)
+	require.NoError(t, j.Reserved(oid))
+	require.NoError(t, j.Close())
+
+	j, err = Open(path)
+	require.NoError(t, err)
+	defer func() {
+		_ = j.Close()
+	}()
+
+	entries, err := j.List(ListOptions{InFlight: true})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, oid, entries[0].OrderID)
+}
+
+func TestNilJournal(t *testing.T) {
+	var j *Journal
+
+	oid := testutil.OrderID(t)
+
+	require.NoError(t, j.Reserved(oid))
+	require.NoError(t, j.Priced(oid, sdk.NewInt64DecCoin(testutil.CoinDenom, 1)))
+	require.NoError(t, j.BidPlaced(oid, "tx", time.Time{}))
+	require.NoError(t, j.Resumed(oid))
+	require.NoError(t, j.Closed(oid, "shutdown"))
+
+	_, err := j.Get(oid)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	entries, err := j.List(ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	pruned, err := j.Prune(time.Now())
+	require.NoError(t, err)
+	require.Zero(t, pruned)
+
+	require.NoError(t, j.Close())
+}
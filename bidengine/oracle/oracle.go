@@ -0,0 +1,318 @@
+package oracle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// maxResponseSize limits amount of data read from rate service
+	maxResponseSize = 4096
+)
+
+var (
+	ErrInvalidRates = errors.New("price oracle: invalid rates")
+	ErrDenoms       = errors.New("price oracle: quote denominations required")
+	ErrNoRate       = errors.New("price oracle: no rate")
+	ErrURL          = errors.New("price oracle: url must be absolute http(s) url")
+	ErrUnexpected   = errors.New("price oracle: unexpected response")
+)
+
+// Rate is exchange rate between two denominations
+type Rate struct {
+	Base  string `json:"base" yaml:"base"`
+	Quote string `json:"quote" yaml:"quote"`
+	// Rate is amount of quote denomination single unit of base denomination is worth
+	Rate string `json:"rate" yaml:"rate"`
+}
+
+// Config is the static rates file
+type Config struct {
+	Rates []Rate `json:"rates" yaml:"rates"`
+}
+
+type pair struct {
+	base  string
+	quote string
+}
+
+// Static serves rates from the file. rate of the inverse pair is derived when only one direction is set
+type Static struct {
+	rates map[pair]sdk.Dec
+}
+
+// ReadFile loads static rates file
+func ReadFile(file string) (*Static, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(data)
+}
+
+// Parse decodes and validates static rates document
+func Parse(data []byte) (*Static, error) {
+	cfg := Config{}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRates, err)
+	}
+
+	return NewStatic(cfg)
+}
+
+// NewStatic validates config and creates oracle out of it
+func NewStatic(cfg Config) (*Static, error) {
+	res := &Static{
+		rates: make(map[pair]sdk.Dec),
+	}
+
+	for idx, rate := range cfg.Rates {
+		if err := sdk.ValidateDenom(rate.Base); err != nil {
+			return nil, fmt.Errorf("%w: rate #%d: %w", ErrInvalidRates, idx, err)
+		}
+
+		if err := sdk.ValidateDenom(rate.Quote); err != nil {
+			return nil, fmt.Errorf("%w: rate #%d: %w", ErrInvalidRates, idx, err)
+		}
+
+		if rate.Base == rate.Quote {
+			return nil, fmt.Errorf("%w: rate #%d: base and quote denominations are the same", ErrInvalidRates, idx)
+		}
+
+		val, err := parseRate(rate.Rate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: rate #%d: %w", ErrInvalidRates, idx, err)
+		}
+
+		key := pair{base: rate.Base, quote: rate.Quote}
+		if _, exists := res.rates[key]; exists {
+			return nil, fmt.Errorf("%w: rate #%d: duplicate rate %s/%s", ErrInvalidRates, idx, rate.Base, rate.Quote)
+		}
+
+		res.rates[key] = val
+	}
+
+	return res, nil
+}
+
+// Supports reports whether rates file has rate of the pair in either direction
+func (s *Static) Supports(base, quote string) bool {
+	if base == quote {
+		return true
+	}
+
+	_, exists := s.rates[pair{base: base, quote: quote}]
+	if !exists {
+		_, exists = s.rates[pair{base: quote, quote: base}]
+	}
+
+	return exists
+}
+
+func (s *Static) Rate(_ context.Context, base, quote string) (sdk.Dec, error) {
+	if base == quote {
+		return sdk.OneDec(), nil
+	}
+
+	if rate, exists := s.rates[pair{base: base, quote: quote}]; exists {
+		return rate, nil
+	}
+
+	if rate, exists := s.rates[pair{base: quote, quote: base}]; exists {
+		return sdk.OneDec().Quo(rate), nil
+	}
+
+	return sdk.Dec{}, fmt.Errorf("%w: %s/%s", ErrNoRate, base, quote)
+}
+
+type httpReply struct {
+	Rate string `json:"rate"`
+}
+
+type cacheEntry struct {
+	rate    sdk.Dec
+	expires time.Time
+}
+
+// HTTP queries rates from the service with GET <url>?base=<denom>&quote=<denom>.
+// service responds with JSON object {"rate": "<amount>"}
+type HTTP struct {
+	url      *url.URL
+	denoms   map[string]bool
+	client   *http.Client
+	cacheTTL time.Duration
+
+	lock  sync.Mutex
+	cache map[pair]cacheEntry
+
+	now func() time.Time
+}
+
+// NewHTTP creates oracle backed by the rate service. service is only asked for rates into quote denominations
+// listed in denoms as it can't tell in advance which it has. rates are reused for cacheTTL, zero disables cache
+func NewHTTP(endpoint string, denoms []string, timeout time.Duration, cacheTTL time.Duration) (*HTTP, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || !u.IsAbs() || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("%w: %q", ErrURL, endpoint)
+	}
+
+	if len(denoms) == 0 {
+		return nil, ErrDenoms
+	}
+
+	quotes := make(map[string]bool, len(denoms))
+	for _, denom := range denoms {
+		if err := sdk.ValidateDenom(denom); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrDenoms, err)
+		}
+
+		quotes[denom] = true
+	}
+
+	res := &HTTP{
+		url:    u,
+		denoms: quotes,
+		client: &http.Client{
+			Timeout: timeout,
+		},
+		cacheTTL: cacheTTL,
+		cache:    make(map[pair]cacheEntry),
+		now:      time.Now,
+	}
+
+	return res, nil
+}
+
+// Supports reports whether quote is one of denominations oracle has been configured with
+func (h *HTTP) Supports(base, quote string) bool {
+	return base == quote || h.denoms[quote]
+}
+
+func (h *HTTP) Rate(ctx context.Context, base, quote string) (sdk.Dec, error) {
+	if base == quote {
+		return sdk.OneDec(), nil
+	}
+
+	if !h.denoms[quote] {
+		return sdk.Dec{}, fmt.Errorf("%w: %s/%s", ErrNoRate, base, quote)
+	}
+
+	key := pair{base: base, quote: quote}
+
+	if rate, valid := h.cached(key); valid {
+		return rate, nil
+	}
+
+	rate, err := h.query(ctx, base, quote)
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	if h.cacheTTL > 0 {
+		h.lock.Lock()
+		h.cache[key] = cacheEntry{
+			rate:    rate,
+			expires: h.now().Add(h.cacheTTL),
+		}
+		h.lock.Unlock()
+	}
+
+	return rate, nil
+}
+
+func (h *HTTP) cached(key pair) (sdk.Dec, bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	entry, exists := h.cache[key]
+	if !exists {
+		return sdk.Dec{}, false
+	}
+
+	if !h.now().Before(entry.expires) {
+		delete(h.cache, key)
+		return sdk.Dec{}, false
+	}
+
+	return entry.rate, true
+}
+
+func (h *HTTP) query(ctx context.Context, base, quote string) (sdk.Dec, error) {
+	u := *h.url
+
+	query := u.Query()
+	query.Set("base", base)
+	query.Set("quote", quote)
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return sdk.Dec{}, fmt.Errorf("%w: %s/%s", ErrNoRate, base, quote)
+	default:
+		return sdk.Dec{}, fmt.Errorf("%w: status %d: %s", ErrUnexpected, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	reply := httpReply{}
+	if err := json.Unmarshal(data, &reply); err != nil {
+		return sdk.Dec{}, fmt.Errorf("%w: %w", ErrUnexpected, err)
+	}
+
+	rate, err := parseRate(reply.Rate)
+	if err != nil {
+		return sdk.Dec{}, fmt.Errorf("%w: %w", ErrUnexpected, err)
+	}
+
+	return rate, nil
+}
+
+func parseRate(val string) (sdk.Dec, error) {
+	rate, err := sdk.NewDecFromStr(strings.TrimSpace(val))
+	if err != nil {
+		return sdk.Dec{}, err
+	}
+
+	if !rate.IsPositive() {
+		return sdk.Dec{}, fmt.Errorf("rate must be positive, got %s", val)
+	}
+
+	return rate, nil
+}
@@ -0,0 +1,135 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+const usdc = "ibc/170C677610AC31DF0904FFE09CD3B5C657492170E7E52372E48756B71E56F2F1"
+
+func TestStaticRates(t *testing.T) {
+	oracle, err := Parse([]byte(fmt.Sprintf(`
+rates:
+  - base: uakt
+    quote: %s
+    rate: "2.5"
+`, usdc)))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	rate, err := oracle.Rate(ctx, "uakt", usdc)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("2.5"), rate)
+
+	rate, err = oracle.Rate(ctx, usdc, "uakt")
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("0.4"), rate)
+
+	rate, err = oracle.Rate(ctx, "uakt", "uakt")
+	require.NoError(t, err)
+	require.Equal(t, sdk.OneDec(), rate)
+
+	_, err = oracle.Rate(ctx, "uakt", "uatom")
+	require.ErrorIs(t, err, ErrNoRate)
+
+	require.True(t, oracle.Supports("uakt", usdc))
+	require.True(t, oracle.Supports(usdc, "uakt"))
+	require.False(t, oracle.Supports("uakt", "uatom"))
+}
+
+func TestStaticRejectsInvalidRates(t *testing.T) {
+	for _, doc := range []string{
+		"rates:\n  - base: uakt\n    quote: uusdc\n    rate: \"0\"\n",
+		"rates:\n  - base: uakt\n    quote: uusdc\n    rate: abc\n",
+		"rates:\n  - base: uakt\n    quote: uakt\n    rate: \"1\"\n",
+		"rates:\n  - base: \"1\"\n    quote: uakt\n    rate: \"1\"\n",
+		"rates:\n  - base: uakt\n    quote: uusdc\n    rate: \"1\"\n  - base: uakt\n    quote: uusdc\n    rate: \"2\"\n",
+		"rate: []\n",
+	} {
+		_, err := Parse([]byte(doc))
+		require.ErrorIs(t, err, ErrInvalidRates, doc)
+	}
+}
+
+func TestHTTPRates(t *testing.T) {
+	calls := &atomic.Int32{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+
+		if r.URL.Query().Get("base") != "uakt" || r.URL.Query().Get("quote") != usdc {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"rate": "3.1"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	oracle, err := NewHTTP(server.URL+"/rate", []string{usdc}, time.Second, time.Minute)
+	require.NoError(t, err)
+
+	now := time.Now()
+	oracle.now = func() time.Time {
+		return now
+	}
+
+	ctx := context.Background()
+
+	rate, err := oracle.Rate(ctx, "uakt", usdc)
+	require.NoError(t, err)
+	require.Equal(t, sdk.MustNewDecFromStr("3.1"), rate)
+
+	// served from cache
+	_, err = oracle.Rate(ctx, "uakt", usdc)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), calls.Load())
+
+	now = now.Add(2 * time.Minute)
+
+	_, err = oracle.Rate(ctx, "uakt", usdc)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), calls.Load())
+
+	_, err = oracle.Rate(ctx, usdc, "uakt")
+	require.ErrorIs(t, err, ErrNoRate)
+
+	// denominations service was not configured with are not queried
+	require.True(t, oracle.Supports("uakt", usdc))
+	require.False(t, oracle.Supports("uakt", "uatom"))
+
+	_, err = oracle.Rate(ctx, "uakt", "uatom")
+	require.ErrorIs(t, err, ErrNoRate)
+	require.Equal(t, int32(2), calls.Load())
+
+	_, err = NewHTTP("localhost:8080", []string{usdc}, time.Second, 0)
+	require.ErrorIs(t, err, ErrURL)
+
+	_, err = NewHTTP(server.URL, nil, time.Second, 0)
+	require.ErrorIs(t, err, ErrDenoms)
+
+	_, err = NewHTTP(server.URL, []string{"1"}, time.Second, 0)
+	require.ErrorIs(t, err, ErrDenoms)
+}
+
+func TestHTTPUnexpectedReply(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"rate": "-1"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	oracle, err := NewHTTP(server.URL, []string{usdc}, time.Second, 0)
+	require.NoError(t, err)
+
+	_, err = oracle.Rate(context.Background(), "uakt", usdc)
+	require.ErrorIs(t, err, ErrUnexpected)
+}
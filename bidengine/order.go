@@ -2,6 +2,7 @@ package bidengine
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"time"
@@ -14,13 +15,13 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/tendermint/tendermint/libs/log"
 
-	atypes "github.com/akash-network/akash-api/go/node/audit/v1beta3"
 	dtypes "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
 	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
 	"github.com/akash-network/node/pubsub"
 	metricsutils "github.com/akash-network/node/util/metrics"
 	"github.com/akash-network/node/util/runner"
 
+	"github.com/akash-network/provider/bidengine/policy"
 	"github.com/akash-network/provider/cluster"
 	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	"github.com/akash-network/provider/event"
@@ -38,9 +39,10 @@ type order struct {
 	sub                        pubsub.Subscriber
 	reservationFulfilledNotify chan<- int
 
-	log  log.Logger
-	lc   lifecycle.Lifecycle
-	pass ProviderAttrSignatureService
+	log    log.Logger
+	lc     lifecycle.Lifecycle
+	pass   ProviderAttrSignatureService
+	wallet *walletMonitor
 }
 
 var (
@@ -105,6 +107,7 @@ func newOrderInternal(svc *service, oid mtypes.OrderID, cfg Config, pass Provide
 		lc:                         lifecycle.New(),
 		reservationFulfilledNotify: reservationFulfilledNotify, // Normally nil in production
 		pass:                       pass,
+		wallet:                     svc.wallet,
 	}
 
 	// Shut down when parent begins shutting down
@@ -171,6 +174,9 @@ func (o *order) run(checkForExistingBid bool) {
 
 		won bool
 		msg *mtypes.MsgCreateBid
+
+		// closeReason is recorded in the bid journal once order is done with
+		closeReason = "aborted"
 	)
 
 	// Begin fetching group details immediately.
@@ -199,6 +205,7 @@ loop:
 	for {
 		select {
 		case <-o.lc.ShutdownRequest():
+			closeReason = "shutdown"
 			break loop
 
 		case queryBid := <-queryBidCh:
@@ -225,12 +232,30 @@ loop:
 				}
 				bidPlaced = true
 
-				if o.isStaleBid(bid) {
-					o.session.Log().Info("found expired bid", "block-height", bid.GetCreatedAt())
-					break loop
+				entry, err := o.cfg.Journal.Get(o.orderID)
+				if err == nil {
+					o.logJournalError(o.cfg.Journal.Resumed(o.orderID))
 				}
 
-				bidTimeout = o.getBidTimeout()
+				// journal knows when bid times out, so there is no need to estimate bid age from block height
+				if err == nil && o.bidTimeoutEnabled() && !entry.BidDeadline.IsZero() {
+					remaining := time.Until(entry.BidDeadline)
+					if remaining <= 0 {
+						o.session.Log().Info("found expired bid", "deadline", entry.BidDeadline)
+						closeReason = "bid-timeout"
+						break loop
+					}
+
+					bidTimeout = time.After(remaining)
+				} else {
+					if o.isStaleBid(bid) {
+						o.session.Log().Info("found expired bid", "block-height", bid.GetCreatedAt())
+						closeReason = "bid-timeout"
+						break loop
+					}
+
+					bidTimeout = o.getBidTimeout()
+				}
 			}
 			groupch = storedGroupCh // Allow getting the group details result now
 			storedGroupCh = nil
@@ -250,6 +275,7 @@ loop:
 					orderCompleteCounter.WithLabelValues("lease-lost").Inc()
 					o.log.Info("lease lost", "lease", ev.ID)
 					bidPlaced = false // Lease lost, network closes bid
+					closeReason = "lease-lost"
 					break loop
 				}
 				orderCompleteCounter.WithLabelValues("lease-won").Inc()
@@ -265,6 +291,7 @@ loop:
 					o.log.Error("failed to publish to event queue", err)
 				}
 				won = true
+				closeReason = "lease-won"
 
 				break loop
 
@@ -276,6 +303,7 @@ loop:
 
 				o.log.Info("order closed")
 				orderCompleteCounter.WithLabelValues("order-closed").Inc()
+				closeReason = "order-closed"
 				break loop
 
 			case mtypes.EventBidClosed:
@@ -297,6 +325,7 @@ loop:
 				// Bid has been closed (possibly by someone manually closing it on the CLI)
 				bidPlaced = false // bid already not on the blockchain
 				orderCompleteCounter.WithLabelValues("bid-closed-external").Inc()
+				closeReason = "bid-closed-external"
 				break loop
 			}
 
@@ -315,7 +344,7 @@ loop:
 			group = &res
 
 			shouldBidCh = runner.Do(func() runner.Result {
-				return runner.NewResult(o.shouldBid(group))
+				return runner.NewResult(o.shouldBid(ctx, group))
 			})
 
 		case result := <-shouldBidCh:
@@ -364,6 +393,20 @@ loop:
 
 			// Resources reserved
 			reservation = result.Value().(ctypes.Reservation)
+			o.logJournalError(o.cfg.Journal.Reserved(o.orderID))
+
+			// wildcard GPU requests are known only after inventory has picked the model
+			if decline, err := o.evaluatePolicy(ctx, group, reservation.GetAllocatedResources()); err != nil {
+				o.log.Error("evaluating bid policy", "err", err)
+				break loop
+			} else if decline != nil {
+				decline.Record()
+				shouldBidCounter.WithLabelValues("decline").Inc()
+				o.log.Info("unable to fulfill: declined by bid policy", "rule", decline.Rule, "kind", decline.Kind, "reason", decline.Reason)
+				closeReason = "declined"
+				break loop
+			}
+
 			if bidPlaced {
 				o.log.Info("Fulfillment already exists")
 				// fulfillment already created (state recovered via queryExistingOrders)
@@ -382,6 +425,12 @@ loop:
 		case result := <-pricech:
 			pricech = nil
 			if result.Error() != nil {
+				if errors.Is(result.Error(), ErrBidDeclined) {
+					o.log.Info("unable to fulfill: pricing declined order", "reason", result.Error())
+					closeReason = DeclinePricing
+					break loop
+				}
+
 				o.log.Error("error calculating price", "err", result.Error())
 				break loop
 			}
@@ -389,16 +438,28 @@ loop:
 			price := result.Value().(sdk.DecCoin)
 			maxPrice := group.GroupSpec.Price()
 
+			// pricing strategy has priced order in some other denomination, bid would be rejected by the chain
 			if maxPrice.GetDenom() != price.GetDenom() {
-				o.log.Error("Unsupported Denomination", "calculated", price.String(), "max-price", maxPrice.String())
+				o.log.Info("unable to fulfill: unsupported denomination", "calculated", price.String(), "max-price", maxPrice.String())
+				closeReason = "unsupported-denom"
 				break loop
 			}
 
 			if maxPrice.IsLT(price) {
 				o.log.Info("Price too high, not bidding", "price", price.String(), "max-price", maxPrice.String())
+				closeReason = "price-too-high"
 				break loop
 			}
 
+			// balance may have dropped while resources were being reserved
+			if o.wallet.paused() {
+				o.log.Info("unable to fulfill: bidding paused", "reason", DeclineWalletBalance)
+				closeReason = DeclineWalletBalance
+				break loop
+			}
+
+			o.logJournalError(o.cfg.Journal.Priced(o.orderID, price))
+
 			o.log.Debug("submitting fulfillment", "price", price)
 
 			offer := mtypes.ResourceOfferFromRU(reservation.GetAllocatedResources())
@@ -414,6 +475,8 @@ loop:
 			if result.Error() != nil {
 				bidCounter.WithLabelValues(metricsutils.OpenLabel, metricsutils.FailLabel).Inc()
 				o.log.Error("bid failed", "err", result.Error())
+				// bid may have failed for lack of funds, don't wait for scheduled check to find out
+				o.wallet.refresh()
 				break loop
 			}
 
@@ -424,10 +487,23 @@ loop:
 			bidPlaced = true
 
 			bidTimeout = o.getBidTimeout()
+
+			var deadline time.Time
+			if o.bidTimeoutEnabled() {
+				deadline = time.Now().Add(o.cfg.BidTimeout)
+			}
+
+			var txHash string
+			if resp, valid := result.Value().(*sdk.TxResponse); valid {
+				txHash = resp.TxHash
+			}
+
+			o.logJournalError(o.cfg.Journal.BidPlaced(o.orderID, txHash, deadline))
 		case <-bidTimeout:
 			// The bid was not acted upon (e.g. lease created or deployment closed) so close it now
 			o.log.Info("bid timeout, closing bid")
 			orderCompleteCounter.WithLabelValues("bid-timeout").Inc()
+			closeReason = "bid-timeout"
 			break loop
 		}
 	}
@@ -466,12 +542,19 @@ loop:
 			if err != nil {
 				o.log.Error("closing bid", "err", err)
 				bidCounter.WithLabelValues("close", metricsutils.FailLabel).Inc()
+				// keep the order in flight, so bid is closed when journal is recovered
+				closeReason = ""
 			} else {
 				o.log.Info("bid closed", "order-id", o.orderID)
 				bidCounter.WithLabelValues("close", metricsutils.SuccessLabel).Inc()
 			}
 		}
 	}
+
+	if closeReason != "" {
+		o.logJournalError(o.cfg.Journal.Closed(o.orderID, closeReason))
+	}
+
 	cancel()
 
 	// Wait for all runners to complete.
@@ -489,75 +572,91 @@ loop:
 	}
 }
 
-func (o *order) shouldBid(group *dtypes.Group) (bool, error) {
-	// does provider have required attributes?
-	if !group.GroupSpec.MatchAttributes(o.session.Provider().Attributes) {
-		o.log.Debug("unable to fulfill: incompatible provider attributes")
-		return false, nil
+func (o *order) logJournalError(err error) {
+	if err != nil {
+		o.log.Error("recording order in bid journal", "err", err)
 	}
+}
 
-	// does order have required attributes?
-	if !o.cfg.Attributes.SubsetOf(group.GroupSpec.Requirements.Attributes) {
-		o.log.Debug("unable to fulfill: incompatible order attributes")
-		return false, nil
+func (o *order) shouldBid(ctx context.Context, group *dtypes.Group) (bool, error) {
+	checker := groupChecker{
+		provider: o.session.Provider(),
+		cfg:      o.cfg,
+		pass:     o.pass,
+		wallet:   o.wallet,
 	}
 
-	attr, err := o.pass.GetAttributes()
+	declines, err := checker.check(&group.GroupSpec, false)
 	if err != nil {
 		return false, err
 	}
 
-	// does provider have required capabilities?
-	if !group.GroupSpec.MatchResourcesRequirements(attr) {
-		o.log.Debug("unable to fulfill: incompatible attributes for resources requirements", "wanted", group.GroupSpec, "have", attr)
+	if len(declines) != 0 {
+		if declines[0].Code == DeclineWalletBalance {
+			o.log.Info("unable to fulfill: bidding paused", "reason", declines[0].Code, "message", declines[0].Message)
+		} else {
+			o.log.Debug("unable to fulfill", "reason", declines[0].Code, "message", declines[0].Message)
+		}
+
 		return false, nil
 	}
 
-	for _, resources := range group.GroupSpec.GetResourceUnits() {
-		if len(resources.Resources.Storage) > o.cfg.MaxGroupVolumes {
-			o.log.Info(fmt.Sprintf("unable to fulfill: group volumes count exceeds (%d > %d)", len(resources.Resources.Storage), o.cfg.MaxGroupVolumes))
-			return false, nil
-		}
+	// operator defined bid policy
+	if decline, err := o.evaluatePolicy(ctx, group, nil); err != nil {
+		return false, err
+	} else if decline != nil {
+		decline.Record()
+		o.log.Info("unable to fulfill: declined by bid policy", "rule", decline.Rule, "kind", decline.Kind, "reason", decline.Reason)
+		return false, nil
 	}
-	signatureRequirements := group.GroupSpec.Requirements.SignedBy
-	if signatureRequirements.Size() != 0 {
-		// Check that the signature requirements are met for each attribute
-		var provAttr []atypes.Provider
-		ownAttrs := atypes.Provider{
-			Owner:      o.session.Provider().Owner,
-			Auditor:    "",
-			Attributes: o.session.Provider().Attributes,
-		}
-		provAttr = append(provAttr, ownAttrs)
-		auditors := make([]string, 0)
-		auditors = append(auditors, group.GroupSpec.Requirements.SignedBy.AllOf...)
-		auditors = append(auditors, group.GroupSpec.Requirements.SignedBy.AnyOf...)
-
-		gotten := make(map[string]struct{})
-		for _, auditor := range auditors {
-			_, done := gotten[auditor]
-			if done {
-				continue
-			}
-			result, err := o.pass.GetAuditorAttributeSignatures(auditor)
-			if err != nil {
-				return false, err
+
+	// TEE workloads can be placed only onto nodes exposing TEE devices.
+	// check there is a capable node with enough room before going any further
+	if ctypes.ResourceGroupRequiresTEE(group) {
+		if err := o.cluster.CheckCapacity(group); err != nil {
+			if errors.Is(err, ctypes.ErrInsufficientCapacity) || errors.Is(err, ctypes.ErrTEEProfileUnknown) {
+				o.log.Info("unable to fulfill: no TEE capable nodes with enough capacity", "err", err)
+				return false, nil
 			}
-			provAttr = append(provAttr, result...)
-			gotten[auditor] = struct{}{}
-		}
 
-		ok := group.GroupSpec.MatchRequirements(provAttr)
-		if !ok {
-			o.log.Debug("attribute signature requirements not met")
-			return false, nil
+			return false, err
 		}
 	}
 
-	if err := group.GroupSpec.ValidateBasic(); err != nil {
-		o.log.Error("unable to fulfill: group validation error",
-			"err", err)
-		return false, nil
-	}
 	return true, nil
 }
+
+// evaluatePolicy checks group against operator defined bid policy.
+// allocated resources are passed once reservation is made, so rules see GPU models inventory has picked
+func (o *order) evaluatePolicy(ctx context.Context, group *dtypes.Group, allocated dtypes.ResourceUnits) (*policy.Decline, error) {
+	if o.cfg.Policy == nil {
+		return nil, nil
+	}
+
+	p := o.cfg.Policy.Policy()
+	if p.Rules() == 0 {
+		return nil, nil
+	}
+
+	req := policy.Order{
+		Owner:              group.GroupID.Owner,
+		GroupSpec:          &group.GroupSpec,
+		AllocatedResources: allocated,
+	}
+
+	if p.NeedsEscrow() {
+		res, err := o.session.Client().Query().Deployment(ctx, &dtypes.QueryDeploymentRequest{ID: group.GroupID.DeploymentID()})
+		if err != nil {
+			return nil, err
+		}
+
+		balance := res.EscrowAccount.Balance
+		if funds := res.EscrowAccount.Funds; funds.Denom == balance.Denom && !funds.Amount.IsNil() {
+			balance = balance.Add(funds)
+		}
+
+		req.EscrowBalance = &balance
+	}
+
+	return p.Evaluate(req), nil
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/akash-network/node/pubsub"
 	"github.com/akash-network/node/testutil"
 
+	"github.com/akash-network/provider/bidengine/policy"
 	clustermocks "github.com/akash-network/provider/cluster/mocks"
 	clmocks "github.com/akash-network/provider/cluster/types/v1beta3/mocks"
 	"github.com/akash-network/provider/session"
@@ -721,5 +722,35 @@ func Test_ShouldntBidIfOrderAttrsDontMatch(t *testing.T) {
 	scaffold.cluster.AssertNotCalled(t, "Unreserve", scaffold.orderID, mock.Anything)
 }
 
+func Test_ShouldntBidIfPolicyDeclines(t *testing.T) {
+	// test group requests 2 replicas
+	p, err := policy.New(policy.Config{
+		Rules: []policy.Rule{
+			{
+				Name:        "small-groups",
+				MaxReplicas: 1,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	cfg := &Config{Policy: policy.NewStaticSource(p)}
+	order, scaffold, _ := makeOrderForTest(t, false, mtypes.BidStateInvalid, nil, cfg, testBidCreatedAt)
+
+	<-order.lc.Done() // Stops whenever it figures it shouldn't bid
+
+	// Should not have called reserve ever
+	scaffold.cluster.AssertNotCalled(t, "Reserve", scaffold.orderID, mock.Anything)
+
+	var broadcast []sdk.Msg
+
+	select {
+	case broadcast = <-scaffold.broadcasts:
+	default:
+	}
+	// Should never have broadcast since bid was declined
+	require.Nil(t, broadcast)
+}
+
 // TODO - add test failing the call to Broadcast on TxClient and
 // and then confirm that the reservation is cancelled
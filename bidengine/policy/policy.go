@@ -0,0 +1,497 @@
+package policy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"gopkg.in/yaml.v3"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	dtypes "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
+	netutil "github.com/akash-network/node/util/network"
+)
+
+const (
+	KindOwners           = "owners"
+	KindImages           = "images"
+	KindMinPrice         = "min_price"
+	KindMinLeaseDuration = "min_lease_duration"
+	KindMaxReplicas      = "max_replicas"
+	KindGPU              = "gpu"
+
+	defaultRegistry = "docker.io"
+)
+
+var (
+	ErrInvalidPolicy = errors.New("bid policy: invalid")
+	ErrDeclined      = errors.New("bid policy: declined")
+
+	declineCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "provider_bid_policy_decline",
+		Help: "The total number of orders and manifests declined by bid policy",
+	}, []string{"rule", "kind"})
+)
+
+// ListRule matches value against allow and deny lists.
+// deny takes precedence, empty allow list allows everything not denied
+type ListRule struct {
+	Allow []string `json:"allow,omitempty" yaml:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty" yaml:"deny,omitempty"`
+}
+
+// GPURule reserves GPU models for given tenants.
+// models are <vendor>/<model> patterns, e.g. nvidia/h100 or nvidia/*
+type GPURule struct {
+	Models  []string `json:"models" yaml:"models"`
+	Tenants []string `json:"tenants" yaml:"tenants"`
+}
+
+// Rule is a single policy statement. Exactly one of the kinds must be set
+type Rule struct {
+	Name   string    `json:"name" yaml:"name"`
+	Owners *ListRule `json:"owners,omitempty" yaml:"owners,omitempty"`
+	// Images allow and deny image registries, e.g. ghcr.io or *.gcr.io.
+	// images without registry are resolved to docker.io
+	Images *ListRule `json:"images,omitempty" yaml:"images,omitempty"`
+	// MinPrice is minimal max price per block of single replica tenant must offer, e.g. 100uakt
+	MinPrice []string `json:"min_price,omitempty" yaml:"min_price,omitempty"`
+	// MinLeaseDuration is minimal duration deployment escrow must be able to pay
+	// the group at its max price for
+	MinLeaseDuration time.Duration `json:"min_lease_duration,omitempty" yaml:"min_lease_duration,omitempty"`
+	MaxReplicas      uint32        `json:"max_replicas,omitempty" yaml:"max_replicas,omitempty"`
+	GPU              *GPURule      `json:"gpu,omitempty" yaml:"gpu,omitempty"`
+
+	minPrice sdk.DecCoins
+}
+
+// Config is the bid policy file
+type Config struct {
+	Rules []Rule `json:"rules" yaml:"rules"`
+}
+
+// Policy is validated set of rules evaluated in order they are declared.
+// nil Policy accepts everything
+type Policy struct {
+	rules []Rule
+}
+
+// Order is the subject of the policy evaluation
+type Order struct {
+	Owner     string
+	GroupSpec *dtypes.GroupSpec
+	// AllocatedResources are set once inventory has reserved resources for the order
+	AllocatedResources dtypes.ResourceUnits
+	// EscrowBalance is amount available in deployment escrow. nil if unknown
+	EscrowBalance *sdk.DecCoin
+}
+
+// Decline describes rule which has declined the order
+type Decline struct {
+	Rule   string `json:"rule"`
+	Kind   string `json:"kind"`
+	Reason string `json:"reason"`
+}
+
+func (d Decline) String() string {
+	return fmt.Sprintf("rule %q (%s): %s", d.Rule, d.Kind, d.Reason)
+}
+
+// Record counts decline in metrics
+func (d Decline) Record() {
+	declineCounter.WithLabelValues(d.Rule, d.Kind).Inc()
+}
+
+// Err returns decline as error wrapping ErrDeclined
+func (d Decline) Err() error {
+	return fmt.Errorf("%w: %s", ErrDeclined, d)
+}
+
+// ReadFile loads and validates policy file
+func ReadFile(file string) (*Policy, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(data)
+}
+
+// Parse decodes and validates policy document
+func Parse(data []byte) (*Policy, error) {
+	cfg := Config{}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPolicy, err)
+	}
+
+	return New(cfg)
+}
+
+// New validates config and creates policy out of it
+func New(cfg Config) (*Policy, error) {
+	names := make(map[string]bool)
+	res := &Policy{
+		rules: make([]Rule, 0, len(cfg.Rules)),
+	}
+
+	for i, rule := range cfg.Rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("%w: rule #%d: name cannot be empty", ErrInvalidPolicy, i)
+		}
+
+		if names[rule.Name] {
+			return nil, fmt.Errorf("%w: rule %q: duplicate name", ErrInvalidPolicy, rule.Name)
+		}
+		names[rule.Name] = true
+
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("%w: rule %q: %w", ErrInvalidPolicy, rule.Name, err)
+		}
+
+		res.rules = append(res.rules, rule)
+	}
+
+	return res, nil
+}
+
+func (r *Rule) kind() []string {
+	var kinds []string
+
+	if r.Owners != nil {
+		kinds = append(kinds, KindOwners)
+	}
+	if r.Images != nil {
+		kinds = append(kinds, KindImages)
+	}
+	if len(r.MinPrice) != 0 {
+		kinds = append(kinds, KindMinPrice)
+	}
+	if r.MinLeaseDuration != 0 {
+		kinds = append(kinds, KindMinLeaseDuration)
+	}
+	if r.MaxReplicas != 0 {
+		kinds = append(kinds, KindMaxReplicas)
+	}
+	if r.GPU != nil {
+		kinds = append(kinds, KindGPU)
+	}
+
+	return kinds
+}
+
+// Kind returns type of the rule
+func (r *Rule) Kind() string {
+	if kinds := r.kind(); len(kinds) == 1 {
+		return kinds[0]
+	}
+
+	return ""
+}
+
+func (r *Rule) validate() error {
+	kinds := r.kind()
+	if len(kinds) != 1 {
+		return fmt.Errorf("exactly one of %v must be set, got %v",
+			[]string{KindOwners, KindImages, KindMinPrice, KindMinLeaseDuration, KindMaxReplicas, KindGPU}, kinds)
+	}
+
+	switch kinds[0] {
+	case KindOwners:
+		if len(r.Owners.Allow) == 0 && len(r.Owners.Deny) == 0 {
+			return errors.New("owners: allow or deny must be set")
+		}
+	case KindImages:
+		if len(r.Images.Allow) == 0 && len(r.Images.Deny) == 0 {
+			return errors.New("images: allow or deny must be set")
+		}
+
+		for _, pattern := range append(append([]string{}, r.Images.Allow...), r.Images.Deny...) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("images: pattern %q: %w", pattern, err)
+			}
+		}
+	case KindMinPrice:
+		r.minPrice = make(sdk.DecCoins, 0, len(r.MinPrice))
+
+		for _, val := range r.MinPrice {
+			coin, err := sdk.ParseDecCoin(val)
+			if err != nil {
+				return fmt.Errorf("min_price: %w", err)
+			}
+			r.minPrice = append(r.minPrice, coin)
+		}
+	case KindMinLeaseDuration:
+		if r.MinLeaseDuration < 0 {
+			return errors.New("min_lease_duration cannot be negative")
+		}
+	case KindGPU:
+		if len(r.GPU.Models) == 0 {
+			return errors.New("gpu: models cannot be empty")
+		}
+
+		for _, pattern := range r.GPU.Models {
+			if len(strings.Split(pattern, "/")) != 2 {
+				return fmt.Errorf("gpu: model %q must be in form <vendor>/<model>", pattern)
+			}
+
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("gpu: model %q: %w", pattern, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Rules returns number of rules in the policy
+func (p *Policy) Rules() int {
+	if p == nil {
+		return 0
+	}
+
+	return len(p.rules)
+}
+
+// NeedsEscrow reports if policy has rules evaluated against deployment escrow balance
+func (p *Policy) NeedsEscrow() bool {
+	if p == nil {
+		return false
+	}
+
+	for i := range p.rules {
+		if p.rules[i].Kind() == KindMinLeaseDuration {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Evaluate checks order against every order rule and returns first matching decline.
+// image rules are not evaluated as orders do not carry images, see EvaluateImages
+func (p *Policy) Evaluate(o Order) *Decline {
+	if p == nil {
+		return nil
+	}
+
+	for i := range p.rules {
+		rule := &p.rules[i]
+
+		var reason string
+
+		switch rule.Kind() {
+		case KindOwners:
+			reason = rule.evaluateOwner(o.Owner)
+		case KindMinPrice:
+			reason = rule.evaluateMinPrice(o)
+		case KindMinLeaseDuration:
+			reason = rule.evaluateMinLeaseDuration(o)
+		case KindMaxReplicas:
+			if replicas := groupReplicas(o.GroupSpec); replicas > rule.MaxReplicas {
+				reason = fmt.Sprintf("group requests %d replicas, maximum is %d", replicas, rule.MaxReplicas)
+			}
+		case KindGPU:
+			reason = rule.evaluateGPU(o)
+		}
+
+		if reason != "" {
+			return &Decline{
+				Rule:   rule.Name,
+				Kind:   rule.Kind(),
+				Reason: reason,
+			}
+		}
+	}
+
+	return nil
+}
+
+// EvaluateImages checks images of the tenant manifest against image rules
+func (p *Policy) EvaluateImages(images []string) *Decline {
+	if p == nil {
+		return nil
+	}
+
+	for i := range p.rules {
+		rule := &p.rules[i]
+		if rule.Kind() != KindImages {
+			continue
+		}
+
+		for _, image := range images {
+			registry := ImageRegistry(image)
+
+			if matchAny(rule.Images.Deny, registry) {
+				return &Decline{
+					Rule:   rule.Name,
+					Kind:   KindImages,
+					Reason: fmt.Sprintf("image %q: registry %q is denied", image, registry),
+				}
+			}
+
+			if len(rule.Images.Allow) != 0 && !matchAny(rule.Images.Allow, registry) {
+				return &Decline{
+					Rule:   rule.Name,
+					Kind:   KindImages,
+					Reason: fmt.Sprintf("image %q: registry %q is not allowed", image, registry),
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *Rule) evaluateOwner(owner string) string {
+	for _, val := range r.Owners.Deny {
+		if val == owner {
+			return fmt.Sprintf("owner %s is denied", owner)
+		}
+	}
+
+	if len(r.Owners.Allow) == 0 {
+		return ""
+	}
+
+	for _, val := range r.Owners.Allow {
+		if val == owner {
+			return ""
+		}
+	}
+
+	return fmt.Sprintf("owner %s is not allowed", owner)
+}
+
+func (r *Rule) evaluateMinPrice(o Order) string {
+	price := o.GroupSpec.Price()
+
+	replicas := groupReplicas(o.GroupSpec)
+	if replicas == 0 {
+		return ""
+	}
+
+	var minPrice sdk.Dec
+	for _, coin := range r.minPrice {
+		if coin.Denom == price.Denom {
+			minPrice = coin.Amount
+		}
+	}
+
+	// tenant pays in denomination rule does not set minimum for
+	if minPrice.IsNil() {
+		return ""
+	}
+
+	perUnit := price.Amount.QuoInt64(int64(replicas))
+	if perUnit.LT(minPrice) {
+		return fmt.Sprintf("max price per replica %s%s is below minimum %s%s", perUnit, price.Denom, minPrice, price.Denom)
+	}
+
+	return ""
+}
+
+func (r *Rule) evaluateMinLeaseDuration(o Order) string {
+	if o.EscrowBalance == nil {
+		return ""
+	}
+
+	price := o.GroupSpec.Price()
+	if price.Denom != o.EscrowBalance.Denom || !price.Amount.IsPositive() {
+		return ""
+	}
+
+	blocks := o.EscrowBalance.Amount.Quo(price.Amount).TruncateInt64()
+	duration := time.Duration(blocks) * netutil.AverageBlockTime
+
+	if duration < r.MinLeaseDuration {
+		return fmt.Sprintf("escrow funds the group for %s at max price, minimum is %s", duration, r.MinLeaseDuration)
+	}
+
+	return ""
+}
+
+func (r *Rule) evaluateGPU(o Order) string {
+	for _, tenant := range r.GPU.Tenants {
+		if tenant == o.Owner {
+			return ""
+		}
+	}
+
+	resources := o.GroupSpec.Resources
+	if len(o.AllocatedResources) != 0 {
+		resources = o.AllocatedResources
+	}
+
+	for _, res := range resources {
+		if res.GPU == nil || res.GPU.Units.Value() == 0 {
+			continue
+		}
+
+		for _, attr := range res.GPU.Attributes {
+			// vendor/nvidia/model/h100[/ram/80Gi][/interface/sxm]
+			tokens := strings.Split(attr.Key, "/")
+			if len(tokens) < 4 || tokens[0] != "vendor" || tokens[2] != "model" {
+				continue
+			}
+
+			// wildcard requests are checked against model inventory has picked
+			if tokens[3] == "*" {
+				continue
+			}
+
+			model := strings.ToLower(tokens[1] + "/" + tokens[3])
+			if matchAny(r.GPU.Models, model) {
+				return fmt.Sprintf("gpu model %s is reserved", model)
+			}
+		}
+	}
+
+	return ""
+}
+
+func groupReplicas(gspec *dtypes.GroupSpec) uint32 {
+	var replicas uint32
+
+	for _, res := range gspec.Resources {
+		replicas += res.Count
+	}
+
+	return replicas
+}
+
+func matchAny(patterns []string, val string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(strings.ToLower(pattern), val); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ImageRegistry returns registry host of the image reference
+func ImageRegistry(image string) string {
+	idx := strings.Index(image, "/")
+	if idx == -1 {
+		return defaultRegistry
+	}
+
+	host := image[:idx]
+	if host != "localhost" && !strings.ContainsAny(host, ".:") {
+		return defaultRegistry
+	}
+
+	return strings.ToLower(host)
+}
@@ -0,0 +1,177 @@
+package policy
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	dtypes "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
+	atypes "github.com/akash-network/akash-api/go/node/types/v1beta3"
+)
+
+const testPolicy = `---
+rules:
+  - name: blocked
+    owners:
+      deny: [akash1blocked]
+  - name: trusted-registries
+    images:
+      allow: [docker.io, ghcr.io, "*.gcr.io"]
+      deny: [evil.gcr.io]
+  - name: min-price
+    min_price: [10uakt]
+  - name: small-groups
+    max_replicas: 4
+  - name: runway
+    min_lease_duration: 1h
+  - name: h100-reserved
+    gpu:
+      models: [nvidia/h100]
+      tenants: [akash1vip]
+`
+
+func testGroupSpec(count uint32, price int64, gpuAttrs ...string) *dtypes.GroupSpec {
+	gpu := &atypes.GPU{
+		Units: atypes.NewResourceValue(0),
+	}
+
+	if len(gpuAttrs) != 0 {
+		gpu.Units = atypes.NewResourceValue(1)
+		for _, attr := range gpuAttrs {
+			gpu.Attributes = append(gpu.Attributes, atypes.Attribute{Key: attr, Value: "true"})
+		}
+	}
+
+	return &dtypes.GroupSpec{
+		Name: "test",
+		Resources: dtypes.ResourceUnits{
+			{
+				Resources: atypes.Resources{
+					ID:  1,
+					GPU: gpu,
+				},
+				Count: count,
+				Price: sdk.NewInt64DecCoin("uakt", price),
+			},
+		},
+	}
+}
+
+func requireDecline(t *testing.T, decline *Decline, rule string, kind string) {
+	t.Helper()
+
+	require.NotNil(t, decline)
+	require.Equal(t, rule, decline.Rule)
+	require.Equal(t, kind, decline.Kind)
+	require.NotEmpty(t, decline.Reason)
+}
+
+func TestPolicyParse(t *testing.T) {
+	p, err := Parse([]byte(testPolicy))
+	require.NoError(t, err)
+	require.Equal(t, 6, p.Rules())
+	require.True(t, p.NeedsEscrow())
+
+	p, err = Parse(nil)
+	require.NoError(t, err)
+	require.Equal(t, 0, p.Rules())
+	require.False(t, p.NeedsEscrow())
+
+	invalid := []string{
+		"rules: [{max_replicas: 1}]",
+		"rules: [{name: a, max_replicas: 1}, {name: a, max_replicas: 2}]",
+		"rules: [{name: a}]",
+		"rules: [{name: a, max_replicas: 1, owners: {deny: [x]}}]",
+		"rules: [{name: a, owners: {}}]",
+		"rules: [{name: a, images: {allow: ['[']}}]",
+		"rules: [{name: a, min_price: [abc]}]",
+		"rules: [{name: a, gpu: {models: [h100]}}]",
+		"rules: [{name: a, unknown: 1}]",
+	}
+
+	for _, doc := range invalid {
+		_, err = Parse([]byte(doc))
+		require.ErrorIs(t, err, ErrInvalidPolicy, doc)
+	}
+}
+
+func TestPolicyEvaluate(t *testing.T) {
+	p, err := Parse([]byte(testPolicy))
+	require.NoError(t, err)
+
+	require.Nil(t, p.Evaluate(Order{Owner: "akash1tenant", GroupSpec: testGroupSpec(2, 40)}))
+
+	requireDecline(t, p.Evaluate(Order{Owner: "akash1blocked", GroupSpec: testGroupSpec(2, 40)}), "blocked", KindOwners)
+	requireDecline(t, p.Evaluate(Order{Owner: "akash1tenant", GroupSpec: testGroupSpec(4, 2)}), "min-price", KindMinPrice)
+	requireDecline(t, p.Evaluate(Order{Owner: "akash1tenant", GroupSpec: testGroupSpec(5, 100)}), "small-groups", KindMaxReplicas)
+
+	// runway is checked only when escrow balance is known
+	balance := sdk.NewInt64DecCoin("uakt", 80*100)
+	requireDecline(t, p.Evaluate(Order{Owner: "akash1tenant", GroupSpec: testGroupSpec(2, 40), EscrowBalance: &balance}), "runway", KindMinLeaseDuration)
+
+	balance = sdk.NewInt64DecCoin("uakt", 80*1000)
+	require.Nil(t, p.Evaluate(Order{Owner: "akash1tenant", GroupSpec: testGroupSpec(2, 40), EscrowBalance: &balance}))
+
+	// min price is not set for other denominations
+	gspec := testGroupSpec(2, 1)
+	gspec.Resources[0].Price.Denom = "uusdc"
+	require.Nil(t, p.Evaluate(Order{Owner: "akash1tenant", GroupSpec: gspec}))
+}
+
+func TestPolicyEvaluateGPU(t *testing.T) {
+	p, err := Parse([]byte(testPolicy))
+	require.NoError(t, err)
+
+	gspec := testGroupSpec(1, 100, "vendor/nvidia/model/h100/ram/80Gi")
+	requireDecline(t, p.Evaluate(Order{Owner: "akash1tenant", GroupSpec: gspec}), "h100-reserved", KindGPU)
+	require.Nil(t, p.Evaluate(Order{Owner: "akash1vip", GroupSpec: gspec}))
+
+	require.Nil(t, p.Evaluate(Order{Owner: "akash1tenant", GroupSpec: testGroupSpec(1, 100, "vendor/nvidia/model/a100")}))
+
+	// wildcard is checked against allocated model
+	gspec = testGroupSpec(1, 100, "vendor/nvidia/model/*")
+	require.Nil(t, p.Evaluate(Order{Owner: "akash1tenant", GroupSpec: gspec}))
+
+	allocated := testGroupSpec(1, 100, "vendor/nvidia/model/h100")
+	requireDecline(t, p.Evaluate(Order{
+		Owner:              "akash1tenant",
+		GroupSpec:          gspec,
+		AllocatedResources: allocated.Resources,
+	}), "h100-reserved", KindGPU)
+}
+
+func TestPolicyEvaluateImages(t *testing.T) {
+	p, err := Parse([]byte(testPolicy))
+	require.NoError(t, err)
+
+	require.Nil(t, p.EvaluateImages([]string{"nginx", "library/nginx:1.25", "ghcr.io/akash-network/provider:0.6", "us.gcr.io/proj/app"}))
+
+	decline := p.EvaluateImages([]string{"nginx", "quay.io/app/app"})
+	requireDecline(t, decline, "trusted-registries", KindImages)
+	require.ErrorIs(t, decline.Err(), ErrDeclined)
+
+	requireDecline(t, p.EvaluateImages([]string{"evil.gcr.io/app"}), "trusted-registries", KindImages)
+	requireDecline(t, p.EvaluateImages([]string{"localhost:5000/app"}), "trusted-registries", KindImages)
+}
+
+func TestPolicyNil(t *testing.T) {
+	var p *Policy
+
+	require.Nil(t, p.Evaluate(Order{Owner: "akash1blocked", GroupSpec: testGroupSpec(100, 1)}))
+	require.Nil(t, p.EvaluateImages([]string{"quay.io/app/app"}))
+	require.Equal(t, 0, p.Rules())
+}
+
+func TestImageRegistry(t *testing.T) {
+	for image, registry := range map[string]string{
+		"nginx":                        "docker.io",
+		"library/nginx":                "docker.io",
+		"docker.io/library/nginx:1.25": "docker.io",
+		"GHCR.io/org/app@sha256:abcd":  "ghcr.io",
+		"localhost/app":                "localhost",
+		"registry:5000/app":            "registry:5000",
+	} {
+		require.Equal(t, registry, ImageRegistry(image), image)
+	}
+}
@@ -0,0 +1,156 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+var (
+	reloadCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "provider_bid_policy_reload",
+		Help: "The total number of bid policy reloads",
+	}, []string{"result"})
+
+	rulesGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "provider_bid_policy_rules",
+		Help: "Number of rules in the active bid policy",
+	})
+)
+
+// Source provides policy currently in effect
+type Source interface {
+	Policy() *Policy
+}
+
+type staticSource struct {
+	policy *Policy
+}
+
+// NewStaticSource returns source which always provides given policy
+func NewStaticSource(p *Policy) Source {
+	return staticSource{policy: p}
+}
+
+func (s staticSource) Policy() *Policy {
+	return s.policy
+}
+
+// Watcher keeps policy loaded from the file and reloads it when file changes.
+// invalid file keeps previous policy in effect, removed file disables the policy
+type Watcher struct {
+	file    string
+	log     log.Logger
+	current atomic.Pointer[Policy]
+	data    []byte
+}
+
+var _ Source = (*Watcher)(nil)
+
+// NewWatcher loads policy file. Loading errors are fatal at the start
+func NewWatcher(log log.Logger, file string) (*Watcher, error) {
+	file, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		file: file,
+		log:  log.With("cmp", "bid-policy"),
+	}
+
+	if w.data, err = os.ReadFile(file); err != nil {
+		return nil, err
+	}
+
+	p, err := Parse(w.data)
+	if err != nil {
+		return nil, err
+	}
+
+	w.set(p)
+
+	return w, nil
+}
+
+func (w *Watcher) Policy() *Policy {
+	return w.current.Load()
+}
+
+func (w *Watcher) set(p *Policy) {
+	w.current.Store(p)
+	rulesGauge.Set(float64(p.Rules()))
+}
+
+// Run watches directory of the policy file rather than file itself
+// so atomic replacements (editors, mounted ConfigMaps) are picked up as well
+func (w *Watcher) Run(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = watcher.Close()
+	}()
+
+	if err = watcher.Add(filepath.Dir(w.file)); err != nil {
+		return err
+	}
+
+	w.log.Info("started", "file", w.file, "rules", w.Policy().Rules())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-watcher.Errors:
+			w.log.Error("watching policy file", "err", err)
+		case <-watcher.Events:
+			w.reload()
+		}
+	}
+}
+
+func (w *Watcher) reload() {
+	data, err := os.ReadFile(w.file)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			reloadCounter.WithLabelValues("fail").Inc()
+			w.log.Error("reading policy file, keeping previous policy", "err", err)
+			return
+		}
+
+		data = nil
+	}
+
+	if bytes.Equal(data, w.data) {
+		return
+	}
+
+	p, err := Parse(data)
+	if err != nil {
+		reloadCounter.WithLabelValues("fail").Inc()
+		w.log.Error("invalid policy file, keeping previous policy", "err", err)
+		return
+	}
+
+	w.data = data
+	w.set(p)
+
+	reloadCounter.WithLabelValues("success").Inc()
+
+	if data == nil {
+		w.log.Info("policy file removed, policy disabled")
+	} else {
+		w.log.Info("policy reloaded", "rules", p.Rules())
+	}
+}
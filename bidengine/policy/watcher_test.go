@@ -0,0 +1,97 @@
+package policy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/akash-network/node/testutil"
+)
+
+func TestWatcherReload(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("rules: [{name: a, max_replicas: 1}]"), 0o600))
+
+	w, err := NewWatcher(testutil.Logger(t), file)
+	require.NoError(t, err)
+	require.Equal(t, 1, w.Policy().Rules())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Run(ctx)
+	}()
+
+	// eventually waits for cond, failing with the error of Run if watcher stops before cond is met
+	eventually := func(cond func() bool, tick time.Duration) {
+		t.Helper()
+
+		timeout := time.NewTimer(5 * time.Second)
+		defer timeout.Stop()
+
+		ticker := time.NewTicker(tick)
+		defer ticker.Stop()
+
+		for !cond() {
+			select {
+			case err := <-done:
+				require.NoError(t, err, "watcher stopped")
+				require.FailNow(t, "watcher stopped")
+			case <-timeout.C:
+				require.FailNow(t, "condition never satisfied")
+			case <-ticker.C:
+			}
+		}
+	}
+
+	requireRules := func(expected int) {
+		t.Helper()
+		eventually(func() bool {
+			return w.Policy().Rules() == expected
+		}, 10*time.Millisecond)
+	}
+
+	// Run may not have registered watch yet, keep writing until change is picked up
+	eventually(func() bool {
+		_ = os.WriteFile(file, []byte("rules: [{name: a, max_replicas: 1}, {name: b, owners: {deny: [x]}}]"), 0o600)
+		return w.Policy().Rules() == 2
+	}, 50*time.Millisecond)
+
+	// invalid policy keeps previous one in effect
+	require.NoError(t, os.WriteFile(file, []byte("rules: [{name: a}]"), 0o600))
+	time.Sleep(100 * time.Millisecond)
+	requireRules(2)
+
+	// atomic replacement
+	tmp := file + ".tmp"
+	require.NoError(t, os.WriteFile(tmp, []byte("rules: []"), 0o600))
+	require.NoError(t, os.Rename(tmp, file))
+	requireRules(0)
+
+	require.NoError(t, os.WriteFile(file, []byte("rules: [{name: a, max_replicas: 1}]"), 0o600))
+	requireRules(1)
+
+	// removed file disables policy
+	require.NoError(t, os.Remove(file))
+	requireRules(0)
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestWatcherInvalidAtStart(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "policy.yaml")
+
+	_, err := NewWatcher(testutil.Logger(t), file)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	require.NoError(t, os.WriteFile(file, []byte("rules: [{name: a}]"), 0o600))
+	_, err = NewWatcher(testutil.Logger(t), file)
+	require.ErrorIs(t, err, ErrInvalidPolicy)
+}
@@ -3,7 +3,9 @@ package bidengine
 import (
 	"context"
 	"crypto/rand"
+	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
@@ -27,6 +29,8 @@ type Request struct {
 
 const (
 	DefaultPricePrecision = 6
+
+	gpuScaleWildcard = "*"
 )
 
 type BidPricingStrategy interface {
@@ -36,6 +40,8 @@ type BidPricingStrategy interface {
 var (
 	errAllScalesZero               = errors.New("at least one bid price must be a non-zero number")
 	errNoPriceScaleForStorageClass = errors.New("no pricing configured for storage class")
+	errNoPriceScaleForGPU          = errors.New("no pricing configured for gpu model")
+	errInvalidGPUScaleKey          = errors.New("invalid gpu pricing key")
 	errScaleNegative               = errors.New("scale price cannot be negative")
 )
 
@@ -77,36 +83,150 @@ func (ss Storage) AllLessThenOrEqual(val decimal.Decimal) bool {
 	return true
 }
 
+// GPU holds price per GPU unit keyed by "<vendor>/<model>[/ram/<size>][/interface/<pcie|sxm>]".
+// model "*" prices any model of the vendor and key "*" prices any GPU.
+// most specific key matching the GPU is used
+type GPU map[string]decimal.Decimal
+
+// ParseGPUScaleKey validates and normalizes GPU pricing key
+func ParseGPUScaleKey(key string) (string, error) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	if key == gpuScaleWildcard {
+		return key, nil
+	}
+
+	tokens := strings.Split(key, "/")
+	if len(tokens) < 2 || len(tokens)%2 != 0 {
+		return "", fmt.Errorf("%w: %q", errInvalidGPUScaleKey, key)
+	}
+
+	for _, token := range tokens {
+		if token == "" {
+			return "", fmt.Errorf("%w: %q", errInvalidGPUScaleKey, key)
+		}
+	}
+
+	if tokens[1] == gpuScaleWildcard && len(tokens) > 2 {
+		return "", fmt.Errorf("%w: %q: ram and interface require model", errInvalidGPUScaleKey, key)
+	}
+
+	for i := 2; i < len(tokens); i += 2 {
+		switch tokens[i] {
+		case "ram":
+		case "interface":
+			switch tokens[i+1] {
+			case "pcie", "sxm":
+			default:
+				return "", fmt.Errorf("%w: %q: unsupported interface %q", errInvalidGPUScaleKey, key, tokens[i+1])
+			}
+		default:
+			return "", fmt.Errorf("%w: %q: unknown attribute %q", errInvalidGPUScaleKey, key, tokens[i])
+		}
+	}
+
+	return key, nil
+}
+
+func (gs GPU) IsAnyZero() bool {
+	if len(gs) == 0 {
+		return true
+	}
+
+	for _, val := range gs {
+		if val.IsZero() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (gs GPU) IsAnyNegative() bool {
+	for _, val := range gs {
+		if val.IsNegative() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PriceOf returns unit price of given GPU model looking up keys from the most to the least specific
+func (gs GPU) PriceOf(vendor string, attrs gpuVendorAttributes) (decimal.Decimal, bool) {
+	vendor = strings.ToLower(vendor)
+	model := strings.ToLower(attrs.Model)
+
+	base := fmt.Sprintf("%s/%s", vendor, model)
+	keys := make([]string, 0, 6)
+
+	if attrs.RAM != nil && attrs.Interface != nil {
+		keys = append(keys, fmt.Sprintf("%s/ram/%s/interface/%s", base, strings.ToLower(*attrs.RAM), strings.ToLower(*attrs.Interface)))
+	}
+
+	if attrs.RAM != nil {
+		keys = append(keys, fmt.Sprintf("%s/ram/%s", base, strings.ToLower(*attrs.RAM)))
+	}
+
+	if attrs.Interface != nil {
+		keys = append(keys, fmt.Sprintf("%s/interface/%s", base, strings.ToLower(*attrs.Interface)))
+	}
+
+	keys = append(keys, base, fmt.Sprintf("%s/%s", vendor, gpuScaleWildcard), gpuScaleWildcard)
+
+	for _, key := range keys {
+		if val, exists := gs[key]; exists {
+			return val, true
+		}
+	}
+
+	return decimal.Decimal{}, false
+}
+
 type scalePricing struct {
 	cpuScale      decimal.Decimal
 	memoryScale   decimal.Decimal
+	gpuScale      GPU
 	storageScale  Storage
 	endpointScale decimal.Decimal
 	ipScale       decimal.Decimal
+	// snapshotScale prices room persistent volumes of the order may take with their snapshots.
+	// lease price is fixed at bid time, so it is charged whether or not volumes get snapshotted
+	snapshotScale decimal.Decimal
 }
 
 func MakeScalePricing(
 	cpuScale decimal.Decimal,
 	memoryScale decimal.Decimal,
+	gpuScale GPU,
 	storageScale Storage,
 	endpointScale decimal.Decimal,
 	ipScale decimal.Decimal,
+	snapshotScale decimal.Decimal,
 ) (BidPricingStrategy, error) {
-	if cpuScale.IsZero() && memoryScale.IsZero() && storageScale.IsAnyZero() && endpointScale.IsZero() && ipScale.IsZero() {
+	if cpuScale.IsZero() && memoryScale.IsZero() && gpuScale.IsAnyZero() && storageScale.IsAnyZero() && endpointScale.IsZero() &&
+		ipScale.IsZero() && snapshotScale.IsZero() {
 		return nil, errAllScalesZero
 	}
 
-	if cpuScale.IsNegative() || memoryScale.IsNegative() || storageScale.IsAnyNegative() || endpointScale.IsNegative() ||
-		ipScale.IsNegative() {
+	if cpuScale.IsNegative() || memoryScale.IsNegative() || gpuScale.IsAnyNegative() || storageScale.IsAnyNegative() ||
+		endpointScale.IsNegative() || ipScale.IsNegative() || snapshotScale.IsNegative() {
 		return nil, errScaleNegative
 	}
 
+	for key := range gpuScale {
+		if _, err := ParseGPUScaleKey(key); err != nil {
+			return nil, err
+		}
+	}
+
 	result := scalePricing{
 		cpuScale:      cpuScale,
 		memoryScale:   memoryScale,
+		gpuScale:      gpuScale,
 		storageScale:  storageScale,
 		endpointScale: endpointScale,
 		ipScale:       ipScale,
+		snapshotScale: snapshotScale,
 	}
 
 	return result, nil
@@ -115,6 +235,8 @@ func MakeScalePricing(
 var (
 	ErrBidQuantityInvalid = errors.New("A bid quantity is invalid")
 	ErrBidZero            = errors.New("A bid of zero was produced")
+	// ErrBidDeclined is returned by pricing strategy which has deliberately refused to price the order
+	ErrBidDeclined = errors.New("pricing strategy declined to bid")
 )
 
 func ceilBigRatToBigInt(v *big.Rat) *big.Int {
@@ -135,6 +257,7 @@ func (fp scalePricing) CalculatePrice(_ context.Context, req Request) (sdk.DecCo
 	// a possible configuration
 	cpuTotal := decimal.NewFromInt(0)
 	memoryTotal := decimal.NewFromInt(0)
+	gpuTotal := decimal.NewFromInt(0)
 	storageTotal := make(Storage)
 	denom := req.GSpec.Price().Denom
 
@@ -142,10 +265,17 @@ func (fp scalePricing) CalculatePrice(_ context.Context, req Request) (sdk.DecCo
 		storageTotal[k] = decimal.NewFromInt(0)
 	}
 
+	snapshotTotal := decimal.NewFromInt(0)
 	endpointTotal := decimal.NewFromInt(0)
 	ipTotal := decimal.NewFromInt(0).Add(fp.ipScale)
 	ipTotal = ipTotal.Mul(decimal.NewFromInt(int64(util.GetEndpointQuantityOfResourceGroup(req.GSpec, atypes.Endpoint_LEASED_IP)))) // nolint: gosec
 
+	// price GPU models inventory has picked for the order rather than ones tenant asked for
+	allocatedGPU := make(map[uint32]*atypes.GPU, len(req.AllocatedResources))
+	for _, group := range req.AllocatedResources {
+		allocatedGPU[group.ID] = group.GPU
+	}
+
 	// iterate over everything & sum it up
 	for _, group := range req.GSpec.Resources {
 		groupCount := decimal.NewFromInt(int64(group.Count)) // // nolint: gosec
@@ -158,6 +288,17 @@ func (fp scalePricing) CalculatePrice(_ context.Context, req Request) (sdk.DecCo
 		memoryQuantity = memoryQuantity.Mul(groupCount)
 		memoryTotal = memoryTotal.Add(memoryQuantity)
 
+		gpu := group.Resources.GPU
+		if val, exists := allocatedGPU[group.ID]; exists {
+			gpu = val
+		}
+
+		gpuCost, err := fp.gpuCost(gpu)
+		if err != nil {
+			return sdk.DecCoin{}, err
+		}
+		gpuTotal = gpuTotal.Add(gpuCost.Mul(groupCount))
+
 		for _, storage := range group.Resources.Storage {
 			storageQuantity := decimal.NewFromBigInt(storage.Quantity.Val.BigInt(), 0)
 			storageQuantity = storageQuantity.Mul(groupCount)
@@ -169,6 +310,8 @@ func (fp scalePricing) CalculatePrice(_ context.Context, req Request) (sdk.DecCo
 				if class, set := attr.AsString(); set {
 					storageClass = class
 				}
+
+				snapshotTotal = snapshotTotal.Add(storageQuantity)
 			}
 
 			total, exists := storageTotal[storageClass]
@@ -202,13 +345,18 @@ func (fp scalePricing) CalculatePrice(_ context.Context, req Request) (sdk.DecCo
 		storageTotal[class] = total
 	}
 
+	snapshotTotal = snapshotTotal.Div(mebibytes)
+	snapshotTotal = snapshotTotal.Mul(fp.snapshotScale)
+
 	endpointTotal = endpointTotal.Mul(fp.endpointScale)
 
 	// Each quantity must be non-negative
 	// and fit into an Int64
 	if cpuTotal.IsNegative() ||
 		memoryTotal.IsNegative() ||
+		gpuTotal.IsNegative() ||
 		storageTotal.IsAnyNegative() ||
+		snapshotTotal.IsNegative() ||
 		endpointTotal.IsNegative() ||
 		ipTotal.IsNegative() {
 		return sdk.DecCoin{}, ErrBidQuantityInvalid
@@ -216,9 +364,11 @@ func (fp scalePricing) CalculatePrice(_ context.Context, req Request) (sdk.DecCo
 
 	totalCost := cpuTotal
 	totalCost = totalCost.Add(memoryTotal)
+	totalCost = totalCost.Add(gpuTotal)
 	for _, total := range storageTotal {
 		totalCost = totalCost.Add(total)
 	}
+	totalCost = totalCost.Add(snapshotTotal)
 	totalCost = totalCost.Add(endpointTotal)
 	totalCost = totalCost.Add(ipTotal)
 
@@ -243,6 +393,41 @@ func (fp scalePricing) CalculatePrice(_ context.Context, req Request) (sdk.DecCo
 	return sdk.NewDecCoinFromDec(denom, costDec), nil
 }
 
+// gpuCost returns price of GPU units of single resource unit.
+// GPUs are not priced if provider has not configured any GPU scale
+func (fp scalePricing) gpuCost(res *atypes.GPU) (decimal.Decimal, error) {
+	if res == nil || len(fp.gpuScale) == 0 || res.Units.Val.IsZero() {
+		return decimal.Zero, nil
+	}
+
+	units := decimal.NewFromBigInt(res.Units.Val.BigInt(), 0)
+	gpu := parseGPU(res)
+
+	if len(gpu.Attributes.Vendor) == 0 {
+		price, exists := fp.gpuScale[gpuScaleWildcard]
+		if !exists {
+			return decimal.Zero, errors.Wrapf(errNoPriceScaleForGPU, "%s", gpuScaleWildcard)
+		}
+
+		return units.Mul(price), nil
+	}
+
+	// group spec may allow several vendors, bid with the most expensive of them
+	var cost decimal.Decimal
+	for vendor, attrs := range gpu.Attributes.Vendor {
+		price, exists := fp.gpuScale.PriceOf(vendor, attrs)
+		if !exists {
+			return decimal.Zero, errors.Wrapf(errNoPriceScaleForGPU, "%s/%s", vendor, attrs.Model)
+		}
+
+		if price.GreaterThan(cost) {
+			cost = price
+		}
+	}
+
+	return units.Mul(cost), nil
+}
+
 type randomRangePricing int
 
 func MakeRandomRangePricing() (BidPricingStrategy, error) {
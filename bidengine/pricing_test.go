@@ -32,28 +32,28 @@ import (
 )
 
 func Test_ScalePricingRejectsAllZero(t *testing.T) {
-	pricing, err := MakeScalePricing(decimal.Zero, decimal.Zero, make(Storage), decimal.Zero, decimal.Zero)
+	pricing, err := MakeScalePricing(decimal.Zero, decimal.Zero, make(GPU), make(Storage), decimal.Zero, decimal.Zero, decimal.Zero)
 	require.NotNil(t, err)
 	require.Nil(t, pricing)
 }
 
 func Test_ScalePricingAcceptsOneForASingleScale(t *testing.T) {
-	pricing, err := MakeScalePricing(decimal.NewFromInt(1), decimal.Zero, make(Storage), decimal.Zero, decimal.Zero)
+	pricing, err := MakeScalePricing(decimal.NewFromInt(1), decimal.Zero, make(GPU), make(Storage), decimal.Zero, decimal.Zero, decimal.Zero)
 	require.NoError(t, err)
 	require.NotNil(t, pricing)
 
-	pricing, err = MakeScalePricing(decimal.Zero, decimal.NewFromInt(1), make(Storage), decimal.Zero, decimal.Zero)
+	pricing, err = MakeScalePricing(decimal.Zero, decimal.NewFromInt(1), make(GPU), make(Storage), decimal.Zero, decimal.Zero, decimal.Zero)
 	require.NoError(t, err)
 	require.NotNil(t, pricing)
 
 	storageScale := Storage{
 		"": decimal.NewFromInt(1),
 	}
-	pricing, err = MakeScalePricing(decimal.Zero, decimal.Zero, storageScale, decimal.Zero, decimal.Zero)
+	pricing, err = MakeScalePricing(decimal.Zero, decimal.Zero, make(GPU), storageScale, decimal.Zero, decimal.Zero, decimal.Zero)
 	require.NoError(t, err)
 	require.NotNil(t, pricing)
 
-	pricing, err = MakeScalePricing(decimal.Zero, decimal.Zero, make(Storage), decimal.NewFromInt(1), decimal.Zero)
+	pricing, err = MakeScalePricing(decimal.Zero, decimal.Zero, make(GPU), make(Storage), decimal.NewFromInt(1), decimal.Zero, decimal.Zero)
 	require.NoError(t, err)
 	require.NotNil(t, pricing)
 }
@@ -128,7 +128,7 @@ func Test_ScalePricingFailsOnOverflow(t *testing.T) {
 		sdl.StorageEphemeral: decimal.NewFromInt(1),
 	}
 
-	pricing, err := MakeScalePricing(decimal.New(math.MaxInt64, 2), decimal.Zero, storageScale, decimal.Zero, decimal.Zero)
+	pricing, err := MakeScalePricing(decimal.New(math.MaxInt64, 2), decimal.Zero, make(GPU), storageScale, decimal.Zero, decimal.Zero, decimal.Zero)
 	require.NoError(t, err)
 	require.NotNil(t, pricing)
 
@@ -146,7 +146,7 @@ func Test_ScalePricingFailsOnOverflow(t *testing.T) {
 func Test_ScalePricingOnCpu(t *testing.T) {
 	cpuScale := decimal.NewFromInt(22)
 
-	pricing, err := MakeScalePricing(cpuScale, decimal.Zero, make(Storage), decimal.Zero, decimal.Zero)
+	pricing, err := MakeScalePricing(cpuScale, decimal.Zero, make(GPU), make(Storage), decimal.Zero, decimal.Zero, decimal.Zero)
 	require.NoError(t, err)
 	require.NotNil(t, pricing)
 
@@ -170,7 +170,7 @@ func Test_ScalePricingOnCpu(t *testing.T) {
 func Test_ScalePricingOnMemory(t *testing.T) {
 	memoryScale := uint64(23)
 	memoryPrice := decimal.NewFromInt(int64(memoryScale)).Mul(decimal.NewFromInt(unit.Mi))
-	pricing, err := MakeScalePricing(decimal.Zero, memoryPrice, make(Storage), decimal.Zero, decimal.Zero)
+	pricing, err := MakeScalePricing(decimal.Zero, memoryPrice, make(GPU), make(Storage), decimal.Zero, decimal.Zero, decimal.Zero)
 	require.NoError(t, err)
 	require.NotNil(t, pricing)
 
@@ -193,7 +193,7 @@ func Test_ScalePricingOnMemory(t *testing.T) {
 func Test_ScalePricingOnMemoryLessThanOne(t *testing.T) {
 	memoryScale := uint64(1) // 1 uakt per megabyte
 	memoryPrice := decimal.NewFromInt(int64(memoryScale))
-	pricing, err := MakeScalePricing(decimal.Zero, memoryPrice, make(Storage), decimal.Zero, decimal.Zero)
+	pricing, err := MakeScalePricing(decimal.Zero, memoryPrice, make(GPU), make(Storage), decimal.Zero, decimal.Zero, decimal.Zero)
 	require.NoError(t, err)
 	require.NotNil(t, pricing)
 
@@ -244,7 +244,7 @@ func Test_ScalePricingOnStorage(t *testing.T) {
 		sdl.StorageEphemeral: decimal.NewFromInt(int64(storageScale)).Mul(decimal.NewFromInt(unit.Mi)),
 	}
 
-	pricing, err := MakeScalePricing(decimal.Zero, decimal.Zero, storagePrice, decimal.Zero, decimal.Zero)
+	pricing, err := MakeScalePricing(decimal.Zero, decimal.Zero, make(GPU), storagePrice, decimal.Zero, decimal.Zero, decimal.Zero)
 	require.NoError(t, err)
 	require.NotNil(t, pricing)
 
@@ -261,13 +261,51 @@ func Test_ScalePricingOnStorage(t *testing.T) {
 	decNearly(t, price.Amount, int64(storageScale*storageQuantity)) // nolint: gosec
 }
 
+func Test_ScalePricingOnStorageSnapshots(t *testing.T) {
+	storageScale := uint64(24)
+	persistentScale := uint64(10)
+	snapshotScale := uint64(3)
+	storagePrice := Storage{
+		sdl.StorageEphemeral: decimal.NewFromInt(int64(storageScale)).Mul(decimal.NewFromInt(unit.Mi)),
+		"beta2":              decimal.NewFromInt(int64(persistentScale)).Mul(decimal.NewFromInt(unit.Mi)),
+	}
+	snapshotPrice := decimal.NewFromInt(int64(snapshotScale)).Mul(decimal.NewFromInt(unit.Mi))
+
+	pricing, err := MakeScalePricing(decimal.Zero, decimal.Zero, make(GPU), storagePrice, decimal.Zero, decimal.Zero, snapshotPrice)
+	require.NoError(t, err)
+	require.NotNil(t, pricing)
+
+	gspec := defaultGroupSpec()
+	storageQuantity := uint64(98765)
+	persistentQuantity := uint64(4321)
+	gspec.Resources[0].Resources.Storage[0].Quantity = atypes.NewResourceValue(storageQuantity)
+	gspec.Resources[0].Resources.Storage = append(gspec.Resources[0].Resources.Storage, atypes.Storage{
+		Name:     "data",
+		Quantity: atypes.NewResourceValue(persistentQuantity),
+		Attributes: atypes.Attributes{
+			{Key: sdl.StorageAttributePersistent, Value: "true"},
+			{Key: sdl.StorageAttributeClass, Value: "beta2"},
+		},
+	})
+
+	req := Request{
+		Owner: testutil.AccAddress(t).String(),
+		GSpec: gspec,
+	}
+	price, err := pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+
+	// persistent storage is priced with both its class and snapshot scales, ephemeral storage is not
+	decNearly(t, price.Amount, int64(storageScale*storageQuantity+(persistentScale+snapshotScale)*persistentQuantity)) // nolint: gosec
+}
+
 func Test_ScalePricingByCountOfResources(t *testing.T) {
 	storageScale := uint64(3)
 	storagePrice := Storage{
 		sdl.StorageEphemeral: decimal.NewFromInt(int64(storageScale)).Mul(decimal.NewFromInt(unit.Mi)),
 	}
 
-	pricing, err := MakeScalePricing(decimal.Zero, decimal.Zero, storagePrice, decimal.Zero, decimal.Zero)
+	pricing, err := MakeScalePricing(decimal.Zero, decimal.Zero, make(GPU), storagePrice, decimal.Zero, decimal.Zero, decimal.Zero)
 	require.NoError(t, err)
 	require.NotNil(t, pricing)
 
@@ -294,9 +332,9 @@ func Test_ScalePricingForIPs(t *testing.T) {
 	ipPriceInt := int64(testutil.RandRangeInt(100, 1000))
 	ipPrice := decimal.NewFromInt(ipPriceInt)
 
-	pricing, err := MakeScalePricing(decimal.Zero, decimal.Zero, Storage{
+	pricing, err := MakeScalePricing(decimal.Zero, decimal.Zero, make(GPU), Storage{
 		sdl.StorageEphemeral: decimal.Zero,
-	}, decimal.Zero, ipPrice)
+	}, decimal.Zero, ipPrice, decimal.Zero)
 	require.NoError(t, err)
 	require.NotNil(t, pricing)
 
@@ -334,6 +372,146 @@ func Test_ScalePricingForIPs(t *testing.T) {
 	decNearly(t, price.Amount, 2*ipPriceInt)
 }
 
+func gpuGroupSpec(units uint64, count uint32, attrs ...string) *dtypes.GroupSpec {
+	gspec := defaultGroupSpecCPUMem()
+	gspec.Resources[0].Count = count
+	gspec.Resources[0].Resources.GPU = &atypes.GPU{
+		Units: atypes.NewResourceValue(units),
+	}
+
+	for _, attr := range attrs {
+		gspec.Resources[0].Resources.GPU.Attributes = append(gspec.Resources[0].Resources.GPU.Attributes, atypes.Attribute{
+			Key:   attr,
+			Value: "true",
+		})
+	}
+
+	return gspec
+}
+
+func Test_ScalePricingOnGPU(t *testing.T) {
+	gpuScale := GPU{
+		"nvidia/a100":                        decimal.NewFromInt(1000),
+		"nvidia/a100/ram/80gi":               decimal.NewFromInt(1500),
+		"nvidia/a100/ram/80gi/interface/sxm": decimal.NewFromInt(1700),
+		"nvidia/t4/interface/pcie":           decimal.NewFromInt(200),
+		"nvidia/*":                           decimal.NewFromInt(300),
+	}
+
+	pricing, err := MakeScalePricing(decimal.Zero, decimal.Zero, gpuScale, make(Storage), decimal.Zero, decimal.Zero, decimal.Zero)
+	require.NoError(t, err)
+
+	cases := []struct {
+		desc     string
+		gspec    *dtypes.GroupSpec
+		expected int64
+	}{
+		{
+			desc:     "model",
+			gspec:    gpuGroupSpec(2, 1, "vendor/nvidia/model/a100"),
+			expected: 2000,
+		},
+		{
+			desc:     "model with ram",
+			gspec:    gpuGroupSpec(1, 3, "vendor/nvidia/model/a100/ram/80Gi"),
+			expected: 4500,
+		},
+		{
+			desc:     "model with ram and interface",
+			gspec:    gpuGroupSpec(1, 1, "vendor/nvidia/model/a100/ram/80Gi/interface/sxm"),
+			expected: 1700,
+		},
+		{
+			desc:     "model with unpriced ram falls back to model",
+			gspec:    gpuGroupSpec(1, 1, "vendor/nvidia/model/a100/ram/40Gi"),
+			expected: 1000,
+		},
+		{
+			desc:     "model with interface",
+			gspec:    gpuGroupSpec(1, 1, "vendor/nvidia/model/t4/interface/pcie"),
+			expected: 200,
+		},
+		{
+			desc:     "unpriced model falls back to vendor wildcard",
+			gspec:    gpuGroupSpec(1, 1, "vendor/nvidia/model/h100"),
+			expected: 300,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			price, err := pricing.CalculatePrice(context.Background(), Request{
+				Owner: testutil.AccAddress(t).String(),
+				GSpec: tc.gspec,
+			})
+			require.NoError(t, err)
+			require.Equal(t, testutil.AkashDecCoin(t, tc.expected), price)
+		})
+	}
+
+	_, err = pricing.CalculatePrice(context.Background(), Request{
+		Owner: testutil.AccAddress(t).String(),
+		GSpec: gpuGroupSpec(1, 1, "vendor/amd/model/mi100"),
+	})
+	require.ErrorIs(t, err, errNoPriceScaleForGPU)
+
+	_, err = pricing.CalculatePrice(context.Background(), Request{
+		Owner: testutil.AccAddress(t).String(),
+		GSpec: gpuGroupSpec(1, 1),
+	})
+	require.ErrorIs(t, err, errNoPriceScaleForGPU)
+
+	// without GPUs nothing is priced by this scale, so no bid is made
+	_, err = pricing.CalculatePrice(context.Background(), Request{
+		Owner: testutil.AccAddress(t).String(),
+		GSpec: gpuGroupSpec(0, 1),
+	})
+	require.ErrorIs(t, err, ErrBidZero)
+}
+
+func Test_ScalePricingOnGPUPrefersAllocatedResources(t *testing.T) {
+	pricing, err := MakeScalePricing(decimal.Zero, decimal.Zero, GPU{
+		"nvidia/a100": decimal.NewFromInt(1000),
+		"*":           decimal.NewFromInt(10),
+	}, make(Storage), decimal.Zero, decimal.Zero, decimal.Zero)
+	require.NoError(t, err)
+
+	gspec := gpuGroupSpec(2, 1, "vendor/nvidia/model/*")
+	allocated := gpuGroupSpec(2, 1, "vendor/nvidia/model/a100")
+
+	req := Request{
+		Owner: testutil.AccAddress(t).String(),
+		GSpec: gspec,
+	}
+
+	price, err := pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, testutil.AkashDecCoin(t, 20), price)
+
+	req.AllocatedResources = allocated.Resources
+	price, err = pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, testutil.AkashDecCoin(t, 2000), price)
+}
+
+func Test_ScalePricingGPUScaleKeys(t *testing.T) {
+	for _, key := range []string{"*", "nvidia/*", "NVIDIA/A100", "nvidia/a100/ram/80gi", "nvidia/a100/interface/sxm", "nvidia/a100/ram/80gi/interface/pcie"} {
+		_, err := ParseGPUScaleKey(key)
+		require.NoError(t, err, key)
+	}
+
+	for _, key := range []string{"", "nvidia", "nvidia/a100/ram", "nvidia/a100/vram/80gi", "nvidia/a100/interface/nvlink", "nvidia/*/ram/80gi"} {
+		_, err := ParseGPUScaleKey(key)
+		require.ErrorIs(t, err, errInvalidGPUScaleKey, key)
+	}
+
+	_, err := MakeScalePricing(decimal.Zero, decimal.Zero, GPU{"nvidia": decimal.NewFromInt(1)}, make(Storage), decimal.Zero, decimal.Zero, decimal.Zero)
+	require.ErrorIs(t, err, errInvalidGPUScaleKey)
+
+	_, err = MakeScalePricing(decimal.Zero, decimal.Zero, GPU{"nvidia/a100": decimal.NewFromInt(-1)}, make(Storage), decimal.Zero, decimal.Zero, decimal.Zero)
+	require.ErrorIs(t, err, errScaleNegative)
+}
+
 func Test_ScriptPricingRejectsEmptyStringForPath(t *testing.T) {
 	pricing, err := MakeShellScriptPricing("", 1, 30000*time.Millisecond)
 	require.NotNil(t, err)
@@ -6,9 +6,13 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"regexp"
+	"time"
 
 	sclient "github.com/akash-network/akash-api/go/node/client/v1beta2"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkquery "github.com/cosmos/cosmos-sdk/types/query"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	tpubsub "github.com/troian/pubsub"
@@ -16,11 +20,15 @@ import (
 
 	"github.com/boz/go-lifecycle"
 
+	dtypes "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
 	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
 	provider "github.com/akash-network/akash-api/go/provider/v1"
 	"github.com/akash-network/node/pubsub"
+	metricsutils "github.com/akash-network/node/util/metrics"
 	mquery "github.com/akash-network/node/x/market/query"
 
+	"github.com/akash-network/provider/bidengine/journal"
+	"github.com/akash-network/provider/bidengine/policy"
 	"github.com/akash-network/provider/cluster"
 	"github.com/akash-network/provider/operator/waiter"
 	"github.com/akash-network/provider/session"
@@ -44,6 +52,15 @@ type StatusClient interface {
 	StatusV1(ctx context.Context) (*provider.BidEngineStatus, error)
 }
 
+var (
+	journalRecoveryCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "provider_bid_journal_recovery",
+		Help: "The total number of orders recovered from bid journal on startup",
+	}, []string{"result"})
+
+	matchOrderNotFound = regexp.MustCompile("order not found")
+)
+
 var (
 	orderManagerGauge = promauto.NewGauge(prometheus.GaugeOpts{
 		Name:        "provider_order_manager",
@@ -55,6 +72,7 @@ var (
 // Service handles bidding on orders.
 type Service interface {
 	StatusClient
+	Validator
 	Close() error
 	Done() <-chan struct{}
 }
@@ -93,8 +111,38 @@ func NewService(pctx context.Context, aqc sclient.QueryClient, session session.S
 		waiter:   waiter,
 	}
 
+	if cfg.Wallet != nil {
+		owner := session.Provider().Owner
+		denom := cfg.Deposit.Denom
+
+		s.wallet, err = newWalletMonitor(session.Log(), *cfg.Wallet, cfg.Deposit, func(ctx context.Context) (sdk.Coin, error) {
+			res, err := aqc.Bank().Balance(ctx, &banktypes.QueryBalanceRequest{Address: owner, Denom: denom})
+			if err != nil {
+				return sdk.Coin{}, err
+			}
+
+			if res.Balance == nil {
+				return sdk.NewCoin(denom, sdk.ZeroInt()), nil
+			}
+
+			return *res.Balance, nil
+		})
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+
+		group.Go(func() error {
+			return s.wallet.run(ctx)
+		})
+	}
+
 	go s.lc.WatchContext(ctx)
 	go s.run(pctx)
+	group.Go(func() error {
+		return s.recoverJournal(ctx)
+	})
+
 	group.Go(func() error {
 		err := s.ordersFetcher(ctx, aqc)
 
@@ -123,6 +171,7 @@ type service struct {
 	cancel context.CancelFunc
 	lc     lifecycle.Lifecycle
 	pass   *providerAttrSignatureService
+	wallet *walletMonitor
 
 	waiter waiter.OperatorWaiter
 }
@@ -166,6 +215,80 @@ func (s *service) StatusV1(ctx context.Context) (*provider.BidEngineStatus, erro
 	return &provider.BidEngineStatus{Orders: res.Orders}, nil
 }
 
+// Validate runs group spec through the same checks as bidding on the order does.
+// unlike bidding it does not stop at the first decline, so all reasons are reported at once
+func (s *service) Validate(ctx context.Context, owner string, gspec dtypes.GroupSpec) (ValidateResult, error) {
+	checker := groupChecker{
+		provider: s.session.Provider(),
+		cfg:      s.cfg,
+		pass:     s.pass,
+		wallet:   s.wallet,
+	}
+
+	declines, err := checker.check(&gspec, true)
+	if err != nil {
+		return ValidateResult{}, err
+	}
+
+	res := ValidateResult{
+		Declines: declines,
+	}
+
+	for _, decline := range declines {
+		// invalid group can be neither placed onto inventory nor priced
+		if decline.Code == DeclineInvalidGroup {
+			return res, nil
+		}
+	}
+
+	if s.cfg.Policy != nil {
+		// escrow is not known before deployment is created, so duration rules are skipped here
+		res.PolicyDecline = s.cfg.Policy.Policy().Evaluate(policy.Order{
+			Owner:     owner,
+			GroupSpec: &gspec,
+		})
+
+		if res.PolicyDecline != nil {
+			res.Declines = append(res.Declines, policyDeclineReason(res.PolicyDecline))
+		}
+	}
+
+	reason, err := capacityDeclineReason(s.cluster.CheckCapacity(gspec))
+	if err != nil {
+		return ValidateResult{}, err
+	}
+
+	if reason != nil {
+		res.Declines = append(res.Declines, *reason)
+	}
+
+	price, err := s.cfg.PricingStrategy.CalculatePrice(ctx, Request{
+		Owner:          owner,
+		GSpec:          &gspec,
+		PricePrecision: DefaultPricePrecision,
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return ValidateResult{}, ctx.Err()
+		}
+
+		res.Declines = append(res.Declines, DeclineReason{
+			Code:    DeclinePricing,
+			Message: err.Error(),
+		})
+
+		return res, nil
+	}
+
+	res.Price = price
+
+	if reason := priceDeclineReason(price, &gspec); reason != nil {
+		res.Declines = append(res.Declines, *reason)
+	}
+
+	return res, nil
+}
+
 func (s *service) updateOrderManagerGauge() {
 	orderManagerGauge.Set(float64(len(s.orders)))
 }
@@ -251,6 +374,119 @@ loop:
 	return ctx.Err()
 }
 
+// recoverJournal picks up orders bid engine has been working on before restart.
+// orders still open are handed over to order managers. the rest are orphans,
+// their bids are closed and reservations released, then entries are closed so it happens exactly once
+func (s *service) recoverJournal(ctx context.Context) error {
+	if s.cfg.Journal == nil {
+		return nil
+	}
+
+	if s.cfg.JournalRetention > 0 {
+		pruned, err := s.cfg.Journal.Prune(time.Now().Add(-s.cfg.JournalRetention))
+		if err != nil {
+			s.session.Log().Error("pruning bid journal", "err", err)
+		} else if pruned > 0 {
+			s.session.Log().Info("pruned bid journal", "entries", pruned)
+		}
+	}
+
+	entries, err := s.cfg.Journal.List(journal.ListOptions{InFlight: true})
+	if err != nil {
+		s.session.Log().Error("reading bid journal", "err", err)
+		return nil
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	// orphans release reservations, inventory must be up before that
+	if err = s.waiter.WaitForAll(ctx); err != nil {
+		return err
+	}
+
+	s.session.Log().Info("recovering orders from bid journal", "qty", len(entries))
+
+	var resume []mtypes.OrderID
+
+	for _, entry := range entries {
+		res, err := s.session.Client().Query().Order(ctx, &mtypes.QueryOrderRequest{ID: entry.OrderID})
+		if err == nil && res.Order.State == mtypes.OrderOpen {
+			journalRecoveryCounter.WithLabelValues("resumed").Inc()
+			resume = append(resume, entry.OrderID)
+			continue
+		}
+
+		if err != nil && !matchOrderNotFound.MatchString(err.Error()) {
+			if errors.Is(err, context.Canceled) {
+				return err
+			}
+
+			// entry stays in flight and is retried on next start
+			journalRecoveryCounter.WithLabelValues("fail").Inc()
+			s.session.Log().Error("querying journaled order", "order", entry.OrderID, "err", err)
+			continue
+		}
+
+		if err = s.closeOrphan(ctx, entry); err != nil {
+			journalRecoveryCounter.WithLabelValues("fail").Inc()
+			s.session.Log().Error("closing orphaned order", "order", entry.OrderID, "err", err)
+		}
+	}
+
+	if len(resume) == 0 {
+		return nil
+	}
+
+	select {
+	case s.ordersch <- resume:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+func (s *service) closeOrphan(ctx context.Context, entry journal.Entry) error {
+	bidID := mtypes.MakeBidID(entry.OrderID, s.session.Provider().Address())
+
+	res, err := s.session.Client().Query().Bid(ctx, &mtypes.QueryBidRequest{ID: bidID})
+	if err != nil && !matchBidNotFound.MatchString(err.Error()) {
+		return err
+	}
+
+	if err == nil {
+		switch res.Bid.State {
+		case mtypes.BidActive:
+			// lease has been created while provider was down, reservation belongs to the deployment now
+			journalRecoveryCounter.WithLabelValues("lease-won").Inc()
+			return s.cfg.Journal.Closed(entry.OrderID, "lease-won")
+		case mtypes.BidOpen:
+			msg := &mtypes.MsgCloseBid{
+				BidID: bidID,
+			}
+
+			if _, err = s.session.Client().Tx().Broadcast(ctx, []sdk.Msg{msg}, sclient.WithResultCodeAsError()); err != nil {
+				bidCounter.WithLabelValues("close", metricsutils.FailLabel).Inc()
+				return err
+			}
+
+			bidCounter.WithLabelValues("close", metricsutils.SuccessLabel).Inc()
+		}
+	}
+
+	// reservations are not persisted, there is nothing to release unless order has been picked up again
+	if err = s.cluster.Unreserve(entry.OrderID); err == nil {
+		reservationCounter.WithLabelValues("close", metricsutils.SuccessLabel)
+	}
+
+	journalRecoveryCounter.WithLabelValues("orphaned").Inc()
+	s.session.Log().Info("closed orphaned order", "order", entry.OrderID)
+
+	return s.cfg.Journal.Closed(entry.OrderID, "orphaned")
+}
+
 func (s *service) run(ctx context.Context) {
 	defer s.lc.ShutdownCompleted()
 	defer s.sub.Close()
@@ -288,6 +524,11 @@ loop:
 		case orders := <-s.ordersch:
 			for _, orderID := range orders {
 				key := mquery.OrderPath(orderID)
+				// same order may come from both bid journal and orders fetcher
+				if _, exists := s.orders[key]; exists {
+					continue
+				}
+
 				s.session.Log().Debug("creating catchup order", "order", key)
 				order, err := newOrder(s, orderID, s.cfg, s.pass, true)
 				if err != nil {
@@ -324,6 +565,7 @@ loop:
 		case ch := <-s.statusch:
 			ch <- &Status{
 				Orders: uint32(len(s.orders)), // nolint: gosec
+				Wallet: s.wallet.status(),
 			}
 		case order := <-s.drainch:
 			// child done
@@ -0,0 +1,305 @@
+package bidengine
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+// Shapes of surge pricing curve
+const (
+	SurgeCurveLinear      = "linear"
+	SurgeCurveQuadratic   = "quadratic"
+	SurgeCurveExponential = "exponential"
+
+	// surgeExponentialRate is steepness of exponential curve
+	surgeExponentialRate = 3.0
+	// surgeMultiplierPrecision is number of decimal places multiplier is applied with
+	surgeMultiplierPrecision = 6
+
+	surgeResourceCPU    = "cpu"
+	surgeResourceGPU    = "gpu"
+	surgeResourceMemory = "memory"
+)
+
+var (
+	errSurgeCurve      = errors.New("surge pricing: unknown curve")
+	errSurgeTarget     = errors.New("surge pricing: target utilization must be greater than 0 and less than 1")
+	errSurgeFloor      = errors.New("surge pricing: floor multiplier must be greater than 0 and not greater than 1")
+	errSurgeCeiling    = errors.New("surge pricing: ceiling multiplier must not be less than 1")
+	errSurgeWindow     = errors.New("surge pricing: smoothing window cannot be negative")
+	errSurgeNoStrategy = errors.New("surge pricing: base pricing strategy required")
+
+	surgeMultiplierGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "provider_bid_surge_multiplier",
+		Help: "Multiplier surge pricing applied to the last calculated bid price",
+	})
+
+	surgeUtilizationGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "provider_bid_surge_utilization",
+		Help: "Smoothed cluster utilization surge pricing is based on",
+	}, []string{"resource"})
+
+	surgeResources = []string{surgeResourceCPU, surgeResourceGPU, surgeResourceMemory}
+)
+
+// InventoryStatus provides cluster inventory surge pricing measures utilization of
+type InventoryStatus interface {
+	Status(context.Context) (*ctypes.Status, error)
+}
+
+// SurgePricingConfig configures how price of the base strategy follows cluster utilization.
+// at Target utilization base price is bid. as utilization falls to 0 multiplier goes down to Floor,
+// as cluster fills up to 100% multiplier goes up to Ceiling. Curve shapes both sides of the target.
+type SurgePricingConfig struct {
+	Curve   string
+	Target  float64
+	Floor   float64
+	Ceiling float64
+	// Window is time constant of exponential moving average utilization is smoothed with. zero disables smoothing
+	Window time.Duration
+}
+
+func (c SurgePricingConfig) validate() error {
+	if _, valid := surgeCurves[c.Curve]; !valid {
+		return fmt.Errorf("%w: %q", errSurgeCurve, c.Curve)
+	}
+
+	if !(c.Target > 0 && c.Target < 1) {
+		return errSurgeTarget
+	}
+
+	if !(c.Floor > 0 && c.Floor <= 1) {
+		return errSurgeFloor
+	}
+
+	if c.Ceiling < 1 {
+		return errSurgeCeiling
+	}
+
+	if c.Window < 0 {
+		return errSurgeWindow
+	}
+
+	return nil
+}
+
+// multiplier returns price multiplier for given utilization in range [0, 1]
+func (c SurgePricingConfig) multiplier(utilization float64) float64 {
+	curve := surgeCurves[c.Curve]
+
+	utilization = math.Min(math.Max(utilization, 0), 1)
+
+	if utilization < c.Target {
+		return 1 - (1-c.Floor)*curve((c.Target-utilization)/c.Target)
+	}
+
+	return 1 + (c.Ceiling-1)*curve((utilization-c.Target)/(1-c.Target))
+}
+
+// surgeCurves map distance from target utilization in range [0, 1] to share of max price change
+var surgeCurves = map[string]func(float64) float64{
+	SurgeCurveLinear: func(x float64) float64 {
+		return x
+	},
+	SurgeCurveQuadratic: func(x float64) float64 {
+		return x * x
+	},
+	SurgeCurveExponential: func(x float64) float64 {
+		return math.Expm1(surgeExponentialRate*x) / math.Expm1(surgeExponentialRate)
+	},
+}
+
+type surgePricing struct {
+	base      BidPricingStrategy
+	inventory InventoryStatus
+	cfg       SurgePricingConfig
+
+	lock     sync.Mutex
+	smoothed map[string]float64
+	sampled  time.Time
+
+	now func() time.Time
+}
+
+// MakeSurgePricing wraps base pricing strategy, adjusting its price by utilization of the cluster.
+// utilization of the resource is its allocated and reserved share of allocatable amount,
+// bid follows the busiest of cpu, memory and gpu (if requested) resources of the group.
+// price is capped by max price of the group unless base price exceeds it already
+func MakeSurgePricing(base BidPricingStrategy, inventory InventoryStatus, cfg SurgePricingConfig) (BidPricingStrategy, error) {
+	if base == nil {
+		return nil, errSurgeNoStrategy
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	result := &surgePricing{
+		base:      base,
+		inventory: inventory,
+		cfg:       cfg,
+		smoothed:  make(map[string]float64),
+		now:       time.Now,
+	}
+
+	return result, nil
+}
+
+func (sp *surgePricing) CalculatePrice(ctx context.Context, req Request) (sdk.DecCoin, error) {
+	price, err := sp.base.CalculatePrice(ctx, req)
+	if err != nil {
+		return sdk.DecCoin{}, err
+	}
+
+	// keep using last known utilization if inventory is not available at the moment
+	if status, err := sp.inventory.Status(ctx); err == nil {
+		if utilization, err := clusterUtilization(status); err == nil {
+			sp.sample(utilization)
+		}
+	}
+
+	utilization, measured := sp.groupUtilization(req)
+	if !measured {
+		surgeMultiplierGauge.Set(1)
+		return price, nil
+	}
+
+	multiplier := sp.cfg.multiplier(utilization)
+	surgeMultiplierGauge.Set(multiplier)
+
+	mult, err := sdk.NewDecFromStr(strconv.FormatFloat(multiplier, 'f', surgeMultiplierPrecision, 64))
+	if err != nil {
+		return sdk.DecCoin{}, err
+	}
+
+	result := sdk.NewDecCoinFromDec(price.Denom, price.Amount.Mul(mult))
+	if result.IsZero() {
+		return sdk.DecCoin{}, ErrBidZero
+	}
+
+	maxPrice := req.GSpec.Price()
+	if maxPrice.Denom == price.Denom && maxPrice.IsLT(result) && !maxPrice.IsLT(price) {
+		result = maxPrice
+	}
+
+	return result, nil
+}
+
+func (sp *surgePricing) AcceptsDenom(denom string) bool {
+	return acceptsDenom(sp.base, denom)
+}
+
+// sample folds current utilization into moving average
+func (sp *surgePricing) sample(utilization map[string]float64) {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
+
+	now := sp.now()
+
+	alpha := 1.0
+	if !sp.sampled.IsZero() && sp.cfg.Window > 0 {
+		alpha = -math.Expm1(-float64(now.Sub(sp.sampled)) / float64(sp.cfg.Window))
+	}
+
+	sp.sampled = now
+
+	for _, resource := range surgeResources {
+		current, valid := utilization[resource]
+		if !valid {
+			delete(sp.smoothed, resource)
+			surgeUtilizationGauge.DeleteLabelValues(resource)
+			continue
+		}
+
+		smoothed, valid := sp.smoothed[resource]
+		if !valid {
+			smoothed = current
+		}
+
+		smoothed += alpha * (current - smoothed)
+
+		sp.smoothed[resource] = smoothed
+		surgeUtilizationGauge.WithLabelValues(resource).Set(smoothed)
+	}
+}
+
+// groupUtilization returns utilization of the busiest resource requested by the group
+func (sp *surgePricing) groupUtilization(req Request) (float64, bool) {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
+
+	requested := map[string]bool{
+		surgeResourceCPU:    true,
+		surgeResourceMemory: true,
+	}
+
+	for _, unit := range req.GSpec.GetResourceUnits() {
+		if unit.GPU != nil && unit.GPU.Units.Value() > 0 {
+			requested[surgeResourceGPU] = true
+		}
+	}
+
+	result := 0.0
+	measured := false
+
+	for resource := range requested {
+		if utilization, valid := sp.smoothed[resource]; valid {
+			result = math.Max(result, utilization)
+			measured = true
+		}
+	}
+
+	return result, measured
+}
+
+// clusterUtilization calculates share of allocatable resources used by workloads or reserved by pending bids.
+// resources cluster does not have are omitted
+func clusterUtilization(status *ctypes.Status) (map[string]float64, error) {
+	if status.Inventory.Error != nil {
+		return nil, status.Inventory.Error
+	}
+
+	allocatable := make(map[string]float64)
+	used := make(map[string]float64)
+
+	for _, node := range status.Inventory.Available.Nodes {
+		allocatable[surgeResourceCPU] += float64(node.Allocatable.CPU)
+		allocatable[surgeResourceGPU] += float64(node.Allocatable.GPU)
+		allocatable[surgeResourceMemory] += float64(node.Allocatable.Memory)
+
+		used[surgeResourceCPU] += float64(node.Allocatable.CPU) - float64(node.Available.CPU)
+		used[surgeResourceGPU] += float64(node.Allocatable.GPU) - float64(node.Available.GPU)
+		used[surgeResourceMemory] += float64(node.Allocatable.Memory) - float64(node.Available.Memory)
+	}
+
+	// pending reservations are not running yet, so nodes still report them available
+	for _, pending := range status.Inventory.Pending {
+		used[surgeResourceCPU] += float64(pending.CPU)
+		used[surgeResourceGPU] += float64(pending.GPU)
+		used[surgeResourceMemory] += float64(pending.Memory)
+	}
+
+	result := make(map[string]float64)
+
+	for _, resource := range surgeResources {
+		if allocatable[resource] <= 0 {
+			continue
+		}
+
+		result[resource] = math.Min(math.Max(used[resource]/allocatable[resource], 0), 1)
+	}
+
+	return result, nil
+}
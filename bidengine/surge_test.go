@@ -0,0 +1,204 @@
+package bidengine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	inventoryV1 "github.com/akash-network/akash-api/go/inventory/v1"
+	atypes "github.com/akash-network/akash-api/go/node/types/v1beta3"
+
+	"github.com/akash-network/node/testutil"
+
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+type fixedInventory struct {
+	status *ctypes.Status
+	err    error
+}
+
+func (fi *fixedInventory) Status(_ context.Context) (*ctypes.Status, error) {
+	return fi.status, fi.err
+}
+
+// inventoryStatus returns status of single node cluster with given share of cpu and gpu in use
+func inventoryStatus(cpuUsed, gpuUsed uint64) *ctypes.Status {
+	status := &ctypes.Status{}
+	status.Inventory.Available.Nodes = []inventoryV1.NodeMetrics{
+		{
+			Name:        "node",
+			Allocatable: inventoryV1.ResourcesMetric{CPU: 100, GPU: 10, Memory: 100},
+			Available:   inventoryV1.ResourcesMetric{CPU: 100 - cpuUsed, GPU: 10 - gpuUsed, Memory: 100},
+		},
+	}
+
+	return status
+}
+
+func defaultSurgeConfig() SurgePricingConfig {
+	return SurgePricingConfig{
+		Curve:   SurgeCurveLinear,
+		Target:  0.5,
+		Floor:   0.5,
+		Ceiling: 2,
+	}
+}
+
+func Test_SurgePricingRejectsInvalidConfig(t *testing.T) {
+	base := fixedPricing(sdk.NewInt64DecCoin(testutil.CoinDenom, 10))
+	inventory := &fixedInventory{status: inventoryStatus(0, 0)}
+
+	_, err := MakeSurgePricing(nil, inventory, defaultSurgeConfig())
+	require.ErrorIs(t, err, errSurgeNoStrategy)
+
+	for _, tc := range []struct {
+		mutate func(*SurgePricingConfig)
+		err    error
+	}{
+		{func(c *SurgePricingConfig) { c.Curve = "sine" }, errSurgeCurve},
+		{func(c *SurgePricingConfig) { c.Target = 0 }, errSurgeTarget},
+		{func(c *SurgePricingConfig) { c.Target = 1 }, errSurgeTarget},
+		{func(c *SurgePricingConfig) { c.Floor = 0 }, errSurgeFloor},
+		{func(c *SurgePricingConfig) { c.Floor = 1.1 }, errSurgeFloor},
+		{func(c *SurgePricingConfig) { c.Ceiling = 0.9 }, errSurgeCeiling},
+		{func(c *SurgePricingConfig) { c.Window = -time.Second }, errSurgeWindow},
+	} {
+		cfg := defaultSurgeConfig()
+		tc.mutate(&cfg)
+
+		_, err = MakeSurgePricing(base, inventory, cfg)
+		require.ErrorIs(t, err, tc.err)
+	}
+}
+
+func Test_SurgeMultiplierCurves(t *testing.T) {
+	cfg := defaultSurgeConfig()
+
+	for _, curve := range []string{SurgeCurveLinear, SurgeCurveQuadratic, SurgeCurveExponential} {
+		cfg.Curve = curve
+
+		require.InDelta(t, 0.5, cfg.multiplier(0), 1e-9, curve)
+		require.InDelta(t, 1, cfg.multiplier(0.5), 1e-9, curve)
+		require.InDelta(t, 2, cfg.multiplier(1), 1e-9, curve)
+		require.InDelta(t, 2, cfg.multiplier(1.5), 1e-9, curve)
+
+		// prices never go down as utilization grows
+		prev := cfg.multiplier(0)
+		for u := 0.05; u <= 1; u += 0.05 {
+			current := cfg.multiplier(u)
+			require.GreaterOrEqual(t, current, prev, curve)
+			prev = current
+		}
+	}
+
+	cfg.Curve = SurgeCurveLinear
+	require.InDelta(t, 1.5, cfg.multiplier(0.75), 1e-9)
+
+	cfg.Curve = SurgeCurveQuadratic
+	require.InDelta(t, 1.25, cfg.multiplier(0.75), 1e-9)
+}
+
+func Test_SurgePricingFollowsUtilization(t *testing.T) {
+	base := fixedPricing(sdk.NewInt64DecCoin(testutil.CoinDenom, 10))
+	inventory := &fixedInventory{status: inventoryStatus(0, 0)}
+
+	pricing, err := MakeSurgePricing(base, inventory, defaultSurgeConfig())
+	require.NoError(t, err)
+
+	req := Request{
+		Owner:          testutil.AccAddress(t).String(),
+		GSpec:          defaultGroupSpec(),
+		PricePrecision: DefaultPricePrecision,
+	}
+
+	// idle cluster gets discount
+	price, err := pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewInt64DecCoin(testutil.CoinDenom, 5), price)
+
+	inventory.status = inventoryStatus(75, 10)
+
+	// gpus are busy but group does not ask for them
+	price, err = pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewInt64DecCoin(testutil.CoinDenom, 15), price)
+
+	// surge is capped by max price of the group
+	req.GSpec = defaultGroupSpec()
+	req.GSpec.Resources[0].Resources.GPU = &atypes.GPU{Units: atypes.NewResourceValue(1)}
+	req.GSpec.Resources[0].Price = sdk.NewInt64DecCoin(testutil.CoinDenom, 18)
+
+	price, err = pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, req.GSpec.Price(), price)
+
+	// last known utilization is used while inventory is not available
+	inventory.err = ErrNotRunning
+
+	price, err = pricing.CalculatePrice(context.Background(), Request{GSpec: defaultGroupSpec()})
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewInt64DecCoin(testutil.CoinDenom, 15), price)
+}
+
+func Test_SurgePricingSmoothsUtilization(t *testing.T) {
+	base := fixedPricing(sdk.NewInt64DecCoin(testutil.CoinDenom, 10))
+	inventory := &fixedInventory{status: inventoryStatus(50, 0)}
+
+	cfg := defaultSurgeConfig()
+	cfg.Window = time.Minute
+
+	pricing, err := MakeSurgePricing(base, inventory, cfg)
+	require.NoError(t, err)
+
+	sp := pricing.(*surgePricing)
+	now := time.Now()
+	sp.now = func() time.Time {
+		return now
+	}
+
+	req := Request{GSpec: defaultGroupSpec()}
+
+	price, err := pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewInt64DecCoin(testutil.CoinDenom, 10), price)
+
+	// sudden spike moves price only partially within the window
+	inventory.status = inventoryStatus(100, 0)
+	now = now.Add(time.Minute)
+
+	price, err = pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+	require.True(t, price.Amount.GT(sdk.NewDec(10)))
+	require.True(t, price.Amount.LT(sdk.NewDec(20)))
+
+	// and catches up once utilization stays for longer than the window
+	now = now.Add(time.Hour)
+
+	price, err = pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewInt64DecCoin(testutil.CoinDenom, 20), price)
+}
+
+func Test_ClusterUtilization(t *testing.T) {
+	status := inventoryStatus(20, 0)
+	status.Inventory.Available.Nodes[0].Allocatable.GPU = 0
+	status.Inventory.Available.Nodes[0].Available.GPU = 0
+	status.Inventory.Pending = []inventoryV1.MetricTotal{
+		{CPU: 30, Memory: 10},
+	}
+
+	utilization, err := clusterUtilization(status)
+	require.NoError(t, err)
+	require.Equal(t, map[string]float64{
+		surgeResourceCPU:    0.5,
+		surgeResourceMemory: 0.1,
+	}, utilization)
+
+	status.Inventory.Error = ErrNotRunning
+	_, err = clusterUtilization(status)
+	require.Error(t, err)
+}
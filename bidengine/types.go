@@ -2,5 +2,6 @@ package bidengine
 
 // Status stores orders
 type Status struct {
-	Orders uint32 `json:"orders"`
+	Orders uint32        `json:"orders"`
+	Wallet *WalletStatus `json:"wallet,omitempty"`
 }
@@ -0,0 +1,213 @@
+package bidengine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/akash-network/provider/tools/coin"
+)
+
+const (
+	defaultWalletCheckInterval = time.Minute
+)
+
+var (
+	errWalletMinimumBalance = errors.New("wallet monitor: invalid minimum balance")
+
+	walletBalanceGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "provider_wallet_balance",
+		Help: "Balance of the provider account as of its last check",
+	}, []string{"denom"})
+
+	biddingPausedGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "provider_bidding_paused",
+		Help: "Set to 1 while bidding is paused because provider account balance is too low",
+	})
+)
+
+// WalletConfig configures monitoring of provider account balance
+type WalletConfig struct {
+	// MinimumBalance is balance below which new bids are not placed.
+	// bidding is paused also when balance can't cover bid deposit
+	MinimumBalance sdk.Coin
+	// CheckInterval is how often balance is queried
+	CheckInterval time.Duration
+}
+
+// WalletStatus is the provider account balance as seen by the bid engine
+type WalletStatus struct {
+	Balance        sdk.Coin  `json:"balance"`
+	MinimumBalance sdk.Coin  `json:"minimum_balance"`
+	BiddingPaused  bool      `json:"bidding_paused"`
+	CheckedAt      time.Time `json:"checked_at"`
+	Error          string    `json:"error,omitempty"`
+}
+
+type walletBalanceFunc func(context.Context) (sdk.Coin, error)
+
+// walletMonitor tracks provider account balance and pauses bidding while balance is below minimum,
+// so bids which would fail on-chain for lack of funds are not broadcast.
+// all methods of nil monitor report bidding is not paused
+type walletMonitor struct {
+	log      log.Logger
+	query    walletBalanceFunc
+	minimum  sdk.Coin
+	interval time.Duration
+
+	lock    sync.RWMutex
+	current WalletStatus
+	checked bool
+
+	refreshch chan struct{}
+	now       func() time.Time
+}
+
+// newWalletMonitor creates monitor pausing bidding below the larger of configured minimum balance and bid deposit
+func newWalletMonitor(log log.Logger, cfg WalletConfig, deposit sdk.Coin, query walletBalanceFunc) (*walletMonitor, error) {
+	minimum := cfg.MinimumBalance
+	if minimum.Denom == "" {
+		minimum = sdk.NewCoin(deposit.Denom, sdk.ZeroInt())
+	}
+
+	if err := minimum.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", errWalletMinimumBalance, err)
+	}
+
+	if minimum.Denom == deposit.Denom && minimum.IsLT(deposit) {
+		minimum = deposit
+	}
+
+	interval := cfg.CheckInterval
+	if interval <= 0 {
+		interval = defaultWalletCheckInterval
+	}
+
+	return &walletMonitor{
+		log:       log.With("cmp", "wallet-monitor"),
+		query:     query,
+		minimum:   minimum,
+		interval:  interval,
+		refreshch: make(chan struct{}, 1),
+		now:       time.Now,
+	}, nil
+}
+
+func (w *walletMonitor) run(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-w.refreshch:
+		}
+	}
+}
+
+// check queries account balance and updates bidding state. failed query keeps previous state
+func (w *walletMonitor) check(ctx context.Context) {
+	balance, err := w.query(ctx)
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.current.MinimumBalance = w.minimum
+	w.current.CheckedAt = w.now().UTC()
+
+	if err != nil {
+		if ctx.Err() == nil {
+			w.log.Error("querying provider account balance", "err", err)
+		}
+
+		w.current.Error = err.Error()
+		return
+	}
+
+	paused := balance.Denom == w.minimum.Denom && balance.IsLT(w.minimum)
+
+	switch {
+	case paused && !w.current.BiddingPaused:
+		w.log.Info("pausing bidding: provider account balance is below minimum", "balance", balance, "minimum", w.minimum)
+	case !paused && w.current.BiddingPaused:
+		w.log.Info("resuming bidding: provider account has been funded", "balance", balance, "minimum", w.minimum)
+	}
+
+	w.current.Balance = balance
+	w.current.BiddingPaused = paused
+	w.current.Error = ""
+	w.checked = true
+
+	walletBalanceGauge.WithLabelValues(balance.Denom).Set(coin.Float64(balance))
+	if paused {
+		biddingPausedGauge.Set(1)
+	} else {
+		biddingPausedGauge.Set(0)
+	}
+}
+
+// paused reports whether bidding is paused. bidding goes on until balance is known
+func (w *walletMonitor) paused() bool {
+	if w == nil {
+		return false
+	}
+
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	return w.current.BiddingPaused
+}
+
+// refresh requests balance check ahead of schedule, e.g. after bid transaction has failed
+func (w *walletMonitor) refresh() {
+	if w == nil {
+		return
+	}
+
+	select {
+	case w.refreshch <- struct{}{}:
+	default:
+	}
+}
+
+func (w *walletMonitor) status() *WalletStatus {
+	if w == nil {
+		return nil
+	}
+
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	if !w.checked && w.current.Error == "" {
+		return nil
+	}
+
+	res := w.current
+
+	return &res
+}
+
+func (w *walletMonitor) declineReason() *DeclineReason {
+	if !w.paused() {
+		return nil
+	}
+
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	return &DeclineReason{
+		Code:    DeclineWalletBalance,
+		Message: fmt.Sprintf("provider account balance %s is below minimum %s, bidding is paused", w.current.Balance, w.minimum),
+	}
+}
@@ -0,0 +1,158 @@
+package bidengine
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	ptypes "github.com/akash-network/akash-api/go/node/provider/v1beta3"
+
+	"github.com/akash-network/node/testutil"
+)
+
+type testWallet struct {
+	lock    sync.Mutex
+	balance sdk.Coin
+	err     error
+}
+
+func (tw *testWallet) query(context.Context) (sdk.Coin, error) {
+	tw.lock.Lock()
+	defer tw.lock.Unlock()
+
+	return tw.balance, tw.err
+}
+
+func newTestWalletMonitor(t *testing.T, minimum int64, wallet *testWallet) *walletMonitor {
+	t.Helper()
+
+	monitor, err := newWalletMonitor(testutil.Logger(t), WalletConfig{
+		MinimumBalance: sdk.NewInt64Coin(testutil.CoinDenom, minimum),
+	}, sdk.NewInt64Coin(testutil.CoinDenom, 500), wallet.query)
+	require.NoError(t, err)
+
+	return monitor
+}
+
+func Test_WalletMonitorPausesBidding(t *testing.T) {
+	wallet := &testWallet{balance: sdk.NewInt64Coin(testutil.CoinDenom, 2000)}
+	monitor := newTestWalletMonitor(t, 1000, wallet)
+
+	ctx := context.Background()
+
+	// balance is not known yet
+	require.False(t, monitor.paused())
+	require.Nil(t, monitor.status())
+
+	monitor.check(ctx)
+	require.False(t, monitor.paused())
+	require.Nil(t, monitor.declineReason())
+
+	status := monitor.status()
+	require.NotNil(t, status)
+	require.Equal(t, sdk.NewInt64Coin(testutil.CoinDenom, 2000), status.Balance)
+	require.Equal(t, sdk.NewInt64Coin(testutil.CoinDenom, 1000), status.MinimumBalance)
+	require.False(t, status.BiddingPaused)
+
+	wallet.balance = sdk.NewInt64Coin(testutil.CoinDenom, 999)
+	monitor.check(ctx)
+	require.True(t, monitor.paused())
+	require.True(t, monitor.status().BiddingPaused)
+
+	reason := monitor.declineReason()
+	require.NotNil(t, reason)
+	require.Equal(t, DeclineWalletBalance, reason.Code)
+
+	// failed query keeps bidding state
+	wallet.err = errors.New("rpc unavailable")
+	monitor.check(ctx)
+	require.True(t, monitor.paused())
+	require.Equal(t, "rpc unavailable", monitor.status().Error)
+
+	wallet.err = nil
+	wallet.balance = sdk.NewInt64Coin(testutil.CoinDenom, 1000)
+	monitor.check(ctx)
+	require.False(t, monitor.paused())
+	require.Empty(t, monitor.status().Error)
+}
+
+func Test_WalletMonitorMinimumCoversDeposit(t *testing.T) {
+	wallet := &testWallet{balance: sdk.NewInt64Coin(testutil.CoinDenom, 400)}
+	monitor := newTestWalletMonitor(t, 0, wallet)
+
+	require.Equal(t, sdk.NewInt64Coin(testutil.CoinDenom, 500), monitor.minimum)
+
+	monitor.check(context.Background())
+	require.True(t, monitor.paused())
+
+	_, err := newWalletMonitor(testutil.Logger(t), WalletConfig{
+		MinimumBalance: sdk.Coin{Denom: "1", Amount: sdk.NewInt(1)},
+	}, sdk.NewInt64Coin(testutil.CoinDenom, 500), wallet.query)
+	require.ErrorIs(t, err, errWalletMinimumBalance)
+}
+
+func Test_WalletMonitorRefresh(t *testing.T) {
+	wallet := &testWallet{balance: sdk.NewInt64Coin(testutil.CoinDenom, 2000)}
+
+	monitor, err := newWalletMonitor(testutil.Logger(t), WalletConfig{
+		MinimumBalance: sdk.NewInt64Coin(testutil.CoinDenom, 1000),
+		CheckInterval:  time.Hour,
+	}, sdk.NewInt64Coin(testutil.CoinDenom, 500), wallet.query)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	donech := make(chan error, 1)
+	go func() {
+		donech <- monitor.run(ctx)
+	}()
+
+	require.Eventually(t, func() bool {
+		return monitor.status() != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	wallet.lock.Lock()
+	wallet.balance = sdk.NewInt64Coin(testutil.CoinDenom, 10)
+	wallet.lock.Unlock()
+
+	monitor.refresh()
+
+	require.Eventually(t, monitor.paused, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-donech)
+}
+
+func Test_NilWalletMonitor(t *testing.T) {
+	var monitor *walletMonitor
+
+	require.False(t, monitor.paused())
+	require.Nil(t, monitor.status())
+	require.Nil(t, monitor.declineReason())
+	monitor.refresh()
+}
+
+func Test_GroupCheckerDeclinesWalletBalance(t *testing.T) {
+	wallet := &testWallet{balance: sdk.NewInt64Coin(testutil.CoinDenom, 10)}
+	monitor := newTestWalletMonitor(t, 1000, wallet)
+	monitor.check(context.Background())
+
+	checker := groupChecker{
+		provider: &ptypes.Provider{},
+		cfg: Config{
+			MaxGroupVolumes: 1,
+		},
+		pass:   nullProviderAttrSignatureService{},
+		wallet: monitor,
+	}
+
+	reasons, err := checker.check(validGroupSpec(), true)
+	require.NoError(t, err)
+	require.Equal(t, []string{DeclineWalletBalance}, declineCodes(reasons))
+}
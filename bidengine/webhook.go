@@ -0,0 +1,392 @@
+package bidengine
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	webhookHeaderOwner = "X-Akash-Owner"
+	webhookHeaderDenom = "X-Akash-Denom"
+
+	// webhookMaxResponseSize limits amount of data read from pricing service
+	webhookMaxResponseSize = 4096
+)
+
+var (
+	errWebhookURL             = errors.New("webhook url must be absolute http(s) url")
+	errWebhookTimeoutZero     = errors.New("webhook timeout must be greater than zero")
+	errWebhookFallback        = errors.New("webhook cannot fall back to itself")
+	errWebhookCircuitOpen     = errors.New("webhook circuit breaker is open")
+	errWebhookUnexpectedReply = errors.New("webhook unexpected response")
+	errWebhookInvalidReply    = errors.New("webhook invalid response")
+
+	webhookCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "provider_bid_pricing_webhook",
+		Help: "The total number of bid pricing webhook lookups",
+	}, []string{"result"})
+)
+
+// WebhookPricingConfig configures bid pricing backed by HTTP pricing service
+type WebhookPricingConfig struct {
+	URL     string
+	Timeout time.Duration
+	// Retries is number of additional attempts made when request fails
+	// with network error or 5xx/429 status
+	Retries      uint
+	RetryBackoff time.Duration
+	// CacheTTL is how long price for the same request is reused. zero disables cache
+	CacheTTL  time.Duration
+	CacheSize int
+	// BreakerThreshold is number of consecutive failed lookups after which
+	// webhook is not called for BreakerCooldown. only network errors and 5xx/429 statuses
+	// count as failures. zero disables circuit breaker
+	BreakerThreshold uint
+	BreakerCooldown  time.Duration
+	// Fallback is used when webhook is not available. if nil lookup error is returned
+	Fallback BidPricingStrategy
+}
+
+type webhookPricingReply struct {
+	Price string `json:"price"`
+}
+
+type webhookCacheEntry struct {
+	price   sdk.DecCoin
+	expires time.Time
+}
+
+type webhookPricing struct {
+	cfg    WebhookPricingConfig
+	client *http.Client
+
+	lock     sync.Mutex
+	cache    map[string]webhookCacheEntry
+	failures uint
+	openTill time.Time
+	probing  bool
+
+	now func() time.Time
+}
+
+// MakeWebhookPricing creates pricing strategy which POSTs same JSON document shellScript strategy
+// writes to the script stdin. Owner and denom of the order are passed as X-Akash-Owner and X-Akash-Denom headers.
+// Service responds either with plain text amount or JSON object {"price": "<amount>"}.
+// Any other 4xx status or zero amount declines the order without consulting the fallback
+func MakeWebhookPricing(cfg WebhookPricingConfig) (BidPricingStrategy, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil || !u.IsAbs() || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("%w: %q", errWebhookURL, cfg.URL)
+	}
+
+	if cfg.Timeout == 0 {
+		return nil, errWebhookTimeoutZero
+	}
+
+	if _, valid := cfg.Fallback.(*webhookPricing); valid {
+		return nil, errWebhookFallback
+	}
+
+	if cfg.CacheSize <= 0 {
+		cfg.CacheSize = 1024
+	}
+
+	result := &webhookPricing{
+		cfg: cfg,
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+		},
+		cache: make(map[string]webhookCacheEntry),
+		now:   time.Now,
+	}
+
+	return result, nil
+}
+
+func (wp *webhookPricing) CalculatePrice(ctx context.Context, r Request) (sdk.DecCoin, error) {
+	d := newDataForScript(r)
+	denom := r.GSpec.Price().Denom
+
+	body, err := json.Marshal(&d)
+	if err != nil {
+		return sdk.DecCoin{}, err
+	}
+
+	key := webhookCacheKey(body, r.Owner, denom)
+	if price, valid := wp.cached(key); valid {
+		webhookCounter.WithLabelValues("cache-hit").Inc()
+		return price, nil
+	}
+
+	if !wp.allow() {
+		webhookCounter.WithLabelValues("circuit-open").Inc()
+		return wp.fallback(ctx, r, errWebhookCircuitOpen)
+	}
+
+	price, err := wp.lookup(ctx, body, r.Owner, denom)
+	if err != nil {
+		switch {
+		case ctx.Err() != nil:
+			// do not penalize pricing service for order being cancelled
+			wp.release()
+			return sdk.DecCoin{}, ctx.Err()
+		case errors.Is(err, ErrBidDeclined):
+			// service is up and has decided not to bid, fallback must not bid in its place
+			webhookCounter.WithLabelValues("declined").Inc()
+			wp.reachable()
+			return sdk.DecCoin{}, err
+		case errors.Is(err, errWebhookInvalidReply):
+			// service is up yet its reply can't be used, it does not count towards opening the breaker
+			webhookCounter.WithLabelValues("invalid").Inc()
+			wp.release()
+		default:
+			webhookCounter.WithLabelValues("fail").Inc()
+			wp.failure()
+		}
+
+		return wp.fallback(ctx, r, err)
+	}
+
+	webhookCounter.WithLabelValues("success").Inc()
+	wp.success(key, price)
+
+	return price, nil
+}
+
+func (wp *webhookPricing) fallback(ctx context.Context, r Request, err error) (sdk.DecCoin, error) {
+	if wp.cfg.Fallback == nil {
+		return sdk.DecCoin{}, err
+	}
+
+	return wp.cfg.Fallback.CalculatePrice(ctx, r)
+}
+
+func (wp *webhookPricing) lookup(ctx context.Context, body []byte, owner, denom string) (sdk.DecCoin, error) {
+	var result sdk.DecCoin
+
+	err := retry.Do(func() error {
+		var err error
+		result, err = wp.post(ctx, body, owner, denom)
+		return err
+	},
+		retry.Attempts(wp.cfg.Retries+1),
+		retry.Delay(wp.cfg.RetryBackoff),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.Context(ctx),
+	)
+
+	return result, err
+}
+
+func (wp *webhookPricing) post(ctx context.Context, body []byte, owner, denom string) (sdk.DecCoin, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wp.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return sdk.DecCoin{}, retry.Unrecoverable(err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookHeaderOwner, owner)
+	req.Header.Set(webhookHeaderDenom, denom)
+
+	resp, err := wp.client.Do(req)
+	if err != nil {
+		return sdk.DecCoin{}, err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, webhookMaxResponseSize))
+	if err != nil {
+		return sdk.DecCoin{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		reply := fmt.Sprintf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+		if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+			return sdk.DecCoin{}, fmt.Errorf("%w: %s", errWebhookUnexpectedReply, reply)
+		}
+
+		// any other status is the service refusing to price the order
+		return sdk.DecCoin{}, retry.Unrecoverable(fmt.Errorf("%w: %s", ErrBidDeclined, reply))
+	}
+
+	price, err := parseWebhookPrice(resp.Header.Get("Content-Type"), data)
+	if err != nil {
+		if errors.Is(err, ErrBidZero) {
+			return sdk.DecCoin{}, retry.Unrecoverable(fmt.Errorf("%w: %w", ErrBidDeclined, err))
+		}
+
+		return sdk.DecCoin{}, retry.Unrecoverable(fmt.Errorf("%w: %w", errWebhookInvalidReply, err))
+	}
+
+	return sdk.NewDecCoinFromDec(denom, price), nil
+}
+
+func parseWebhookPrice(contentType string, data []byte) (sdk.Dec, error) {
+	valueStr := strings.TrimSpace(string(data))
+
+	if strings.HasPrefix(contentType, "application/json") {
+		reply := webhookPricingReply{}
+		if err := json.Unmarshal(data, &reply); err != nil {
+			return sdk.Dec{}, fmt.Errorf("%w: %w", errWebhookUnexpectedReply, err)
+		}
+
+		valueStr = strings.TrimSpace(reply.Price)
+	}
+
+	if valueStr == "" {
+		return sdk.Dec{}, fmt.Errorf("bid webhook must return amount:%w%w", io.EOF, ErrBidQuantityInvalid)
+	}
+
+	price, err := sdk.NewDecFromStr(valueStr)
+	if err != nil {
+		return sdk.Dec{}, fmt.Errorf("%w%w", err, ErrBidQuantityInvalid)
+	}
+
+	if price.IsZero() {
+		return sdk.Dec{}, ErrBidZero
+	}
+
+	if price.IsNegative() {
+		return sdk.Dec{}, ErrBidQuantityInvalid
+	}
+
+	return price, nil
+}
+
+func webhookCacheKey(body []byte, owner, denom string) string {
+	h := sha256.New()
+	_, _ = h.Write(body)
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(owner))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(denom))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (wp *webhookPricing) cached(key string) (sdk.DecCoin, bool) {
+	if wp.cfg.CacheTTL == 0 {
+		return sdk.DecCoin{}, false
+	}
+
+	wp.lock.Lock()
+	defer wp.lock.Unlock()
+
+	entry, exists := wp.cache[key]
+	if !exists {
+		return sdk.DecCoin{}, false
+	}
+
+	if !wp.now().Before(entry.expires) {
+		delete(wp.cache, key)
+		return sdk.DecCoin{}, false
+	}
+
+	return entry.price, true
+}
+
+// allow reports if webhook can be called. once cooldown of the open breaker expires
+// only single probe request is let through until it either succeeds or fails
+func (wp *webhookPricing) allow() bool {
+	if wp.cfg.BreakerThreshold == 0 {
+		return true
+	}
+
+	wp.lock.Lock()
+	defer wp.lock.Unlock()
+
+	if wp.failures < wp.cfg.BreakerThreshold {
+		return true
+	}
+
+	if wp.probing || wp.now().Before(wp.openTill) {
+		return false
+	}
+
+	wp.probing = true
+
+	return true
+}
+
+func (wp *webhookPricing) release() {
+	wp.lock.Lock()
+	defer wp.lock.Unlock()
+
+	wp.probing = false
+}
+
+func (wp *webhookPricing) failure() {
+	wp.lock.Lock()
+	defer wp.lock.Unlock()
+
+	wp.probing = false
+	wp.failures++
+
+	if wp.cfg.BreakerThreshold > 0 && wp.failures >= wp.cfg.BreakerThreshold {
+		wp.openTill = wp.now().Add(wp.cfg.BreakerCooldown)
+	}
+}
+
+// reachable closes the breaker once service has replied
+func (wp *webhookPricing) reachable() {
+	wp.lock.Lock()
+	defer wp.lock.Unlock()
+
+	wp.probing = false
+	wp.failures = 0
+}
+
+func (wp *webhookPricing) success(key string, price sdk.DecCoin) {
+	wp.reachable()
+
+	if wp.cfg.CacheTTL == 0 {
+		return
+	}
+
+	wp.lock.Lock()
+	defer wp.lock.Unlock()
+
+	now := wp.now()
+
+	if len(wp.cache) >= wp.cfg.CacheSize {
+		for k, entry := range wp.cache {
+			if !now.Before(entry.expires) {
+				delete(wp.cache, k)
+			}
+		}
+	}
+
+	// still full, evict arbitrary entry
+	if len(wp.cache) >= wp.cfg.CacheSize {
+		for k := range wp.cache {
+			delete(wp.cache, k)
+			break
+		}
+	}
+
+	wp.cache[key] = webhookCacheEntry{
+		price:   price,
+		expires: now.Add(wp.cfg.CacheTTL),
+	}
+}
@@ -0,0 +1,345 @@
+package bidengine
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/akash-network/node/testutil"
+)
+
+type fixedPricing sdk.DecCoin
+
+func (fp fixedPricing) CalculatePrice(_ context.Context, _ Request) (sdk.DecCoin, error) {
+	return sdk.DecCoin(fp), nil
+}
+
+func newWebhookTestServer(t *testing.T, handler http.HandlerFunc) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	calls := &atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, calls
+}
+
+func Test_WebhookPricingRejectsInvalidConfig(t *testing.T) {
+	_, err := MakeWebhookPricing(WebhookPricingConfig{URL: "", Timeout: time.Second})
+	require.ErrorIs(t, err, errWebhookURL)
+
+	_, err = MakeWebhookPricing(WebhookPricingConfig{URL: "tcp://localhost:1234", Timeout: time.Second})
+	require.ErrorIs(t, err, errWebhookURL)
+
+	_, err = MakeWebhookPricing(WebhookPricingConfig{URL: "http://localhost:1234"})
+	require.ErrorIs(t, err, errWebhookTimeoutZero)
+
+	pricing, err := MakeWebhookPricing(WebhookPricingConfig{URL: "http://localhost:1234", Timeout: time.Second})
+	require.NoError(t, err)
+
+	_, err = MakeWebhookPricing(WebhookPricingConfig{URL: "http://localhost:1234", Timeout: time.Second, Fallback: pricing})
+	require.ErrorIs(t, err, errWebhookFallback)
+}
+
+func Test_WebhookPricingPostsDataForScript(t *testing.T) {
+	req := Request{
+		Owner: testutil.AccAddress(t).String(),
+		GSpec: defaultGroupSpec(),
+	}
+
+	server, _ := newWebhookTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, req.Owner, r.Header.Get(webhookHeaderOwner))
+		require.Equal(t, "uakt", r.Header.Get(webhookHeaderDenom))
+
+		expected, err := json.Marshal(newDataForScript(req))
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, string(expected), string(body))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"price": "132.5"}`))
+	})
+
+	pricing, err := MakeWebhookPricing(WebhookPricingConfig{URL: server.URL, Timeout: time.Second})
+	require.NoError(t, err)
+
+	price, err := pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, "uakt", price.Denom)
+	require.Equal(t, sdk.MustNewDecFromStr("132.5"), price.Amount)
+}
+
+func Test_WebhookPricingRetries(t *testing.T) {
+	server, calls := newWebhookTestServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	pricing, err := MakeWebhookPricing(WebhookPricingConfig{URL: server.URL, Timeout: time.Second, Retries: 2})
+	require.NoError(t, err)
+
+	req := Request{
+		Owner: testutil.AccAddress(t).String(),
+		GSpec: defaultGroupSpec(),
+	}
+
+	_, err = pricing.CalculatePrice(context.Background(), req)
+	require.ErrorIs(t, err, errWebhookUnexpectedReply)
+	require.Equal(t, int32(3), calls.Load())
+
+	// client errors are not retried
+	server, calls = newWebhookTestServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	pricing, err = MakeWebhookPricing(WebhookPricingConfig{URL: server.URL, Timeout: time.Second, Retries: 2})
+	require.NoError(t, err)
+
+	_, err = pricing.CalculatePrice(context.Background(), req)
+	require.ErrorIs(t, err, ErrBidDeclined)
+	require.Equal(t, int32(1), calls.Load())
+
+	// neither are invalid prices
+	server, calls = newWebhookTestServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("0"))
+	})
+
+	pricing, err = MakeWebhookPricing(WebhookPricingConfig{URL: server.URL, Timeout: time.Second, Retries: 2})
+	require.NoError(t, err)
+
+	_, err = pricing.CalculatePrice(context.Background(), req)
+	require.ErrorIs(t, err, ErrBidZero)
+	require.ErrorIs(t, err, ErrBidDeclined)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func Test_WebhookPricingCachesByRequest(t *testing.T) {
+	server, calls := newWebhookTestServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("100\n"))
+	})
+
+	pricing, err := MakeWebhookPricing(WebhookPricingConfig{URL: server.URL, Timeout: time.Second, CacheTTL: time.Minute})
+	require.NoError(t, err)
+
+	now := time.Now()
+	pricing.(*webhookPricing).now = func() time.Time { return now }
+
+	req := Request{
+		Owner: testutil.AccAddress(t).String(),
+		GSpec: defaultGroupSpec(),
+	}
+
+	for i := 0; i < 3; i++ {
+		price, err := pricing.CalculatePrice(context.Background(), req)
+		require.NoError(t, err)
+		require.Equal(t, sdk.NewDec(100), price.Amount)
+	}
+	require.Equal(t, int32(1), calls.Load())
+
+	// different owner is priced separately
+	req.Owner = testutil.AccAddress(t).String()
+	_, err = pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), calls.Load())
+
+	now = now.Add(time.Minute)
+	_, err = pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, int32(3), calls.Load())
+}
+
+func Test_WebhookPricingCircuitBreakerFallsBack(t *testing.T) {
+	var healthy atomic.Bool
+
+	server, calls := newWebhookTestServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte("100"))
+	})
+
+	fallback := fixedPricing(sdk.NewDecCoin("uakt", sdk.NewInt(7)))
+
+	pricing, err := MakeWebhookPricing(WebhookPricingConfig{
+		URL:              server.URL,
+		Timeout:          time.Second,
+		BreakerThreshold: 2,
+		BreakerCooldown:  time.Minute,
+		Fallback:         fallback,
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	pricing.(*webhookPricing).now = func() time.Time { return now }
+
+	req := Request{
+		Owner: testutil.AccAddress(t).String(),
+		GSpec: defaultGroupSpec(),
+	}
+
+	for i := 0; i < 4; i++ {
+		price, err := pricing.CalculatePrice(context.Background(), req)
+		require.NoError(t, err)
+		require.Equal(t, sdk.DecCoin(fallback), price)
+	}
+
+	// breaker opened after second failure
+	require.Equal(t, int32(2), calls.Load())
+
+	// probe after cooldown fails and reopens breaker
+	now = now.Add(time.Minute)
+	_, err = pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+	_, err = pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, int32(3), calls.Load())
+
+	// successful probe closes breaker
+	healthy.Store(true)
+	now = now.Add(time.Minute)
+
+	for i := 0; i < 2; i++ {
+		price, err := pricing.CalculatePrice(context.Background(), req)
+		require.NoError(t, err)
+		require.Equal(t, sdk.NewDec(100), price.Amount)
+	}
+	require.Equal(t, int32(5), calls.Load())
+}
+
+func Test_WebhookPricingDeclineDoesNotFallBack(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{
+			name: "client error",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+			},
+		},
+		{
+			name: "zero price",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"price": "0"}`))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server, calls := newWebhookTestServer(t, test.handler)
+
+			pricing, err := MakeWebhookPricing(WebhookPricingConfig{
+				URL:              server.URL,
+				Timeout:          time.Second,
+				BreakerThreshold: 2,
+				BreakerCooldown:  time.Minute,
+				Fallback:         fixedPricing(sdk.NewDecCoin("uakt", sdk.NewInt(7))),
+			})
+			require.NoError(t, err)
+
+			req := Request{
+				Owner: testutil.AccAddress(t).String(),
+				GSpec: defaultGroupSpec(),
+			}
+
+			// declines neither use fallback nor open the breaker
+			for i := 0; i < 4; i++ {
+				_, err = pricing.CalculatePrice(context.Background(), req)
+				require.ErrorIs(t, err, ErrBidDeclined)
+			}
+			require.Equal(t, int32(4), calls.Load())
+			require.Zero(t, pricing.(*webhookPricing).failures)
+		})
+	}
+}
+
+func Test_WebhookPricingDeclineResetsBreaker(t *testing.T) {
+	var declining atomic.Bool
+
+	server, calls := newWebhookTestServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		if declining.Load() {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	fallback := fixedPricing(sdk.NewDecCoin("uakt", sdk.NewInt(7)))
+
+	pricing, err := MakeWebhookPricing(WebhookPricingConfig{
+		URL:              server.URL,
+		Timeout:          time.Second,
+		BreakerThreshold: 2,
+		BreakerCooldown:  time.Minute,
+		Fallback:         fallback,
+	})
+	require.NoError(t, err)
+
+	req := Request{
+		Owner: testutil.AccAddress(t).String(),
+		GSpec: defaultGroupSpec(),
+	}
+
+	price, err := pricing.CalculatePrice(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, sdk.DecCoin(fallback), price)
+	require.Equal(t, uint(1), pricing.(*webhookPricing).failures)
+
+	// service replying with decline is reachable
+	declining.Store(true)
+	_, err = pricing.CalculatePrice(context.Background(), req)
+	require.ErrorIs(t, err, ErrBidDeclined)
+	require.Zero(t, pricing.(*webhookPricing).failures)
+	require.Equal(t, int32(2), calls.Load())
+}
+
+func Test_WebhookPricingInvalidReplyFallsBack(t *testing.T) {
+	server, calls := newWebhookTestServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("not a price"))
+	})
+
+	fallback := fixedPricing(sdk.NewDecCoin("uakt", sdk.NewInt(7)))
+
+	pricing, err := MakeWebhookPricing(WebhookPricingConfig{
+		URL:              server.URL,
+		Timeout:          time.Second,
+		BreakerThreshold: 1,
+		BreakerCooldown:  time.Minute,
+		Fallback:         fallback,
+	})
+	require.NoError(t, err)
+
+	req := Request{
+		Owner: testutil.AccAddress(t).String(),
+		GSpec: defaultGroupSpec(),
+	}
+
+	// unusable reply is priced by fallback, yet does not open the breaker
+	for i := 0; i < 3; i++ {
+		price, err := pricing.CalculatePrice(context.Background(), req)
+		require.NoError(t, err)
+		require.Equal(t, sdk.DecCoin(fallback), price)
+	}
+	require.Equal(t, int32(3), calls.Load())
+
+	pricing.(*webhookPricing).cfg.Fallback = nil
+	_, err = pricing.CalculatePrice(context.Background(), req)
+	require.ErrorIs(t, err, errWebhookInvalidReply)
+	require.ErrorIs(t, err, ErrBidQuantityInvalid)
+}
@@ -0,0 +1,78 @@
+package broadcaster
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
+)
+
+// Priority orders queued transactions. transactions of higher priority are broadcast first,
+// transactions of the same priority in order they have been queued
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	}
+
+	return "unknown"
+}
+
+type priorityCtxKey struct{}
+
+// WithPriority returns context setting priority of transactions broadcast with it,
+// overriding priority derived from their messages
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityCtxKey{}, p)
+}
+
+// MsgPriority returns priority of transaction composed of given messages, which is the highest priority of its messages.
+// closing bids and leases releases provider resources and stops the lease from charging, so it goes ahead of anything else.
+// new bids wait for everything else
+func MsgPriority(msgs []sdk.Msg) Priority {
+	if len(msgs) == 0 {
+		return PriorityNormal
+	}
+
+	res := PriorityLow
+
+	for _, msg := range msgs {
+		var prio Priority
+
+		switch msg.(type) {
+		case *mtypes.MsgCloseBid, *mtypes.MsgCloseLease:
+			prio = PriorityHigh
+		case *mtypes.MsgCreateBid:
+			prio = PriorityLow
+		default:
+			prio = PriorityNormal
+		}
+
+		if prio > res {
+			res = prio
+		}
+	}
+
+	return res
+}
+
+func priorityOf(ctx context.Context, msgs []sdk.Msg) Priority {
+	if prio, valid := ctx.Value(priorityCtxKey{}).(Priority); valid {
+		return prio
+	}
+
+	return MsgPriority(msgs)
+}
@@ -0,0 +1,41 @@
+package broadcaster
+
+import (
+	"container/heap"
+)
+
+// queue is the heap of pending requests, ordered by priority and then by arrival
+type queue []request
+
+var _ heap.Interface = (*queue)(nil)
+
+func (q queue) Len() int {
+	return len(q)
+}
+
+func (q queue) Less(i, k int) bool {
+	if q[i].priority != q[k].priority {
+		return q[i].priority > q[k].priority
+	}
+
+	return q[i].seq < q[k].seq
+}
+
+func (q queue) Swap(i, k int) {
+	q[i], q[k] = q[k], q[i]
+}
+
+func (q *queue) Push(val interface{}) {
+	*q = append(*q, val.(request))
+}
+
+func (q *queue) Pop() interface{} {
+	old := *q
+	n := len(old)
+
+	res := old[n-1]
+	old[n-1] = request{}
+	*q = old[:n-1]
+
+	return res
+}
@@ -0,0 +1,334 @@
+package broadcaster
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/boz/go-lifecycle"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tendermint/tendermint/libs/log"
+
+	aclient "github.com/akash-network/akash-api/go/node/client/v1beta2"
+)
+
+const (
+	defaultRetryBackoff    = time.Second
+	defaultMaxRetryBackoff = 30 * time.Second
+
+	retrySequenceMismatch = "sequence-mismatch"
+	retryMempoolFull      = "mempool-full"
+
+	resultSuccess  = "success"
+	resultFailed   = "failed"
+	resultCanceled = "canceled"
+)
+
+var (
+	ErrNotRunning = errors.New("broadcaster: not running")
+
+	sequenceMismatchRegexp = regexp.MustCompile(`account sequence mismatch, expected \d+, got \d+`)
+
+	txMessagesCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "provider_tx_messages",
+		Help: "Messages provider has broadcast, by message type and outcome of their transaction",
+	}, []string{"msg_type", "result"})
+
+	txRetriesCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "provider_tx_retries",
+		Help: "Transaction broadcasts retried, by reason",
+	}, []string{"reason"})
+
+	txQueueGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "provider_tx_queue_length",
+		Help: "Transactions waiting to be broadcast, by priority",
+	}, []string{"priority"})
+
+	txGasUsedHistogram = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "provider_tx_gas_used",
+		Help:    "Gas used by committed transactions",
+		Buckets: prometheus.ExponentialBuckets(50000, 2, 8),
+	})
+)
+
+// Config configures the transaction service
+type Config struct {
+	// EstimateGas simulates transactions to estimate gas they need instead of using fixed gas limit.
+	// fee is then estimated gas times gas prices the client is configured with
+	EstimateGas bool
+	// Timeout limits single broadcast attempt. zero leaves it to the client
+	Timeout time.Duration
+	// MaxRetries is how many times broadcast is retried on account sequence mismatch or full mempool
+	MaxRetries uint
+	// RetryBackoff is delay before the first retry, doubled with every next one up to MaxRetryBackoff
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+}
+
+// Service broadcasts transactions of all provider components through single account.
+// transactions are queued by priority and broadcast one at a time, so account sequence is never used concurrently
+type Service interface {
+	aclient.TxClient
+	Close() error
+	Done() <-chan struct{}
+}
+
+type response struct {
+	resp interface{}
+	err  error
+}
+
+type request struct {
+	ctx      context.Context
+	msgs     []sdk.Msg
+	opts     []aclient.BroadcastOption
+	priority Priority
+	seq      uint64
+	respch   chan<- response
+}
+
+type service struct {
+	tx    aclient.TxClient
+	cfg   Config
+	log   log.Logger
+	lc    lifecycle.Lifecycle
+	reqch chan request
+}
+
+var _ Service = (*service)(nil)
+
+// NewService creates transaction service broadcasting through given client
+func NewService(ctx context.Context, log log.Logger, tx aclient.TxClient, cfg Config) Service {
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = defaultRetryBackoff
+	}
+
+	if cfg.MaxRetryBackoff < cfg.RetryBackoff {
+		cfg.MaxRetryBackoff = defaultMaxRetryBackoff
+	}
+
+	s := &service{
+		tx:    tx,
+		cfg:   cfg,
+		log:   log.With("cmp", "tx-broadcaster"),
+		lc:    lifecycle.New(),
+		reqch: make(chan request),
+	}
+
+	go s.lc.WatchContext(ctx)
+	go s.run()
+
+	return s
+}
+
+// Broadcast queues transaction and waits for its result
+func (s *service) Broadcast(ctx context.Context, msgs []sdk.Msg, opts ...aclient.BroadcastOption) (interface{}, error) {
+	respch := make(chan response, 1)
+
+	req := request{
+		ctx:      ctx,
+		msgs:     msgs,
+		opts:     opts,
+		priority: priorityOf(ctx, msgs),
+		respch:   respch,
+	}
+
+	select {
+	case s.reqch <- req:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-s.lc.ShuttingDown():
+		return nil, ErrNotRunning
+	}
+
+	select {
+	case resp := <-respch:
+		return resp.resp, resp.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-s.lc.ShuttingDown():
+		return nil, ErrNotRunning
+	}
+}
+
+func (s *service) Close() error {
+	s.lc.Shutdown(nil)
+	return s.lc.Error()
+}
+
+func (s *service) Done() <-chan struct{} {
+	return s.lc.Done()
+}
+
+func (s *service) run() {
+	defer s.lc.ShutdownCompleted()
+
+	pending := &queue{}
+	donech := make(chan struct{}, 1)
+	busy := false
+
+	var seq uint64
+
+	next := func() {
+		if busy || pending.Len() == 0 {
+			return
+		}
+
+		req := heap.Pop(pending).(request)
+		txQueueGauge.WithLabelValues(req.priority.String()).Dec()
+
+		busy = true
+
+		go func() {
+			req.respch <- s.broadcast(req)
+			donech <- struct{}{}
+		}()
+	}
+
+loop:
+	for {
+		select {
+		case err := <-s.lc.ShutdownRequest():
+			s.lc.ShutdownInitiated(err)
+			break loop
+		case req := <-s.reqch:
+			seq++
+			req.seq = seq
+
+			heap.Push(pending, req)
+			txQueueGauge.WithLabelValues(req.priority.String()).Inc()
+
+			next()
+		case <-donech:
+			busy = false
+			next()
+		}
+	}
+
+	for _, req := range *pending {
+		txQueueGauge.WithLabelValues(req.priority.String()).Dec()
+	}
+
+	if busy {
+		<-donech
+	}
+}
+
+// broadcast broadcasts transaction of the request, retrying failures which are expected to clear up
+func (s *service) broadcast(req request) response {
+	opts := req.opts
+	if s.cfg.EstimateGas {
+		// options of the caller take precedence
+		opts = append([]aclient.BroadcastOption{aclient.WithGas(flags.GasSetting{Simulate: true})}, opts...)
+	}
+
+	backoff := s.cfg.RetryBackoff
+
+	var resp interface{}
+	var err error
+
+loop:
+	for attempt := uint(0); ; attempt++ {
+		if err = req.ctx.Err(); err != nil {
+			break
+		}
+
+		resp, err = s.attempt(req.ctx, req.msgs, opts)
+
+		reason := retryReason(err)
+		if reason == "" || attempt >= s.cfg.MaxRetries {
+			break
+		}
+
+		txRetriesCounter.WithLabelValues(reason).Inc()
+		s.log.Info("retrying transaction", "reason", reason, "attempt", attempt+1, "backoff", backoff, "err", err)
+
+		select {
+		case <-req.ctx.Done():
+			err = req.ctx.Err()
+			break loop
+		case <-s.lc.ShuttingDown():
+			err = ErrNotRunning
+			break loop
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > s.cfg.MaxRetryBackoff {
+			backoff = s.cfg.MaxRetryBackoff
+		}
+	}
+
+	s.observe(req, resp, err)
+
+	return response{resp: resp, err: err}
+}
+
+func (s *service) attempt(ctx context.Context, msgs []sdk.Msg, opts []aclient.BroadcastOption) (interface{}, error) {
+	if s.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.Timeout)
+		defer cancel()
+	}
+
+	return s.tx.Broadcast(ctx, msgs, opts...)
+}
+
+func (s *service) observe(req request, resp interface{}, err error) {
+	result := resultSuccess
+
+	switch {
+	case err == nil:
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrNotRunning):
+		result = resultCanceled
+	default:
+		result = resultFailed
+	}
+
+	for _, msg := range req.msgs {
+		txMessagesCounter.WithLabelValues(sdk.MsgTypeURL(msg), result).Inc()
+	}
+
+	if res, valid := resp.(*sdk.TxResponse); valid && res != nil && err == nil {
+		txGasUsedHistogram.Observe(float64(res.GasUsed))
+		s.log.Debug("transaction committed", "hash", res.TxHash, "height", res.Height, "gas-wanted", res.GasWanted, "gas-used", res.GasUsed)
+	}
+}
+
+// retryReason returns why failed broadcast is worth another attempt, or empty string if it is not
+func retryReason(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, sdkerrors.ErrWrongSequence) || sequenceMismatchRegexp.MatchString(err.Error()):
+		return retrySequenceMismatch
+	case errors.Is(err, sdkerrors.ErrMempoolIsFull) || strings.Contains(err.Error(), "mempool is full"):
+		return retryMempoolFull
+	}
+
+	return ""
+}
+
+type client struct {
+	aclient.Client
+	tx aclient.TxClient
+}
+
+// WrapClient returns client broadcasting transactions through given tx client, e.g. the transaction service
+func WrapClient(cl aclient.Client, tx aclient.TxClient) aclient.Client {
+	return client{
+		Client: cl,
+		tx:     tx,
+	}
+}
+
+func (c client) Tx() aclient.TxClient {
+	return c.tx
+}
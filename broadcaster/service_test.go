@@ -0,0 +1,233 @@
+package broadcaster
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	promtest "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	aclient "github.com/akash-network/akash-api/go/node/client/v1beta2"
+	clientmocks "github.com/akash-network/akash-api/go/node/client/v1beta2/mocks"
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
+
+	"github.com/akash-network/node/testutil"
+)
+
+func newTestService(t *testing.T, tx aclient.TxClient, cfg Config) Service {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	svc := NewService(ctx, testutil.Logger(t), tx, cfg)
+	t.Cleanup(func() {
+		cancel()
+		<-svc.Done()
+	})
+
+	return svc
+}
+
+func createBidMsg(t *testing.T) sdk.Msg {
+	return &mtypes.MsgCreateBid{
+		Order: testutil.OrderID(t),
+	}
+}
+
+func closeBidMsg(t *testing.T) sdk.Msg {
+	return &mtypes.MsgCloseBid{
+		BidID: testutil.BidID(t),
+	}
+}
+
+func TestMsgPriority(t *testing.T) {
+	withdraw := &mtypes.MsgWithdrawLease{}
+
+	require.Equal(t, PriorityNormal, MsgPriority(nil))
+	require.Equal(t, PriorityLow, MsgPriority([]sdk.Msg{createBidMsg(t)}))
+	require.Equal(t, PriorityNormal, MsgPriority([]sdk.Msg{withdraw}))
+	require.Equal(t, PriorityHigh, MsgPriority([]sdk.Msg{closeBidMsg(t)}))
+	require.Equal(t, PriorityHigh, MsgPriority([]sdk.Msg{&mtypes.MsgCloseLease{}}))
+	require.Equal(t, PriorityNormal, MsgPriority([]sdk.Msg{createBidMsg(t), withdraw}))
+
+	ctx := WithPriority(context.Background(), PriorityHigh)
+	require.Equal(t, PriorityHigh, priorityOf(ctx, []sdk.Msg{createBidMsg(t)}))
+}
+
+func TestServiceBroadcastsByPriority(t *testing.T) {
+	releasech := make(chan struct{})
+	startedch := make(chan struct{})
+	calls := make(chan sdk.Msg, 3)
+
+	blocking := &mtypes.MsgWithdrawLease{}
+
+	tx := &clientmocks.TxClient{}
+	tx.On("Broadcast", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		msg := args.Get(1).([]sdk.Msg)[0]
+		if msg == blocking {
+			close(startedch)
+			<-releasech
+		}
+
+		calls <- msg
+	}).Return(&sdk.TxResponse{}, nil)
+
+	svc := newTestService(t, tx, Config{})
+
+	ctx := context.Background()
+	errch := make(chan error, 3)
+
+	broadcast := func(msg sdk.Msg) {
+		_, err := svc.Broadcast(ctx, []sdk.Msg{msg}, aclient.WithResultCodeAsError())
+		errch <- err
+	}
+
+	go broadcast(blocking)
+
+	// wait for the first transaction to occupy the broadcaster, then queue the rest
+	testutil.ChannelWaitForClose(t, startedch)
+
+	createBid := createBidMsg(t)
+	closeBid := closeBidMsg(t)
+
+	go broadcast(createBid)
+	require.Eventually(t, func() bool {
+		return promtest.ToFloat64(txQueueGauge.WithLabelValues(PriorityLow.String())) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	go broadcast(closeBid)
+	require.Eventually(t, func() bool {
+		return promtest.ToFloat64(txQueueGauge.WithLabelValues(PriorityHigh.String())) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	close(releasech)
+
+	require.Equal(t, blocking, testutil.ChannelWaitForValue(t, calls))
+	require.Equal(t, closeBid, testutil.ChannelWaitForValue(t, calls))
+	require.Equal(t, createBid, testutil.ChannelWaitForValue(t, calls))
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, <-errch)
+	}
+}
+
+func TestServiceRetries(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		calls int
+	}{
+		{
+			name:  "sequence mismatch",
+			err:   errors.New("simulating tx: account sequence mismatch, expected 10, got 9: incorrect account sequence"),
+			calls: 2,
+		},
+		{
+			name:  "wrong sequence",
+			err:   sdkerrors.Wrap(sdkerrors.ErrWrongSequence, "tx"),
+			calls: 2,
+		},
+		{
+			name:  "mempool full",
+			err:   sdkerrors.Wrap(sdkerrors.ErrMempoolIsFull, "tx"),
+			calls: 2,
+		},
+		{
+			name:  "insufficient funds",
+			err:   sdkerrors.Wrap(sdkerrors.ErrInsufficientFunds, "tx"),
+			calls: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expected := &sdk.TxResponse{TxHash: "TX", GasUsed: 100000}
+
+			tx := &clientmocks.TxClient{}
+			tx.On("Broadcast", mock.Anything, mock.Anything, mock.Anything).Return(nil, test.err).Once()
+			tx.On("Broadcast", mock.Anything, mock.Anything, mock.Anything).Return(expected, nil).Once()
+
+			svc := newTestService(t, tx, Config{
+				MaxRetries:   3,
+				RetryBackoff: time.Millisecond,
+			})
+
+			resp, err := svc.Broadcast(context.Background(), []sdk.Msg{createBidMsg(t)}, aclient.WithResultCodeAsError())
+			if test.calls == 1 {
+				require.ErrorIs(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, expected, resp)
+			}
+
+			tx.AssertNumberOfCalls(t, "Broadcast", test.calls)
+		})
+	}
+}
+
+func TestServiceRetriesExhausted(t *testing.T) {
+	tx := &clientmocks.TxClient{}
+	tx.On("Broadcast", mock.Anything, mock.Anything, mock.Anything).Return(nil, sdkerrors.ErrMempoolIsFull)
+
+	svc := newTestService(t, tx, Config{
+		MaxRetries:   2,
+		RetryBackoff: time.Millisecond,
+	})
+
+	_, err := svc.Broadcast(context.Background(), []sdk.Msg{closeBidMsg(t)}, aclient.WithResultCodeAsError())
+	require.ErrorIs(t, err, sdkerrors.ErrMempoolIsFull)
+
+	tx.AssertNumberOfCalls(t, "Broadcast", 3)
+}
+
+func TestServiceEstimatesGas(t *testing.T) {
+	tx := &clientmocks.TxClient{}
+	tx.On("Broadcast", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&sdk.TxResponse{}, nil)
+
+	svc := newTestService(t, tx, Config{EstimateGas: true})
+
+	_, err := svc.Broadcast(context.Background(), []sdk.Msg{createBidMsg(t)}, aclient.WithResultCodeAsError())
+	require.NoError(t, err)
+
+	// gas option is passed along with options of the caller
+	tx.AssertNumberOfCalls(t, "Broadcast", 1)
+}
+
+func TestServiceSkipsCanceledRequests(t *testing.T) {
+	tx := &clientmocks.TxClient{}
+
+	svc := newTestService(t, tx, Config{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := svc.Broadcast(ctx, []sdk.Msg{createBidMsg(t)}, aclient.WithResultCodeAsError())
+	require.ErrorIs(t, err, context.Canceled)
+
+	tx.AssertNotCalled(t, "Broadcast", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestServiceClosed(t *testing.T) {
+	tx := &clientmocks.TxClient{}
+
+	svc := NewService(context.Background(), testutil.Logger(t), tx, Config{})
+	require.NoError(t, svc.Close())
+
+	_, err := svc.Broadcast(context.Background(), []sdk.Msg{createBidMsg(t)}, aclient.WithResultCodeAsError())
+	require.ErrorIs(t, err, ErrNotRunning)
+}
+
+func TestWrapClient(t *testing.T) {
+	tx := &clientmocks.TxClient{}
+
+	cl := &clientmocks.Client{}
+	cl.On("Tx").Return(&clientmocks.TxClient{})
+
+	wrapped := WrapClient(cl, tx)
+	require.Equal(t, tx, wrapped.Tx())
+}
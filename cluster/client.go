@@ -35,6 +35,9 @@ var (
 	ErrExecCommandDoesNotExist     = fmt.Errorf("%w: command could not be executed because it does not exist", ErrExec)
 	ErrExecDeploymentNotYetRunning = fmt.Errorf("%w: deployment is not yet active", ErrExec)
 	ErrExecPodIndexOutOfRange      = fmt.Errorf("%w: pod index out of range", ErrExec)
+	ErrAttestationNotEnabled       = fmt.Errorf("%w: service does not run tee sidecar", ErrExec)
+	ErrAttestationSidecar          = fmt.Errorf("%w: tee sidecar failed to produce quote", ErrExec)
+	ErrPortForwardFailed           = fmt.Errorf("%w: port forward failed", ErrExec)
 	ErrUnknownStorageClass         = errors.New("inventory: unknown storage class")
 	errNotImplemented              = errors.New("not implemented")
 )
@@ -46,11 +49,21 @@ type ReadClient interface {
 	LeaseStatus(context.Context, mtypes.LeaseID) (map[string]*ctypes.ServiceStatus, error)
 	ForwardedPortStatus(context.Context, mtypes.LeaseID) (map[string][]ctypes.ForwardedPortStatus, error)
 	LeaseEvents(context.Context, mtypes.LeaseID, string, bool) (ctypes.EventsWatcher, error)
-	LeaseLogs(context.Context, mtypes.LeaseID, string, bool, *int64) ([]*ctypes.ServiceLog, error)
+	LeaseLogs(context.Context, mtypes.LeaseID, string, ctypes.LeaseLogsOptions) ([]*ctypes.ServiceLog, error)
 	ServiceStatus(context.Context, mtypes.LeaseID, string) (*ctypes.ServiceStatus, error)
 
 	AllHostnames(context.Context) ([]chostname.ActiveHostname, error)
 	GetManifestGroup(context.Context, mtypes.LeaseID) (bool, crd.ManifestGroup, error)
+	// ManifestRevisions lists manifest groups lease has been deployed with, oldest first
+	ManifestRevisions(context.Context, mtypes.LeaseID) ([]ctypes.ManifestRevision, error)
+	// LeasePaused reports if workloads of the lease have been scaled down by the tenant
+	LeasePaused(context.Context, mtypes.LeaseID) (bool, error)
+	// LeaseProbeFailures lists probes kubelet has recently reported failing for current pods of the lease, by service name
+	LeaseProbeFailures(context.Context, mtypes.LeaseID) (map[string][]ctypes.ProbeStatus, error)
+	// LeaseSnapshots lists snapshots taken of persistent volumes of the lease, oldest first
+	LeaseSnapshots(context.Context, mtypes.LeaseID) ([]ctypes.VolumeSnapshot, error)
+	// SnapshotStorage returns storage volume snapshots of all leases take, in bytes by storage class
+	SnapshotStorage(context.Context) (map[string]int64, error)
 
 	ObserveHostnameState(ctx context.Context) (<-chan chostname.ResourceEvent, error)
 	GetHostnameDeploymentConnections(ctx context.Context) ([]chostname.LeaseIDConnection, error)
@@ -67,6 +80,14 @@ type Client interface {
 	Deploy(ctx context.Context, deployment ctypes.IDeployment) error
 	TeardownLease(context.Context, mtypes.LeaseID) error
 	Deployments(context.Context) ([]ctypes.IDeployment, error)
+	// ManifestRevision returns deployment of the lease with manifest group of given revision, zero being the previous one
+	ManifestRevision(ctx context.Context, lID mtypes.LeaseID, revision uint64) (ctypes.IDeployment, error)
+	// PauseLease scales workloads of the lease to zero keeping its volumes and hostnames
+	PauseLease(ctx context.Context, lID mtypes.LeaseID) error
+	// ResumeLease scales workloads of the paused lease back to service counts of its manifest
+	ResumeLease(ctx context.Context, lID mtypes.LeaseID) error
+	// SnapshotLeaseVolumes takes snapshot of every persistent volume of the lease
+	SnapshotLeaseVolumes(ctx context.Context, lID mtypes.LeaseID, name string) ([]ctypes.VolumeSnapshot, error)
 	Exec(ctx context.Context,
 		lID mtypes.LeaseID,
 		service string,
@@ -78,6 +99,13 @@ type Client interface {
 		tty bool,
 		tsq remotecommand.TerminalSizeQueue) (ctypes.ExecResult, error)
 
+	// LeaseAttestation requests quote from TEE sidecar of the service pod
+	LeaseAttestation(ctx context.Context, lID mtypes.LeaseID, service string, podIndex uint, req ctypes.AttestationRequest) (*ctypes.AttestationQuote, error)
+
+	// PortForward tunnels stream to TCP port of the service pod until either side closes it.
+	// port the service exposes its container port as is accepted too
+	PortForward(ctx context.Context, lID mtypes.LeaseID, service string, podIndex uint, port uint32, stream io.ReadWriter) error
+
 	// ConnectHostnameToDeployment Connect a given hostname to a deployment
 	ConnectHostnameToDeployment(ctx context.Context, directive chostname.ConnectToDeploymentDirective) error
 	// RemoveHostnameFromDeployment Remove a given hostname from a deployment
@@ -268,7 +296,7 @@ func (c *nullClient) ServiceStatus(_ context.Context, _ mtypes.LeaseID, _ string
 	return nil, nil
 }
 
-func (c *nullClient) LeaseLogs(_ context.Context, _ mtypes.LeaseID, _ string, _ bool, _ *int64) ([]*ctypes.ServiceLog, error) {
+func (c *nullClient) LeaseLogs(_ context.Context, _ mtypes.LeaseID, _ string, _ ctypes.LeaseLogsOptions) ([]*ctypes.ServiceLog, error) {
 	return nil, nil
 }
 
@@ -292,10 +320,54 @@ func (c *nullClient) Exec(context.Context, mtypes.LeaseID, string, uint, []strin
 	return nil, errNotImplemented
 }
 
+func (c *nullClient) LeaseAttestation(context.Context, mtypes.LeaseID, string, uint, ctypes.AttestationRequest) (*ctypes.AttestationQuote, error) {
+	return nil, errNotImplemented
+}
+
+func (c *nullClient) PortForward(context.Context, mtypes.LeaseID, string, uint, uint32, io.ReadWriter) error {
+	return errNotImplemented
+}
+
 func (c *nullClient) GetManifestGroup(context.Context, mtypes.LeaseID) (bool, crd.ManifestGroup, error) {
 	return false, crd.ManifestGroup{}, nil
 }
 
+func (c *nullClient) ManifestRevisions(context.Context, mtypes.LeaseID) ([]ctypes.ManifestRevision, error) {
+	return nil, errNotImplemented
+}
+
+func (c *nullClient) ManifestRevision(context.Context, mtypes.LeaseID, uint64) (ctypes.IDeployment, error) {
+	return nil, errNotImplemented
+}
+
+func (c *nullClient) LeasePaused(context.Context, mtypes.LeaseID) (bool, error) {
+	return false, nil
+}
+
+func (c *nullClient) PauseLease(context.Context, mtypes.LeaseID) error {
+	return errNotImplemented
+}
+
+func (c *nullClient) ResumeLease(context.Context, mtypes.LeaseID) error {
+	return errNotImplemented
+}
+
+func (c *nullClient) SnapshotLeaseVolumes(context.Context, mtypes.LeaseID, string) ([]ctypes.VolumeSnapshot, error) {
+	return nil, errNotImplemented
+}
+
+func (c *nullClient) LeaseProbeFailures(context.Context, mtypes.LeaseID) (map[string][]ctypes.ProbeStatus, error) {
+	return nil, nil
+}
+
+func (c *nullClient) LeaseSnapshots(context.Context, mtypes.LeaseID) ([]ctypes.VolumeSnapshot, error) {
+	return nil, nil
+}
+
+func (c *nullClient) SnapshotStorage(context.Context) (map[string]int64, error) {
+	return nil, nil
+}
+
 func (c *nullClient) AllHostnames(context.Context) ([]chostname.ActiveHostname, error) {
 	return nil, nil
 }
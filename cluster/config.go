@@ -1,6 +1,10 @@
 package cluster
 
-import "time"
+import (
+	"time"
+
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
 
 type Config struct {
 	InventoryResourcePollPeriod     time.Duration
@@ -19,6 +23,9 @@ type Config struct {
 	MonitorHealthcheckPeriod        time.Duration
 	MonitorHealthcheckPeriodJitter  time.Duration
 	ClusterSettings                 map[interface{}]interface{}
+	TEE                             ctypes.TEEConfig
+	// VolumeSnapshots counts storage taken by volume snapshots of leases against inventory
+	VolumeSnapshots bool
 }
 
 func NewDefaultConfig() Config {
@@ -30,5 +37,6 @@ func NewDefaultConfig() Config {
 		MonitorRetryPeriodJitter:        time.Second * 15,
 		MonitorHealthcheckPeriod:        time.Second * 10, // nolint revive
 		MonitorHealthcheckPeriodJitter:  time.Second * 5,
+		TEE:                             ctypes.NewDefaultTEEConfig(),
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"sync/atomic"
 	"time"
 
@@ -43,8 +44,12 @@ var (
 	errInventoryReservation     = errors.New("inventory error")
 	errNoLeasedIPsAvailable     = fmt.Errorf("%w: no leased IPs available", errInventoryReservation)
 	errInsufficientIPs          = fmt.Errorf("%w: insufficient number of IPs", errInventoryReservation)
+	errInventoryNotAvailable    = fmt.Errorf("%w: inventory is not available yet", errInventoryReservation)
 )
 
+// snapshotStoragePollPeriod is how often storage taken by volume snapshots is refreshed
+var snapshotStoragePollPeriod = time.Minute
+
 var (
 	inventoryRequestsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name:        "provider_inventory_requests",
@@ -91,6 +96,7 @@ type inventoryService struct {
 	statusch               chan chan<- inventoryV1.InventoryMetrics
 	statusV1ch             chan chan<- invSnapshotResp
 	lookupch               chan inventoryRequest
+	checkch                chan inventoryRequest
 	reservech              chan inventoryRequest
 	unreservech            chan inventoryRequest
 	reservationCount       int64
@@ -127,6 +133,7 @@ func newInventoryService(
 		statusch:               make(chan chan<- inventoryV1.InventoryMetrics),
 		statusV1ch:             make(chan chan<- invSnapshotResp),
 		lookupch:               make(chan inventoryRequest),
+		checkch:                make(chan inventoryRequest),
 		reservech:              make(chan inventoryRequest),
 		unreservech:            make(chan inventoryRequest),
 		readych:                make(chan struct{}),
@@ -178,6 +185,23 @@ func (is *inventoryService) lookup(order mtypes.OrderID, resources dtypes.Resour
 	}
 }
 
+// checkCapacity verifies resources fit into the inventory without reserving them
+func (is *inventoryService) checkCapacity(resources dtypes.ResourceGroup) error {
+	ch := make(chan inventoryResponse, 1)
+	req := inventoryRequest{
+		resources: resources,
+		ch:        ch,
+	}
+
+	select {
+	case is.checkch <- req:
+		response := <-ch
+		return response.err
+	case <-is.lc.ShuttingDown():
+		return ErrNotRunning
+	}
+}
+
 func (is *inventoryService) reserve(order mtypes.OrderID, resources dtypes.ResourceGroup) (ctypes.Reservation, error) {
 	for idx, res := range resources.GetResourceUnits() {
 		if res.CPU == nil {
@@ -273,7 +297,7 @@ func (is *inventoryService) statusV1(ctx context.Context) (*provider.Inventory,
 	}
 }
 
-func (is *inventoryService) resourcesToCommit(rgroup dtypes.ResourceGroup) dtypes.ResourceGroup {
+func (is *inventoryService) resourcesToCommit(rgroup dtypes.ResourceGroup) (dtypes.ResourceGroup, error) {
 	replacedResources := make(dtypes.ResourceUnits, 0)
 
 	for _, resource := range rgroup.GetResourceUnits() {
@@ -294,6 +318,19 @@ func (is *inventoryService) resourcesToCommit(rgroup dtypes.ResourceGroup) dtype
 			Endpoints: resource.Resources.GetEndpoints(),
 		}
 
+		// TEE sidecar runs next to each replica, so its resources are reserved on top of requested ones
+		if profileName, enabled := ctypes.ResourcesTEEProfile(resource.Resources); enabled {
+			profile, err := is.config.TEE.Profile(profileName)
+			if err != nil {
+				return dtypes.GroupSpec{}, fmt.Errorf("%w: %w", errInventoryReservation, err)
+			}
+
+			cpu, memory := profile.Resources.Overhead()
+
+			runits.CPU.Units = atypes.NewResourceValue(runits.CPU.Units.Value() + cpu)
+			runits.Memory.Quantity = atypes.NewResourceValue(runits.Memory.Quantity.Value() + memory)
+		}
+
 		storage := make(atypes.Volumes, 0, len(resource.Resources.GetStorage()))
 
 		for _, volume := range resource.Resources.GetStorage() {
@@ -321,7 +358,7 @@ func (is *inventoryService) resourcesToCommit(rgroup dtypes.ResourceGroup) dtype
 		Resources:    replacedResources,
 	}
 
-	return result
+	return result, nil
 }
 
 func (is *inventoryService) updateInventoryMetrics(metrics inventoryV1.Metrics) {
@@ -399,9 +436,10 @@ func updateReservationMetrics(reservations []*reservation) {
 }
 
 type inventoryServiceState struct {
-	inventory    ctypes.Inventory
-	ipAddrUsage  cip.AddressUsage
-	reservations []*reservation
+	inventory       ctypes.Inventory
+	ipAddrUsage     cip.AddressUsage
+	reservations    []*reservation
+	snapshotStorage map[string]int64
 }
 
 func countPendingIPs(state *inventoryServiceState) uint {
@@ -417,7 +455,13 @@ func countPendingIPs(state *inventoryServiceState) uint {
 
 func (is *inventoryService) handleRequest(req inventoryRequest, state *inventoryServiceState) {
 	// convert the resources to the committed amount
-	resourcesToCommit := is.resourcesToCommit(req.resources)
+	resourcesToCommit, err := is.resourcesToCommit(req.resources)
+	if err != nil {
+		inventoryRequestsCounter.WithLabelValues("reserve", "invalid-resources").Inc()
+		req.ch <- inventoryResponse{err: err}
+		return
+	}
+
 	// create new registration if capacity available
 	reservation := newReservation(req.order, resourcesToCommit)
 
@@ -444,7 +488,7 @@ func (is *inventoryService) handleRequest(req inventoryRequest, state *inventory
 		reservation.ipsConfirmed = true // No IPs, just mark it as confirmed implicitly
 	}
 
-	err := state.inventory.Adjust(reservation)
+	err = state.inventory.Adjust(reservation)
 	if err != nil {
 		is.log.Info("insufficient capacity for reservation", "order", req.order)
 		inventoryRequestsCounter.WithLabelValues("reserve", "insufficient-capacity").Inc()
@@ -459,6 +503,33 @@ func (is *inventoryService) handleRequest(req inventoryRequest, state *inventory
 
 }
 
+func (is *inventoryService) handleCheck(req inventoryRequest, state *inventoryServiceState) {
+	if state.inventory == nil {
+		inventoryRequestsCounter.WithLabelValues("check", "not-available").Inc()
+		req.ch <- inventoryResponse{err: errInventoryNotAvailable}
+		return
+	}
+
+	resourcesToCommit, err := is.resourcesToCommit(req.resources)
+	if err != nil {
+		inventoryRequestsCounter.WithLabelValues("check", "invalid-resources").Inc()
+		req.ch <- inventoryResponse{err: err}
+		return
+	}
+
+	reservation := newReservation(req.order, resourcesToCommit)
+
+	err = state.inventory.Adjust(reservation, ctypes.WithDryRun())
+	if err != nil {
+		inventoryRequestsCounter.WithLabelValues("check", "insufficient-capacity").Inc()
+		req.ch <- inventoryResponse{err: err}
+		return
+	}
+
+	inventoryRequestsCounter.WithLabelValues("check", "available").Inc()
+	req.ch <- inventoryResponse{}
+}
+
 func (is *inventoryService) run(ctx context.Context, reservationsArg []*reservation) {
 	defer is.lc.ShutdownCompleted()
 	defer is.sub.Close()
@@ -480,8 +551,18 @@ func (is *inventoryService) run(ctx context.Context, reservationsArg []*reservat
 	}
 
 	var runch <-chan runner.Result
+	var snapch <-chan runner.Result
+	var snaptick <-chan time.Time
 	var currinv ctypes.Inventory
 
+	if is.config.VolumeSnapshots {
+		ticker := time.NewTicker(snapshotStoragePollPeriod)
+		defer ticker.Stop()
+
+		snaptick = ticker.C
+		snapch = is.runSnapshotStorage(rctx)
+	}
+
 	invupch := make(chan ctypes.Inventory, 1)
 
 	invch := is.clients.inventory.ResultChan()
@@ -565,8 +646,35 @@ loop:
 			}
 		case <-t.C:
 			updateIPs()
+		case <-snaptick:
+			if snapch == nil {
+				snapch = is.runSnapshotStorage(rctx)
+			}
+		case res := <-snapch:
+			snapch = nil
+
+			if err := res.Error(); err != nil {
+				is.log.Error("checking volume snapshots storage", "err", err)
+				continue
+			}
+
+			storage, _ := res.Value().(map[string]int64)
+			if maps.Equal(storage, state.snapshotStorage) {
+				continue
+			}
+
+			state.snapshotStorage = storage
+
+			if currinv != nil {
+				select {
+				case invupch <- currinv:
+				default:
+				}
+			}
 		case req := <-reservech:
 			is.handleRequest(req, state)
+		case req := <-is.checkch:
+			is.handleCheck(req, state)
 		case req := <-is.lookupch:
 			// lookup registration
 			for _, res := range state.reservations {
@@ -668,6 +776,12 @@ loop:
 				}
 			}
 
+			for class, quantity := range state.snapshotStorage {
+				if err := state.inventory.ReserveStorage(class, quantity); err != nil {
+					is.log.Error("adjust inventory for volume snapshots", "error", err.Error())
+				}
+			}
+
 			trySignal()
 		case run := <-runch:
 			runch = nil
@@ -710,6 +824,10 @@ loop:
 		<-runch
 	}
 
+	if snapch != nil {
+		<-snapch
+	}
+
 	if is.clients.ip != nil {
 		is.clients.ip.Stop()
 	}
@@ -775,6 +893,13 @@ func (is *inventoryService) runCheck(ctx context.Context, state *inventoryServic
 	})
 }
 
+// runSnapshotStorage reads storage volume snapshots of the leases take, it is not part of reservations
+func (is *inventoryService) runSnapshotStorage(ctx context.Context) <-chan runner.Result {
+	return runner.Do(func() runner.Result {
+		return runner.NewResult(is.client.SnapshotStorage(ctx))
+	})
+}
+
 func (is *inventoryService) getStatus(state *inventoryServiceState) inventoryV1.InventoryMetrics {
 	status := inventoryV1.InventoryMetrics{}
 
@@ -275,6 +275,64 @@ func TestInventory_ClusterDeploymentDeployed(t *testing.T) {
 	<-inv.lc.Done()
 }
 
+func TestInventory_VolumeSnapshotsStorage(t *testing.T) {
+	config := Config{
+		InventoryResourcePollPeriod:     time.Second,
+		InventoryResourceDebugFrequency: 1,
+		InventoryExternalPortQuantity:   1000,
+		VolumeSnapshots:                 true,
+	}
+	myLog := testutil.Logger(t)
+	bus := pubsub.NewBus()
+	defer bus.Close()
+
+	subscriber, err := bus.Subscribe()
+	require.NoError(t, err)
+
+	clusterClient := &mocks.Client{}
+	clusterClient.On("SnapshotStorage", mock.Anything).Return(map[string]int64{"beta2": 4 * unit.Gi}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = context.WithValue(ctx, fromctx.CtxKeyPubSub, tpubsub.New(ctx, 1000))
+
+	kc := kfake.NewSimpleClientset()
+	ac := afake.NewSimpleClientset()
+
+	ctx = context.WithValue(ctx, fromctx.CtxKeyKubeClientSet, kubernetes.Interface(kc))
+	ctx = context.WithValue(ctx, fromctx.CtxKeyAkashClientSet, aclient.Interface(ac))
+	ctx = context.WithValue(ctx, cfromctx.CtxKeyClientInventory, cinventory.NewNull(ctx, "nodeA"))
+
+	inv, err := newInventoryService(
+		ctx,
+		config,
+		myLog,
+		subscriber,
+		clusterClient,
+		waiter.NewNullWaiter(), // Do not need to wait in test
+		make([]ctypes.IDeployment, 0))
+	require.NoError(t, err)
+	require.NotNil(t, inv)
+
+	// null inventory has 10Gi of beta2 storage available
+	require.Eventually(t, func() bool {
+		status, err := inv.status(context.Background())
+		if err != nil {
+			return false
+		}
+
+		for _, storage := range status.Available.Storage {
+			if storage.Class == "beta2" {
+				return storage.Size == 6*unit.Gi
+			}
+		}
+
+		return false
+	}, 10*time.Second, 50*time.Millisecond)
+
+	cancel()
+	<-inv.lc.Done()
+}
+
 type inventoryScaffold struct {
 	leaseIDs []mtypes.LeaseID
 	donech   chan struct{}
@@ -29,16 +29,21 @@ type k8sPatch struct {
 	Value interface{} `json:"value"`
 }
 
+// apply helpers return created object, updated object and state of the object before update,
+// which is nil for objects that did not exist. previous state is used to roll the lease back
 func applyNS(ctx context.Context, kc kubernetes.Interface, b builder.NS) (*corev1.Namespace, *corev1.Namespace, *corev1.Namespace, error) {
 	oobj, err := kc.CoreV1().Namespaces().Get(ctx, b.Name(), metav1.GetOptions{})
 	metricsutils.IncCounterVecWithLabelValuesFiltered(kubeCallsCounter, "namespaces-get", err, errors.IsNotFound)
 
 	var nobj *corev1.Namespace
 	var uobj *corev1.Namespace
+	var pobj *corev1.Namespace
 
 	switch {
 	case err == nil:
 		curr := oobj.DeepCopy()
+		pobj = curr
+
 		oobj, err = b.Update(oobj)
 
 		if err == nil && (!b.IsObjectRevisionLatest(oobj.Labels) ||
@@ -55,7 +60,7 @@ func applyNS(ctx context.Context, kc kubernetes.Interface, b builder.NS) (*corev
 		}
 	}
 
-	return nobj, uobj, oobj, err
+	return nobj, uobj, pobj, err
 }
 
 // Apply list of Network Policies
@@ -119,10 +124,13 @@ func applyServiceCredentials(ctx context.Context, kc kubernetes.Interface, b bui
 
 	var nobj *corev1.Secret
 	var uobj *corev1.Secret
+	var pobj *corev1.Secret
 
 	switch {
 	case err == nil:
 		curr := oobj.DeepCopy()
+		pobj = curr
+
 		oobj, err = b.Update(oobj)
 		if err == nil && (!b.IsObjectRevisionLatest(curr.Labels) ||
 			!reflect.DeepEqual(&curr.Data, &oobj.Data) ||
@@ -138,7 +146,7 @@ func applyServiceCredentials(ctx context.Context, kc kubernetes.Interface, b bui
 		}
 	}
 
-	return nobj, uobj, oobj, err
+	return nobj, uobj, pobj, err
 
 }
 
@@ -148,10 +156,13 @@ func applyDeployment(ctx context.Context, kc kubernetes.Interface, b builder.Dep
 
 	var nobj *appsv1.Deployment
 	var uobj *appsv1.Deployment
+	var pobj *appsv1.Deployment
 
 	switch {
 	case err == nil:
 		curr := oobj.DeepCopy()
+		pobj = curr
+
 		oobj, err = b.Update(oobj)
 		if err != nil {
 			break
@@ -212,7 +223,7 @@ func applyDeployment(ctx context.Context, kc kubernetes.Interface, b builder.Dep
 		}
 	}
 
-	return nobj, uobj, oobj, err
+	return nobj, uobj, pobj, err
 }
 
 func applyStatefulSet(ctx context.Context, kc kubernetes.Interface, b builder.StatefulSet) (*appsv1.StatefulSet, *appsv1.StatefulSet, *appsv1.StatefulSet, error) {
@@ -221,10 +232,13 @@ func applyStatefulSet(ctx context.Context, kc kubernetes.Interface, b builder.St
 
 	var nobj *appsv1.StatefulSet
 	var uobj *appsv1.StatefulSet
+	var pobj *appsv1.StatefulSet
 
 	switch {
 	case err == nil:
 		curr := oobj.DeepCopy()
+		pobj = curr
+
 		oobj, err = b.Update(oobj)
 		if err != nil {
 			break
@@ -285,7 +299,7 @@ func applyStatefulSet(ctx context.Context, kc kubernetes.Interface, b builder.St
 		}
 	}
 
-	return nobj, uobj, oobj, err
+	return nobj, uobj, pobj, err
 }
 
 func applyService(ctx context.Context, kc kubernetes.Interface, b builder.Service) (*corev1.Service, *corev1.Service, *corev1.Service, error) {
@@ -293,10 +307,13 @@ func applyService(ctx context.Context, kc kubernetes.Interface, b builder.Servic
 	metricsutils.IncCounterVecWithLabelValuesFiltered(kubeCallsCounter, "services-get", err, errors.IsNotFound)
 	var nobj *corev1.Service
 	var uobj *corev1.Service
+	var pobj *corev1.Service
 
 	switch {
 	case err == nil:
 		curr := oobj.DeepCopy()
+		pobj = curr
+
 		oobj, err = b.Update(oobj)
 		if err == nil && (b.IsObjectRevisionLatest(curr.Labels) ||
 			!reflect.DeepEqual(&curr.Spec, &oobj.Spec) ||
@@ -312,7 +329,7 @@ func applyService(ctx context.Context, kc kubernetes.Interface, b builder.Servic
 		}
 	}
 
-	return nobj, uobj, oobj, err
+	return nobj, uobj, pobj, err
 }
 
 func applyManifest(ctx context.Context, kc crdapi.Interface, b builder.Manifest) (*crd.Manifest, *crd.Manifest, *crd.Manifest, error) {
@@ -321,10 +338,13 @@ func applyManifest(ctx context.Context, kc crdapi.Interface, b builder.Manifest)
 
 	var nobj *crd.Manifest
 	var uobj *crd.Manifest
+	var pobj *crd.Manifest
 
 	switch {
 	case err == nil:
 		curr := oobj.DeepCopy()
+		pobj = curr
+
 		oobj, err = b.Update(oobj)
 		if err == nil && (!reflect.DeepEqual(&curr.Spec, &oobj.Spec) || !reflect.DeepEqual(curr.Labels, oobj.Labels)) {
 			uobj, err = kc.AkashV2beta2().Manifests(b.NS()).Update(ctx, oobj, metav1.UpdateOptions{})
@@ -338,5 +358,67 @@ func applyManifest(ctx context.Context, kc crdapi.Interface, b builder.Manifest)
 		}
 	}
 
-	return nobj, uobj, oobj, err
+	return nobj, uobj, pobj, err
+}
+
+func applyResourceQuota(ctx context.Context, kc kubernetes.Interface, b builder.ResourceQuota) (*corev1.ResourceQuota, *corev1.ResourceQuota, *corev1.ResourceQuota, error) {
+	oobj, err := kc.CoreV1().ResourceQuotas(b.NS()).Get(ctx, b.Name(), metav1.GetOptions{})
+	metricsutils.IncCounterVecWithLabelValuesFiltered(kubeCallsCounter, "resource-quotas-get", err, errors.IsNotFound)
+
+	var nobj *corev1.ResourceQuota
+	var uobj *corev1.ResourceQuota
+	var pobj *corev1.ResourceQuota
+
+	switch {
+	case err == nil:
+		curr := oobj.DeepCopy()
+		pobj = curr
+
+		oobj, err = b.Update(oobj)
+		if err == nil && (!b.IsObjectRevisionLatest(curr.Labels) ||
+			!reflect.DeepEqual(&curr.Spec, &oobj.Spec) ||
+			!reflect.DeepEqual(curr.Labels, oobj.Labels)) {
+			uobj, err = kc.CoreV1().ResourceQuotas(b.NS()).Update(ctx, oobj, metav1.UpdateOptions{})
+			metricsutils.IncCounterVecWithLabelValues(kubeCallsCounter, "resource-quotas-update", err)
+		}
+	case errors.IsNotFound(err):
+		oobj, err = b.Create()
+		if err == nil {
+			nobj, err = kc.CoreV1().ResourceQuotas(b.NS()).Create(ctx, oobj, metav1.CreateOptions{})
+			metricsutils.IncCounterVecWithLabelValues(kubeCallsCounter, "resource-quotas-create", err)
+		}
+	}
+
+	return nobj, uobj, pobj, err
+}
+
+func applyLimitRange(ctx context.Context, kc kubernetes.Interface, b builder.LimitRange) (*corev1.LimitRange, *corev1.LimitRange, *corev1.LimitRange, error) {
+	oobj, err := kc.CoreV1().LimitRanges(b.NS()).Get(ctx, b.Name(), metav1.GetOptions{})
+	metricsutils.IncCounterVecWithLabelValuesFiltered(kubeCallsCounter, "limit-ranges-get", err, errors.IsNotFound)
+
+	var nobj *corev1.LimitRange
+	var uobj *corev1.LimitRange
+	var pobj *corev1.LimitRange
+
+	switch {
+	case err == nil:
+		curr := oobj.DeepCopy()
+		pobj = curr
+
+		oobj, err = b.Update(oobj)
+		if err == nil && (!b.IsObjectRevisionLatest(curr.Labels) ||
+			!reflect.DeepEqual(&curr.Spec, &oobj.Spec) ||
+			!reflect.DeepEqual(curr.Labels, oobj.Labels)) {
+			uobj, err = kc.CoreV1().LimitRanges(b.NS()).Update(ctx, oobj, metav1.UpdateOptions{})
+			metricsutils.IncCounterVecWithLabelValues(kubeCallsCounter, "limit-ranges-update", err)
+		}
+	case errors.IsNotFound(err):
+		oobj, err = b.Create()
+		if err == nil {
+			nobj, err = kc.CoreV1().LimitRanges(b.NS()).Create(ctx, oobj, metav1.CreateOptions{})
+			metricsutils.IncCounterVecWithLabelValues(kubeCallsCounter, "limit-ranges-create", err)
+		}
+	}
+
+	return nobj, uobj, pobj, err
 }
@@ -27,6 +27,7 @@ const (
 	AkashServiceTarget            = "akash.network/service-target"
 	AkashServiceCapabilityGPU     = "akash.network/capabilities.gpu"
 	AkashServiceCapabilityStorage = "akash.network/capabilities.storage"
+	AkashServiceCapabilityTEE     = "akash.network/capabilities.tee"
 	AkashMetalLB                  = "metal-lb"
 	akashDeploymentPolicyName     = "akash-deployment-restrictions"
 	akashNetworkNamespace         = "akash.network/namespace"
@@ -37,6 +38,11 @@ const (
 	AkashLeaseProviderLabelName   = "akash.network/lease.id.provider"
 	AkashLeaseManifestVersion     = "akash.network/manifest.version"
 	AkashLeaseUpdatedAt           = "akash.network/lease.updated_at"
+	AkashLeasePaused              = "akash.network/lease.paused"
+	AkashVolumeSnapshotLabelName  = "akash.network/volume-snapshot"
+	AkashVolumeLabelName          = "akash.network/volume"
+	AkashStorageClassLabelName    = "akash.network/storageclass"
+	AkashVolumeSize               = "akash.network/volume.size"
 	AkashManifestResourceVersion  = "akash.network/manifest.resource.version"
 )
 
@@ -76,6 +82,9 @@ type IClusterDeployment interface {
 	ClusterParams() crd.ClusterSettings
 	SetResourceVersion(string)
 	GetResourceVersion() string
+	// SetPaused makes workloads of the lease to be built with no replicas
+	SetPaused(bool)
+	Paused() bool
 }
 
 type ClusterDeployment struct {
@@ -84,6 +93,7 @@ type ClusterDeployment struct {
 	Sparams         crd.ClusterSettings
 	resourceVersion string
 	updateManifest  bool
+	paused          bool
 }
 
 var _ IClusterDeployment = (*ClusterDeployment)(nil)
@@ -165,6 +175,14 @@ func (d *ClusterDeployment) GetResourceVersion() string {
 	return d.resourceVersion
 }
 
+func (d *ClusterDeployment) SetPaused(val bool) {
+	d.paused = val
+}
+
+func (d *ClusterDeployment) Paused() bool {
+	return d.paused
+}
+
 func (d *ClusterDeployment) UpdateManifest() bool {
 	return d.updateManifest
 }
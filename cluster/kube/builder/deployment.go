@@ -29,65 +29,82 @@ func NewDeployment(workload Workload) Deployment {
 	return ss
 }
 
-func (b *deployment) Create() (*appsv1.Deployment, error) {
-    falseValue := false
-    revisionHistoryLimit := int32(10)
-    maxSurge := intstr.FromInt32(0)
-    maxUnavailable := intstr.FromInt32(1)
-
-    kdeployment := &appsv1.Deployment{
-        ObjectMeta: metav1.ObjectMeta{
-            Name:   b.Name(),
-            Labels: b.labels(),
-        },
-        Spec: appsv1.DeploymentSpec{
-            Selector: &metav1.LabelSelector{
-                MatchLabels: b.selectorLabels(),
-            },
-            Strategy: appsv1.DeploymentStrategy{
-                Type: appsv1.RollingUpdateDeploymentStrategyType,
-                RollingUpdate: &appsv1.RollingUpdateDeployment{
-                    MaxUnavailable: &maxUnavailable,
-                    MaxSurge:       &maxSurge,
-                },
-            },
-            RevisionHistoryLimit: &revisionHistoryLimit,
-            Replicas:             b.replicas(),
-            Template: corev1.PodTemplateSpec{
-                ObjectMeta: metav1.ObjectMeta{
-                    Labels: b.labels(),
-                },
-                Spec: corev1.PodSpec{
-                    Affinity:         b.affinity(),
-                    RuntimeClassName: b.runtimeClass(),
-                    SecurityContext: &corev1.PodSecurityContext{
-                        RunAsNonRoot: &falseValue,
-                    },
-                    AutomountServiceAccountToken: &falseValue,
-                    // Before: Containers: []corev1.Container{ b.container() },
-                    // Now, so we can add sidecar (doing slice of containers):
-                    Containers:       b.containers(),
-                    ImagePullSecrets: b.secretsRefs,
-                    Volumes:         b.volumesObjs,
-                },
-            },
-        },
-    }
-
-    return kdeployment, nil
+func (b *deployment) Create() (*appsv1.Deployment, error) { // nolint:golint,unparam
+	falseValue := false
+	revisionHistoryLimit := int32(10)
+	maxSurge := intstr.FromInt32(0)
+	maxUnavailable := intstr.FromInt32(1)
+
+	containers, err := b.containers()
+	if err != nil {
+		return nil, err
+	}
+
+	volumes, err := b.volumes()
+	if err != nil {
+		return nil, err
+	}
+
+	kdeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   b.Name(),
+			Labels: b.labels(),
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: b.selectorLabels(),
+			},
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDeployment{
+					MaxUnavailable: &maxUnavailable,
+					MaxSurge:       &maxSurge,
+				},
+			},
+			RevisionHistoryLimit: &revisionHistoryLimit,
+			Replicas:             b.replicas(),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: b.labels(),
+				},
+				Spec: corev1.PodSpec{
+					Affinity:         b.affinity(),
+					RuntimeClassName: b.runtimeClass(),
+					SecurityContext: &corev1.PodSecurityContext{
+						RunAsNonRoot: &falseValue,
+					},
+					AutomountServiceAccountToken: &falseValue,
+					Containers:                   containers,
+					ImagePullSecrets:             b.secretsRefs,
+					Volumes:                      volumes,
+				},
+			},
+		},
+	}
+
+	return kdeployment, nil
 }
 
 func (b *deployment) Update(obj *appsv1.Deployment) (*appsv1.Deployment, error) { // nolint:golint,unparam
+	containers, err := b.containers()
+	if err != nil {
+		return nil, err
+	}
+
+	volumes, err := b.volumes()
+	if err != nil {
+		return nil, err
+	}
+
 	obj.Labels = updateAkashLabels(obj.Labels, b.labels())
 	obj.Spec.Selector.MatchLabels = b.selectorLabels()
 	obj.Spec.Replicas = b.replicas()
 	obj.Spec.Template.Labels = b.labels()
 	obj.Spec.Template.Spec.Affinity = b.affinity()
 	obj.Spec.Template.Spec.RuntimeClassName = b.runtimeClass()
-    // Before: obj.Spec.Template.Spec.Containers = []corev1.Container{ b.container() }
-    // Now:
-    obj.Spec.Template.Spec.Containers = b.containers()
+	obj.Spec.Template.Spec.Containers = containers
 	obj.Spec.Template.Spec.ImagePullSecrets = b.imagePullSecrets()
+	obj.Spec.Template.Spec.Volumes = volumes
 
 	return obj, nil
 }
@@ -5,10 +5,14 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	atypes "github.com/akash-network/akash-api/go/node/types/v1beta3"
 
 	"github.com/akash-network/node/sdl"
 	"github.com/akash-network/node/testutil"
 
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
 )
 
@@ -72,3 +76,164 @@ func TestDeploySetsEnvironmentVariables(t *testing.T) {
 	require.True(t, ok)
 	require.Equal(t, lid.Provider, value)
 }
+
+func TestDeployTEESidecarFromProfile(t *testing.T) {
+	log := testutil.Logger(t)
+	lid := testutil.LeaseID(t)
+	sdl, err := sdl.ReadFile("../../../testdata/deployment/deployment.yaml")
+	require.NoError(t, err)
+
+	mani, err := sdl.Manifest()
+	require.NoError(t, err)
+
+	group := mani.GetGroups()[0]
+	group.Services[0].Resources.CPU.Attributes = atypes.Attributes{
+		{Key: ctypes.TEEAttributeKey, Value: "hardened"},
+	}
+
+	sparams := make([]*crd.SchedulerParams, len(group.Services))
+
+	cdep := &ClusterDeployment{
+		Lid:     lid,
+		Group:   &group,
+		Sparams: crd.ClusterSettings{SchedulerParams: sparams},
+	}
+
+	runAsUser := int64(1000)
+
+	settings := NewDefaultSettings()
+	settings.TEE = ctypes.TEEConfig{
+		DefaultProfile: "default",
+		Profiles: map[string]ctypes.TEESidecarProfile{
+			"default": ctypes.DefaultTEESidecarProfile(),
+			"hardened": {
+				Image:      "tee-api:v2.0.0",
+				PullPolicy: "Always",
+				Resources: ctypes.TEESidecarResources{
+					CPU:    "100m",
+					Memory: "64Mi",
+				},
+				Devices: []string{"/dev/tpmrm0"},
+				SecurityContext: ctypes.TEESecurityContext{
+					RunAsUser:        &runAsUser,
+					CapabilitiesDrop: []string{"ALL"},
+				},
+			},
+		},
+	}
+	require.NoError(t, ValidateSettings(settings))
+
+	kdeployment, err := NewDeployment(NewWorkloadBuilder(log, settings, cdep, 0)).Create()
+	require.NoError(t, err)
+
+	containers := kdeployment.Spec.Template.Spec.Containers
+	require.Len(t, containers, 2)
+
+	sidecar := containers[1]
+	require.Equal(t, ctypes.TEESidecarContainerName, sidecar.Name)
+	require.Equal(t, "tee-api:v2.0.0", sidecar.Image)
+	require.Equal(t, corev1.PullAlways, sidecar.ImagePullPolicy)
+	require.False(t, *sidecar.SecurityContext.Privileged)
+	require.Equal(t, runAsUser, *sidecar.SecurityContext.RunAsUser)
+	require.Equal(t, "100m", sidecar.Resources.Requests.Cpu().String())
+	require.Equal(t, "64Mi", sidecar.Resources.Limits.Memory().String())
+	require.Len(t, sidecar.VolumeMounts, 1)
+
+	found := false
+	for _, vol := range kdeployment.Spec.Template.Spec.Volumes {
+		if vol.Name == sidecar.VolumeMounts[0].Name {
+			found = true
+			require.NotNil(t, vol.HostPath)
+			require.Equal(t, "/dev/tpmrm0", vol.HostPath.Path)
+		}
+	}
+	require.True(t, found)
+
+	// pods must be pinned onto nodes exposing devices profile requires
+	found = false
+	terms := kdeployment.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	for _, expr := range terms[0].MatchExpressions {
+		if expr.Key == TEEDeviceLabel("/dev/tpmrm0") {
+			found = true
+			require.Equal(t, corev1.NodeSelectorOpGt, expr.Operator)
+		}
+	}
+	require.True(t, found)
+
+	group.Services[0].Resources.CPU.Attributes[0].Value = "unknown"
+	_, err = NewDeployment(NewWorkloadBuilder(log, settings, cdep, 0)).Create()
+	require.ErrorIs(t, err, ctypes.ErrTEEProfileUnknown)
+
+	// pod must not be built without devices of the profile either
+	wl := NewWorkloadBuilder(log, settings, cdep, 0)
+	_, err = wl.volumes()
+	require.ErrorIs(t, err, ctypes.ErrTEEProfileUnknown)
+}
+
+func TestDeployHealthChecks(t *testing.T) {
+	log := testutil.Logger(t)
+	lid := testutil.LeaseID(t)
+	sdl, err := sdl.ReadFile("../../../testdata/deployment/deployment.yaml")
+	require.NoError(t, err)
+
+	mani, err := sdl.Manifest()
+	require.NoError(t, err)
+
+	group := mani.GetGroups()[0]
+	sparams := make([]*crd.SchedulerParams, len(group.Services))
+
+	cdep := &ClusterDeployment{
+		Lid:     lid,
+		Group:   &group,
+		Sparams: crd.ClusterSettings{SchedulerParams: sparams},
+	}
+
+	settings := NewDefaultSettings()
+
+	// no probes are configured by default
+	kdeployment, err := NewDeployment(NewWorkloadBuilder(log, settings, cdep, 0)).Create()
+	require.NoError(t, err)
+	require.Nil(t, kdeployment.Spec.Template.Spec.Containers[0].LivenessProbe)
+	require.Nil(t, kdeployment.Spec.Template.Spec.Containers[0].ReadinessProbe)
+
+	settings.HealthChecks = ctypes.HealthCheckConfig{
+		Default: ctypes.HealthChecks{
+			Readiness: &ctypes.HealthCheck{
+				TCP:           &ctypes.TCPHealthCheck{},
+				PeriodSeconds: 5,
+			},
+		},
+	}
+	require.NoError(t, ValidateSettings(settings))
+
+	kdeployment, err = NewDeployment(NewWorkloadBuilder(log, settings, cdep, 0)).Create()
+	require.NoError(t, err)
+
+	container := kdeployment.Spec.Template.Spec.Containers[0]
+	require.Nil(t, container.LivenessProbe)
+	require.NotNil(t, container.ReadinessProbe)
+	require.NotNil(t, container.ReadinessProbe.TCPSocket)
+	require.Equal(t, int32(80), container.ReadinessProbe.TCPSocket.Port.IntVal)
+	require.Equal(t, int32(5), container.ReadinessProbe.PeriodSeconds)
+
+	// probes of the service override provider defaults
+	group.Services[0].Env = append(group.Services[0].Env,
+		`DOOOR_HEALTHCHECK={"liveness":{"http":{"path":"/healthz","port":8080},"failure_threshold":3},"startup":{"exec":{"command":["pg_isready"]}}}`)
+
+	kdeployment, err = NewDeployment(NewWorkloadBuilder(log, settings, cdep, 0)).Create()
+	require.NoError(t, err)
+
+	container = kdeployment.Spec.Template.Spec.Containers[0]
+	require.Nil(t, container.ReadinessProbe)
+	require.NotNil(t, container.LivenessProbe)
+	require.Equal(t, "/healthz", container.LivenessProbe.HTTPGet.Path)
+	require.Equal(t, int32(8080), container.LivenessProbe.HTTPGet.Port.IntVal)
+	require.Equal(t, int32(3), container.LivenessProbe.FailureThreshold)
+	require.NotNil(t, container.StartupProbe)
+	require.Equal(t, []string{"pg_isready"}, container.StartupProbe.Exec.Command)
+
+	group.Services[0].Env[len(group.Services[0].Env)-1] = `DOOOR_HEALTHCHECK={"liveness":{"http":{"path":"/"},"tcp":{}}}`
+
+	_, err = NewDeployment(NewWorkloadBuilder(log, settings, cdep, 0)).Create()
+	require.ErrorIs(t, err, ctypes.ErrHealthCheckConfig)
+}
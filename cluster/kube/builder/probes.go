@@ -0,0 +1,121 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+var (
+	errProbeNoPort = errors.New("port is not set and service exposes no ports")
+)
+
+// healthChecks returns probes of the service. probes service defines take precedence over provider defaults
+func (b *Workload) healthChecks() (ctypes.HealthChecks, error) {
+	service := &b.deployment.ManifestGroup().Services[b.serviceIdx]
+
+	checks, found, err := ctypes.HealthChecksFromEnv(service.Env)
+	if err != nil {
+		return ctypes.HealthChecks{}, fmt.Errorf("%w: service %s: %w", ErrKubeBuilder, service.Name, err)
+	}
+
+	if found {
+		return checks, nil
+	}
+
+	return b.settings.HealthChecks.ForPorts(b.exposedPorts()), nil
+}
+
+func (b *Workload) exposedPorts() []uint32 {
+	service := &b.deployment.ManifestGroup().Services[b.serviceIdx]
+
+	ports := make([]uint32, 0, len(service.Expose))
+	for _, expose := range service.Expose {
+		ports = append(ports, expose.Port)
+	}
+
+	return ports
+}
+
+// applyProbes sets liveness, readiness and startup probes of the service container
+func (b *Workload) applyProbes(kcontainer *corev1.Container) error {
+	checks, err := b.healthChecks()
+	if err != nil || checks.Empty() {
+		return err
+	}
+
+	ports := b.exposedPorts()
+
+	if kcontainer.LivenessProbe, err = makeProbe(checks.Liveness, ports); err != nil {
+		return fmt.Errorf("%w: service %s: liveness probe: %w", ErrKubeBuilder, kcontainer.Name, err)
+	}
+
+	if kcontainer.ReadinessProbe, err = makeProbe(checks.Readiness, ports); err != nil {
+		return fmt.Errorf("%w: service %s: readiness probe: %w", ErrKubeBuilder, kcontainer.Name, err)
+	}
+
+	if kcontainer.StartupProbe, err = makeProbe(checks.Startup, ports); err != nil {
+		return fmt.Errorf("%w: service %s: startup probe: %w", ErrKubeBuilder, kcontainer.Name, err)
+	}
+
+	return nil
+}
+
+func makeProbe(check *ctypes.HealthCheck, ports []uint32) (*corev1.Probe, error) {
+	if check == nil {
+		return nil, nil
+	}
+
+	probePort := func(port uint32) (intstr.IntOrString, error) {
+		if port == 0 {
+			if len(ports) == 0 {
+				return intstr.IntOrString{}, errProbeNoPort
+			}
+
+			port = ports[0]
+		}
+
+		return intstr.FromInt32(int32(port)), nil // nolint: gosec
+	}
+
+	probe := &corev1.Probe{
+		InitialDelaySeconds: check.InitialDelaySeconds,
+		PeriodSeconds:       check.PeriodSeconds,
+		TimeoutSeconds:      check.TimeoutSeconds,
+		SuccessThreshold:    check.SuccessThreshold,
+		FailureThreshold:    check.FailureThreshold,
+	}
+
+	switch {
+	case check.HTTP != nil:
+		port, err := probePort(check.HTTP.Port)
+		if err != nil {
+			return nil, err
+		}
+
+		probe.HTTPGet = &corev1.HTTPGetAction{
+			Path:   check.HTTP.Path,
+			Port:   port,
+			Scheme: corev1.URISchemeHTTP,
+		}
+	case check.TCP != nil:
+		port, err := probePort(check.TCP.Port)
+		if err != nil {
+			return nil, err
+		}
+
+		probe.TCPSocket = &corev1.TCPSocketAction{
+			Port: port,
+		}
+	case check.Exec != nil:
+		probe.Exec = &corev1.ExecAction{
+			Command: check.Exec.Command,
+		}
+	}
+
+	return probe, nil
+}
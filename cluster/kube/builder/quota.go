@@ -0,0 +1,271 @@
+package builder
+
+import (
+	"github.com/tendermint/tendermint/libs/log"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	AkashLeaseQuotaName      = "akash-lease-quota"
+	AkashLeaseLimitRangeName = "akash-lease-limits"
+)
+
+// computeResources are restricted by quota only if every container of the lease sets them,
+// otherwise kubernetes would reject pods of containers which do not
+var computeResources = []corev1.ResourceName{
+	corev1.ResourceCPU,
+	corev1.ResourceMemory,
+	corev1.ResourceEphemeralStorage,
+}
+
+type ResourceQuota interface {
+	builderBase
+	Create() (*corev1.ResourceQuota, error)
+	Update(obj *corev1.ResourceQuota) (*corev1.ResourceQuota, error)
+}
+
+type LimitRange interface {
+	builderBase
+	Create() (*corev1.LimitRange, error)
+	Update(obj *corev1.LimitRange) (*corev1.LimitRange, error)
+}
+
+type resourceQuota struct {
+	builder
+}
+
+type limitRange struct {
+	builder
+}
+
+var _ ResourceQuota = (*resourceQuota)(nil)
+var _ LimitRange = (*limitRange)(nil)
+
+// BuildResourceQuota limits total of resources objects in the lease namespace can use to resources allocated to the lease
+func BuildResourceQuota(log log.Logger, settings Settings, deployment IClusterDeployment) ResourceQuota {
+	return &resourceQuota{builder: builder{log: log, settings: settings, deployment: deployment}}
+}
+
+// BuildLimitRange limits resources of every single container and volume in the lease namespace
+// to the largest ones allocated to the lease
+func BuildLimitRange(log log.Logger, settings Settings, deployment IClusterDeployment) LimitRange {
+	return &limitRange{builder: builder{log: log, settings: settings, deployment: deployment}}
+}
+
+// leaseAllocation is resources allocated to the lease as kubernetes sees them,
+// including resources of sidecars provider adds to service pods
+type leaseAllocation struct {
+	// total is sum of limits of all containers and requests of all persistent volumes of the lease
+	total corev1.ResourceList
+	// container is the largest compute resources single container of the lease is allowed
+	container corev1.ResourceList
+	// volume is the largest single persistent volume of the lease
+	volume corev1.ResourceList
+	pods   int64
+	pvcs   int64
+}
+
+func (b *builder) allocation() (leaseAllocation, error) {
+	res := leaseAllocation{
+		total:     make(corev1.ResourceList),
+		container: make(corev1.ResourceList),
+		volume:    make(corev1.ResourceList),
+	}
+
+	unset := make(map[corev1.ResourceName]bool)
+
+	group := b.deployment.ManifestGroup()
+
+	for idx := range group.Services {
+		count := int64(group.Services[idx].Count)
+
+		workload := NewWorkloadBuilder(b.log, b.settings, b.deployment, idx)
+
+		ctrs, err := workload.containers()
+		if err != nil {
+			return leaseAllocation{}, err
+		}
+
+		res.pods += count
+
+		for _, ctr := range ctrs {
+			for _, name := range computeResources {
+				val, exists := ctr.Resources.Limits[name]
+				if !exists {
+					unset[name] = true
+					continue
+				}
+
+				maxQuantity(res.container, name, val)
+			}
+
+			for name, val := range ctr.Resources.Limits {
+				addQuantity(res.total, name, val, count)
+			}
+		}
+
+		for _, pvc := range workload.pvcsObjs {
+			val := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+
+			addQuantity(res.total, corev1.ResourceStorage, val, count)
+			maxQuantity(res.volume, corev1.ResourceStorage, val)
+
+			res.pvcs += count
+		}
+	}
+
+	for name := range unset {
+		delete(res.total, name)
+		delete(res.container, name)
+	}
+
+	return res, nil
+}
+
+func addQuantity(list corev1.ResourceList, name corev1.ResourceName, val resource.Quantity, count int64) {
+	val = val.DeepCopy()
+	val.Mul(count)
+
+	curr, exists := list[name]
+	if !exists {
+		list[name] = val
+		return
+	}
+
+	curr.Add(val)
+	list[name] = curr
+}
+
+func maxQuantity(list corev1.ResourceList, name corev1.ResourceName, val resource.Quantity) {
+	if curr, exists := list[name]; !exists || curr.Cmp(val) < 0 {
+		list[name] = val.DeepCopy()
+	}
+}
+
+func (b *resourceQuota) Name() string {
+	return AkashLeaseQuotaName
+}
+
+func (b *resourceQuota) labels() map[string]string {
+	return AppendLeaseLabels(b.deployment.LeaseID(), b.builder.labels())
+}
+
+func (b *resourceQuota) hard() (corev1.ResourceList, error) {
+	alloc, err := b.allocation()
+	if err != nil {
+		return nil, err
+	}
+
+	hard := corev1.ResourceList{
+		corev1.ResourcePods:                   *resource.NewQuantity(alloc.pods, resource.DecimalSI),
+		corev1.ResourcePersistentVolumeClaims: *resource.NewQuantity(alloc.pvcs, resource.DecimalSI),
+	}
+
+	for name, val := range alloc.total {
+		switch name {
+		case corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourceEphemeralStorage:
+			hard[corev1.ResourceName("requests."+name)] = val.DeepCopy()
+			hard[corev1.ResourceName("limits."+name)] = val.DeepCopy()
+		default:
+			// storage and extended resources such as GPUs can only be restricted by requests
+			hard[corev1.ResourceName("requests."+name)] = val.DeepCopy()
+		}
+	}
+
+	return hard, nil
+}
+
+func (b *resourceQuota) Create() (*corev1.ResourceQuota, error) {
+	hard, err := b.hard()
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      b.Name(),
+			Namespace: b.NS(),
+			Labels:    b.labels(),
+		},
+		Spec: corev1.ResourceQuotaSpec{
+			Hard: hard,
+		},
+	}, nil
+}
+
+func (b *resourceQuota) Update(obj *corev1.ResourceQuota) (*corev1.ResourceQuota, error) {
+	hard, err := b.hard()
+	if err != nil {
+		return nil, err
+	}
+
+	obj.Labels = updateAkashLabels(obj.Labels, b.labels())
+	obj.Spec.Hard = hard
+
+	return obj, nil
+}
+
+func (b *limitRange) Name() string {
+	return AkashLeaseLimitRangeName
+}
+
+func (b *limitRange) labels() map[string]string {
+	return AppendLeaseLabels(b.deployment.LeaseID(), b.builder.labels())
+}
+
+func (b *limitRange) limits() ([]corev1.LimitRangeItem, error) {
+	alloc, err := b.allocation()
+	if err != nil {
+		return nil, err
+	}
+
+	var items []corev1.LimitRangeItem
+
+	if len(alloc.container) != 0 {
+		items = append(items, corev1.LimitRangeItem{
+			Type: corev1.LimitTypeContainer,
+			Max:  alloc.container,
+		})
+	}
+
+	if len(alloc.volume) != 0 {
+		items = append(items, corev1.LimitRangeItem{
+			Type: corev1.LimitTypePersistentVolumeClaim,
+			Max:  alloc.volume,
+		})
+	}
+
+	return items, nil
+}
+
+func (b *limitRange) Create() (*corev1.LimitRange, error) {
+	items, err := b.limits()
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      b.Name(),
+			Namespace: b.NS(),
+			Labels:    b.labels(),
+		},
+		Spec: corev1.LimitRangeSpec{
+			Limits: items,
+		},
+	}, nil
+}
+
+func (b *limitRange) Update(obj *corev1.LimitRange) (*corev1.LimitRange, error) {
+	items, err := b.limits()
+	if err != nil {
+		return nil, err
+	}
+
+	obj.Labels = updateAkashLabels(obj.Labels, b.labels())
+	obj.Spec.Limits = items
+
+	return obj, nil
+}
@@ -0,0 +1,152 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	atypes "github.com/akash-network/akash-api/go/node/types/v1beta3"
+
+	"github.com/akash-network/node/sdl"
+	"github.com/akash-network/node/testutil"
+
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
+)
+
+func quotaTestDeployment(t *testing.T, path string) *ClusterDeployment {
+	t.Helper()
+
+	sdl, err := sdl.ReadFile(path)
+	require.NoError(t, err)
+
+	mani, err := sdl.Manifest()
+	require.NoError(t, err)
+
+	group := mani.GetGroups()[0]
+
+	return &ClusterDeployment{
+		Lid:     testutil.LeaseID(t),
+		Group:   &group,
+		Sparams: crd.ClusterSettings{SchedulerParams: make([]*crd.SchedulerParams, len(group.Services))},
+	}
+}
+
+func requireQuantity(t *testing.T, expected string, list corev1.ResourceList, name corev1.ResourceName) {
+	t.Helper()
+
+	val, exists := list[name]
+	require.True(t, exists, "resource %s", name)
+	expectedVal := resource.MustParse(expected)
+	require.Zero(t, expectedVal.Cmp(val), "resource %s: expected %s, got %s", name, expected, val.String())
+}
+
+func TestResourceQuotaFromAllocation(t *testing.T) {
+	log := testutil.Logger(t)
+
+	cdep := quotaTestDeployment(t, "../../../testdata/deployment/deployment-v2-storage-default.yaml")
+	for idx := range cdep.Group.Services {
+		if cdep.Group.Services[idx].Name == "web" {
+			cdep.Group.Services[idx].Count = 2
+		}
+	}
+
+	quota, err := BuildResourceQuota(log, NewDefaultSettings(), cdep).Create()
+	require.NoError(t, err)
+
+	require.Equal(t, AkashLeaseQuotaName, quota.Name)
+	require.Equal(t, LidNS(cdep.Lid), quota.Namespace)
+	require.Equal(t, "true", quota.Labels[AkashManagedLabelName])
+	require.Equal(t, cdep.Lid.Owner, quota.Labels[AkashLeaseOwnerLabelName])
+
+	hard := quota.Spec.Hard
+	requireQuantity(t, "3", hard, corev1.ResourcePods)
+	requireQuantity(t, "2", hard, corev1.ResourcePersistentVolumeClaims)
+	requireQuantity(t, "30m", hard, corev1.ResourceRequestsCPU)
+	requireQuantity(t, "30m", hard, corev1.ResourceLimitsCPU)
+	requireQuantity(t, "384Mi", hard, corev1.ResourceRequestsMemory)
+	requireQuantity(t, "384Mi", hard, corev1.ResourceLimitsMemory)
+	requireQuantity(t, "1536Mi", hard, corev1.ResourceRequestsEphemeralStorage)
+	requireQuantity(t, "1536Mi", hard, corev1.ResourceLimitsEphemeralStorage)
+	requireQuantity(t, "256Mi", hard, corev1.ResourceRequestsStorage)
+
+	limits, err := BuildLimitRange(log, NewDefaultSettings(), cdep).Create()
+	require.NoError(t, err)
+
+	require.Equal(t, AkashLeaseLimitRangeName, limits.Name)
+	require.Len(t, limits.Spec.Limits, 2)
+
+	require.Equal(t, corev1.LimitTypeContainer, limits.Spec.Limits[0].Type)
+	requireQuantity(t, "10m", limits.Spec.Limits[0].Max, corev1.ResourceCPU)
+	requireQuantity(t, "128Mi", limits.Spec.Limits[0].Max, corev1.ResourceMemory)
+	requireQuantity(t, "512Mi", limits.Spec.Limits[0].Max, corev1.ResourceEphemeralStorage)
+
+	require.Equal(t, corev1.LimitTypePersistentVolumeClaim, limits.Spec.Limits[1].Type)
+	requireQuantity(t, "128Mi", limits.Spec.Limits[1].Max, corev1.ResourceStorage)
+}
+
+func TestResourceQuotaIncludesTEESidecar(t *testing.T) {
+	log := testutil.Logger(t)
+
+	cdep := quotaTestDeployment(t, "../../../testdata/deployment/deployment.yaml")
+	cdep.Group.Services[0].Resources.CPU.Attributes = atypes.Attributes{
+		{Key: ctypes.TEEAttributeKey, Value: "true"},
+	}
+
+	settings := NewDefaultSettings()
+	settings.TEE = ctypes.TEEConfig{
+		DefaultProfile: "default",
+		Profiles: map[string]ctypes.TEESidecarProfile{
+			"default": {
+				Image: "tee-api:v1.0.0",
+				Resources: ctypes.TEESidecarResources{
+					CPU:    "100m",
+					Memory: "64Mi",
+				},
+			},
+		},
+	}
+	require.NoError(t, ValidateSettings(settings))
+
+	quota, err := BuildResourceQuota(log, settings, cdep).Create()
+	require.NoError(t, err)
+
+	hard := quota.Spec.Hard
+	requireQuantity(t, "1", hard, corev1.ResourcePods)
+	requireQuantity(t, "110m", hard, corev1.ResourceLimitsCPU)
+	requireQuantity(t, "192Mi", hard, corev1.ResourceLimitsMemory)
+
+	// sidecar does not set ephemeral storage, kubernetes would reject it were ephemeral storage restricted
+	require.NotContains(t, hard, corev1.ResourceRequestsEphemeralStorage)
+	require.NotContains(t, hard, corev1.ResourceLimitsEphemeralStorage)
+
+	limits, err := BuildLimitRange(log, settings, cdep).Create()
+	require.NoError(t, err)
+	require.Len(t, limits.Spec.Limits, 1)
+	requireQuantity(t, "100m", limits.Spec.Limits[0].Max, corev1.ResourceCPU)
+	requireQuantity(t, "128Mi", limits.Spec.Limits[0].Max, corev1.ResourceMemory)
+	require.NotContains(t, limits.Spec.Limits[0].Max, corev1.ResourceEphemeralStorage)
+}
+
+func TestResourceQuotaUpdate(t *testing.T) {
+	log := testutil.Logger(t)
+
+	cdep := quotaTestDeployment(t, "../../../testdata/deployment/deployment.yaml")
+	cdep.SetResourceVersion("2")
+
+	obj := &corev1.ResourceQuota{}
+	obj.Labels = map[string]string{"custom": "label", AkashManifestResourceVersion: "1"}
+	obj.Spec.Hard = corev1.ResourceList{
+		corev1.ResourcePods: resource.MustParse("10"),
+	}
+
+	obj, err := BuildResourceQuota(log, NewDefaultSettings(), cdep).Update(obj)
+	require.NoError(t, err)
+
+	require.Equal(t, "label", obj.Labels["custom"])
+	require.Equal(t, "2", obj.Labels[AkashManifestResourceVersion])
+	requireQuantity(t, "1", obj.Spec.Hard, corev1.ResourcePods)
+	requireQuantity(t, "10m", obj.Spec.Hard, corev1.ResourceLimitsCPU)
+}
@@ -2,11 +2,16 @@ package builder
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	vutil "github.com/akash-network/node/util/validation"
+
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
 )
 
 // Settings configures k8s object generation such that it is customized to the
@@ -43,6 +48,26 @@ type Settings struct {
 
 	// Name of the image pull secret to use in pod spec
 	DockerImagePullSecretsName string
+
+	// TEE sidecar profiles available to services requesting TEE
+	TEE ctypes.TEEConfig
+
+	// HealthChecks are default probes of services which do not define their own
+	HealthChecks ctypes.HealthCheckConfig
+
+	// DeploymentStagedUpdate makes manifest updates wait for workloads of the new revision to become ready
+	// and roll every object of the lease back to the previous revision if they do not
+	DeploymentStagedUpdate bool
+	// DeploymentRolloutTimeout is how long staged update waits for workloads to become ready
+	DeploymentRolloutTimeout time.Duration
+
+	// VolumeSnapshotClass is the CSI VolumeSnapshotClass snapshots of lease volumes are taken with.
+	// empty disables volume snapshots
+	VolumeSnapshotClass string
+
+	// LogSplitStreams streams stdout and stderr of containers separately.
+	// requires PodLogsQuerySplitStreams feature gate to be enabled in the cluster
+	LogSplitStreams bool
 }
 
 var ErrSettingsValidation = errors.New("settings validation")
@@ -58,6 +83,24 @@ func ValidateSettings(settings Settings) error {
 		}
 	}
 
+	if err := settings.TEE.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrSettingsValidation, err)
+	}
+
+	if err := settings.HealthChecks.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrSettingsValidation, err)
+	}
+
+	if settings.DeploymentStagedUpdate && settings.DeploymentRolloutTimeout <= 0 {
+		return fmt.Errorf("%w: rollout timeout must be positive for staged update", ErrSettingsValidation)
+	}
+
+	if settings.VolumeSnapshotClass != "" {
+		if errs := validation.IsDNS1123Subdomain(settings.VolumeSnapshotClass); len(errs) != 0 {
+			return fmt.Errorf("%w: invalid volume snapshot class %q: %s", ErrSettingsValidation, settings.VolumeSnapshotClass, strings.Join(errs, ", "))
+		}
+	}
+
 	return nil
 }
 
@@ -67,6 +110,8 @@ func NewDefaultSettings() Settings {
 		DeploymentIngressStaticHosts:   false,
 		DeploymentIngressExposeLBHosts: false,
 		NetworkPoliciesEnabled:         false,
+		TEE:                            ctypes.NewDefaultTEEConfig(),
+		DeploymentRolloutTimeout:       5 * time.Minute,
 	}
 }
 
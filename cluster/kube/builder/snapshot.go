@@ -0,0 +1,37 @@
+package builder
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+const (
+	VolumeSnapshotAPIGroup = "snapshot.storage.k8s.io"
+	VolumeSnapshotKind     = "VolumeSnapshot"
+)
+
+// volumeRestores returns snapshots persistent volumes of the service are to be restored from, by volume name
+func (b *Workload) volumeRestores() (map[string]string, error) {
+	service := &b.deployment.ManifestGroup().Services[b.serviceIdx]
+
+	restores, err := ctypes.ResourcesSnapshotRestores(service.Env, service.Resources)
+	if err != nil {
+		return nil, fmt.Errorf("%w: service %s: %w", ErrKubeBuilder, service.Name, err)
+	}
+
+	return restores, nil
+}
+
+// VolumeSnapshotDataSource returns data source of the claim restoring volume from the snapshot
+func VolumeSnapshotDataSource(snapshot string) *corev1.TypedLocalObjectReference {
+	group := VolumeSnapshotAPIGroup
+
+	return &corev1.TypedLocalObjectReference{
+		APIGroup: &group,
+		Kind:     VolumeSnapshotKind,
+		Name:     snapshot,
+	}
+}
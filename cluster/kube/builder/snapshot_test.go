@@ -0,0 +1,59 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/akash-network/node/testutil"
+
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+func TestStatefulSetRestoresVolumeFromSnapshot(t *testing.T) {
+	log := testutil.Logger(t)
+
+	cdep := quotaTestDeployment(t, "../../../testdata/deployment/deployment-v2-storage-default.yaml")
+
+	svcIdx := -1
+	for idx := range cdep.Group.Services {
+		if cdep.Group.Services[idx].Name == "web" {
+			svcIdx = idx
+		}
+	}
+	require.NotEqual(t, -1, svcIdx)
+
+	service := &cdep.Group.Services[svcIdx]
+	settings := NewDefaultSettings()
+
+	sset, err := BuildStatefulSet(NewWorkloadBuilder(log, settings, cdep, svcIdx)).Create()
+	require.NoError(t, err)
+	require.Len(t, sset.Spec.VolumeClaimTemplates, 1)
+	require.Nil(t, sset.Spec.VolumeClaimTemplates[0].Spec.DataSource)
+
+	service.Env = append(service.Env, `DOOOR_RESTORE_SNAPSHOTS={"data":"nightly-web-data-web-0"}`)
+
+	restored, err := BuildStatefulSet(NewWorkloadBuilder(log, settings, cdep, svcIdx)).Create()
+	require.NoError(t, err)
+
+	source := restored.Spec.VolumeClaimTemplates[0].Spec.DataSource
+	require.NotNil(t, source)
+	require.Equal(t, VolumeSnapshotAPIGroup, *source.APIGroup)
+	require.Equal(t, VolumeSnapshotKind, source.Kind)
+	require.Equal(t, "nightly-web-data-web-0", source.Name)
+
+	// claim templates of existing statefulset keep data source they have been created with
+	updated, err := BuildStatefulSet(NewWorkloadBuilder(log, settings, cdep, svcIdx)).Update(sset.DeepCopy())
+	require.NoError(t, err)
+	require.Nil(t, updated.Spec.VolumeClaimTemplates[0].Spec.DataSource)
+
+	service.Env[len(service.Env)-1] = `DOOOR_RESTORE_SNAPSHOTS={"cache":"nightly-web-data-web-0"}`
+
+	_, err = BuildStatefulSet(NewWorkloadBuilder(log, settings, cdep, svcIdx)).Create()
+	require.ErrorIs(t, err, ctypes.ErrSnapshotRestoreConfig)
+
+	service.Env[len(service.Env)-1] = `DOOOR_RESTORE_SNAPSHOTS={"data":"Nightly_Snapshot"}`
+
+	_, err = BuildStatefulSet(NewWorkloadBuilder(log, settings, cdep, svcIdx)).Create()
+	require.ErrorIs(t, err, ctypes.ErrSnapshotRestoreConfig)
+}
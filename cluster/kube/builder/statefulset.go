@@ -32,6 +32,16 @@ func BuildStatefulSet(workload Workload) StatefulSet {
 func (b *statefulSet) Create() (*appsv1.StatefulSet, error) { // nolint:golint,unparam
 	falseValue := false
 
+	containers, err := b.containers()
+	if err != nil {
+		return nil, err
+	}
+
+	volumes, err := b.volumes()
+	if err != nil {
+		return nil, err
+	}
+
 	revisionHistoryLimit := int32(1)
 
 	partition := int32(0)
@@ -66,10 +76,9 @@ func (b *statefulSet) Create() (*appsv1.StatefulSet, error) { // nolint:golint,u
 						RunAsNonRoot: &falseValue,
 					},
 					AutomountServiceAccountToken: &falseValue,
-					// Containers:                   []corev1.Container{b.container()},
-					Containers:       b.containers(),
+					Containers:                   containers,
 					ImagePullSecrets:             b.secretsRefs,
-					Volumes:                      b.volumesObjs,
+					Volumes:                      volumes,
 				},
 			},
 			VolumeClaimTemplates: b.pvcsObjs,
@@ -80,16 +89,40 @@ func (b *statefulSet) Create() (*appsv1.StatefulSet, error) { // nolint:golint,u
 }
 
 func (b *statefulSet) Update(obj *appsv1.StatefulSet) (*appsv1.StatefulSet, error) { // nolint:golint,unparam
+	containers, err := b.containers()
+	if err != nil {
+		return nil, err
+	}
+
+	volumes, err := b.volumes()
+	if err != nil {
+		return nil, err
+	}
+
 	obj.Labels = updateAkashLabels(obj.Labels, b.labels())
 	obj.Spec.Replicas = b.replicas()
 	obj.Spec.Selector.MatchLabels = b.selectorLabels()
 	obj.Spec.Template.Labels = b.labels()
 	obj.Spec.Template.Spec.Affinity = b.affinity()
 	obj.Spec.Template.Spec.RuntimeClassName = b.runtimeClass()
-	// obj.Spec.Template.Spec.Containers = []corev1.Container{b.container()}
-	obj.Spec.Template.Spec.Containers = b.containers()
+	obj.Spec.Template.Spec.Containers = containers
 	obj.Spec.Template.Spec.ImagePullSecrets = b.imagePullSecrets()
-	obj.Spec.VolumeClaimTemplates = b.persistentVolumeClaims()
+	obj.Spec.Template.Spec.Volumes = volumes
+
+	// claim templates of existing statefulset cannot change, volumes keep snapshot they have been restored from
+	pvcs := b.persistentVolumeClaims()
+	for i := range pvcs {
+		pvcs[i].Spec.DataSource = nil
+
+		for _, curr := range obj.Spec.VolumeClaimTemplates {
+			if curr.Name == pvcs[i].Name {
+				pvcs[i].Spec.DataSource = curr.Spec.DataSource
+				break
+			}
+		}
+	}
+
+	obj.Spec.VolumeClaimTemplates = pvcs
 
 	return obj, nil
 }
@@ -0,0 +1,183 @@
+package builder
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+// teeProfile returns TEE sidecar profile selected for the service.
+// nil is returned if service does not request TEE
+func (b *Workload) teeProfile() (*ctypes.TEESidecarProfile, error) {
+	service := &b.deployment.ManifestGroup().Services[b.serviceIdx]
+
+	name, enabled := ctypes.ResourcesTEEProfile(service.Resources)
+	if !enabled {
+		return nil, nil
+	}
+
+	profile, err := b.settings.TEE.Profile(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: service %s: %w", ErrKubeBuilder, service.Name, err)
+	}
+
+	return &profile, nil
+}
+
+func (b *Workload) teeSidecar() (*corev1.Container, error) {
+	profile, err := b.teeProfile()
+	if err != nil || profile == nil {
+		return nil, err
+	}
+
+	cpu, memory, err := profile.Resources.Quantities()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrKubeBuilder, err)
+	}
+
+	pullPolicy := corev1.PullIfNotPresent
+	if profile.PullPolicy != "" {
+		pullPolicy = corev1.PullPolicy(profile.PullPolicy)
+	}
+
+	privileged := profile.SecurityContext.Privileged
+	allowPrivilegeEscalation := profile.SecurityContext.AllowPrivilegeEscalation || privileged
+
+	kcontainer := &corev1.Container{
+		Name:            ctypes.TEESidecarContainerName,
+		Image:           profile.Image,
+		ImagePullPolicy: pullPolicy,
+		Resources: corev1.ResourceRequirements{
+			Limits:   make(corev1.ResourceList),
+			Requests: make(corev1.ResourceList),
+		},
+		SecurityContext: &corev1.SecurityContext{
+			Privileged:               &privileged,
+			AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+			RunAsUser:                profile.SecurityContext.RunAsUser,
+			RunAsGroup:               profile.SecurityContext.RunAsGroup,
+		},
+	}
+
+	if len(profile.SecurityContext.CapabilitiesAdd) != 0 || len(profile.SecurityContext.CapabilitiesDrop) != 0 {
+		caps := &corev1.Capabilities{}
+		for _, val := range profile.SecurityContext.CapabilitiesAdd {
+			caps.Add = append(caps.Add, corev1.Capability(val))
+		}
+		for _, val := range profile.SecurityContext.CapabilitiesDrop {
+			caps.Drop = append(caps.Drop, corev1.Capability(val))
+		}
+
+		kcontainer.SecurityContext.Capabilities = caps
+	}
+
+	if !cpu.IsZero() {
+		kcontainer.Resources.Requests[corev1.ResourceCPU] = cpu.DeepCopy()
+		kcontainer.Resources.Limits[corev1.ResourceCPU] = cpu.DeepCopy()
+	}
+
+	if !memory.IsZero() {
+		kcontainer.Resources.Requests[corev1.ResourceMemory] = memory.DeepCopy()
+		kcontainer.Resources.Limits[corev1.ResourceMemory] = memory.DeepCopy()
+	}
+
+	for _, dev := range profile.Devices {
+		kcontainer.VolumeMounts = append(kcontainer.VolumeMounts, corev1.VolumeMount{
+			Name:      teeDeviceVolumeName(dev),
+			MountPath: dev,
+		})
+	}
+
+	// keep env order stable to avoid needless pod restarts on update
+	envNames := make([]string, 0, len(profile.Env))
+	for name := range profile.Env {
+		envNames = append(envNames, name)
+	}
+	sort.Strings(envNames)
+
+	for _, name := range envNames {
+		kcontainer.Env = append(kcontainer.Env, corev1.EnvVar{Name: name, Value: profile.Env[name]})
+	}
+
+	return kcontainer, nil
+}
+
+// TEEDeviceLabel returns node label advertising TEE device,
+// for example "akash.network/capabilities.tee.device.tpmrm0"
+func TEEDeviceLabel(dev string) string {
+	return fmt.Sprintf("%s.device.%s", AkashServiceCapabilityTEE, path.Base(dev))
+}
+
+// TEENodeLabels returns labels node must have (with value greater than 0) to run given TEE profile.
+// profile without devices only requires node to have any TEE device
+func TEENodeLabels(profile ctypes.TEESidecarProfile) []string {
+	if len(profile.Devices) == 0 {
+		return []string{AkashServiceCapabilityTEE}
+	}
+
+	res := make([]string, 0, len(profile.Devices))
+	for _, dev := range profile.Devices {
+		res = append(res, TEEDeviceLabel(dev))
+	}
+
+	return res
+}
+
+func (b *Workload) teeNodeSelectors() []corev1.NodeSelectorRequirement {
+	profile, err := b.teeProfile()
+	if err != nil || profile == nil {
+		return nil
+	}
+
+	labels := TEENodeLabels(*profile)
+	selectors := make([]corev1.NodeSelectorRequirement, 0, len(labels))
+
+	for _, label := range labels {
+		selectors = append(selectors, corev1.NodeSelectorRequirement{
+			Key:      label,
+			Operator: corev1.NodeSelectorOpGt,
+			Values: []string{
+				"0",
+			},
+		})
+	}
+
+	return selectors
+}
+
+func teeDeviceVolumes(profile *ctypes.TEESidecarProfile) []corev1.Volume {
+	volumes := make([]corev1.Volume, 0, len(profile.Devices))
+
+	for _, dev := range profile.Devices {
+		hostPathType := corev1.HostPathCharDev
+
+		volumes = append(volumes, corev1.Volume{
+			Name: teeDeviceVolumeName(dev),
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: dev,
+					Type: &hostPathType,
+				},
+			},
+		})
+	}
+
+	return volumes
+}
+
+func teeDeviceVolumeName(dev string) string {
+	name := strings.ToLower(path.Base(dev))
+	name = strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '-'
+	}, name)
+
+	return fmt.Sprintf("tee-device-%s", name)
+}
@@ -32,7 +32,6 @@ type workloadBase interface {
 type Workload struct {
 	builder
 	serviceIdx  int
-	volumesObjs []corev1.Volume
 	pvcsObjs    []corev1.PersistentVolumeClaim
 	secretsRefs []corev1.LocalObjectReference
 }
@@ -53,7 +52,6 @@ func NewWorkloadBuilder(
 		serviceIdx: serviceIdx,
 	}
 
-	res.volumesObjs = res.volumes()
 	res.pvcsObjs = res.persistentVolumeClaims()
 	res.secretsRefs = res.imagePullSecrets()
 
@@ -68,55 +66,28 @@ func (b *Workload) NS() string {
 	return LidNS(b.deployment.LeaseID())
 }
 
-func boolPtr(b bool) *bool {
-    return &b
-}
+func (b *Workload) containers() ([]corev1.Container, error) {
+	ctrs := []corev1.Container{b.container()}
 
-func (b *Workload) containers() []corev1.Container {
-    mainCtr := b.container() // container principal
-
-    // verificar DOOOR_TEE nas env
-    service := &b.deployment.ManifestGroup().Services[b.serviceIdx]
-    dooorTee := false
-
-    for _, envLine := range service.Env {
-        if strings.HasPrefix(envLine, "DOOOR_TEE=") {
-            parts := strings.SplitN(envLine, "=", 2)
-            if len(parts) == 2 && strings.EqualFold(parts[1], "true") {
-                dooorTee = true
-            }
-        }
-    }
-
-    // montamos um slice de containers
-    ctrs := []corev1.Container{ mainCtr }
-
-    if dooorTee {
-        sidecar := corev1.Container{
-            Name:  "sidecar-tee",
-            Image: "brunolaureano/tee-go-api:v1.0.0",
-            SecurityContext: &corev1.SecurityContext{
-                Privileged: boolPtr(true),
-            },
-			VolumeMounts: []corev1.VolumeMount{
-				{
-					Name:      "tpm-device",
-					MountPath: "/dev/tpmrm0",
-				},
-			},
-			Env: []corev1.EnvVar{
-				{
-					Name:  "TPM2TOOLS_TCTI",
-					Value: "device:/dev/tpmrm0",
-				},
-			},
-			// ImagePullPolicy: corev1.PullAlways,
-            // se quiser volumeMount p/ /dev/tpm0
-        }
-        ctrs = append(ctrs, sidecar)
-    }
+	if err := b.applyProbes(&ctrs[0]); err != nil {
+		return nil, err
+	}
+
+	// claims are built along with the workload, restores they ask for are validated here
+	if _, err := b.volumeRestores(); err != nil {
+		return nil, err
+	}
+
+	sidecar, err := b.teeSidecar()
+	if err != nil {
+		return nil, err
+	}
+
+	if sidecar != nil {
+		ctrs = append(ctrs, *sidecar)
+	}
 
-    return ctrs
+	return ctrs, nil
 }
 
 func (b *Workload) container() corev1.Container {
@@ -207,19 +178,11 @@ func (b *Workload) container() corev1.Container {
 	}
 
 	envVarsAdded := make(map[string]int)
-	var dooorTee bool
-
-	b.log.Info("!!!!!!!! VERIFYING PARAMS !!!!!!!!!!!")
-
 	for _, env := range service.Env {
 		parts := strings.SplitN(env, "=", 2)
 		switch len(parts) {
 		case 2:
 			kcontainer.Env = append(kcontainer.Env, corev1.EnvVar{Name: parts[0], Value: parts[1]})
-			if parts[0] == "DOOOR_TEE" && strings.ToLower(parts[1]) == "true" {
-				b.log.Info("DOOOR_TEE was set to true in env!", "value", parts[1])
-				dooorTee = true
-			}	
 		case 1:
 			kcontainer.Env = append(kcontainer.Env, corev1.EnvVar{Name: parts[0]})
 		}
@@ -227,10 +190,6 @@ func (b *Workload) container() corev1.Container {
 	}
 	kcontainer.Env = b.addEnvVarsForDeployment(envVarsAdded, kcontainer.Env)
 
-	if dooorTee {
-		b.log.Info("!!!!! DOOOR_TEE was set to true in env !!!!!")
-	}
-
 	for _, expose := range service.Expose {
 		kcontainer.Ports = append(kcontainer.Ports, corev1.ContainerPort{
 			ContainerPort: int32(expose.Port), // nolint: gosec
@@ -240,21 +199,8 @@ func (b *Workload) container() corev1.Container {
 	return kcontainer
 }
 
-func (b *Workload) shouldInjectTPM() bool {
-	service := &b.deployment.ManifestGroup().Services[b.serviceIdx]
-	for _, envLine := range service.Env {
-		if strings.HasPrefix(envLine, "DOOOR_TEE=") {
-			parts := strings.SplitN(envLine, "=", 2)
-			if len(parts) == 2 && strings.EqualFold(parts[1], "true") {
-				return true
-			}
-		}
-	}
-	return false
-}
-
-// Return RAM volumes
-func (b *Workload) volumes() []corev1.Volume {
+// Return RAM volumes and devices of TEE sidecar
+func (b *Workload) volumes() ([]corev1.Volume, error) {
 	var volumes []corev1.Volume // nolint:prealloc
 
 	service := &b.deployment.ManifestGroup().Services[b.serviceIdx]
@@ -285,22 +231,16 @@ func (b *Workload) volumes() []corev1.Volume {
 		})
 	}
 
-	if b.shouldInjectTPM() {
-		volumes = append(volumes, corev1.Volume{
-			Name: "tpm-device",
-			VolumeSource: corev1.VolumeSource{
-				HostPath: &corev1.HostPathVolumeSource{
-					Path: "/dev/tpmrm0",
-					Type: func() *corev1.HostPathType {
-						t := corev1.HostPathCharDev
-						return &t
-					}(),
-				},
-			},
-		})
+	profile, err := b.teeProfile()
+	if err != nil {
+		return nil, err
 	}
 
-	return volumes
+	if profile != nil {
+		volumes = append(volumes, teeDeviceVolumes(profile)...)
+	}
+
+	return volumes, nil
 }
 
 func (b *Workload) persistentVolumeClaims() []corev1.PersistentVolumeClaim {
@@ -308,6 +248,9 @@ func (b *Workload) persistentVolumeClaims() []corev1.PersistentVolumeClaim {
 
 	service := &b.deployment.ManifestGroup().Services[b.serviceIdx]
 
+	// invalid restores are reported by containers
+	restores, _ := b.volumeRestores()
+
 	for _, storage := range service.Resources.Storage {
 		attr := storage.Attributes.Find(sdl.StorageAttributePersistent)
 		if persistent, valid := attr.AsBool(); !valid || !persistent {
@@ -327,10 +270,14 @@ func (b *Workload) persistentVolumeClaims() []corev1.PersistentVolumeClaim {
 				},
 				VolumeMode:       &volumeMode,
 				StorageClassName: nil,
-				DataSource:       nil, // bind to existing pvc. akash does not support it. yet
+				DataSource:       nil,
 			},
 		}
 
+		if snapshot, exists := restores[storage.Name]; exists {
+			pvc.Spec.DataSource = VolumeSnapshotDataSource(snapshot)
+		}
+
 		pvc.Spec.Resources.Requests[corev1.ResourceStorage] = resource.NewQuantity(int64(storage.Quantity.Value()), resource.DecimalSI).DeepCopy() // nolint: gosec
 
 		attr = storage.Attributes.Find(sdl.StorageAttributeClass)
@@ -361,6 +308,10 @@ func (b *Workload) runtimeClass() *string {
 
 func (b *Workload) replicas() *int32 {
 	replicas := new(int32)
+	if b.deployment.Paused() {
+		return replicas
+	}
+
 	*replicas = int32(b.deployment.ManifestGroup().Services[b.serviceIdx].Count) // nolint: gosec
 
 	return replicas
@@ -402,6 +353,9 @@ func (b *Workload) affinity() *corev1.Affinity {
 		}
 
 	}
+
+	selectors = append(selectors, b.teeNodeSelectors()...)
+
 	affinity := &corev1.Affinity{
 		NodeAffinity: &corev1.NodeAffinity{
 			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
@@ -4,6 +4,8 @@ import (
 	"context"
 
 	mani "github.com/akash-network/akash-api/go/manifest/v2beta2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
@@ -14,8 +16,18 @@ import (
 	"github.com/akash-network/provider/cluster/kube/builder"
 )
 
-func cleanupStaleResources(ctx context.Context, kc kubernetes.Interface, lid mtypes.LeaseID, group *mani.Group) error {
+// staleObjs are objects cleanupStaleResources deleted, so they can be recreated on rollback
+type staleObjs struct {
+	deployments  []appsv1.Deployment
+	statefulSets []appsv1.StatefulSet
+	services     []corev1.Service
+	quotas       []corev1.ResourceQuota
+	limitRanges  []corev1.LimitRange
+}
+
+func cleanupStaleResources(ctx context.Context, kc kubernetes.Interface, lid mtypes.LeaseID, group *mani.Group) (*staleObjs, error) {
 	ns := builder.LidNS(lid)
+	res := &staleObjs{}
 
 	// build label selector for objects not in current manifest group
 	svcnames := make([]string, 0, len(group.Services))
@@ -25,32 +37,46 @@ func cleanupStaleResources(ctx context.Context, kc kubernetes.Interface, lid mty
 
 	req1, err := labels.NewRequirement(builder.AkashManifestServiceLabelName, selection.NotIn, svcnames)
 	if err != nil {
-		return err
+		return res, err
 	}
 	req2, err := labels.NewRequirement(builder.AkashServiceTarget, selection.Equals, []string{"true"})
 	if err != nil {
-		return err
+		return res, err
 	}
 
 	req3, err := labels.NewRequirement(builder.AkashManagedLabelName, selection.NotIn, []string{builder.AkashMetalLB})
 	if err != nil {
-		return err
+		return res, err
 	}
 
 	selector := labels.NewSelector().Add(*req1).Add(*req2).Add(*req3).String()
 
 	// delete stale deployments
-	if err := kc.AppsV1().Deployments(ns).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{
+	deployments, err := kc.AppsV1().Deployments(ns).List(ctx, metav1.ListOptions{
 		LabelSelector: selector,
-	}); err != nil {
-		return err
+	})
+	if err != nil {
+		return res, err
+	}
+	for _, obj := range deployments.Items {
+		if err := kc.AppsV1().Deployments(ns).Delete(ctx, obj.Name, metav1.DeleteOptions{}); err != nil {
+			return res, err
+		}
+		res.deployments = append(res.deployments, obj)
 	}
 
-	// delete stale deployments
-	if err := kc.AppsV1().StatefulSets(ns).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{
+	// delete stale statefulsets
+	statefulSets, err := kc.AppsV1().StatefulSets(ns).List(ctx, metav1.ListOptions{
 		LabelSelector: selector,
-	}); err != nil {
-		return err
+	})
+	if err != nil {
+		return res, err
+	}
+	for _, obj := range statefulSets.Items {
+		if err := kc.AppsV1().StatefulSets(ns).Delete(ctx, obj.Name, metav1.DeleteOptions{}); err != nil {
+			return res, err
+		}
+		res.statefulSets = append(res.statefulSets, obj)
 	}
 
 	// delete stale services (no DeleteCollection)
@@ -58,12 +84,57 @@ func cleanupStaleResources(ctx context.Context, kc kubernetes.Interface, lid mty
 		LabelSelector: selector,
 	})
 	if err != nil {
-		return err
+		return res, err
 	}
 	for _, svc := range services.Items {
 		if err := kc.CoreV1().Services(ns).Delete(ctx, svc.Name, metav1.DeleteOptions{}); err != nil {
+			return res, err
+		}
+		res.services = append(res.services, svc)
+	}
+
+	return res, cleanupStaleQuotas(ctx, kc, ns, res)
+}
+
+// cleanupStaleQuotas deletes quotas and limit ranges provider no longer manages in the lease namespace
+// so they do not restrict workloads beyond current allocation
+func cleanupStaleQuotas(ctx context.Context, kc kubernetes.Interface, ns string, res *staleObjs) error {
+	selector := labels.SelectorFromSet(labels.Set{builder.AkashManagedLabelName: "true"}).String()
+
+	quotas, err := kc.CoreV1().ResourceQuotas(ns).List(ctx, metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, quota := range quotas.Items {
+		if quota.Name == builder.AkashLeaseQuotaName {
+			continue
+		}
+
+		if err := kc.CoreV1().ResourceQuotas(ns).Delete(ctx, quota.Name, metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+		res.quotas = append(res.quotas, quota)
+	}
+
+	limits, err := kc.CoreV1().LimitRanges(ns).List(ctx, metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, limit := range limits.Items {
+		if limit.Name == builder.AkashLeaseLimitRangeName {
+			continue
+		}
+
+		if err := kc.CoreV1().LimitRanges(ns).Delete(ctx, limit.Name, metav1.DeleteOptions{}); err != nil {
 			return err
 		}
+		res.limitRanges = append(res.limitRanges, limit)
 	}
 
 	return nil
@@ -3,10 +3,13 @@ package kube
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"runtime/debug"
 	"slices"
+	"sort"
+	"strconv"
 	"strings"
 
 	mapi "github.com/akash-network/akash-api/go/manifest/v2beta2"
@@ -23,6 +26,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
 
@@ -55,6 +59,7 @@ type client struct {
 	ctx               context.Context
 	kc                kubernetes.Interface
 	ac                akashclient.Interface
+	dc                dynamic.Interface
 	ns                string
 	log               log.Logger
 	kubeContentConfig *restclient.Config
@@ -96,6 +101,12 @@ func NewClient(ctx context.Context, log log.Logger, ns string) (Client, error) {
 		return nil, err
 	}
 
+	// objects of CRDs provider does not ship types of, e.g. volume snapshots
+	dc, err := dynamic.NewForConfig(kubecfg)
+	if err != nil {
+		return nil, err
+	}
+
 	_, err = kc.CoreV1().Namespaces().Get(ctx, ns, metav1.GetOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("kube: unable to fetch leases namespace: %w", err)
@@ -105,6 +116,7 @@ func NewClient(ctx context.Context, log log.Logger, ns string) (Client, error) {
 		ctx:               ctx,
 		kc:                kc,
 		ac:                ac,
+		dc:                dc,
 		ns:                ns,
 		log:               log.With("client", "kube"),
 		kubeContentConfig: kubecfg,
@@ -197,10 +209,12 @@ type deploymentService struct {
 }
 
 type deploymentApplies struct {
-	ns        builder.NS
-	netPol    builder.NetPol
-	cmanifest builder.Manifest
-	services  []*deploymentService
+	ns         builder.NS
+	netPol     builder.NetPol
+	quota      builder.ResourceQuota
+	limitRange builder.LimitRange
+	cmanifest  builder.Manifest
+	services   []*deploymentService
 }
 
 type previousObj struct {
@@ -213,12 +227,19 @@ type previousObj struct {
 	nNetPolicies    []netv1.NetworkPolicy
 	uNetPolicies    []netv1.NetworkPolicy
 	oNetPolicies    []netv1.NetworkPolicy
+	nQuota          *corev1.ResourceQuota
+	uQuota          *corev1.ResourceQuota
+	oQuota          *corev1.ResourceQuota
+	nLimitRange     *corev1.LimitRange
+	uLimitRange     *corev1.LimitRange
+	oLimitRange     *corev1.LimitRange
 	nServiceCreds   []*corev1.Secret
 	uServiceCreds   []*corev1.Secret
 	oServiceCreds   []*corev1.Secret
 	nStatefulSets   []*appsv1.StatefulSet
 	uStatefulSets   []*appsv1.StatefulSet
 	oStatefulSets   []*appsv1.StatefulSet
+	dStatefulSets   []*appsv1.StatefulSet
 	nDeployments    []*appsv1.Deployment
 	uDeployments    []*appsv1.Deployment
 	oDeployments    []*appsv1.Deployment
@@ -228,6 +249,7 @@ type previousObj struct {
 	nGlobalServices []*corev1.Service
 	uGlobalServices []*corev1.Service
 	oGlobalServices []*corev1.Service
+	stale           *staleObjs
 }
 
 func (p *previousObj) recover(ctx context.Context, kc kubernetes.Interface, ac akashclient.Interface) []error {
@@ -240,6 +262,9 @@ func (p *previousObj) recover(ctx context.Context, kc kubernetes.Interface, ac a
 	}
 
 	for _, val := range slices.Backward(p.oGlobalServices) {
+		val = val.DeepCopy()
+		prepareRestore(&val.ObjectMeta)
+
 		if _, err := kc.CoreV1().Services(val.Namespace).Update(ctx, val, metav1.UpdateOptions{}); err != nil {
 			errs = append(errs, err)
 		}
@@ -252,23 +277,60 @@ func (p *previousObj) recover(ctx context.Context, kc kubernetes.Interface, ac a
 	}
 
 	for _, val := range slices.Backward(p.oLocalServices) {
+		val = val.DeepCopy()
+		prepareRestore(&val.ObjectMeta)
+
 		if _, err := kc.CoreV1().Services(val.Namespace).Update(ctx, val, metav1.UpdateOptions{}); err != nil {
 			errs = append(errs, err)
 		}
 	}
 
+	for _, val := range slices.Backward(p.nDeployments) {
+		if err := kc.AppsV1().Deployments(val.Namespace).Delete(ctx, val.Name, metav1.DeleteOptions{}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	for _, val := range slices.Backward(p.oDeployments) {
+		val = val.DeepCopy()
+		prepareRestore(&val.ObjectMeta)
+
 		if _, err := kc.AppsV1().Deployments(val.Namespace).Update(ctx, val, metav1.UpdateOptions{}); err != nil {
 			errs = append(errs, err)
 		}
 	}
 
+	for _, val := range slices.Backward(p.nStatefulSets) {
+		if err := kc.AppsV1().StatefulSets(val.Namespace).Delete(ctx, val.Name, metav1.DeleteOptions{}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	for _, val := range slices.Backward(p.oStatefulSets) {
+		val = val.DeepCopy()
+		prepareRestore(&val.ObjectMeta)
+
 		if _, err := kc.AppsV1().StatefulSets(val.Namespace).Update(ctx, val, metav1.UpdateOptions{}); err != nil {
 			errs = append(errs, err)
 		}
 	}
 
+	// statefulsets deleted to restore volumes from snapshot are recreated. claims of restored volumes are gone,
+	// so statefulset creates them anew from its own claim templates
+	for _, val := range slices.Backward(p.dStatefulSets) {
+		val = val.DeepCopy()
+		prepareRecreate(&val.ObjectMeta)
+		val.Status = appsv1.StatefulSetStatus{}
+
+		if _, err := kc.AppsV1().StatefulSets(val.Namespace).Create(ctx, val, metav1.CreateOptions{}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if p.stale != nil {
+		errs = append(errs, p.stale.recreate(ctx, kc)...)
+	}
+
 	for _, val := range slices.Backward(p.nServiceCreds) {
 		if err := kc.CoreV1().Secrets(val.Namespace).Delete(ctx, val.Name, metav1.DeleteOptions{}); err != nil {
 			errs = append(errs, err)
@@ -276,11 +338,44 @@ func (p *previousObj) recover(ctx context.Context, kc kubernetes.Interface, ac a
 	}
 
 	for _, val := range slices.Backward(p.oServiceCreds) {
+		val = val.DeepCopy()
+		prepareRestore(&val.ObjectMeta)
+
 		if _, err := kc.CoreV1().Secrets(val.Namespace).Update(ctx, val, metav1.UpdateOptions{}); err != nil {
 			errs = append(errs, err)
 		}
 	}
 
+	if p.nLimitRange != nil {
+		if err := kc.CoreV1().LimitRanges(p.nLimitRange.Namespace).Delete(ctx, p.nLimitRange.Name, metav1.DeleteOptions{}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if p.uLimitRange != nil && p.oLimitRange != nil {
+		val := p.oLimitRange.DeepCopy()
+		prepareRestore(&val.ObjectMeta)
+
+		if _, err := kc.CoreV1().LimitRanges(val.Namespace).Update(ctx, val, metav1.UpdateOptions{}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if p.nQuota != nil {
+		if err := kc.CoreV1().ResourceQuotas(p.nQuota.Namespace).Delete(ctx, p.nQuota.Name, metav1.DeleteOptions{}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if p.uQuota != nil && p.oQuota != nil {
+		val := p.oQuota.DeepCopy()
+		prepareRestore(&val.ObjectMeta)
+
+		if _, err := kc.CoreV1().ResourceQuotas(val.Namespace).Update(ctx, val, metav1.UpdateOptions{}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	for _, val := range slices.Backward(p.nNetPolicies) {
 		if err := kc.NetworkingV1().NetworkPolicies(val.Namespace).Delete(ctx, val.Name, metav1.DeleteOptions{}); err != nil {
 			errs = append(errs, err)
@@ -288,6 +383,8 @@ func (p *previousObj) recover(ctx context.Context, kc kubernetes.Interface, ac a
 	}
 
 	for _, val := range slices.Backward(p.oNetPolicies) {
+		prepareRestore(&val.ObjectMeta)
+
 		if _, err := kc.NetworkingV1().NetworkPolicies(val.Namespace).Update(ctx, &val, metav1.UpdateOptions{}); err != nil {
 			errs = append(errs, err)
 		}
@@ -300,13 +397,100 @@ func (p *previousObj) recover(ctx context.Context, kc kubernetes.Interface, ac a
 	}
 
 	if p.ons != nil {
-		if _, err := kc.CoreV1().Namespaces().Update(ctx, p.ons, metav1.UpdateOptions{}); err != nil {
+		val := p.ons.DeepCopy()
+		prepareRestore(&val.ObjectMeta)
+
+		if _, err := kc.CoreV1().Namespaces().Update(ctx, val, metav1.UpdateOptions{}); err != nil {
 			errs = append(errs, err)
 		}
 	}
 
-	if p.omani != nil {
-		if _, err := ac.AkashV2beta2().Manifests(p.omani.Namespace).Update(ctx, p.omani, metav1.UpdateOptions{}); err != nil {
+	if p.nmani != nil {
+		if err := ac.AkashV2beta2().Manifests(p.nmani.Namespace).Delete(ctx, p.nmani.Name, metav1.DeleteOptions{}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if p.umani != nil && p.omani != nil {
+		// custom resources cannot be updated unconditionally, so previous state goes over the latest revision
+		val := p.omani.DeepCopy()
+		val.ResourceVersion = p.umani.ResourceVersion
+
+		if _, err := ac.AkashV2beta2().Manifests(val.Namespace).Update(ctx, val, metav1.UpdateOptions{}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// prepareRestore drops fields server sets on the object which previous state is restored from,
+// so it is applied over whichever revision of the object is in the cluster now
+func prepareRestore(meta *metav1.ObjectMeta) {
+	meta.ResourceVersion = ""
+	meta.ManagedFields = nil
+}
+
+// prepareRecreate drops fields server sets on the object which is created again after it has been deleted
+func prepareRecreate(meta *metav1.ObjectMeta) {
+	prepareRestore(meta)
+
+	meta.UID = ""
+	meta.Generation = 0
+	meta.CreationTimestamp = metav1.Time{}
+	meta.DeletionTimestamp = nil
+	meta.DeletionGracePeriodSeconds = nil
+}
+
+func (s *staleObjs) recreate(ctx context.Context, kc kubernetes.Interface) []error {
+	var errs []error
+
+	for _, val := range s.limitRanges {
+		prepareRecreate(&val.ObjectMeta)
+
+		if _, err := kc.CoreV1().LimitRanges(val.Namespace).Create(ctx, &val, metav1.CreateOptions{}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, val := range s.quotas {
+		prepareRecreate(&val.ObjectMeta)
+		val.Status = corev1.ResourceQuotaStatus{}
+
+		if _, err := kc.CoreV1().ResourceQuotas(val.Namespace).Create(ctx, &val, metav1.CreateOptions{}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, val := range s.services {
+		prepareRecreate(&val.ObjectMeta)
+		val.Status = corev1.ServiceStatus{}
+
+		// cluster ip may have been taken since service was deleted, let cluster allocate new one
+		if val.Spec.ClusterIP != corev1.ClusterIPNone {
+			val.Spec.ClusterIP = ""
+			val.Spec.ClusterIPs = nil
+		}
+
+		if _, err := kc.CoreV1().Services(val.Namespace).Create(ctx, &val, metav1.CreateOptions{}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, val := range s.statefulSets {
+		prepareRecreate(&val.ObjectMeta)
+		val.Status = appsv1.StatefulSetStatus{}
+
+		if _, err := kc.AppsV1().StatefulSets(val.Namespace).Create(ctx, &val, metav1.CreateOptions{}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, val := range s.deployments {
+		prepareRecreate(&val.ObjectMeta)
+		val.Status = appsv1.DeploymentStatus{}
+
+		if _, err := kc.AppsV1().Deployments(val.Namespace).Create(ctx, &val, metav1.CreateOptions{}); err != nil {
 			errs = append(errs, err)
 		}
 	}
@@ -373,7 +557,14 @@ func (c *client) Deploy(ctx context.Context, deployment ctypes.IDeployment) (err
 			c.log.Error(fmt.Sprintf(dMsg, dArgs...))
 
 			c.log.Info("attempting recover objects to previous state")
-			po.recover(ctx, c.kc, c.ac)
+			if errs := po.recover(ctx, c.kc, c.ac); len(errs) != 0 {
+				rerr := errors.Join(errs...)
+				c.log.Error("recovering objects to previous state", "err", rerr, "lease", lid)
+
+				if errors.Is(err, kubeclienterrors.ErrRolloutFailed) {
+					err = fmt.Errorf("%w: %w", kubeclienterrors.ErrRollbackFailed, rerr)
+				}
+			}
 		}
 	}()
 
@@ -405,8 +596,18 @@ func (c *client) Deploy(ctx context.Context, deployment ctypes.IDeployment) (err
 		cdeployment.SetResourceVersion(resourceVersion)
 	}
 
+	paused, err := c.LeasePaused(ctx, lid)
+	if err != nil {
+		c.log.Error("checking lease paused", "err", err, "lease", lid)
+		return err
+	}
+
+	cdeployment.SetPaused(paused)
+
 	applies.ns = builder.BuildNS(settings, cdeployment)
 	applies.netPol = builder.BuildNetPol(settings, cdeployment)
+	applies.quota = builder.BuildResourceQuota(c.log, settings, cdeployment)
+	applies.limitRange = builder.BuildLimitRange(c.log, settings, cdeployment)
 
 	for svcIdx := range group.Services {
 		workload := builder.NewWorkloadBuilder(c.log, settings, cdeployment, svcIdx)
@@ -456,7 +657,19 @@ func (c *client) Deploy(ctx context.Context, deployment ctypes.IDeployment) (err
 		return err
 	}
 
-	if err = cleanupStaleResources(ctx, c.kc, lid, group); err != nil {
+	po.nQuota, po.uQuota, po.oQuota, err = applyResourceQuota(ctx, c.kc, applies.quota)
+	if err != nil {
+		c.log.Error("applying namespace resource quota", "err", err, "lease", lid)
+		return err
+	}
+
+	po.nLimitRange, po.uLimitRange, po.oLimitRange, err = applyLimitRange(ctx, c.kc, applies.limitRange)
+	if err != nil {
+		c.log.Error("applying namespace limit range", "err", err, "lease", lid)
+		return err
+	}
+
+	if po.stale, err = cleanupStaleResources(ctx, c.kc, lid, group); err != nil {
 		c.log.Error("cleaning stale resources", "err", err, "lease", lid)
 		return err
 	}
@@ -484,6 +697,15 @@ func (c *client) Deploy(ctx context.Context, deployment ctypes.IDeployment) (err
 		}
 
 		if applyObjs.statefulSet != nil {
+			dobj, err := c.restoreVolumes(ctx, applyObjs.statefulSet)
+			if dobj != nil {
+				po.dStatefulSets = append(po.dStatefulSets, dobj)
+			}
+			if err != nil {
+				c.log.Error("restoring volumes", "err", err, "lease", lid, "service", service.Name)
+				return err
+			}
+
 			nobj, uobj, oobj, err := applyStatefulSet(ctx, c.kc, applyObjs.statefulSet)
 			if err != nil {
 				c.log.Error("applying statefulSet", "err", err, "lease", lid, "service", service.Name)
@@ -556,6 +778,16 @@ func (c *client) Deploy(ctx context.Context, deployment ctypes.IDeployment) (err
 		}
 	}
 
+	// workloads of new lease have nothing to go back to, they are only waited for when lease is updated
+	if settings.DeploymentStagedUpdate && po.ons != nil {
+		if err = c.waitForRollout(ctx, lid, settings.DeploymentRolloutTimeout); err != nil {
+			c.log.Error("waiting for rollout", "err", err, "lease", lid)
+			return err
+		}
+	}
+
+	c.recordManifestRevision(ctx, lid, po)
+
 	return nil
 }
 
@@ -673,7 +905,11 @@ func (c *client) LeaseEvents(ctx context.Context, lid mtypes.LeaseID, services s
 }
 
 func (c *client) LeaseLogs(ctx context.Context, lid mtypes.LeaseID,
-	services string, follow bool, tailLines *int64) ([]*ctypes.ServiceLog, error) {
+	services string, opts ctypes.LeaseLogsOptions) ([]*ctypes.ServiceLog, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
 	if err := c.leaseExists(ctx, lid); err != nil {
 		return nil, err
 	}
@@ -692,25 +928,150 @@ func (c *client) LeaseLogs(ctx context.Context, lid mtypes.LeaseID,
 		c.log.Error("listing pods", "err", err)
 		return nil, fmt.Errorf("%s: %w", kubeclienterrors.ErrInternalError.Error(), err)
 	}
-	streams := make([]*ctypes.ServiceLog, len(pods.Items))
-	for i, pod := range pods.Items {
-		stream, err := wrapKubeCall("pods-getlogs", func() (io.ReadCloser, error) {
-			return c.kc.CoreV1().Pods(builder.LidNS(lid)).GetLogs(pod.Name, &corev1.PodLogOptions{
-				Follow:     follow,
-				TailLines:  tailLines,
-				Timestamps: false,
-			}).Stream(ctx)
-		})
 
-		if err != nil {
-			c.log.Error("get pod logs", "err", err)
-			return nil, fmt.Errorf("%s: %w", kubeclienterrors.ErrInternalError.Error(), err)
+	// kubernetes refuses to split outputs of the container when tail is requested
+	outputs := []string{""}
+	if settings, valid := ctx.Value(builder.SettingsKey).(builder.Settings); valid && settings.LogSplitStreams && opts.TailLines == nil {
+		outputs = []string{ctypes.LogOutputStdout, ctypes.LogOutputStderr}
+	}
+
+	replicas := podReplicaIndexes(pods.Items)
+
+	streams := make([]*ctypes.ServiceLog, 0, len(pods.Items)*len(outputs))
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+
+		container, valid := podLogsContainer(pod, opts)
+		if !valid {
+			continue
+		}
+
+		for _, output := range outputs {
+			logOpts := &corev1.PodLogOptions{
+				Container:    container,
+				Follow:       opts.Follow,
+				TailLines:    opts.TailLines,
+				SinceSeconds: opts.SinceSeconds,
+				Timestamps:   opts.Timestamps,
+				Previous:     opts.Previous,
+				LimitBytes:   opts.LimitBytes,
+			}
+
+			if opts.SinceTime != nil {
+				since := metav1.NewTime(*opts.SinceTime)
+				logOpts.SinceTime = &since
+			}
+
+			if output != "" {
+				kstream := corev1.LogStreamStdout
+				if output == ctypes.LogOutputStderr {
+					kstream = corev1.LogStreamStderr
+				}
+
+				logOpts.Stream = &kstream
+			}
+
+			stream, err := wrapKubeCall("pods-getlogs", func() (io.ReadCloser, error) {
+				return c.kc.CoreV1().Pods(builder.LidNS(lid)).GetLogs(pod.Name, logOpts).Stream(ctx)
+			})
+
+			if err != nil {
+				c.log.Error("get pod logs", "err", err)
+				for _, stream := range streams {
+					_ = stream.Stream.Close()
+				}
+				return nil, fmt.Errorf("%s: %w", kubeclienterrors.ErrInternalError.Error(), err)
+			}
+
+			slog := cluster.NewServiceLog(pod.Name, stream)
+			slog.Service = pod.Labels[builder.AkashManifestServiceLabelName]
+			slog.Replica = replicas[pod.Name]
+			slog.Container = container
+			slog.Output = output
+
+			streams = append(streams, slog)
 		}
-		streams[i] = cluster.NewServiceLog(pod.Name, stream)
 	}
 	return streams, nil
 }
 
+// podReplicaIndexes returns index of each pod among replicas of its service.
+// statefulset pods are indexed by their ordinal, pods of deployments in order they have been created
+func podReplicaIndexes(pods []corev1.Pod) map[string]int {
+	res := make(map[string]int, len(pods))
+	unordered := make(map[string][]*corev1.Pod)
+
+	for i := range pods {
+		pod := &pods[i]
+
+		if val, exists := pod.Labels[appsv1.PodIndexLabel]; exists {
+			if idx, err := strconv.Atoi(val); err == nil {
+				res[pod.Name] = idx
+				continue
+			}
+		}
+
+		service := pod.Labels[builder.AkashManifestServiceLabelName]
+		unordered[service] = append(unordered[service], pod)
+	}
+
+	for _, spods := range unordered {
+		sort.SliceStable(spods, func(i, j int) bool {
+			if !spods[i].CreationTimestamp.Equal(&spods[j].CreationTimestamp) {
+				return spods[i].CreationTimestamp.Before(&spods[j].CreationTimestamp)
+			}
+
+			return spods[i].Name < spods[j].Name
+		})
+
+		for idx, pod := range spods {
+			res[pod.Name] = idx
+		}
+	}
+
+	return res
+}
+
+// podLogsContainer returns container of the pod logs are streamed from.
+// pods without the requested container, or which container has no previous instance
+// when previous logs are requested, are not valid
+func podLogsContainer(pod *corev1.Pod, opts ctypes.LeaseLogsOptions) (string, bool) {
+	container := opts.Container
+	if container == "" {
+		// pods carrying sidecars require container to be named, service container is named after the service
+		container = pod.Labels[builder.AkashManifestServiceLabelName]
+	}
+
+	found := false
+	for _, ctr := range pod.Spec.Containers {
+		if ctr.Name == container {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		if opts.Container != "" {
+			return "", false
+		}
+
+		// let kubernetes pick default container of pods not managed by the builder
+		container = ""
+	}
+
+	if !opts.Previous {
+		return container, true
+	}
+
+	for _, status := range pod.Status.ContainerStatuses {
+		if container == "" || status.Name == container {
+			return container, status.RestartCount > 0
+		}
+	}
+
+	return container, false
+}
+
 func (c *client) ForwardedPortStatus(ctx context.Context, leaseID mtypes.LeaseID) (map[string][]ctypes.ForwardedPortStatus, error) {
 	settingsI := ctx.Value(builder.SettingsKey)
 	if nil == settingsI {
@@ -0,0 +1,103 @@
+package kube
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
+
+	"github.com/akash-network/provider/cluster"
+	"github.com/akash-network/provider/cluster/kube/builder"
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+// LeaseAttestation asks TEE sidecar of the service pod for a TPM quote over requested PCRs.
+// sidecar is reached through the pod proxy on the port defined by the service's TEE profile
+func (c *client) LeaseAttestation(ctx context.Context, leaseID mtypes.LeaseID, serviceName string, podIndex uint, req ctypes.AttestationRequest) (*ctypes.AttestationQuote, error) {
+	settings, valid := ctx.Value(builder.SettingsKey).(builder.Settings)
+	if !valid {
+		return nil, kubeclienterrors.ErrNotConfiguredWithSettings
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	service, pod, err := c.leaseServicePod(ctx, leaseID, serviceName, podIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := service.Resources.ToAkash()
+	if err != nil {
+		return nil, err
+	}
+
+	profileName, enabled := ctypes.ResourcesTEEProfile(res)
+	if !enabled {
+		return nil, cluster.ErrAttestationNotEnabled
+	}
+
+	hasSidecar := false
+	for _, container := range pod.Spec.Containers {
+		if container.Name == ctypes.TEESidecarContainerName {
+			hasSidecar = true
+			break
+		}
+	}
+
+	if !hasSidecar {
+		return nil, cluster.ErrAttestationNotEnabled
+	}
+
+	profile, err := settings.TEE.Profile(profileName)
+	if err != nil {
+		return nil, err
+	}
+
+	pcrs := req.PCRs
+	if len(pcrs) == 0 {
+		pcrs = ctypes.DefaultAttestationPCRs
+	}
+
+	spcrs := make([]string, 0, len(pcrs))
+	for _, pcr := range pcrs {
+		spcrs = append(spcrs, strconv.FormatUint(uint64(pcr), 10))
+	}
+
+	port, path := profile.Attestation.Endpoint()
+
+	c.log.Info("requesting tee quote", "lease", leaseID, "pod", pod.Name, "service", serviceName)
+
+	result, err := c.kc.CoreV1().RESTClient().Get().
+		Namespace(builder.LidNS(leaseID)).
+		Resource("pods").
+		Name(fmt.Sprintf("%s:%d", pod.Name, port)).
+		SubResource("proxy").
+		Suffix(path).
+		Param("nonce", hex.EncodeToString(req.Nonce)).
+		Param("pcrs", strings.Join(spcrs, ",")).
+		Do(ctx).
+		Raw()
+	if err != nil {
+		// Don't send the full text of sidecar errors back to the user
+		c.log.Error("tee sidecar quote request failed", "pod", pod.Name, "err", err)
+		return nil, cluster.ErrAttestationSidecar
+	}
+
+	quote := &ctypes.AttestationQuote{}
+	if err = json.Unmarshal(result, quote); err != nil {
+		c.log.Error("tee sidecar returned malformed quote", "pod", pod.Name, "err", err)
+		return nil, cluster.ErrAttestationSidecar
+	}
+
+	quote.Service = serviceName
+	quote.PodIndex = podIndex
+
+	return quote, nil
+}
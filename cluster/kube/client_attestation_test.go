@@ -0,0 +1,28 @@
+package kube
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/akash-network/provider/cluster"
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+func TestClientLeaseAttestationNotEnabled(t *testing.T) {
+	withExecTestScaffold(t, nil, func(s *execScaffold) {
+		_, err := s.client.LeaseAttestation(s.ctx, s.leaseID, execTestServiceName, 0, ctypes.AttestationRequest{
+			Nonce: []byte("0123456789abcdef"),
+		})
+		require.ErrorIs(t, err, cluster.ErrAttestationNotEnabled)
+	})
+}
+
+func TestClientLeaseAttestationInvalidNonce(t *testing.T) {
+	withExecTestScaffold(t, nil, func(s *execScaffold) {
+		_, err := s.client.LeaseAttestation(s.ctx, s.leaseID, execTestServiceName, 0, ctypes.AttestationRequest{
+			Nonce: []byte("abc"),
+		})
+		require.ErrorIs(t, err, ctypes.ErrAttestation)
+	})
+}
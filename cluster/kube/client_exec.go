@@ -22,6 +22,7 @@ import (
 	"github.com/akash-network/provider/cluster"
 	"github.com/akash-network/provider/cluster/kube/builder"
 	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
 )
 
 // the type implementing the interface returned by the Exec command
@@ -54,90 +55,18 @@ func (c *client) Exec(ctx context.Context, leaseID mtypes.LeaseID, serviceName s
 	tsq remotecommand.TerminalSizeQueue) (ctypes.ExecResult, error) {
 	namespace := builder.LidNS(leaseID)
 
-	mani, err := c.ac.AkashV2beta2().Manifests(c.ns).Get(ctx, namespace, metav1.GetOptions{})
-	if err != nil {
-		return nil, fmt.Errorf("%w: failed getting manifest", err)
-	}
-
-loop:
-	for idx := range mani.Spec.Group.Services {
-		if mani.Spec.Group.Services[idx].Name == serviceName {
-			break loop
-		}
-
-		if idx == len(mani.Spec.Group.Services)-1 {
-			return nil, cluster.ErrExecNoServiceWithName
-		}
-	}
-
-	// Check that the pod exists
-	pods, err := c.kc.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
-		TypeMeta:      metav1.TypeMeta{},
-		LabelSelector: fmt.Sprintf("akash.network/manifest-service=%s", serviceName),
-	})
+	_, selectedPod, err := c.leaseServicePod(ctx, leaseID, serviceName, podIndex)
 	if err != nil {
-		return nil, fmt.Errorf("%w: failed getting pods in namespace %q", err, namespace)
-	}
-
-	// if no pods are found yet then the deployment hasn't been spun up kubernetes yet
-	if 0 == len(pods.Items) {
-		return nil, cluster.ErrExecServiceNotRunning
-	}
-
-	// check that the requested pod is within the range
-	if podIndex >= uint(len(pods.Items)) {
-		return nil, fmt.Errorf("%w: valid range is [0, %d]", cluster.ErrExecPodIndexOutOfRange, len(pods.Items)-1)
-	}
-
-	// sort the pods, since we have no idea what order kubernetes returns them in
-	podsEff := sortablePods(pods.Items)
-	sort.Sort(podsEff)
-	selectedPod := podsEff[podIndex]
-	// validate the pod is in a state where it can be connected to
-	switch selectedPod.Status.Phase {
-	case corev1.PodSucceeded:
-		return nil, fmt.Errorf("%w: the service has completed", cluster.ErrExecServiceNotRunning)
-	case corev1.PodFailed:
-		return nil, fmt.Errorf("%w: the service has failed", cluster.ErrExecServiceNotRunning)
-	default:
-	}
-
-	// Check the conditions, make sure the pod is marked as ready
-	isReady := false
-	for _, cond := range selectedPod.Status.Conditions {
-		if cond.Type == corev1.PodReady {
-			isReady = cond.Status == corev1.ConditionTrue
-		}
-	}
-
-	if !isReady {
-		return nil, fmt.Errorf("%w: the service is not ready", cluster.ErrExecServiceNotRunning)
+		return nil, err
 	}
 
 	podName := selectedPod.Name
 	containerName := serviceName // Container name is always the same as the service name
 
-	// Define the necessary runtime scheme & codec to send the request
-	groupVersion := schema.GroupVersion{Group: "api", Version: "v1"}
-	myScheme := runtime.NewScheme()
-	err = corev1.AddToScheme(myScheme)
+	kubeConfig, kubeRestClient, myParameterCodec, err := c.podsRESTClient(&corev1.PodExecOptions{})
 	if err != nil {
 		return nil, err
 	}
-	myParameterCodec := runtime.NewParameterCodec(myScheme)
-	myScheme.AddKnownTypes(groupVersion, &corev1.PodExecOptions{})
-
-	kubeConfig := restclient.CopyConfig(c.kubeContentConfig) // Make a local copy of the configuration
-	kubeConfig.GroupVersion = &groupVersion
-
-	codecFactory := serializer.NewCodecFactory(myScheme)
-	negotiatedSerializer := runtime.NegotiatedSerializer(codecFactory)
-	kubeConfig.NegotiatedSerializer = negotiatedSerializer
-
-	kubeRestClient, err := restclient.RESTClientFor(kubeConfig)
-	if err != nil {
-		return nil, fmt.Errorf("%w: failed getting REST client", err)
-	}
 
 	c.log.Info("Opening container shell", "namespace", namespace, "pod", podName, "container", containerName)
 	if tty {
@@ -198,3 +127,101 @@ loop:
 
 	return nil, err
 }
+
+// podsRESTClient returns REST client and its configuration for pod subresources requests taking options of given types
+func (c *client) podsRESTClient(options ...runtime.Object) (*restclient.Config, *restclient.RESTClient, runtime.ParameterCodec, error) {
+	// Define the necessary runtime scheme & codec to send the request
+	groupVersion := schema.GroupVersion{Group: "api", Version: "v1"}
+	myScheme := runtime.NewScheme()
+	err := corev1.AddToScheme(myScheme)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	myParameterCodec := runtime.NewParameterCodec(myScheme)
+	myScheme.AddKnownTypes(groupVersion, options...)
+
+	kubeConfig := restclient.CopyConfig(c.kubeContentConfig) // Make a local copy of the configuration
+	kubeConfig.GroupVersion = &groupVersion
+
+	codecFactory := serializer.NewCodecFactory(myScheme)
+	negotiatedSerializer := runtime.NegotiatedSerializer(codecFactory)
+	kubeConfig.NegotiatedSerializer = negotiatedSerializer
+
+	kubeRestClient, err := restclient.RESTClientFor(kubeConfig)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: failed getting REST client", err)
+	}
+
+	return kubeConfig, kubeRestClient, myParameterCodec, nil
+}
+
+// leaseServicePod returns manifest of the lease service along with pod at given index
+// after checking the pod is running and ready to be connected to
+func (c *client) leaseServicePod(ctx context.Context, leaseID mtypes.LeaseID, serviceName string, podIndex uint) (crd.ManifestService, corev1.Pod, error) {
+	namespace := builder.LidNS(leaseID)
+
+	mani, err := c.ac.AkashV2beta2().Manifests(c.ns).Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		return crd.ManifestService{}, corev1.Pod{}, fmt.Errorf("%w: failed getting manifest", err)
+	}
+
+	var service crd.ManifestService
+
+loop:
+	for idx := range mani.Spec.Group.Services {
+		if mani.Spec.Group.Services[idx].Name == serviceName {
+			service = mani.Spec.Group.Services[idx]
+			break loop
+		}
+
+		if idx == len(mani.Spec.Group.Services)-1 {
+			return crd.ManifestService{}, corev1.Pod{}, cluster.ErrExecNoServiceWithName
+		}
+	}
+
+	// Check that the pod exists
+	pods, err := c.kc.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		TypeMeta:      metav1.TypeMeta{},
+		LabelSelector: fmt.Sprintf("akash.network/manifest-service=%s", serviceName),
+	})
+	if err != nil {
+		return crd.ManifestService{}, corev1.Pod{}, fmt.Errorf("%w: failed getting pods in namespace %q", err, namespace)
+	}
+
+	// if no pods are found yet then the deployment hasn't been spun up kubernetes yet
+	if 0 == len(pods.Items) {
+		return crd.ManifestService{}, corev1.Pod{}, cluster.ErrExecServiceNotRunning
+	}
+
+	// check that the requested pod is within the range
+	if podIndex >= uint(len(pods.Items)) {
+		return crd.ManifestService{}, corev1.Pod{}, fmt.Errorf("%w: valid range is [0, %d]", cluster.ErrExecPodIndexOutOfRange, len(pods.Items)-1)
+	}
+
+	// sort the pods, since we have no idea what order kubernetes returns them in
+	podsEff := sortablePods(pods.Items)
+	sort.Sort(podsEff)
+	selectedPod := podsEff[podIndex]
+	// validate the pod is in a state where it can be connected to
+	switch selectedPod.Status.Phase {
+	case corev1.PodSucceeded:
+		return crd.ManifestService{}, corev1.Pod{}, fmt.Errorf("%w: the service has completed", cluster.ErrExecServiceNotRunning)
+	case corev1.PodFailed:
+		return crd.ManifestService{}, corev1.Pod{}, fmt.Errorf("%w: the service has failed", cluster.ErrExecServiceNotRunning)
+	default:
+	}
+
+	// Check the conditions, make sure the pod is marked as ready
+	isReady := false
+	for _, cond := range selectedPod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			isReady = cond.Status == corev1.ConditionTrue
+		}
+	}
+
+	if !isReady {
+		return crd.ManifestService{}, corev1.Pod{}, fmt.Errorf("%w: the service is not ready", cluster.ErrExecServiceNotRunning)
+	}
+
+	return service, selectedPod, nil
+}
@@ -0,0 +1,144 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
+
+	"github.com/akash-network/provider/cluster"
+	"github.com/akash-network/provider/cluster/kube/builder"
+	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
+)
+
+// leaseServiceTargetPort returns container port the service pod is connected on.
+// ports the service exposes are matched first, so port forward to the service behaves as one to its kubernetes service,
+// any other port is taken as container port, which allows to reach ports the service does not expose
+func leaseServiceTargetPort(service crd.ManifestService, port uint32) uint32 {
+	for _, expose := range service.Expose {
+		if uint32(expose.Port) == port {
+			return port
+		}
+	}
+
+	for _, expose := range service.Expose {
+		if expose.ExternalPort != 0 && uint32(expose.ExternalPort) == port {
+			return uint32(expose.Port)
+		}
+	}
+
+	return port
+}
+
+func (c *client) PortForward(ctx context.Context, leaseID mtypes.LeaseID, serviceName string, podIndex uint, port uint32, stream io.ReadWriter) error {
+	namespace := builder.LidNS(leaseID)
+
+	service, selectedPod, err := c.leaseServicePod(ctx, leaseID, serviceName, podIndex)
+	if err != nil {
+		return err
+	}
+
+	targetPort := leaseServiceTargetPort(service, port)
+
+	kubeConfig, kubeRestClient, _, err := c.podsRESTClient()
+	if err != nil {
+		return err
+	}
+
+	const subResource = "portforward" // This value copied from kubectl and never changes
+	req := kubeRestClient.Post().Resource("pods").Name(selectedPod.Name).Namespace(namespace).SubResource(subResource)
+
+	transport, upgrader, err := spdy.RoundTripperFor(kubeConfig)
+	if err != nil {
+		return err
+	}
+
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, req.URL())
+
+	conn, _, err := dialer.Dial(portforward.PortForwardProtocolV1Name)
+	if err != nil {
+		return fmt.Errorf("%w: port forward via SPDY failed", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	c.log.Info("Opening port forward", "namespace", namespace, "pod", selectedPod.Name, "port", targetPort)
+
+	// each connection is sent as single request over its own SPDY connection, so the request ID is always the same
+	headers := http.Header{}
+	headers.Set(corev1.StreamType, corev1.StreamTypeError)
+	headers.Set(corev1.PortHeader, strconv.FormatUint(uint64(targetPort), 10))
+	headers.Set(corev1.PortForwardRequestIDHeader, "0")
+
+	errorStream, err := conn.CreateStream(headers)
+	if err != nil {
+		return fmt.Errorf("%w: failed creating error stream", err)
+	}
+	// nothing is written to the error stream
+	_ = errorStream.Close()
+
+	errch := make(chan error, 1)
+	go func() {
+		message, err := io.ReadAll(errorStream)
+		switch {
+		case err != nil:
+			errch <- fmt.Errorf("%w: failed reading error stream", err)
+		case len(message) > 0:
+			// Don't send the full text of the error back to the user, it is logged so it can be tracked down
+			c.log.Error("port forward failed", "namespace", namespace, "pod", selectedPod.Name, "port", targetPort, "err", string(message))
+			errch <- cluster.ErrPortForwardFailed
+		}
+		close(errch)
+	}()
+
+	headers.Set(corev1.StreamType, corev1.StreamTypeData)
+	dataStream, err := conn.CreateStream(headers)
+	if err != nil {
+		return fmt.Errorf("%w: failed creating data stream", err)
+	}
+
+	localDone := make(chan struct{})
+	remoteDone := make(chan struct{})
+
+	go func() {
+		_, _ = io.Copy(stream, dataStream)
+		close(remoteDone)
+	}()
+
+	go func() {
+		// tell the pod nothing more is sent once the stream is drained
+		defer func() {
+			_ = dataStream.Close()
+		}()
+
+		if _, err := io.Copy(dataStream, stream); err != nil {
+			close(localDone)
+		}
+	}()
+
+	select {
+	case <-remoteDone:
+	case <-localDone:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	// discard data not sent yet, otherwise it may keep error stream blocked
+	_ = dataStream.Reset()
+
+	select {
+	case err = <-errch:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	return err
+}
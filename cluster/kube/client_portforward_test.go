@@ -0,0 +1,43 @@
+package kube
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/akash-network/provider/cluster"
+	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
+)
+
+func TestLeaseServiceTargetPort(t *testing.T) {
+	service := crd.ManifestService{
+		Expose: []crd.ManifestServiceExpose{
+			{Port: 8080, ExternalPort: 80},
+			{Port: 9000},
+		},
+	}
+
+	require.Equal(t, uint32(8080), leaseServiceTargetPort(service, 8080))
+	require.Equal(t, uint32(8080), leaseServiceTargetPort(service, 80))
+	require.Equal(t, uint32(9000), leaseServiceTargetPort(service, 9000))
+	// ports the service does not expose are forwarded as they are
+	require.Equal(t, uint32(6060), leaseServiceTargetPort(service, 6060))
+}
+
+func TestClientPortForward(t *testing.T) {
+	withExecTestScaffold(t, nil, func(s *execScaffold) {
+		err := s.client.PortForward(s.ctx, s.leaseID, execTestServiceName, 0, 80, &bytes.Buffer{})
+		// The arguments are valid, so we expect the code to try & establish a SPDY connection
+		// which has been hijacked & blocked by the scaffold
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "SPDY connections blocked")
+	})
+}
+
+func TestClientPortForwardWrongServiceName(t *testing.T) {
+	withExecTestScaffold(t, nil, func(s *execScaffold) {
+		err := s.client.PortForward(s.ctx, s.leaseID, "notaservice", 0, 80, &bytes.Buffer{})
+		require.ErrorIs(t, err, cluster.ErrExecNoServiceWithName)
+	})
+}
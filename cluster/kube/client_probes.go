@@ -0,0 +1,125 @@
+package kube
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/akash-network/provider/cluster/kube/builder"
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+const (
+	// eventReasonUnhealthy is the reason kubelet reports failed probes of the container with
+	eventReasonUnhealthy = "Unhealthy"
+)
+
+// LeaseProbeFailures summarizes failing probes of the lease services from events kubelet reports for their current pods.
+// it lists pods and events of the lease, so it is kept out of LeaseStatus deployment monitor polls
+func (c *client) LeaseProbeFailures(ctx context.Context, lid mtypes.LeaseID) (map[string][]ctypes.ProbeStatus, error) {
+	ns := builder.LidNS(lid)
+
+	pods, err := wrapKubeCall("pods-list", func() (*corev1.PodList, error) {
+		return c.kc.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	services := make(map[string]string, len(pods.Items))
+	for _, pod := range pods.Items {
+		if name, exists := pod.Labels[builder.AkashManifestServiceLabelName]; exists {
+			services[pod.Name] = name
+		}
+	}
+
+	if len(services) == 0 {
+		return nil, nil
+	}
+
+	events, err := wrapKubeCall("events-list", func() (*corev1.EventList, error) {
+		return c.kc.CoreV1().Events(ns).List(ctx, metav1.ListOptions{
+			FieldSelector: "reason=" + eventReasonUnhealthy + ",involvedObject.kind=Pod",
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	type probeKey struct {
+		service string
+		probe   string
+	}
+
+	statuses := make(map[probeKey]*ctypes.ProbeStatus)
+
+	for _, evt := range events.Items {
+		if evt.Reason != eventReasonUnhealthy || evt.InvolvedObject.Kind != "Pod" {
+			continue
+		}
+
+		service, exists := services[evt.InvolvedObject.Name]
+		if !exists {
+			continue
+		}
+
+		probe := probeFromMessage(evt.Message)
+		if probe == "" {
+			continue
+		}
+
+		key := probeKey{service: service, probe: probe}
+
+		status, exists := statuses[key]
+		if !exists {
+			status = &ctypes.ProbeStatus{Probe: probe}
+			statuses[key] = status
+		}
+
+		count := evt.Count
+		if count == 0 {
+			count = 1
+		}
+
+		status.Failures += count
+
+		last := evt.LastTimestamp.Time
+		if last.IsZero() {
+			last = evt.EventTime.Time
+		}
+
+		if !status.LastFailure.After(last) {
+			status.LastFailure = last
+			status.Message = evt.Message
+		}
+	}
+
+	res := make(map[string][]ctypes.ProbeStatus)
+	for key, status := range statuses {
+		res[key.service] = append(res[key.service], *status)
+	}
+
+	for _, probes := range res {
+		sort.Slice(probes, func(i, j int) bool {
+			return probes[i].Probe < probes[j].Probe
+		})
+	}
+
+	return res, nil
+}
+
+// probeFromMessage returns kind of the probe from kubelet event message such as
+// "Liveness probe failed: HTTP probe failed with statuscode: 500"
+func probeFromMessage(msg string) string {
+	for _, probe := range []string{ctypes.ProbeLiveness, ctypes.ProbeReadiness, ctypes.ProbeStartup} {
+		if strings.HasPrefix(strings.ToLower(msg), probe+" probe") {
+			return probe
+		}
+	}
+
+	return ""
+}
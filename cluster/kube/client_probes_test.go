@@ -0,0 +1,123 @@
+package kube
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/akash-network/node/testutil"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/akash-network/provider/cluster/kube/builder"
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+func TestLeaseProbeFailures(t *testing.T) {
+	lid := testutil.LeaseID(t)
+	ns := builder.LidNS(lid)
+
+	lns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ns,
+		},
+	}
+
+	depl := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web",
+			Namespace: ns,
+		},
+		Status: appsv1.DeploymentStatus{
+			AvailableReplicas: 1,
+			Replicas:          2,
+		},
+	}
+
+	pod := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ns,
+				Labels: map[string]string{
+					builder.AkashManifestServiceLabelName: "web",
+				},
+			},
+		}
+	}
+
+	now := time.Now().Truncate(time.Second)
+
+	event := func(name, pod, reason, msg string, count int32, tm time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ns,
+			},
+			InvolvedObject: corev1.ObjectReference{
+				Kind: "Pod",
+				Name: pod,
+			},
+			Reason:        reason,
+			Message:       msg,
+			Count:         count,
+			LastTimestamp: metav1.NewTime(tm),
+		}
+	}
+
+	objs := []runtime.Object{
+		lns,
+		depl,
+		pod("web-1"),
+		pod("web-2"),
+		event("e1", "web-1", "Unhealthy", "Readiness probe failed: dial tcp 10.0.0.1:80: connect: connection refused", 3, now.Add(-time.Minute)),
+		event("e2", "web-2", "Unhealthy", "Readiness probe failed: HTTP probe failed with statuscode: 503", 2, now),
+		event("e3", "web-2", "Unhealthy", "Liveness probe failed: command timed out", 1, now),
+		event("e4", "web-2", "Pulled", "Successfully pulled image", 1, now),
+		// pod has been replaced since
+		event("e5", "web-0", "Unhealthy", "Startup probe failed: connection refused", 1, now),
+	}
+
+	clientInterface := clientForTest(t, objs, nil)
+
+	ctx := context.WithValue(context.Background(), builder.SettingsKey, builder.Settings{
+		ClusterPublicHostname: "meow.com",
+	})
+
+	status, err := clientInterface.LeaseStatus(ctx, lid)
+	require.NoError(t, err)
+	require.Contains(t, status, "web")
+	require.Empty(t, status["web"].RecentProbeFailures)
+
+	// lease status polled by deployment monitor does not look up pods and events
+	kc := clientInterface.(*client).kc.(*fake.Clientset)
+	for _, action := range kc.Actions() {
+		require.NotContains(t, []string{"pods", "events"}, action.GetResource().Resource)
+	}
+
+	failures, err := clientInterface.LeaseProbeFailures(ctx, lid)
+	require.NoError(t, err)
+	require.Len(t, failures, 1)
+
+	probes := failures["web"]
+	require.Len(t, probes, 2)
+
+	require.Equal(t, ctypes.ProbeLiveness, probes[0].Probe)
+	require.Equal(t, int32(1), probes[0].Failures)
+
+	require.Equal(t, ctypes.ProbeReadiness, probes[1].Probe)
+	require.Equal(t, int32(5), probes[1].Failures)
+	require.True(t, now.Equal(probes[1].LastFailure))
+	require.Equal(t, "Readiness probe failed: HTTP probe failed with statuscode: 503", probes[1].Message)
+}
+
+func TestProbeFromMessage(t *testing.T) {
+	require.Equal(t, ctypes.ProbeLiveness, probeFromMessage("Liveness probe failed: timeout"))
+	require.Equal(t, ctypes.ProbeReadiness, probeFromMessage("Readiness probe errored: rpc error"))
+	require.Equal(t, ctypes.ProbeStartup, probeFromMessage("Startup probe failed: connection refused"))
+	require.Empty(t, probeFromMessage("Back-off restarting failed container"))
+}
@@ -3,6 +3,7 @@ package kube
 import (
 	"context"
 	"testing"
+	"time"
 
 	manifest "github.com/akash-network/akash-api/go/manifest/v2beta2"
 	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
@@ -13,11 +14,15 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/rest"
+	kubetesting "k8s.io/client-go/testing"
 
 	"github.com/akash-network/provider/cluster/kube/builder"
 	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
 	afake "github.com/akash-network/provider/pkg/client/clientset/versioned/fake"
 )
@@ -29,10 +34,14 @@ func clientForTest(t *testing.T, kobjs []runtime.Object, aobjs []runtime.Object)
 
 	kc := fake.NewSimpleClientset(kobjs...)
 	ac := afake.NewSimpleClientset(aobjs...)
+	dc := dfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		volumeSnapshotGVR: "VolumeSnapshotList",
+	})
 
 	result := &client{
 		kc:                kc,
 		ac:                ac,
+		dc:                dc,
 		ns:                testKubeClientNs,
 		log:               myLog.With("mode", "test-kube-provider-client"),
 		kubeContentConfig: &rest.Config{},
@@ -687,3 +696,156 @@ func TestServiceStatusWithoutIngress(t *testing.T) {
 	require.NotNil(t, status)
 	require.Len(t, status.URIs, 0)
 }
+
+func logsTestPod(ns string, name string, service string, restarts int32, containers ...string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+			Labels: map[string]string{
+				builder.AkashManifestServiceLabelName: service,
+			},
+		},
+	}
+
+	for _, ctr := range containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: ctr})
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+			Name:         ctr,
+			RestartCount: restarts,
+		})
+	}
+
+	return pod
+}
+
+func requestedPodLogs(kc *fake.Clientset) map[string]*corev1.PodLogOptions {
+	res := make(map[string]*corev1.PodLogOptions)
+
+	for _, action := range kc.Actions() {
+		if action.GetSubresource() != "log" {
+			continue
+		}
+
+		opts := action.(kubetesting.GenericAction).GetValue().(*corev1.PodLogOptions)
+		res[opts.Container] = opts
+	}
+
+	return res
+}
+
+func TestLeaseLogsOptions(t *testing.T) {
+	lid := testutil.LeaseID(t)
+	ns := builder.LidNS(lid)
+
+	lns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ns,
+		},
+	}
+
+	ctx := context.Background()
+	newClient := func() *client {
+		return clientForTest(t, []runtime.Object{
+			lns,
+			logsTestPod(ns, "web-0", "web", 2, "web", ctypes.TEESidecarContainerName),
+			logsTestPod(ns, "db-0", "db", 0, "db"),
+		}, nil).(*client)
+	}
+
+	c := newClient()
+
+	_, err := c.LeaseLogs(ctx, lid, "", ctypes.LeaseLogsOptions{LimitBytes: new(int64)})
+	require.ErrorIs(t, err, ctypes.ErrLeaseLogsOptions)
+
+	tail := int64(10)
+	since := int64(300)
+	limit := int64(4096)
+
+	logs, err := c.LeaseLogs(ctx, lid, "", ctypes.LeaseLogsOptions{
+		TailLines:    &tail,
+		SinceSeconds: &since,
+		Timestamps:   true,
+		LimitBytes:   &limit,
+	})
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+
+	// service containers are selected by default as pods carrying sidecars require container to be named
+	requested := requestedPodLogs(c.kc.(*fake.Clientset))
+	require.Len(t, requested, 2)
+	require.Contains(t, requested, "web")
+	require.Contains(t, requested, "db")
+	require.Equal(t, &tail, requested["web"].TailLines)
+	require.Equal(t, &since, requested["web"].SinceSeconds)
+	require.Equal(t, &limit, requested["web"].LimitBytes)
+	require.True(t, requested["web"].Timestamps)
+
+	c = newClient()
+
+	logs, err = c.LeaseLogs(ctx, lid, "", ctypes.LeaseLogsOptions{Container: ctypes.TEESidecarContainerName})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	require.Equal(t, "web-0", logs[0].Name)
+	require.Contains(t, requestedPodLogs(c.kc.(*fake.Clientset)), ctypes.TEESidecarContainerName)
+
+	c = newClient()
+
+	// only containers which have restarted have previous instance to read logs of
+	logs, err = c.LeaseLogs(ctx, lid, "", ctypes.LeaseLogsOptions{Previous: true})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	require.Equal(t, "web-0", logs[0].Name)
+	require.True(t, requestedPodLogs(c.kc.(*fake.Clientset))["web"].Previous)
+}
+
+func TestLeaseLogsMetadata(t *testing.T) {
+	lid := testutil.LeaseID(t)
+	ns := builder.LidNS(lid)
+
+	lns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ns,
+		},
+	}
+
+	db := logsTestPod(ns, "db-0", "db", 0, "db")
+	db.Labels[appsv1.PodIndexLabel] = "0"
+
+	older := logsTestPod(ns, "web-zzz", "web", 0, "web")
+	older.CreationTimestamp = metav1.NewTime(time.Unix(100, 0))
+
+	newer := logsTestPod(ns, "web-aaa", "web", 0, "web")
+	newer.CreationTimestamp = metav1.NewTime(time.Unix(200, 0))
+
+	c := clientForTest(t, []runtime.Object{lns, db, older, newer}, nil).(*client)
+
+	settings := builder.NewDefaultSettings()
+	settings.LogSplitStreams = true
+
+	ctx := context.WithValue(context.Background(), builder.SettingsKey, settings)
+
+	logs, err := c.LeaseLogs(ctx, lid, "", ctypes.LeaseLogsOptions{})
+	require.NoError(t, err)
+	require.Len(t, logs, 6)
+
+	replicas := make(map[string]int)
+	outputs := make(map[string][]string)
+	for _, lg := range logs {
+		replicas[lg.Name] = lg.Replica
+		outputs[lg.Name] = append(outputs[lg.Name], lg.Output)
+		require.Equal(t, lg.Service, lg.Container)
+	}
+
+	// deployment pods are indexed in order they have been created in
+	require.Equal(t, map[string]int{"db-0": 0, "web-zzz": 0, "web-aaa": 1}, replicas)
+	require.Equal(t, []string{ctypes.LogOutputStdout, ctypes.LogOutputStderr}, outputs["db-0"])
+
+	// outputs can not be split when tail is requested
+	tail := int64(1)
+
+	logs, err = c.LeaseLogs(ctx, lid, "web", ctypes.LeaseLogsOptions{TailLines: &tail})
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	require.Empty(t, logs[0].Output)
+}
@@ -15,4 +15,14 @@ var (
 	ErrInvalidHostnameConnection = fmt.Errorf("%w: invalid hostname connection", ErrKubeClient)
 	ErrNotConfiguredWithSettings = fmt.Errorf("%w: not configured with settings in the context passed to function", ErrKubeClient)
 	ErrAlreadyExists             = fmt.Errorf("%w: resource already exists", ErrKubeClient)
+	ErrRolloutFailed             = fmt.Errorf("%w: rollout failed", ErrKubeClient)
+	ErrRollbackFailed            = fmt.Errorf("%w: rollback failed", ErrKubeClient)
+	ErrNoManifestRevision        = fmt.Errorf("%w: manifest revision not found", ErrKubeClient)
+	ErrVolumeSnapshotsDisabled   = fmt.Errorf("%w: volume snapshots are not enabled", ErrKubeClient)
+	ErrInvalidVolumeSnapshotName = fmt.Errorf("%w: invalid volume snapshot name", ErrKubeClient)
+	ErrVolumeSnapshotExists      = fmt.Errorf("%w: volume snapshot already exists", ErrKubeClient)
+	ErrVolumeSnapshotNotFound    = fmt.Errorf("%w: volume snapshot not found", ErrKubeClient)
+	ErrVolumeSnapshotNotReady    = fmt.Errorf("%w: volume snapshot is not ready to use", ErrKubeClient)
+	ErrNoPersistentVolumes       = fmt.Errorf("%w: lease has no persistent volumes", ErrKubeClient)
+	ErrVolumeRestoreFailed       = fmt.Errorf("%w: volume restore failed", ErrKubeClient)
 )
@@ -170,7 +170,7 @@ func TestNewClient(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotEqual(t, maxtries, tries)
 
-	logs, err := cl.LeaseLogs(ctx, lid, svcname, true, nil)
+	logs, err := cl.LeaseLogs(ctx, lid, svcname, ctypes.LeaseLogsOptions{Follow: true})
 	require.NoError(t, err)
 	require.Equal(t, int(sstat.AvailableReplicas), len(logs))
 
@@ -20,6 +20,7 @@ import (
 
 	inventoryV1 "github.com/akash-network/akash-api/go/inventory/v1"
 
+	"github.com/akash-network/provider/cluster/kube/builder"
 	kutil "github.com/akash-network/provider/cluster/kube/util"
 	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	cinventory "github.com/akash-network/provider/cluster/types/v1beta3/clients/inventory"
@@ -41,10 +42,13 @@ type client struct {
 	ctx   context.Context
 	group *errgroup.Group
 	subch chan chan<- ctypes.Inventory
+	tee   ctypes.TEEConfig
 }
 
 type inventory struct {
 	inventoryV1.Cluster
+	tee      ctypes.TEEConfig
+	teeNodes teeNodes
 }
 
 type inventoryState struct {
@@ -56,13 +60,14 @@ var (
 	_ cinventory.Client = (*client)(nil)
 )
 
-func NewClient(ctx context.Context) (cinventory.Client, error) {
+func NewClient(ctx context.Context, tee ctypes.TEEConfig) (cinventory.Client, error) {
 	group, ctx := errgroup.WithContext(ctx)
 
 	cl := &client{
 		ctx:   ctx,
 		subch: make(chan chan<- ctypes.Inventory, 1),
 		group: group,
+		tee:   tee,
 	}
 
 	group.Go(cl.discovery)
@@ -100,13 +105,26 @@ func (cl *client) discovery() error {
 
 	defer watcher.Stop()
 
+	// inventory operator labels nodes with TEE devices it discovers.
+	// those are tracked here as inventory service has no notion of TEE capabilities
+	nodesWatcher, err := watchTEENodes(cl.ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		nodesWatcher.Stop()
+	}()
+
 	svcEvents := watcher.ResultChan()
+	nodeEvents := nodesWatcher.ResultChan()
 	inv := inventoryV1.Cluster{}
+	tnodes := make(teeNodes)
 
 	invch := make(chan inventoryState, 1)
 
 	var ctrlexitch <-chan struct{}
-	var subs []chan<- inventoryV1.Cluster
+	var subs []chan<- inventory
 
 	isConnected := false
 
@@ -180,10 +198,41 @@ func (cl *client) discovery() error {
 			}
 
 			for _, ch := range subs {
-				ch <- *inv.Dup()
+				ch <- cl.newInventory(inv, tnodes)
+			}
+		case evt, isopen := <-nodeEvents:
+			if !isopen {
+				nodesWatcher.Stop()
+
+				if nodesWatcher, err = watchTEENodes(cl.ctx); err != nil {
+					return err
+				}
+
+				nodeEvents = nodesWatcher.ResultChan()
+				continue
+			}
+
+			knode, valid := evt.Object.(*corev1.Node)
+			if !valid {
+				continue
+			}
+
+			changed := false
+
+			switch evt.Type {
+			case watch.Added, watch.Modified:
+				changed = tnodes.update(knode.Name, knode.Labels)
+			case watch.Deleted:
+				changed = tnodes.remove(knode.Name)
+			}
+
+			if changed && isConnected {
+				for _, ch := range subs {
+					ch <- cl.newInventory(inv, tnodes)
+				}
 			}
 		case reqch := <-cl.subch:
-			ch := make(chan inventoryV1.Cluster, 1)
+			ch := make(chan inventory, 1)
 
 			subs = append(subs, ch)
 
@@ -192,16 +241,24 @@ func (cl *client) discovery() error {
 			})
 
 			if isConnected {
-				ch <- *inv.Dup()
+				ch <- cl.newInventory(inv, tnodes)
 			}
 		}
 	}
 }
 
-func (cl *client) subscriber(in <-chan inventoryV1.Cluster, out chan<- ctypes.Inventory) error {
+func (cl *client) newInventory(clState inventoryV1.Cluster, tnodes teeNodes) inventory {
+	inv := newInventory(*clState.Dup())
+	inv.tee = cl.tee
+	inv.teeNodes = tnodes.dup()
+
+	return *inv
+}
+
+func (cl *client) subscriber(in <-chan inventory, out chan<- ctypes.Inventory) error {
 	defer close(out)
 
-	var pending []inventoryV1.Cluster
+	var pending []inventory
 	var msg ctypes.Inventory
 	var och chan<- ctypes.Inventory
 
@@ -212,13 +269,15 @@ func (cl *client) subscriber(in <-chan inventoryV1.Cluster, out chan<- ctypes.In
 		case inv := <-in:
 			pending = append(pending, inv)
 			if och == nil {
-				msg = newInventory(pending[0])
+				next := pending[0]
+				msg = &next
 				och = out
 			}
 		case och <- msg:
 			pending = pending[1:]
 			if len(pending) > 0 {
-				msg = newInventory(pending[0])
+				next := pending[0]
+				msg = &next
 			} else {
 				och = nil
 				msg = nil
@@ -227,6 +286,14 @@ func (cl *client) subscriber(in <-chan inventoryV1.Cluster, out chan<- ctypes.In
 	}
 }
 
+func watchTEENodes(ctx context.Context) (watch.Interface, error) {
+	kc := fromctx.MustKubeClientFromCtx(ctx)
+
+	return kc.CoreV1().Nodes().Watch(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=true", builder.AkashManagedLabelName),
+	})
+}
+
 func newInventoryConnector(ctx context.Context, svc *corev1.Service, invch chan<- inventoryState) (<-chan struct{}, error) {
 	ctx, cancel := context.WithCancel(ctx)
 	group, ctx := errgroup.WithContext(ctx)
@@ -31,6 +31,7 @@ import (
 	dtypes "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
 	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
 
+	"github.com/akash-network/provider/cluster/kube/builder"
 	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
 	"github.com/akash-network/provider/testutil"
@@ -299,7 +300,7 @@ func waitForInventory(t *testing.T, ch <-chan ctypes.Inventory) ctypes.Inventory
 func TestInventoryZero(t *testing.T) {
 	scaffold := makeInventoryScaffold(t)
 
-	cl, err := NewClient(scaffold.ctx)
+	cl, err := NewClient(scaffold.ctx, ctypes.NewDefaultTEEConfig())
 	require.NoError(t, err)
 	require.NotNil(t, cl)
 
@@ -320,7 +321,7 @@ func TestInventorySingleNodeNoPods(t *testing.T) {
 	const expectedStorage = 15
 
 	scaffold := makeInventoryScaffold(t)
-	cl, err := NewClient(scaffold.ctx)
+	cl, err := NewClient(scaffold.ctx, ctypes.NewDefaultTEEConfig())
 	require.NoError(t, err)
 	require.NotNil(t, cl)
 
@@ -370,7 +371,7 @@ func TestInventorySingleNodeWithPods(t *testing.T) {
 	const totalContainers = 3
 
 	scaffold := makeInventoryScaffold(t)
-	cl, err := NewClient(scaffold.ctx)
+	cl, err := NewClient(scaffold.ctx, ctypes.NewDefaultTEEConfig())
 	require.NoError(t, err)
 	require.NotNil(t, cl)
 
@@ -412,7 +413,7 @@ func TestInventorySingleNodeWithPods(t *testing.T) {
 
 func TestInventoryMultipleReplicasFulFilled1(t *testing.T) {
 	scaffold := makeInventoryScaffold(t)
-	cl, err := NewClient(scaffold.ctx)
+	cl, err := NewClient(scaffold.ctx, ctypes.NewDefaultTEEConfig())
 	require.NoError(t, err)
 	require.NotNil(t, cl)
 
@@ -442,7 +443,7 @@ func TestInventoryMultipleReplicasFulFilled1(t *testing.T) {
 
 func TestInventoryMultipleReplicasFulFilled2(t *testing.T) {
 	scaffold := makeInventoryScaffold(t)
-	cl, err := NewClient(scaffold.ctx)
+	cl, err := NewClient(scaffold.ctx, ctypes.NewDefaultTEEConfig())
 	require.NoError(t, err)
 	require.NotNil(t, cl)
 
@@ -460,7 +461,7 @@ func TestInventoryMultipleReplicasFulFilled2(t *testing.T) {
 
 func TestInventoryMultipleReplicasFulFilled3(t *testing.T) {
 	scaffold := makeInventoryScaffold(t)
-	cl, err := NewClient(scaffold.ctx)
+	cl, err := NewClient(scaffold.ctx, ctypes.NewDefaultTEEConfig())
 	require.NoError(t, err)
 	require.NotNil(t, cl)
 
@@ -478,7 +479,7 @@ func TestInventoryMultipleReplicasFulFilled3(t *testing.T) {
 
 func TestInventoryMultipleReplicasFulFilled4(t *testing.T) {
 	scaffold := makeInventoryScaffold(t)
-	cl, err := NewClient(scaffold.ctx)
+	cl, err := NewClient(scaffold.ctx, ctypes.NewDefaultTEEConfig())
 	require.NoError(t, err)
 	require.NotNil(t, cl)
 
@@ -496,7 +497,7 @@ func TestInventoryMultipleReplicasFulFilled4(t *testing.T) {
 
 func TestInventoryMultipleReplicasFulFilled5(t *testing.T) {
 	scaffold := makeInventoryScaffold(t)
-	cl, err := NewClient(scaffold.ctx)
+	cl, err := NewClient(scaffold.ctx, ctypes.NewDefaultTEEConfig())
 	require.NoError(t, err)
 	require.NotNil(t, cl)
 
@@ -514,7 +515,7 @@ func TestInventoryMultipleReplicasFulFilled5(t *testing.T) {
 
 func TestInventoryMultipleReplicasFulFilled6(t *testing.T) {
 	scaffold := makeInventoryScaffold(t)
-	cl, err := NewClient(scaffold.ctx)
+	cl, err := NewClient(scaffold.ctx, ctypes.NewDefaultTEEConfig())
 	require.NoError(t, err)
 	require.NotNil(t, cl)
 
@@ -532,7 +533,7 @@ func TestInventoryMultipleReplicasFulFilled6(t *testing.T) {
 
 func TestInventoryMultipleReplicasFulFilled7(t *testing.T) {
 	scaffold := makeInventoryScaffold(t)
-	cl, err := NewClient(scaffold.ctx)
+	cl, err := NewClient(scaffold.ctx, ctypes.NewDefaultTEEConfig())
 	require.NoError(t, err)
 	require.NotNil(t, cl)
 
@@ -550,7 +551,7 @@ func TestInventoryMultipleReplicasFulFilled7(t *testing.T) {
 
 func TestInventoryMultipleReplicasOutOfCapacity1(t *testing.T) {
 	scaffold := makeInventoryScaffold(t)
-	cl, err := NewClient(scaffold.ctx)
+	cl, err := NewClient(scaffold.ctx, ctypes.NewDefaultTEEConfig())
 	require.NoError(t, err)
 	require.NotNil(t, cl)
 
@@ -569,7 +570,7 @@ func TestInventoryMultipleReplicasOutOfCapacity1(t *testing.T) {
 
 func TestInventoryMultipleReplicasOutOfCapacity2(t *testing.T) {
 	scaffold := makeInventoryScaffold(t)
-	cl, err := NewClient(scaffold.ctx)
+	cl, err := NewClient(scaffold.ctx, ctypes.NewDefaultTEEConfig())
 	require.NoError(t, err)
 	require.NotNil(t, cl)
 
@@ -588,7 +589,7 @@ func TestInventoryMultipleReplicasOutOfCapacity2(t *testing.T) {
 
 func TestInventoryMultipleReplicasOutOfCapacity4(t *testing.T) {
 	scaffold := makeInventoryScaffold(t)
-	cl, err := NewClient(scaffold.ctx)
+	cl, err := NewClient(scaffold.ctx, ctypes.NewDefaultTEEConfig())
 	require.NoError(t, err)
 	require.NotNil(t, cl)
 
@@ -802,3 +803,52 @@ func multipleSvcReplicasGenReservations(cpuUnits, gpuUnits uint64, count uint32)
 		},
 	}
 }
+
+func TestInventoryTEERequiresCapableNode(t *testing.T) {
+	inv := newInventory(inventoryV1.Cluster{
+		Nodes: multipleReplicasGenNodes(),
+	})
+	inv.tee = ctypes.NewDefaultTEEConfig()
+	inv.teeNodes = make(teeNodes)
+
+	reservation := multipleReplicasGenReservations(100000, 0, 1)
+	reservation.resources.Resources[0].Resources.CPU.Attributes = atypes.Attributes{
+		{
+			Key:   ctypes.TEEAttributeKey,
+			Value: "true",
+		},
+	}
+
+	err := inv.Adjust(reservation, ctypes.WithDryRun())
+	require.ErrorIs(t, err, ctypes.ErrInsufficientCapacity)
+
+	// node with TEE devices that do not match profile
+	require.True(t, inv.teeNodes.update("node3", map[string]string{
+		builder.AkashServiceCapabilityTEE:        "1",
+		builder.TEEDeviceLabel("/dev/sev-guest"): "1",
+	}))
+
+	err = inv.Adjust(reservation, ctypes.WithDryRun())
+	require.ErrorIs(t, err, ctypes.ErrInsufficientCapacity)
+
+	require.True(t, inv.teeNodes.update("node4", map[string]string{
+		builder.AkashServiceCapabilityTEE:     "1",
+		builder.TEEDeviceLabel("/dev/tpmrm0"): "1",
+	}))
+	require.False(t, inv.teeNodes.update("node4", map[string]string{
+		builder.AkashServiceCapabilityTEE:     "1",
+		builder.TEEDeviceLabel("/dev/tpmrm0"): "1",
+	}))
+
+	err = inv.Adjust(reservation)
+	require.NoError(t, err)
+
+	for _, node := range inv.Metrics().Nodes {
+		if node.Name == "node4" {
+			require.Equal(t, uint64(119800-305-100000), node.Available.CPU)
+		}
+	}
+
+	require.True(t, inv.teeNodes.remove("node4"))
+	require.False(t, inv.teeNodes.remove("node4"))
+}
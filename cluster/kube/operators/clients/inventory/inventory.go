@@ -3,6 +3,7 @@ package inventory
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 
 	inventoryV1 "github.com/akash-network/akash-api/go/inventory/v1"
@@ -27,12 +28,94 @@ func newInventory(clState inventoryV1.Cluster) *inventory {
 
 func (inv *inventory) dup() inventory {
 	dup := inventory{
-		Cluster: *inv.Cluster.Dup(),
+		Cluster:  *inv.Cluster.Dup(),
+		tee:      inv.tee,
+		teeNodes: inv.teeNodes.dup(),
 	}
 
 	return dup
 }
 
+// teeNodes maps node name to TEE capability labels node has been assigned by the inventory operator
+type teeNodes map[string]map[string]bool
+
+func (tn teeNodes) dup() teeNodes {
+	res := make(teeNodes, len(tn))
+
+	for name, labels := range tn {
+		nlabels := make(map[string]bool, len(labels))
+		for key := range labels {
+			nlabels[key] = true
+		}
+
+		res[name] = nlabels
+	}
+
+	return res
+}
+
+// update node's TEE capabilities from its labels. returns true if capabilities have changed
+func (tn teeNodes) update(name string, labels map[string]string) bool {
+	nlabels := make(map[string]bool)
+
+	for key, val := range labels {
+		if !strings.HasPrefix(key, builder.AkashServiceCapabilityTEE) {
+			continue
+		}
+
+		if cnt, err := strconv.ParseUint(val, 10, 32); err != nil || cnt == 0 {
+			continue
+		}
+
+		nlabels[key] = true
+	}
+
+	if len(nlabels) == 0 {
+		return tn.remove(name)
+	}
+
+	if reflect.DeepEqual(tn[name], nlabels) {
+		return false
+	}
+
+	tn[name] = nlabels
+
+	return true
+}
+
+func (tn teeNodes) remove(name string) bool {
+	if _, exists := tn[name]; !exists {
+		return false
+	}
+
+	delete(tn, name)
+
+	return true
+}
+
+// isTEECapable checks if node can run TEE sidecar requested by the resources
+func (inv *inventory) isTEECapable(node string, res *types.Resources) (bool, error) {
+	name, enabled := ctypes.ResourcesTEEProfile(*res)
+	if !enabled {
+		return true, nil
+	}
+
+	profile, err := inv.tee.Profile(name)
+	if err != nil {
+		return false, err
+	}
+
+	labels := inv.teeNodes[node]
+
+	for _, label := range builder.TEENodeLabels(profile) {
+		if !labels[label] {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
 func (inv *inventory) Dup() ctypes.Inventory {
 	dup := inv.dup()
 
@@ -46,6 +129,16 @@ func (inv *inventory) tryAdjust(node int, res *types.Resources) (*crd.SchedulerP
 	nd := inv.Nodes[node].Dup()
 	sparams := &crd.SchedulerParams{}
 
+	teeCapable, err := inv.isTEECapable(nd.Name, res)
+	if err != nil {
+		// unknown TEE profile cannot be satisfied by any node
+		return nil, false, false
+	}
+
+	if !teeCapable {
+		return nil, false, true
+	}
+
 	if !tryAdjustCPU(&nd.Resources.CPU.Quantity, res.CPU) {
 		return nil, false, true
 	}
@@ -210,6 +303,10 @@ func tryAdjustVolumesAttached(rp *inventoryV1.ResourcePair, res types.ResourceVa
 	return rp.SubNLZ(res)
 }
 
+func (inv *inventory) ReserveStorage(class string, quantity int64) error {
+	return cinventory.ReserveStorage(inv.Storage, class, quantity)
+}
+
 func (inv *inventory) Adjust(reservation ctypes.ReservationGroup, opts ...ctypes.InventoryOption) error {
 	cfg := &ctypes.InventoryOptions{}
 	for _, opt := range opts {
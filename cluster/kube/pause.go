@@ -0,0 +1,157 @@
+package kube
+
+import (
+	"context"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/akash-network/provider/cluster/kube/builder"
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
+)
+
+// LeasePaused reports if lease namespace is marked paused. lease which has not been deployed yet is not paused
+func (c *client) LeasePaused(ctx context.Context, lid mtypes.LeaseID) (bool, error) {
+	obj, err := wrapKubeCall("namespace-get", func() (*corev1.Namespace, error) {
+		return c.kc.CoreV1().Namespaces().Get(ctx, builder.LidNS(lid), metav1.GetOptions{})
+	})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return obj.Annotations[builder.AkashLeasePaused] == "true", nil
+}
+
+// PauseLease scales workloads of the lease to zero. namespace keeps the mark, so deploys of the lease
+// keep it scaled down until it is resumed
+func (c *client) PauseLease(ctx context.Context, lid mtypes.LeaseID) error {
+	if err := c.markLeasePaused(ctx, lid, true); err != nil {
+		return err
+	}
+
+	return c.scaleWorkloads(ctx, lid, func(string) int32 {
+		return 0
+	})
+}
+
+// ResumeLease scales workloads of the lease back to service counts of its manifest
+func (c *client) ResumeLease(ctx context.Context, lid mtypes.LeaseID) error {
+	found, group, err := c.GetManifestGroup(ctx, lid)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return kubeclienterrors.ErrLeaseNotFound
+	}
+
+	counts := make(map[string]int32, len(group.Services))
+	for _, svc := range group.Services {
+		counts[svc.Name] = int32(svc.Count) // nolint: gosec
+	}
+
+	if err = c.markLeasePaused(ctx, lid, false); err != nil {
+		return err
+	}
+
+	return c.scaleWorkloads(ctx, lid, func(name string) int32 {
+		return counts[name]
+	})
+}
+
+func (c *client) markLeasePaused(ctx context.Context, lid mtypes.LeaseID, paused bool) error {
+	obj, err := wrapKubeCall("namespace-get", func() (*corev1.Namespace, error) {
+		return c.kc.CoreV1().Namespaces().Get(ctx, builder.LidNS(lid), metav1.GetOptions{})
+	})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return kubeclienterrors.ErrLeaseNotFound
+		}
+
+		return err
+	}
+
+	if (obj.Annotations[builder.AkashLeasePaused] == "true") == paused {
+		return nil
+	}
+
+	if paused {
+		if obj.Annotations == nil {
+			obj.Annotations = make(map[string]string)
+		}
+
+		obj.Annotations[builder.AkashLeasePaused] = "true"
+	} else {
+		delete(obj.Annotations, builder.AkashLeasePaused)
+	}
+
+	_, err = wrapKubeCall("namespaces-update", func() (*corev1.Namespace, error) {
+		return c.kc.CoreV1().Namespaces().Update(ctx, obj, metav1.UpdateOptions{})
+	})
+
+	return err
+}
+
+// scaleWorkloads sets replicas of every deployment and statefulset of the lease to the count of the service they run
+func (c *client) scaleWorkloads(ctx context.Context, lid mtypes.LeaseID, count func(string) int32) error {
+	ns := builder.LidNS(lid)
+	selector := labels.SelectorFromSet(labels.Set{builder.AkashManagedLabelName: "true"}).String()
+
+	deployments, err := wrapKubeCall("deployments-list", func() (*appsv1.DeploymentList, error) {
+		return c.kc.AppsV1().Deployments(ns).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range deployments.Items {
+		obj := &deployments.Items[i]
+
+		replicas := count(obj.Name)
+		if obj.Spec.Replicas != nil && *obj.Spec.Replicas == replicas {
+			continue
+		}
+
+		obj.Spec.Replicas = &replicas
+
+		if _, err = wrapKubeCall("deployments-update", func() (*appsv1.Deployment, error) {
+			return c.kc.AppsV1().Deployments(ns).Update(ctx, obj, metav1.UpdateOptions{})
+		}); err != nil {
+			return err
+		}
+	}
+
+	statefulSets, err := wrapKubeCall("statefulsets-list", func() (*appsv1.StatefulSetList, error) {
+		return c.kc.AppsV1().StatefulSets(ns).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range statefulSets.Items {
+		obj := &statefulSets.Items[i]
+
+		replicas := count(obj.Name)
+		if obj.Spec.Replicas != nil && *obj.Spec.Replicas == replicas {
+			continue
+		}
+
+		obj.Spec.Replicas = &replicas
+
+		if _, err = wrapKubeCall("statefulsets-update", func() (*appsv1.StatefulSet, error) {
+			return c.kc.AppsV1().StatefulSets(ns).Update(ctx, obj, metav1.UpdateOptions{})
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
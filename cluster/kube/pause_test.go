@@ -0,0 +1,80 @@
+package kube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/akash-network/provider/cluster/kube/builder"
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
+	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
+)
+
+func TestPauseResumeLease(t *testing.T) {
+	ctx := context.Background()
+
+	cdep := quotaTestDeployment(t)
+	cdep.Group.Services[0].Count = 2
+	ns := builder.LidNS(cdep.Lid)
+	svcName := cdep.Group.Services[0].Name
+
+	mani, err := crd.NewManifest(testKubeClientNs, cdep.Lid, cdep.Group, cdep.Sparams)
+	require.NoError(t, err)
+
+	replicas := int32(2)
+	c := clientForTest(t, []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      svcName,
+				Namespace: ns,
+				Labels:    map[string]string{builder.AkashManagedLabelName: "true"},
+			},
+			Spec: appsv1.DeploymentSpec{Replicas: &replicas},
+		},
+	}, []runtime.Object{mani}).(*client)
+
+	paused, err := c.LeasePaused(ctx, cdep.Lid)
+	require.NoError(t, err)
+	require.False(t, paused)
+
+	require.NoError(t, c.PauseLease(ctx, cdep.Lid))
+
+	paused, err = c.LeasePaused(ctx, cdep.Lid)
+	require.NoError(t, err)
+	require.True(t, paused)
+
+	obj, err := c.kc.AppsV1().Deployments(ns).Get(ctx, svcName, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, int32(0), *obj.Spec.Replicas)
+
+	// workloads of paused lease are built scaled down
+	cdep.SetPaused(paused)
+	built, err := builder.NewDeployment(builder.NewWorkloadBuilder(c.log, builder.NewDefaultSettings(), cdep, 0)).Create()
+	require.NoError(t, err)
+	require.Equal(t, int32(0), *built.Spec.Replicas)
+
+	require.NoError(t, c.ResumeLease(ctx, cdep.Lid))
+
+	paused, err = c.LeasePaused(ctx, cdep.Lid)
+	require.NoError(t, err)
+	require.False(t, paused)
+
+	obj, err = c.kc.AppsV1().Deployments(ns).Get(ctx, svcName, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), *obj.Spec.Replicas)
+}
+
+func TestPauseLeaseNotFound(t *testing.T) {
+	cdep := quotaTestDeployment(t)
+
+	c := clientForTest(t, nil, nil)
+
+	err := c.PauseLease(context.Background(), cdep.Lid)
+	require.ErrorIs(t, err, kubeclienterrors.ErrLeaseNotFound)
+}
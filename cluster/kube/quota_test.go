@@ -0,0 +1,153 @@
+package kube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akash-network/node/sdl"
+	"github.com/akash-network/node/testutil"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/akash-network/provider/cluster/kube/builder"
+	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
+	afake "github.com/akash-network/provider/pkg/client/clientset/versioned/fake"
+)
+
+func quotaTestDeployment(t *testing.T) *builder.ClusterDeployment {
+	t.Helper()
+
+	sdl, err := sdl.ReadFile("../../testdata/deployment/deployment.yaml")
+	require.NoError(t, err)
+
+	mani, err := sdl.Manifest()
+	require.NoError(t, err)
+
+	group := mani.GetGroups()[0]
+
+	return &builder.ClusterDeployment{
+		Lid:     testutil.LeaseID(t),
+		Group:   &group,
+		Sparams: crd.ClusterSettings{SchedulerParams: make([]*crd.SchedulerParams, len(group.Services))},
+	}
+}
+
+func TestApplyResourceQuotaRecover(t *testing.T) {
+	ctx := context.Background()
+	log := testutil.Logger(t)
+
+	cdep := quotaTestDeployment(t)
+	ns := builder.LidNS(cdep.Lid)
+
+	prev := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      builder.AkashLeaseQuotaName,
+			Namespace: ns,
+			Labels:    map[string]string{builder.AkashManagedLabelName: "true"},
+		},
+		Spec: corev1.ResourceQuotaSpec{
+			Hard: corev1.ResourceList{
+				corev1.ResourcePods: resource.MustParse("5"),
+			},
+		},
+	}
+
+	kc := fake.NewSimpleClientset(prev)
+	ac := afake.NewSimpleClientset()
+
+	po := &previousObj{}
+
+	var err error
+	po.nQuota, po.uQuota, po.oQuota, err = applyResourceQuota(ctx, kc, builder.BuildResourceQuota(log, builder.NewDefaultSettings(), cdep))
+	require.NoError(t, err)
+	require.Nil(t, po.nQuota)
+	require.NotNil(t, po.uQuota)
+	require.Equal(t, prev.Spec, po.oQuota.Spec)
+
+	po.nLimitRange, po.uLimitRange, po.oLimitRange, err = applyLimitRange(ctx, kc, builder.BuildLimitRange(log, builder.NewDefaultSettings(), cdep))
+	require.NoError(t, err)
+	require.NotNil(t, po.nLimitRange)
+	require.Nil(t, po.oLimitRange)
+
+	quota, err := kc.CoreV1().ResourceQuotas(ns).Get(ctx, builder.AkashLeaseQuotaName, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	pods := quota.Spec.Hard[corev1.ResourcePods]
+	require.Equal(t, int64(1), pods.Value())
+
+	require.Empty(t, po.recover(ctx, kc, ac))
+
+	quota, err = kc.CoreV1().ResourceQuotas(ns).Get(ctx, builder.AkashLeaseQuotaName, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	pods = quota.Spec.Hard[corev1.ResourcePods]
+	require.Equal(t, int64(5), pods.Value())
+
+	_, err = kc.CoreV1().LimitRanges(ns).Get(ctx, builder.AkashLeaseLimitRangeName, metav1.GetOptions{})
+	require.True(t, kerrors.IsNotFound(err))
+}
+
+func TestCleanupStaleQuotas(t *testing.T) {
+	ctx := context.Background()
+
+	cdep := quotaTestDeployment(t)
+	ns := builder.LidNS(cdep.Lid)
+
+	managed := map[string]string{builder.AkashManagedLabelName: "true"}
+
+	quota := func(name string, labels map[string]string) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ns,
+				Labels:    labels,
+			},
+		}
+	}
+
+	limitRange := func(name string, labels map[string]string) *corev1.LimitRange {
+		return &corev1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ns,
+				Labels:    labels,
+			},
+		}
+	}
+
+	kc := fake.NewSimpleClientset(
+		quota(builder.AkashLeaseQuotaName, managed),
+		quota("stale-quota", managed),
+		quota("operator-quota", nil),
+		limitRange(builder.AkashLeaseLimitRangeName, managed),
+		limitRange("stale-limits", managed),
+		limitRange("operator-limits", nil),
+	)
+
+	stale, err := cleanupStaleResources(ctx, kc, cdep.Lid, cdep.Group)
+	require.NoError(t, err)
+	require.Len(t, stale.quotas, 1)
+	require.Len(t, stale.limitRanges, 1)
+
+	quotas, err := kc.CoreV1().ResourceQuotas(ns).List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(quotas.Items))
+	for _, item := range quotas.Items {
+		names = append(names, item.Name)
+	}
+	require.ElementsMatch(t, []string{builder.AkashLeaseQuotaName, "operator-quota"}, names)
+
+	limits, err := kc.CoreV1().LimitRanges(ns).List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+
+	names = names[:0]
+	for _, item := range limits.Items {
+		names = append(names, item.Name)
+	}
+	require.ElementsMatch(t, []string{builder.AkashLeaseLimitRangeName, "operator-limits"}, names)
+}
@@ -0,0 +1,246 @@
+package kube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/akash-network/provider/cluster/kube/builder"
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
+)
+
+const (
+	// manifestRevisionsName is the config map in the lease namespace keeping manifest groups deployed to the lease
+	manifestRevisionsName = "akash-manifest-revisions"
+	// manifestRevisionHistoryLimit matches revision history kept for deployments of the lease
+	manifestRevisionHistoryLimit = 10
+)
+
+type manifestRevision struct {
+	DeployedAt time.Time         `json:"deployed_at"`
+	Group      crd.ManifestGroup `json:"group"`
+}
+
+// manifestRevisions loads revision history of the lease. config map is nil if lease has none yet
+func (c *client) manifestRevisions(ctx context.Context, lid mtypes.LeaseID) (*corev1.ConfigMap, []uint64, error) {
+	obj, err := wrapKubeCall("configmaps-get", func() (*corev1.ConfigMap, error) {
+		return c.kc.CoreV1().ConfigMaps(builder.LidNS(lid)).Get(ctx, manifestRevisionsName, metav1.GetOptions{})
+	})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil, nil
+		}
+
+		return nil, nil, err
+	}
+
+	revisions := make([]uint64, 0, len(obj.Data))
+	for key := range obj.Data {
+		rev, err := strconv.ParseUint(key, 10, 64)
+		if err != nil {
+			c.log.Error("invalid manifest revision", "lease-ns", builder.LidNS(lid), "revision", key)
+			continue
+		}
+
+		revisions = append(revisions, rev)
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i] < revisions[j]
+	})
+
+	return obj, revisions, nil
+}
+
+func decodeManifestRevision(obj *corev1.ConfigMap, rev uint64) (manifestRevision, error) {
+	var res manifestRevision
+
+	if err := json.Unmarshal([]byte(obj.Data[strconv.FormatUint(rev, 10)]), &res); err != nil {
+		return manifestRevision{}, fmt.Errorf("%w: manifest revision %d: %w", kubeclienterrors.ErrInternalError, rev, err)
+	}
+
+	return res, nil
+}
+
+// recordManifestRevision adds manifest group lease has been deployed with to its revision history.
+// lease is deployed regardless, so failure is only logged
+func (c *client) recordManifestRevision(ctx context.Context, lid mtypes.LeaseID, po *previousObj) {
+	mani := po.umani
+	if mani == nil {
+		mani = po.nmani
+	}
+
+	if mani == nil {
+		mani = po.omani
+	}
+
+	if mani == nil {
+		return
+	}
+
+	if err := c.doRecordManifestRevision(ctx, lid, mani.Spec.Group); err != nil {
+		c.log.Error("recording manifest revision", "err", err, "lease", lid)
+	}
+}
+
+func (c *client) doRecordManifestRevision(ctx context.Context, lid mtypes.LeaseID, group crd.ManifestGroup) error {
+	obj, revisions, err := c.manifestRevisions(ctx, lid)
+	if err != nil {
+		return err
+	}
+
+	create := obj == nil
+	if create {
+		obj = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      manifestRevisionsName,
+				Namespace: builder.LidNS(lid),
+				Labels: map[string]string{
+					builder.AkashManagedLabelName: "true",
+				},
+			},
+		}
+	}
+
+	if obj.Data == nil {
+		obj.Data = make(map[string]string)
+	}
+
+	next := uint64(1)
+
+	if len(revisions) != 0 {
+		latest := revisions[len(revisions)-1]
+
+		// lease has been re-deployed with the manifest it runs, e.g. after provider restart
+		if prev, err := decodeManifestRevision(obj, latest); err == nil && reflect.DeepEqual(prev.Group, group) {
+			return nil
+		}
+
+		next = latest + 1
+	}
+
+	data, err := json.Marshal(manifestRevision{
+		DeployedAt: time.Now().UTC(),
+		Group:      group,
+	})
+	if err != nil {
+		return err
+	}
+
+	obj.Data[strconv.FormatUint(next, 10)] = string(data)
+
+	revisions = append(revisions, next)
+	for len(revisions) > manifestRevisionHistoryLimit {
+		delete(obj.Data, strconv.FormatUint(revisions[0], 10))
+		revisions = revisions[1:]
+	}
+
+	if create {
+		_, err = wrapKubeCall("configmaps-create", func() (*corev1.ConfigMap, error) {
+			return c.kc.CoreV1().ConfigMaps(obj.Namespace).Create(ctx, obj, metav1.CreateOptions{})
+		})
+	} else {
+		_, err = wrapKubeCall("configmaps-update", func() (*corev1.ConfigMap, error) {
+			return c.kc.CoreV1().ConfigMaps(obj.Namespace).Update(ctx, obj, metav1.UpdateOptions{})
+		})
+	}
+
+	return err
+}
+
+// ManifestRevisions lists revision history of the lease, oldest first
+func (c *client) ManifestRevisions(ctx context.Context, lid mtypes.LeaseID) ([]ctypes.ManifestRevision, error) {
+	if err := c.leaseExists(ctx, lid); err != nil {
+		return nil, err
+	}
+
+	obj, revisions, err := c.manifestRevisions(ctx, lid)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]ctypes.ManifestRevision, 0, len(revisions))
+
+	for idx, rev := range revisions {
+		val, err := decodeManifestRevision(obj, rev)
+		if err != nil {
+			return nil, err
+		}
+
+		services := make([]string, 0, len(val.Group.Services))
+		for _, svc := range val.Group.Services {
+			services = append(services, svc.Name)
+		}
+
+		res = append(res, ctypes.ManifestRevision{
+			Revision:   rev,
+			DeployedAt: val.DeployedAt,
+			Current:    idx == len(revisions)-1,
+			Services:   services,
+		})
+	}
+
+	return res, nil
+}
+
+// ManifestRevision returns deployment of the lease with manifest group of given revision.
+// zero revision refers to the one before current
+func (c *client) ManifestRevision(ctx context.Context, lid mtypes.LeaseID, revision uint64) (ctypes.IDeployment, error) {
+	if err := c.leaseExists(ctx, lid); err != nil {
+		return nil, err
+	}
+
+	obj, revisions, err := c.manifestRevisions(ctx, lid)
+	if err != nil {
+		return nil, err
+	}
+
+	if revision == 0 {
+		if len(revisions) < 2 {
+			return nil, fmt.Errorf("%w: lease has no previous revision", kubeclienterrors.ErrNoManifestRevision)
+		}
+
+		revision = revisions[len(revisions)-2]
+	}
+
+	if obj == nil {
+		return nil, fmt.Errorf("%w: %d", kubeclienterrors.ErrNoManifestRevision, revision)
+	}
+
+	if _, exists := obj.Data[strconv.FormatUint(revision, 10)]; !exists {
+		return nil, fmt.Errorf("%w: %d", kubeclienterrors.ErrNoManifestRevision, revision)
+	}
+
+	val, err := decodeManifestRevision(obj, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	group, sparams, err := val.Group.FromCRD()
+	if err != nil {
+		return nil, err
+	}
+
+	// scheduler params are given per resources, so deploy writes manifest of the revision back to CRD
+	cparams := make(crd.ReservationClusterSettings, len(group.Services))
+	for idx := range group.Services {
+		cparams[group.Services[idx].Resources.ID] = sparams[idx]
+	}
+
+	return &ctypes.Deployment{
+		Lid:     lid,
+		MGroup:  &group,
+		CParams: cparams,
+	}, nil
+}
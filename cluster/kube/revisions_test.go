@@ -0,0 +1,85 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/akash-network/provider/cluster/kube/builder"
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
+	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
+)
+
+func TestManifestRevisions(t *testing.T) {
+	ctx := context.Background()
+
+	cdep := quotaTestDeployment(t)
+
+	c := rolloutTestClient(t, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: builder.LidNS(cdep.Lid)},
+	})
+
+	_, err := c.ManifestRevision(ctx, cdep.Lid, 0)
+	require.ErrorIs(t, err, kubeclienterrors.ErrNoManifestRevision)
+
+	first, err := crd.NewManifest(testKubeClientNs, cdep.Lid, cdep.Group, cdep.Sparams)
+	require.NoError(t, err)
+
+	second := first.DeepCopy()
+	second.Spec.Group.Services[0].Image = "nginx:1.27"
+
+	require.NoError(t, c.doRecordManifestRevision(ctx, cdep.Lid, first.Spec.Group))
+	// lease redeployed with the same manifest does not make a new revision
+	require.NoError(t, c.doRecordManifestRevision(ctx, cdep.Lid, first.Spec.Group))
+	require.NoError(t, c.doRecordManifestRevision(ctx, cdep.Lid, second.Spec.Group))
+
+	revisions, err := c.ManifestRevisions(ctx, cdep.Lid)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	require.Equal(t, uint64(1), revisions[0].Revision)
+	require.False(t, revisions[0].Current)
+	require.Equal(t, uint64(2), revisions[1].Revision)
+	require.True(t, revisions[1].Current)
+	require.Equal(t, []string{cdep.Group.Services[0].Name}, revisions[1].Services)
+
+	deployment, err := c.ManifestRevision(ctx, cdep.Lid, 0)
+	require.NoError(t, err)
+	require.Equal(t, cdep.Lid, deployment.LeaseID())
+	require.Equal(t, cdep.Group.Services[0].Image, deployment.ManifestGroup().Services[0].Image)
+	require.IsType(t, crd.ReservationClusterSettings{}, deployment.ClusterParams())
+
+	deployment, err = c.ManifestRevision(ctx, cdep.Lid, 2)
+	require.NoError(t, err)
+	require.Equal(t, "nginx:1.27", deployment.ManifestGroup().Services[0].Image)
+
+	_, err = c.ManifestRevision(ctx, cdep.Lid, 3)
+	require.ErrorIs(t, err, kubeclienterrors.ErrNoManifestRevision)
+}
+
+func TestManifestRevisionsHistoryLimit(t *testing.T) {
+	ctx := context.Background()
+
+	cdep := quotaTestDeployment(t)
+
+	c := rolloutTestClient(t, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: builder.LidNS(cdep.Lid)},
+	})
+
+	mani, err := crd.NewManifest(testKubeClientNs, cdep.Lid, cdep.Group, cdep.Sparams)
+	require.NoError(t, err)
+
+	for i := 0; i < manifestRevisionHistoryLimit+2; i++ {
+		mani.Spec.Group.Services[0].Image = fmt.Sprintf("nginx:1.%d", i)
+		require.NoError(t, c.doRecordManifestRevision(ctx, cdep.Lid, mani.Spec.Group))
+	}
+
+	revisions, err := c.ManifestRevisions(ctx, cdep.Lid)
+	require.NoError(t, err)
+	require.Len(t, revisions, manifestRevisionHistoryLimit)
+	require.Equal(t, uint64(3), revisions[0].Revision)
+	require.Equal(t, uint64(manifestRevisionHistoryLimit+2), revisions[len(revisions)-1].Revision)
+}
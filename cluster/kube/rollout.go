@@ -0,0 +1,136 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/akash-network/provider/cluster/kube/builder"
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
+)
+
+const (
+	// deploymentReasonProgressDeadline is reason of Progressing condition of the deployment
+	// which did not make progress within its progress deadline
+	deploymentReasonProgressDeadline = "ProgressDeadlineExceeded"
+)
+
+var rolloutPollInterval = 2 * time.Second
+
+// waitForRollout waits for every deployment and statefulset of the lease to run ready replicas of its latest revision.
+// error wrapping ErrRolloutFailed is returned if they do not within timeout
+func (c *client) waitForRollout(ctx context.Context, lid mtypes.LeaseID, timeout time.Duration) error {
+	ns := builder.LidNS(lid)
+	selector := labels.SelectorFromSet(labels.Set{builder.AkashManagedLabelName: "true"}).String()
+
+	var pending string
+
+	err := wait.PollUntilContextTimeout(ctx, rolloutPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		deployments, err := wrapKubeCall("deployments-list", func() (*appsv1.DeploymentList, error) {
+			return c.kc.AppsV1().Deployments(ns).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		})
+		if err != nil {
+			return false, err
+		}
+
+		for i := range deployments.Items {
+			obj := &deployments.Items[i]
+
+			msg, failed := deploymentRolloutStatus(obj)
+			if failed {
+				return false, fmt.Errorf("%w: deployment %s: %s", kubeclienterrors.ErrRolloutFailed, obj.Name, msg)
+			}
+
+			if msg != "" {
+				pending = fmt.Sprintf("deployment %s: %s", obj.Name, msg)
+				return false, nil
+			}
+		}
+
+		statefulSets, err := wrapKubeCall("statefulsets-list", func() (*appsv1.StatefulSetList, error) {
+			return c.kc.AppsV1().StatefulSets(ns).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		})
+		if err != nil {
+			return false, err
+		}
+
+		for i := range statefulSets.Items {
+			obj := &statefulSets.Items[i]
+
+			if msg := statefulSetRolloutStatus(obj); msg != "" {
+				pending = fmt.Sprintf("statefulset %s: %s", obj.Name, msg)
+				return false, nil
+			}
+		}
+
+		return true, nil
+	})
+
+	if err != nil && wait.Interrupted(err) && ctx.Err() == nil {
+		return fmt.Errorf("%w: not ready within %s: %s", kubeclienterrors.ErrRolloutFailed, timeout, pending)
+	}
+
+	return err
+}
+
+// deploymentRolloutStatus returns what rollout of the deployment is waiting for, empty if it is complete.
+// failed is true if deployment won't make any progress
+func deploymentRolloutStatus(obj *appsv1.Deployment) (string, bool) {
+	if obj.Generation > obj.Status.ObservedGeneration {
+		return "waiting for update to be observed", false
+	}
+
+	for _, cond := range obj.Status.Conditions {
+		if cond.Type == appsv1.DeploymentProgressing &&
+			cond.Status == corev1.ConditionFalse &&
+			cond.Reason == deploymentReasonProgressDeadline {
+			return cond.Message, true
+		}
+	}
+
+	replicas := int32(1)
+	if obj.Spec.Replicas != nil {
+		replicas = *obj.Spec.Replicas
+	}
+
+	switch {
+	case obj.Status.UpdatedReplicas < replicas:
+		return fmt.Sprintf("%d of %d replicas updated", obj.Status.UpdatedReplicas, replicas), false
+	case obj.Status.Replicas > obj.Status.UpdatedReplicas:
+		return fmt.Sprintf("%d old replicas pending termination", obj.Status.Replicas-obj.Status.UpdatedReplicas), false
+	case obj.Status.AvailableReplicas < obj.Status.UpdatedReplicas:
+		return fmt.Sprintf("%d of %d updated replicas available", obj.Status.AvailableReplicas, obj.Status.UpdatedReplicas), false
+	}
+
+	return "", false
+}
+
+// statefulSetRolloutStatus returns what rollout of the statefulset is waiting for, empty if it is complete
+func statefulSetRolloutStatus(obj *appsv1.StatefulSet) string {
+	if obj.Status.ObservedGeneration == 0 || obj.Generation > obj.Status.ObservedGeneration {
+		return "waiting for update to be observed"
+	}
+
+	replicas := int32(1)
+	if obj.Spec.Replicas != nil {
+		replicas = *obj.Spec.Replicas
+	}
+
+	switch {
+	case obj.Status.UpdatedReplicas < replicas:
+		return fmt.Sprintf("%d of %d replicas updated", obj.Status.UpdatedReplicas, replicas)
+	case obj.Status.ReadyReplicas < replicas:
+		return fmt.Sprintf("%d of %d replicas ready", obj.Status.ReadyReplicas, replicas)
+	case obj.Status.UpdateRevision != obj.Status.CurrentRevision:
+		return "waiting for revision " + obj.Status.UpdateRevision
+	}
+
+	return ""
+}
@@ -0,0 +1,184 @@
+package kube
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/akash-network/node/testutil"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/akash-network/provider/cluster/kube/builder"
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
+	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
+	afake "github.com/akash-network/provider/pkg/client/clientset/versioned/fake"
+)
+
+func rolloutTestDeployment(ns string, name string, updated int32, available int32) *appsv1.Deployment {
+	replicas := int32(1)
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       name,
+			Namespace:  ns,
+			Generation: 2,
+			Labels:     map[string]string{builder.AkashManagedLabelName: "true"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+		},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 2,
+			Replicas:           updated,
+			UpdatedReplicas:    updated,
+			AvailableReplicas:  available,
+		},
+	}
+}
+
+func rolloutTestClient(t *testing.T, kobjs ...runtime.Object) *client {
+	return clientForTest(t, kobjs, nil).(*client)
+}
+
+func setRolloutPollInterval(t *testing.T, val time.Duration) {
+	prev := rolloutPollInterval
+	rolloutPollInterval = val
+
+	t.Cleanup(func() {
+		rolloutPollInterval = prev
+	})
+}
+
+func TestWaitForRolloutReady(t *testing.T) {
+	setRolloutPollInterval(t, 10*time.Millisecond)
+
+	lid := testutil.LeaseID(t)
+	ns := builder.LidNS(lid)
+
+	replicas := int32(1)
+	sset := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "db",
+			Namespace:  ns,
+			Generation: 1,
+			Labels:     map[string]string{builder.AkashManagedLabelName: "true"},
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: &replicas,
+		},
+		Status: appsv1.StatefulSetStatus{
+			ObservedGeneration: 1,
+			UpdatedReplicas:    1,
+			ReadyReplicas:      1,
+			CurrentRevision:    "db-1",
+			UpdateRevision:     "db-1",
+		},
+	}
+
+	c := rolloutTestClient(t, rolloutTestDeployment(ns, "web", 1, 1), sset)
+
+	require.NoError(t, c.waitForRollout(context.Background(), lid, time.Second))
+}
+
+func TestWaitForRolloutProgressDeadline(t *testing.T) {
+	setRolloutPollInterval(t, 10*time.Millisecond)
+
+	lid := testutil.LeaseID(t)
+
+	obj := rolloutTestDeployment(builder.LidNS(lid), "web", 1, 0)
+	obj.Status.Conditions = []appsv1.DeploymentCondition{
+		{
+			Type:    appsv1.DeploymentProgressing,
+			Status:  corev1.ConditionFalse,
+			Reason:  deploymentReasonProgressDeadline,
+			Message: `ReplicaSet "web-1" has timed out progressing.`,
+		},
+	}
+
+	c := rolloutTestClient(t, obj)
+
+	start := time.Now()
+	err := c.waitForRollout(context.Background(), lid, time.Minute)
+	require.ErrorIs(t, err, kubeclienterrors.ErrRolloutFailed)
+	require.Contains(t, err.Error(), "deployment web")
+	require.Less(t, time.Since(start), time.Minute)
+}
+
+func TestWaitForRolloutTimeout(t *testing.T) {
+	setRolloutPollInterval(t, 10*time.Millisecond)
+
+	lid := testutil.LeaseID(t)
+
+	c := rolloutTestClient(t, rolloutTestDeployment(builder.LidNS(lid), "web", 1, 0))
+
+	err := c.waitForRollout(context.Background(), lid, 50*time.Millisecond)
+	require.ErrorIs(t, err, kubeclienterrors.ErrRolloutFailed)
+	require.Contains(t, err.Error(), "0 of 1 updated replicas available")
+}
+
+func TestRecoverRestoresPreviousRevision(t *testing.T) {
+	ctx := context.Background()
+
+	cdep := quotaTestDeployment(t)
+	ns := builder.LidNS(cdep.Lid)
+
+	prevDeployment := rolloutTestDeployment(ns, "web", 1, 1)
+	prevDeployment.Spec.Template.Spec.Containers = []corev1.Container{{Name: "web", Image: "web:v1"}}
+
+	staleDeployment := rolloutTestDeployment(ns, "api", 1, 1)
+	staleDeployment.UID = "stale-uid"
+	staleDeployment.ResourceVersion = "7"
+
+	prevMani, err := crd.NewManifest(testKubeClientNs, cdep.Lid, cdep.Group, cdep.Sparams)
+	require.NoError(t, err)
+
+	kc := fake.NewSimpleClientset(prevDeployment)
+	ac := afake.NewSimpleClientset(prevMani)
+
+	po := &previousObj{
+		oDeployments: []*appsv1.Deployment{prevDeployment.DeepCopy()},
+		stale:        &staleObjs{deployments: []appsv1.Deployment{*staleDeployment}},
+	}
+
+	// simulate update of the lease to the revision which does not roll out
+	updDeployment := prevDeployment.DeepCopy()
+	updDeployment.Spec.Template.Spec.Containers[0].Image = "web:v2"
+	po.uDeployments = []*appsv1.Deployment{updDeployment}
+	_, err = kc.AppsV1().Deployments(ns).Update(ctx, updDeployment, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	newDeployment := rolloutTestDeployment(ns, "db", 0, 0)
+	po.nDeployments = []*appsv1.Deployment{newDeployment}
+	_, err = kc.AppsV1().Deployments(ns).Create(ctx, newDeployment, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	po.omani, err = ac.AkashV2beta2().Manifests(testKubeClientNs).Get(ctx, prevMani.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	updMani := po.omani.DeepCopy()
+	updMani.Spec.Group.Services = updMani.Spec.Group.Services[:0]
+	po.umani, err = ac.AkashV2beta2().Manifests(testKubeClientNs).Update(ctx, updMani, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	require.Empty(t, po.recover(ctx, kc, ac))
+
+	web, err := kc.AppsV1().Deployments(ns).Get(ctx, "web", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "web:v1", web.Spec.Template.Spec.Containers[0].Image)
+
+	_, err = kc.AppsV1().Deployments(ns).Get(ctx, "db", metav1.GetOptions{})
+	require.Error(t, err)
+
+	api, err := kc.AppsV1().Deployments(ns).Get(ctx, "api", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Empty(t, api.UID)
+
+	mani, err := ac.AkashV2beta2().Manifests(testKubeClientNs).Get(ctx, prevMani.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, prevMani.Spec.Group, mani.Spec.Group)
+}
@@ -0,0 +1,417 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	mapi "github.com/akash-network/akash-api/go/manifest/v2beta2"
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/akash-network/node/sdl"
+
+	"github.com/akash-network/provider/cluster/kube/builder"
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+var volumeSnapshotGVR = schema.GroupVersionResource{
+	Group:    builder.VolumeSnapshotAPIGroup,
+	Version:  "v1",
+	Resource: "volumesnapshots",
+}
+
+var (
+	volumeRestorePollInterval = 2 * time.Second
+	volumeRestoreTimeout      = 5 * time.Minute
+)
+
+// volumeSnapshotsSelector selects volume snapshots taken by the provider
+func volumeSnapshotsSelector() string {
+	snapshotExists, _ := labels.NewRequirement(builder.AkashVolumeSnapshotLabelName, selection.Exists, nil)
+
+	return labels.SelectorFromSet(labels.Set{builder.AkashManagedLabelName: "true"}).Add(*snapshotExists).String()
+}
+
+// SnapshotLeaseVolumes takes snapshot of every persistent volume claim of the lease.
+// snapshot of the claim is named <name>-<claim name>
+func (c *client) SnapshotLeaseVolumes(ctx context.Context, lid mtypes.LeaseID, name string) ([]ctypes.VolumeSnapshot, error) {
+	settings, valid := ctx.Value(builder.SettingsKey).(builder.Settings)
+	if !valid {
+		return nil, kubeclienterrors.ErrNotConfiguredWithSettings
+	}
+
+	if settings.VolumeSnapshotClass == "" {
+		return nil, kubeclienterrors.ErrVolumeSnapshotsDisabled
+	}
+
+	if errs := validation.IsDNS1123Label(name); len(errs) != 0 {
+		return nil, fmt.Errorf("%w: %q: %s", kubeclienterrors.ErrInvalidVolumeSnapshotName, name, strings.Join(errs, ", "))
+	}
+
+	found, mgroup, err := c.GetManifestGroup(ctx, lid)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, kubeclienterrors.ErrLeaseNotFound
+	}
+
+	group, _, err := mgroup.FromCRD()
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := c.LeaseSnapshots(ctx, lid)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, snapshot := range existing {
+		if snapshot.Snapshot == name {
+			return nil, fmt.Errorf("%w: %s", kubeclienterrors.ErrVolumeSnapshotExists, name)
+		}
+	}
+
+	ns := builder.LidNS(lid)
+
+	pvcs, err := wrapKubeCall("persistentvolumeclaims-list", func() (*corev1.PersistentVolumeClaimList, error) {
+		return c.kc.CoreV1().PersistentVolumeClaims(ns).List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var objs []*unstructured.Unstructured // nolint: prealloc
+
+	for i := range pvcs.Items {
+		pvc := &pvcs.Items[i]
+
+		service, volume, owned := leaseVolumeOfClaim(group.Services, pvc.Name)
+		if !owned {
+			continue
+		}
+
+		obj, err := buildVolumeSnapshot(lid, name, settings.VolumeSnapshotClass, service, volume, pvc)
+		if err != nil {
+			return nil, err
+		}
+
+		objs = append(objs, obj)
+	}
+
+	if len(objs) == 0 {
+		return nil, kubeclienterrors.ErrNoPersistentVolumes
+	}
+
+	res := make([]ctypes.VolumeSnapshot, 0, len(objs))
+
+	for _, obj := range objs {
+		obj, err = wrapKubeCall("volumesnapshots-create", func() (*unstructured.Unstructured, error) {
+			return c.dc.Resource(volumeSnapshotGVR).Namespace(ns).Create(ctx, obj, metav1.CreateOptions{})
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, volumeSnapshotStatus(obj))
+	}
+
+	return res, nil
+}
+
+// LeaseSnapshots lists volume snapshots taken of the lease, oldest first
+func (c *client) LeaseSnapshots(ctx context.Context, lid mtypes.LeaseID) ([]ctypes.VolumeSnapshot, error) {
+	list, err := wrapKubeCall("volumesnapshots-list", func() (*unstructured.UnstructuredList, error) {
+		return c.dc.Resource(volumeSnapshotGVR).Namespace(builder.LidNS(lid)).List(ctx, metav1.ListOptions{
+			LabelSelector: volumeSnapshotsSelector(),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]ctypes.VolumeSnapshot, 0, len(list.Items))
+	for i := range list.Items {
+		res = append(res, volumeSnapshotStatus(&list.Items[i]))
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].CreatedAt.Equal(res[j].CreatedAt) {
+			return res[i].Name < res[j].Name
+		}
+
+		return res[i].CreatedAt.Before(res[j].CreatedAt)
+	})
+
+	return res, nil
+}
+
+// SnapshotStorage returns storage volume snapshots of all leases take, in bytes by storage class
+func (c *client) SnapshotStorage(ctx context.Context) (map[string]int64, error) {
+	list, err := wrapKubeCall("volumesnapshots-list", func() (*unstructured.UnstructuredList, error) {
+		return c.dc.Resource(volumeSnapshotGVR).List(ctx, metav1.ListOptions{
+			LabelSelector: volumeSnapshotsSelector(),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]int64)
+	for i := range list.Items {
+		snapshot := volumeSnapshotStatus(&list.Items[i])
+		res[snapshot.StorageClass] += snapshot.RestoreSize
+	}
+
+	return res, nil
+}
+
+// restoreVolumes prepares volumes of the statefulset manifest asks to restore from snapshot.
+// claim templates of existing statefulset cannot be updated, so statefulset is deleted along with
+// claims of volumes to restore, and claims are created anew from the snapshot once statefulset is applied.
+// data volumes had before restore is gone. deleted statefulset is returned, even along with the error,
+// so it can be recreated if deployment is rolled back
+func (c *client) restoreVolumes(ctx context.Context, b builder.StatefulSet) (*appsv1.StatefulSet, error) {
+	desired, err := b.Create()
+	if err != nil {
+		return nil, err
+	}
+
+	var restore []corev1.PersistentVolumeClaim
+
+	for _, pvc := range desired.Spec.VolumeClaimTemplates {
+		if pvc.Spec.DataSource != nil {
+			restore = append(restore, pvc)
+		}
+	}
+
+	if len(restore) == 0 {
+		return nil, nil
+	}
+
+	curr, err := wrapKubeCall("statefulsets-get", func() (*appsv1.StatefulSet, error) {
+		return c.kc.AppsV1().StatefulSets(b.NS()).Get(ctx, b.Name(), metav1.GetOptions{})
+	})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	if err == nil {
+		restore = slices.DeleteFunc(restore, func(pvc corev1.PersistentVolumeClaim) bool {
+			for _, tmpl := range curr.Spec.VolumeClaimTemplates {
+				if tmpl.Name == pvc.Name {
+					return reflect.DeepEqual(tmpl.Spec.DataSource, pvc.Spec.DataSource)
+				}
+			}
+
+			return false
+		})
+
+		if len(restore) == 0 {
+			return nil, nil
+		}
+	} else {
+		curr = nil
+	}
+
+	for _, pvc := range restore {
+		if err = c.checkVolumeSnapshotReady(ctx, b.NS(), pvc.Spec.DataSource.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	if curr == nil {
+		return nil, nil
+	}
+
+	c.log.Info("recreating statefulset to restore volumes from snapshot", "ns", b.NS(), "statefulset", b.Name())
+
+	if err = c.kc.AppsV1().StatefulSets(b.NS()).Delete(ctx, b.Name(), metav1.DeleteOptions{}); err != nil && !kerrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	// claims are named <template>-<statefulset>-<ordinal>
+	prefixes := make([]string, 0, len(restore))
+	for _, pvc := range restore {
+		prefixes = append(prefixes, fmt.Sprintf("%s-%s-", pvc.Name, b.Name()))
+	}
+
+	claimsToRestore := func() ([]string, error) {
+		pvcs, err := wrapKubeCall("persistentvolumeclaims-list", func() (*corev1.PersistentVolumeClaimList, error) {
+			return c.kc.CoreV1().PersistentVolumeClaims(b.NS()).List(ctx, metav1.ListOptions{})
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var res []string
+		for _, pvc := range pvcs.Items {
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(pvc.Name, prefix) {
+					res = append(res, pvc.Name)
+					break
+				}
+			}
+		}
+
+		return res, nil
+	}
+
+	names, err := claimsToRestore()
+	if err != nil {
+		return curr, err
+	}
+
+	for _, name := range names {
+		if err = c.kc.CoreV1().PersistentVolumeClaims(b.NS()).Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !kerrors.IsNotFound(err) {
+			return curr, err
+		}
+	}
+
+	// claims are gone once pods using them have terminated
+	err = wait.PollUntilContextTimeout(ctx, volumeRestorePollInterval, volumeRestoreTimeout, true, func(ctx context.Context) (bool, error) {
+		_, err := c.kc.AppsV1().StatefulSets(b.NS()).Get(ctx, b.Name(), metav1.GetOptions{})
+		if err == nil {
+			return false, nil
+		}
+
+		if !kerrors.IsNotFound(err) {
+			return false, err
+		}
+
+		names, err := claimsToRestore()
+		if err != nil {
+			return false, err
+		}
+
+		return len(names) == 0, nil
+	})
+
+	if err != nil && wait.Interrupted(err) && ctx.Err() == nil {
+		return curr, fmt.Errorf("%w: statefulset %s: volumes have not been released within %s", kubeclienterrors.ErrVolumeRestoreFailed, b.Name(), volumeRestoreTimeout)
+	}
+
+	return curr, err
+}
+
+func (c *client) checkVolumeSnapshotReady(ctx context.Context, ns string, name string) error {
+	obj, err := wrapKubeCall("volumesnapshots-get", func() (*unstructured.Unstructured, error) {
+		return c.dc.Resource(volumeSnapshotGVR).Namespace(ns).Get(ctx, name, metav1.GetOptions{})
+	})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return fmt.Errorf("%w: %s", kubeclienterrors.ErrVolumeSnapshotNotFound, name)
+		}
+
+		return err
+	}
+
+	if !volumeSnapshotStatus(obj).ReadyToUse {
+		return fmt.Errorf("%w: %s", kubeclienterrors.ErrVolumeSnapshotNotReady, name)
+	}
+
+	return nil
+}
+
+// leaseVolumeOfClaim returns service and persistent volume of the lease claim has been created for
+func leaseVolumeOfClaim(services mapi.Services, claim string) (string, string, bool) {
+	for _, svc := range services {
+		for _, storage := range svc.Resources.Storage {
+			if persistent, _ := storage.Attributes.Find(sdl.StorageAttributePersistent).AsBool(); !persistent {
+				continue
+			}
+
+			if strings.HasPrefix(claim, fmt.Sprintf("%s-%s-%s-", svc.Name, storage.Name, svc.Name)) {
+				return svc.Name, storage.Name, true
+			}
+		}
+	}
+
+	return "", "", false
+}
+
+func buildVolumeSnapshot(lid mtypes.LeaseID, name string, class string, service string, volume string, pvc *corev1.PersistentVolumeClaim) (*unstructured.Unstructured, error) {
+	objName := fmt.Sprintf("%s-%s", name, pvc.Name)
+	if errs := validation.IsDNS1123Subdomain(objName); len(errs) != 0 {
+		return nil, fmt.Errorf("%w: %q: %s", kubeclienterrors.ErrInvalidVolumeSnapshotName, objName, strings.Join(errs, ", "))
+	}
+
+	storageClass := sdl.StorageClassDefault
+	if pvc.Spec.StorageClassName != nil {
+		storageClass = *pvc.Spec.StorageClassName
+	}
+
+	size := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(volumeSnapshotGVR.GroupVersion().String())
+	obj.SetKind(builder.VolumeSnapshotKind)
+	obj.SetName(objName)
+	obj.SetNamespace(pvc.Namespace)
+	obj.SetLabels(builder.AppendLeaseLabels(lid, map[string]string{
+		builder.AkashManagedLabelName:         "true",
+		builder.AkashVolumeSnapshotLabelName:  name,
+		builder.AkashManifestServiceLabelName: service,
+		builder.AkashVolumeLabelName:          volume,
+		builder.AkashStorageClassLabelName:    storageClass,
+	}))
+	obj.SetAnnotations(map[string]string{
+		builder.AkashVolumeSize: strconv.FormatInt(size.Value(), 10),
+	})
+
+	if err := unstructured.SetNestedField(obj.Object, class, "spec", "volumeSnapshotClassName"); err != nil {
+		return nil, err
+	}
+
+	if err := unstructured.SetNestedField(obj.Object, pvc.Name, "spec", "source", "persistentVolumeClaimName"); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+func volumeSnapshotStatus(obj *unstructured.Unstructured) ctypes.VolumeSnapshot {
+	lbls := obj.GetLabels()
+
+	res := ctypes.VolumeSnapshot{
+		Name:         obj.GetName(),
+		Snapshot:     lbls[builder.AkashVolumeSnapshotLabelName],
+		Service:      lbls[builder.AkashManifestServiceLabelName],
+		Volume:       lbls[builder.AkashVolumeLabelName],
+		StorageClass: lbls[builder.AkashStorageClassLabelName],
+		CreatedAt:    obj.GetCreationTimestamp().UTC(),
+	}
+
+	res.PVC, _, _ = unstructured.NestedString(obj.Object, "spec", "source", "persistentVolumeClaimName")
+	res.ReadyToUse, _, _ = unstructured.NestedBool(obj.Object, "status", "readyToUse")
+	res.Error, _, _ = unstructured.NestedString(obj.Object, "status", "error", "message")
+
+	// size of the volume snapshot has been taken of stands for snapshot until snapshotter reports restore size
+	res.RestoreSize, _ = strconv.ParseInt(obj.GetAnnotations()[builder.AkashVolumeSize], 10, 64)
+
+	if val, found, _ := unstructured.NestedString(obj.Object, "status", "restoreSize"); found {
+		if size, err := resource.ParseQuantity(val); err == nil {
+			res.RestoreSize = size.Value()
+		}
+	}
+
+	return res
+}
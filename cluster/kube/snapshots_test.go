@@ -0,0 +1,275 @@
+package kube
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/akash-network/node/sdl"
+	"github.com/akash-network/node/testutil"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+
+	"github.com/akash-network/provider/cluster/kube/builder"
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+	crd "github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
+)
+
+func snapshotTestDeployment(t *testing.T) *builder.ClusterDeployment {
+	t.Helper()
+
+	sdl, err := sdl.ReadFile("../../testdata/deployment/deployment-v2-storage-beta2.yaml")
+	require.NoError(t, err)
+
+	mani, err := sdl.Manifest()
+	require.NoError(t, err)
+
+	group := mani.GetGroups()[0]
+
+	return &builder.ClusterDeployment{
+		Lid:     testutil.LeaseID(t),
+		Group:   &group,
+		Sparams: crd.ClusterSettings{SchedulerParams: make([]*crd.SchedulerParams, len(group.Services))},
+	}
+}
+
+func snapshotTestPVC(ns string, name string, class string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &class,
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse("128Mi"),
+				},
+			},
+		},
+	}
+}
+
+func snapshotTestContext(class string) context.Context {
+	settings := builder.NewDefaultSettings()
+	settings.VolumeSnapshotClass = class
+
+	return context.WithValue(context.Background(), builder.SettingsKey, settings)
+}
+
+func setVolumeSnapshotStatus(t *testing.T, c *client, ns string, name string, ready bool, size string) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	obj, err := c.dc.Resource(volumeSnapshotGVR).Namespace(ns).Get(ctx, name, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	require.NoError(t, unstructured.SetNestedField(obj.Object, ready, "status", "readyToUse"))
+	require.NoError(t, unstructured.SetNestedField(obj.Object, size, "status", "restoreSize"))
+
+	_, err = c.dc.Resource(volumeSnapshotGVR).Namespace(ns).Update(ctx, obj, metav1.UpdateOptions{})
+	require.NoError(t, err)
+}
+
+func setVolumeRestorePollInterval(t *testing.T, val time.Duration) {
+	prev := volumeRestorePollInterval
+	volumeRestorePollInterval = val
+
+	t.Cleanup(func() {
+		volumeRestorePollInterval = prev
+	})
+}
+
+func TestSnapshotLeaseVolumes(t *testing.T) {
+	cdep := snapshotTestDeployment(t)
+	ns := builder.LidNS(cdep.Lid)
+
+	mani, err := crd.NewManifest(testKubeClientNs, cdep.Lid, cdep.Group, cdep.Sparams)
+	require.NoError(t, err)
+
+	c := clientForTest(t, []runtime.Object{
+		snapshotTestPVC(ns, "web-data-web-0", "beta2"),
+		snapshotTestPVC(ns, "unrelated", "beta2"),
+	}, []runtime.Object{mani}).(*client)
+
+	ctx := snapshotTestContext("csi-snapclass")
+
+	_, err = c.SnapshotLeaseVolumes(snapshotTestContext(""), cdep.Lid, "nightly")
+	require.ErrorIs(t, err, kubeclienterrors.ErrVolumeSnapshotsDisabled)
+
+	_, err = c.SnapshotLeaseVolumes(ctx, cdep.Lid, "Nightly_Backup")
+	require.ErrorIs(t, err, kubeclienterrors.ErrInvalidVolumeSnapshotName)
+
+	_, err = c.SnapshotLeaseVolumes(ctx, testutil.LeaseID(t), "nightly")
+	require.ErrorIs(t, err, kubeclienterrors.ErrLeaseNotFound)
+
+	snapshots, err := c.SnapshotLeaseVolumes(ctx, cdep.Lid, "nightly")
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	require.Equal(t, "nightly-web-data-web-0", snapshots[0].Name)
+	require.Equal(t, "nightly", snapshots[0].Snapshot)
+	require.Equal(t, "web", snapshots[0].Service)
+	require.Equal(t, "data", snapshots[0].Volume)
+	require.Equal(t, "web-data-web-0", snapshots[0].PVC)
+	require.Equal(t, "beta2", snapshots[0].StorageClass)
+	require.False(t, snapshots[0].ReadyToUse)
+	require.Equal(t, int64(128*1024*1024), snapshots[0].RestoreSize)
+
+	obj, err := c.dc.Resource(volumeSnapshotGVR).Namespace(ns).Get(ctx, "nightly-web-data-web-0", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	class, _, _ := unstructured.NestedString(obj.Object, "spec", "volumeSnapshotClassName")
+	require.Equal(t, "csi-snapclass", class)
+	require.Equal(t, cdep.Lid.Owner, obj.GetLabels()[builder.AkashLeaseOwnerLabelName])
+
+	_, err = c.SnapshotLeaseVolumes(ctx, cdep.Lid, "nightly")
+	require.ErrorIs(t, err, kubeclienterrors.ErrVolumeSnapshotExists)
+
+	setVolumeSnapshotStatus(t, c, ns, "nightly-web-data-web-0", true, "1Gi")
+
+	snapshots, err = c.LeaseSnapshots(ctx, cdep.Lid)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	require.True(t, snapshots[0].ReadyToUse)
+	require.Equal(t, int64(1024*1024*1024), snapshots[0].RestoreSize)
+
+	storage, err := c.SnapshotStorage(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int64{"beta2": 1024 * 1024 * 1024}, storage)
+}
+
+func TestRestoreVolumesRecreatesStatefulSet(t *testing.T) {
+	setVolumeRestorePollInterval(t, 10*time.Millisecond)
+
+	ctx := snapshotTestContext("csi-snapclass")
+
+	cdep := snapshotTestDeployment(t)
+	ns := builder.LidNS(cdep.Lid)
+	log := testutil.Logger(t)
+	settings := builder.NewDefaultSettings()
+
+	curr, err := builder.BuildStatefulSet(builder.NewWorkloadBuilder(log, settings, cdep, 0)).Create()
+	require.NoError(t, err)
+	curr.Namespace = ns
+
+	c := clientForTest(t, []runtime.Object{
+		curr,
+		snapshotTestPVC(ns, "web-data-web-0", "beta2"),
+	}, nil).(*client)
+
+	// nothing to restore
+	deleted, err := c.restoreVolumes(ctx, builder.BuildStatefulSet(builder.NewWorkloadBuilder(log, settings, cdep, 0)))
+	require.NoError(t, err)
+	require.Nil(t, deleted)
+
+	cdep.Group.Services[0].Env = append(cdep.Group.Services[0].Env, `DOOOR_RESTORE_SNAPSHOTS={"data":"nightly-web-data-web-0"}`)
+	sset := builder.BuildStatefulSet(builder.NewWorkloadBuilder(log, settings, cdep, 0))
+
+	_, err = c.restoreVolumes(ctx, sset)
+	require.ErrorIs(t, err, kubeclienterrors.ErrVolumeSnapshotNotFound)
+
+	obj, err := buildVolumeSnapshot(cdep.Lid, "nightly", "csi-snapclass", "web", "data", snapshotTestPVC(ns, "web-data-web-0", "beta2"))
+	require.NoError(t, err)
+
+	_, err = c.dc.Resource(volumeSnapshotGVR).Namespace(ns).Create(ctx, obj, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	deleted, err = c.restoreVolumes(ctx, sset)
+	require.ErrorIs(t, err, kubeclienterrors.ErrVolumeSnapshotNotReady)
+	require.Nil(t, deleted)
+
+	// statefulset and claims stay in place until snapshot can be restored from
+	_, err = c.kc.AppsV1().StatefulSets(ns).Get(ctx, "web", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	setVolumeSnapshotStatus(t, c, ns, "nightly-web-data-web-0", true, "128Mi")
+
+	deleted, err = c.restoreVolumes(ctx, sset)
+	require.NoError(t, err)
+	require.NotNil(t, deleted)
+	require.Equal(t, curr.Spec, deleted.Spec)
+
+	_, err = c.kc.AppsV1().StatefulSets(ns).Get(ctx, "web", metav1.GetOptions{})
+	require.True(t, kerrors.IsNotFound(err))
+
+	_, err = c.kc.CoreV1().PersistentVolumeClaims(ns).Get(ctx, "web-data-web-0", metav1.GetOptions{})
+	require.True(t, kerrors.IsNotFound(err))
+
+	nobj, _, _, err := applyStatefulSet(ctx, c.kc, sset)
+	require.NoError(t, err)
+	require.NotNil(t, nobj)
+	require.Equal(t, "nightly-web-data-web-0", nobj.Spec.VolumeClaimTemplates[0].Spec.DataSource.Name)
+
+	// volume restored from the snapshot already is not restored again
+	deleted, err = c.restoreVolumes(ctx, sset)
+	require.NoError(t, err)
+	require.Nil(t, deleted)
+
+	_, err = c.kc.AppsV1().StatefulSets(ns).Get(ctx, "web", metav1.GetOptions{})
+	require.NoError(t, err)
+}
+
+func TestDeployRecreatesStatefulSetWhenFailingAfterRestore(t *testing.T) {
+	setVolumeRestorePollInterval(t, 10*time.Millisecond)
+
+	ctx := snapshotTestContext("csi-snapclass")
+
+	cdep := snapshotTestDeployment(t)
+	ns := builder.LidNS(cdep.Lid)
+	log := testutil.Logger(t)
+	settings := builder.NewDefaultSettings()
+
+	curr, err := builder.BuildStatefulSet(builder.NewWorkloadBuilder(log, settings, cdep, 0)).Create()
+	require.NoError(t, err)
+	curr.Namespace = ns
+	curr.UID = "prev-uid"
+
+	cdep.Group.Services[0].Env = append(cdep.Group.Services[0].Env, `DOOOR_RESTORE_SNAPSHOTS={"data":"nightly-web-data-web-0"}`)
+
+	mani, err := crd.NewManifest(testKubeClientNs, cdep.Lid, cdep.Group, cdep.Sparams)
+	require.NoError(t, err)
+
+	c := clientForTest(t, []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}},
+		curr,
+		snapshotTestPVC(ns, "web-data-web-0", "beta2"),
+	}, []runtime.Object{mani}).(*client)
+
+	obj, err := buildVolumeSnapshot(cdep.Lid, "nightly", "csi-snapclass", "web", "data", snapshotTestPVC(ns, "web-data-web-0", "beta2"))
+	require.NoError(t, err)
+
+	_, err = c.dc.Resource(volumeSnapshotGVR).Namespace(ns).Create(ctx, obj, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	setVolumeSnapshotStatus(t, c, ns, "nightly-web-data-web-0", true, "128Mi")
+
+	// deploy fails once statefulset has been recreated with restored volumes
+	errServices := errors.New("services unavailable")
+	c.kc.(*fake.Clientset).PrependReactor("create", "services", func(ktesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errServices
+	})
+
+	err = c.Deploy(ctx, &ctypes.Deployment{
+		Lid:     cdep.Lid,
+		MGroup:  cdep.Group,
+		CParams: cdep.Sparams,
+	})
+	require.ErrorIs(t, err, errServices)
+
+	// previous statefulset is back rather than one restoring volumes
+	sset, err := c.kc.AppsV1().StatefulSets(ns).Get(ctx, "web", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Empty(t, sset.UID)
+	require.Equal(t, curr.Spec, sset.Spec)
+	require.Nil(t, sset.Spec.VolumeClaimTemplates[0].Spec.DataSource)
+}
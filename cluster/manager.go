@@ -58,6 +58,8 @@ type deploymentManager struct {
 	session             session.Session
 	state               deploymentState
 	deployment          ctypes.IDeployment
+	deploying           ctypes.IDeployment
+	deployed            ctypes.IDeployment
 	monitor             *deploymentMonitor
 	wg                  sync.WaitGroup
 	updatech            chan ctypes.IDeployment
@@ -180,16 +182,21 @@ loop:
 			}
 			switch dm.state {
 			case dsDeployActive:
-				if result != nil {
+				switch {
+				case errors.Is(result, kubeclienterrors.ErrRolloutFailed):
+					// cluster client has already restored objects of the previous revision
+					dm.rolledBack()
+				case result != nil:
 					// Run the teardown code to get rid of anything created that might be hanging out
 					runch = dm.startTeardown()
-				} else {
+				default:
 					dm.log.Debug("deploy complete")
+					dm.deployed = dm.deploying
 					dm.state = dsDeployComplete
 					dm.startMonitor()
 				}
 			case dsDeployPending:
-				if result != nil {
+				if result != nil && !errors.Is(result, kubeclienterrors.ErrRolloutFailed) {
 					break loop
 				}
 				// start update
@@ -265,9 +272,33 @@ func (dm *deploymentManager) stopMonitor() {
 	}
 }
 
+// rolledBack resumes monitoring of the deployment lease has been rolled back to after update did not roll out
+func (dm *deploymentManager) rolledBack() {
+	dm.log.Info("deployment rolled back to previous revision")
+
+	if dm.deployed != nil {
+		dm.deployment = dm.deployed
+	}
+
+	dm.state = dsDeployComplete
+
+	groupCopy := *dm.deployment.ManifestGroup()
+	err := dm.bus.Publish(event.ClusterDeployment{
+		LeaseID: dm.deployment.LeaseID(),
+		Group:   &groupCopy,
+		Status:  event.ClusterDeploymentRolledBack,
+	})
+	if err != nil {
+		dm.log.Error("failed publishing event", "err", err)
+	}
+
+	dm.startMonitor()
+}
+
 func (dm *deploymentManager) startDeploy(ctx context.Context) <-chan error {
 	dm.stopMonitor()
 	dm.state = dsDeployActive
+	dm.deploying = dm.deployment
 
 	chErr := make(chan error, 1)
 
@@ -335,8 +366,11 @@ func (dm *deploymentManager) doDeploy(ctx context.Context) ([]string, []string,
 	}()
 
 	defer func() {
-		// TODO - run on an isolated context
-		cleanupHelper.purgeAll(ctx)
+		// hostnames and IPs removed by the update are still used by the revision lease has been rolled back to
+		if !errors.Is(err, kubeclienterrors.ErrRolloutFailed) {
+			// TODO - run on an isolated context
+			cleanupHelper.purgeAll(ctx)
+		}
 		cancel()
 	}()
 
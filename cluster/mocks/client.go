@@ -716,6 +716,68 @@ func (_c *Client_KubeVersion_Call) RunAndReturn(run func() (*version.Info, error
 	return _c
 }
 
+// LeaseAttestation provides a mock function with given fields: ctx, lID, service, podIndex, req
+func (_m *Client) LeaseAttestation(ctx context.Context, lID v1beta4.LeaseID, service string, podIndex uint, req v1beta3.AttestationRequest) (*v1beta3.AttestationQuote, error) {
+	ret := _m.Called(ctx, lID, service, podIndex, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LeaseAttestation")
+	}
+
+	var r0 *v1beta3.AttestationQuote
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID, string, uint, v1beta3.AttestationRequest) (*v1beta3.AttestationQuote, error)); ok {
+		return rf(ctx, lID, service, podIndex, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID, string, uint, v1beta3.AttestationRequest) *v1beta3.AttestationQuote); ok {
+		r0 = rf(ctx, lID, service, podIndex, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1beta3.AttestationQuote)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, v1beta4.LeaseID, string, uint, v1beta3.AttestationRequest) error); ok {
+		r1 = rf(ctx, lID, service, podIndex, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_LeaseAttestation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LeaseAttestation'
+type Client_LeaseAttestation_Call struct {
+	*mock.Call
+}
+
+// LeaseAttestation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - lID v1beta4.LeaseID
+//   - service string
+//   - podIndex uint
+//   - req v1beta3.AttestationRequest
+func (_e *Client_Expecter) LeaseAttestation(ctx interface{}, lID interface{}, service interface{}, podIndex interface{}, req interface{}) *Client_LeaseAttestation_Call {
+	return &Client_LeaseAttestation_Call{Call: _e.mock.On("LeaseAttestation", ctx, lID, service, podIndex, req)}
+}
+
+func (_c *Client_LeaseAttestation_Call) Run(run func(ctx context.Context, lID v1beta4.LeaseID, service string, podIndex uint, req v1beta3.AttestationRequest)) *Client_LeaseAttestation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(v1beta4.LeaseID), args[2].(string), args[3].(uint), args[4].(v1beta3.AttestationRequest))
+	})
+	return _c
+}
+
+func (_c *Client_LeaseAttestation_Call) Return(_a0 *v1beta3.AttestationQuote, _a1 error) *Client_LeaseAttestation_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_LeaseAttestation_Call) RunAndReturn(run func(context.Context, v1beta4.LeaseID, string, uint, v1beta3.AttestationRequest) (*v1beta3.AttestationQuote, error)) *Client_LeaseAttestation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // LeaseEvents provides a mock function with given fields: _a0, _a1, _a2, _a3
 func (_m *Client) LeaseEvents(_a0 context.Context, _a1 v1beta4.LeaseID, _a2 string, _a3 bool) (v1beta3.EventsWatcher, error) {
 	ret := _m.Called(_a0, _a1, _a2, _a3)
@@ -777,9 +839,9 @@ func (_c *Client_LeaseEvents_Call) RunAndReturn(run func(context.Context, v1beta
 	return _c
 }
 
-// LeaseLogs provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
-func (_m *Client) LeaseLogs(_a0 context.Context, _a1 v1beta4.LeaseID, _a2 string, _a3 bool, _a4 *int64) ([]*v1beta3.ServiceLog, error) {
-	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
+// LeaseLogs provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *Client) LeaseLogs(_a0 context.Context, _a1 v1beta4.LeaseID, _a2 string, _a3 v1beta3.LeaseLogsOptions) ([]*v1beta3.ServiceLog, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
 
 	if len(ret) == 0 {
 		panic("no return value specified for LeaseLogs")
@@ -787,19 +849,19 @@ func (_m *Client) LeaseLogs(_a0 context.Context, _a1 v1beta4.LeaseID, _a2 string
 
 	var r0 []*v1beta3.ServiceLog
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID, string, bool, *int64) ([]*v1beta3.ServiceLog, error)); ok {
-		return rf(_a0, _a1, _a2, _a3, _a4)
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID, string, v1beta3.LeaseLogsOptions) ([]*v1beta3.ServiceLog, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID, string, bool, *int64) []*v1beta3.ServiceLog); ok {
-		r0 = rf(_a0, _a1, _a2, _a3, _a4)
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID, string, v1beta3.LeaseLogsOptions) []*v1beta3.ServiceLog); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*v1beta3.ServiceLog)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, v1beta4.LeaseID, string, bool, *int64) error); ok {
-		r1 = rf(_a0, _a1, _a2, _a3, _a4)
+	if rf, ok := ret.Get(1).(func(context.Context, v1beta4.LeaseID, string, v1beta3.LeaseLogsOptions) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -816,15 +878,14 @@ type Client_LeaseLogs_Call struct {
 //   - _a0 context.Context
 //   - _a1 v1beta4.LeaseID
 //   - _a2 string
-//   - _a3 bool
-//   - _a4 *int64
-func (_e *Client_Expecter) LeaseLogs(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}, _a4 interface{}) *Client_LeaseLogs_Call {
-	return &Client_LeaseLogs_Call{Call: _e.mock.On("LeaseLogs", _a0, _a1, _a2, _a3, _a4)}
+//   - _a3 v1beta3.LeaseLogsOptions
+func (_e *Client_Expecter) LeaseLogs(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *Client_LeaseLogs_Call {
+	return &Client_LeaseLogs_Call{Call: _e.mock.On("LeaseLogs", _a0, _a1, _a2, _a3)}
 }
 
-func (_c *Client_LeaseLogs_Call) Run(run func(_a0 context.Context, _a1 v1beta4.LeaseID, _a2 string, _a3 bool, _a4 *int64)) *Client_LeaseLogs_Call {
+func (_c *Client_LeaseLogs_Call) Run(run func(_a0 context.Context, _a1 v1beta4.LeaseID, _a2 string, _a3 v1beta3.LeaseLogsOptions)) *Client_LeaseLogs_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(v1beta4.LeaseID), args[2].(string), args[3].(bool), args[4].(*int64))
+		run(args[0].(context.Context), args[1].(v1beta4.LeaseID), args[2].(string), args[3].(v1beta3.LeaseLogsOptions))
 	})
 	return _c
 }
@@ -834,7 +895,182 @@ func (_c *Client_LeaseLogs_Call) Return(_a0 []*v1beta3.ServiceLog, _a1 error) *C
 	return _c
 }
 
-func (_c *Client_LeaseLogs_Call) RunAndReturn(run func(context.Context, v1beta4.LeaseID, string, bool, *int64) ([]*v1beta3.ServiceLog, error)) *Client_LeaseLogs_Call {
+func (_c *Client_LeaseLogs_Call) RunAndReturn(run func(context.Context, v1beta4.LeaseID, string, v1beta3.LeaseLogsOptions) ([]*v1beta3.ServiceLog, error)) *Client_LeaseLogs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LeasePaused provides a mock function with given fields: _a0, _a1
+func (_m *Client) LeasePaused(_a0 context.Context, _a1 v1beta4.LeaseID) (bool, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LeasePaused")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID) (bool, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID) bool); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, v1beta4.LeaseID) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_LeasePaused_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LeasePaused'
+type Client_LeasePaused_Call struct {
+	*mock.Call
+}
+
+// LeasePaused is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 v1beta4.LeaseID
+func (_e *Client_Expecter) LeasePaused(_a0 interface{}, _a1 interface{}) *Client_LeasePaused_Call {
+	return &Client_LeasePaused_Call{Call: _e.mock.On("LeasePaused", _a0, _a1)}
+}
+
+func (_c *Client_LeasePaused_Call) Run(run func(_a0 context.Context, _a1 v1beta4.LeaseID)) *Client_LeasePaused_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(v1beta4.LeaseID))
+	})
+	return _c
+}
+
+func (_c *Client_LeasePaused_Call) Return(_a0 bool, _a1 error) *Client_LeasePaused_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_LeasePaused_Call) RunAndReturn(run func(context.Context, v1beta4.LeaseID) (bool, error)) *Client_LeasePaused_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LeaseProbeFailures provides a mock function with given fields: _a0, _a1
+func (_m *Client) LeaseProbeFailures(_a0 context.Context, _a1 v1beta4.LeaseID) (map[string][]v1beta3.ProbeStatus, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LeaseProbeFailures")
+	}
+
+	var r0 map[string][]v1beta3.ProbeStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID) (map[string][]v1beta3.ProbeStatus, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID) map[string][]v1beta3.ProbeStatus); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string][]v1beta3.ProbeStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, v1beta4.LeaseID) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_LeaseProbeFailures_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LeaseProbeFailures'
+type Client_LeaseProbeFailures_Call struct {
+	*mock.Call
+}
+
+// LeaseProbeFailures is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 v1beta4.LeaseID
+func (_e *Client_Expecter) LeaseProbeFailures(_a0 interface{}, _a1 interface{}) *Client_LeaseProbeFailures_Call {
+	return &Client_LeaseProbeFailures_Call{Call: _e.mock.On("LeaseProbeFailures", _a0, _a1)}
+}
+
+func (_c *Client_LeaseProbeFailures_Call) Run(run func(_a0 context.Context, _a1 v1beta4.LeaseID)) *Client_LeaseProbeFailures_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(v1beta4.LeaseID))
+	})
+	return _c
+}
+
+func (_c *Client_LeaseProbeFailures_Call) Return(_a0 map[string][]v1beta3.ProbeStatus, _a1 error) *Client_LeaseProbeFailures_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_LeaseProbeFailures_Call) RunAndReturn(run func(context.Context, v1beta4.LeaseID) (map[string][]v1beta3.ProbeStatus, error)) *Client_LeaseProbeFailures_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LeaseSnapshots provides a mock function with given fields: _a0, _a1
+func (_m *Client) LeaseSnapshots(_a0 context.Context, _a1 v1beta4.LeaseID) ([]v1beta3.VolumeSnapshot, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LeaseSnapshots")
+	}
+
+	var r0 []v1beta3.VolumeSnapshot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID) ([]v1beta3.VolumeSnapshot, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID) []v1beta3.VolumeSnapshot); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]v1beta3.VolumeSnapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, v1beta4.LeaseID) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_LeaseSnapshots_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LeaseSnapshots'
+type Client_LeaseSnapshots_Call struct {
+	*mock.Call
+}
+
+// LeaseSnapshots is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 v1beta4.LeaseID
+func (_e *Client_Expecter) LeaseSnapshots(_a0 interface{}, _a1 interface{}) *Client_LeaseSnapshots_Call {
+	return &Client_LeaseSnapshots_Call{Call: _e.mock.On("LeaseSnapshots", _a0, _a1)}
+}
+
+func (_c *Client_LeaseSnapshots_Call) Run(run func(_a0 context.Context, _a1 v1beta4.LeaseID)) *Client_LeaseSnapshots_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(v1beta4.LeaseID))
+	})
+	return _c
+}
+
+func (_c *Client_LeaseSnapshots_Call) Return(_a0 []v1beta3.VolumeSnapshot, _a1 error) *Client_LeaseSnapshots_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_LeaseSnapshots_Call) RunAndReturn(run func(context.Context, v1beta4.LeaseID) ([]v1beta3.VolumeSnapshot, error)) *Client_LeaseSnapshots_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -898,6 +1134,125 @@ func (_c *Client_LeaseStatus_Call) RunAndReturn(run func(context.Context, v1beta
 	return _c
 }
 
+// ManifestRevision provides a mock function with given fields: ctx, lID, revision
+func (_m *Client) ManifestRevision(ctx context.Context, lID v1beta4.LeaseID, revision uint64) (v1beta3.IDeployment, error) {
+	ret := _m.Called(ctx, lID, revision)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ManifestRevision")
+	}
+
+	var r0 v1beta3.IDeployment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID, uint64) (v1beta3.IDeployment, error)); ok {
+		return rf(ctx, lID, revision)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID, uint64) v1beta3.IDeployment); ok {
+		r0 = rf(ctx, lID, revision)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(v1beta3.IDeployment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, v1beta4.LeaseID, uint64) error); ok {
+		r1 = rf(ctx, lID, revision)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_ManifestRevision_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ManifestRevision'
+type Client_ManifestRevision_Call struct {
+	*mock.Call
+}
+
+// ManifestRevision is a helper method to define mock.On call
+//   - ctx context.Context
+//   - lID v1beta4.LeaseID
+//   - revision uint64
+func (_e *Client_Expecter) ManifestRevision(ctx interface{}, lID interface{}, revision interface{}) *Client_ManifestRevision_Call {
+	return &Client_ManifestRevision_Call{Call: _e.mock.On("ManifestRevision", ctx, lID, revision)}
+}
+
+func (_c *Client_ManifestRevision_Call) Run(run func(ctx context.Context, lID v1beta4.LeaseID, revision uint64)) *Client_ManifestRevision_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(v1beta4.LeaseID), args[2].(uint64))
+	})
+	return _c
+}
+
+func (_c *Client_ManifestRevision_Call) Return(_a0 v1beta3.IDeployment, _a1 error) *Client_ManifestRevision_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_ManifestRevision_Call) RunAndReturn(run func(context.Context, v1beta4.LeaseID, uint64) (v1beta3.IDeployment, error)) *Client_ManifestRevision_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ManifestRevisions provides a mock function with given fields: _a0, _a1
+func (_m *Client) ManifestRevisions(_a0 context.Context, _a1 v1beta4.LeaseID) ([]v1beta3.ManifestRevision, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ManifestRevisions")
+	}
+
+	var r0 []v1beta3.ManifestRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID) ([]v1beta3.ManifestRevision, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID) []v1beta3.ManifestRevision); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]v1beta3.ManifestRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, v1beta4.LeaseID) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_ManifestRevisions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ManifestRevisions'
+type Client_ManifestRevisions_Call struct {
+	*mock.Call
+}
+
+// ManifestRevisions is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 v1beta4.LeaseID
+func (_e *Client_Expecter) ManifestRevisions(_a0 interface{}, _a1 interface{}) *Client_ManifestRevisions_Call {
+	return &Client_ManifestRevisions_Call{Call: _e.mock.On("ManifestRevisions", _a0, _a1)}
+}
+
+func (_c *Client_ManifestRevisions_Call) Run(run func(_a0 context.Context, _a1 v1beta4.LeaseID)) *Client_ManifestRevisions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(v1beta4.LeaseID))
+	})
+	return _c
+}
+
+func (_c *Client_ManifestRevisions_Call) Return(_a0 []v1beta3.ManifestRevision, _a1 error) *Client_ManifestRevisions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_ManifestRevisions_Call) RunAndReturn(run func(context.Context, v1beta4.LeaseID) ([]v1beta3.ManifestRevision, error)) *Client_ManifestRevisions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ObserveHostnameState provides a mock function with given fields: ctx
 func (_m *Client) ObserveHostnameState(ctx context.Context) (<-chan hostname.ResourceEvent, error) {
 	ret := _m.Called(ctx)
@@ -1014,6 +1369,104 @@ func (_c *Client_ObserveIPState_Call) RunAndReturn(run func(context.Context) (<-
 	return _c
 }
 
+// PauseLease provides a mock function with given fields: ctx, lID
+func (_m *Client) PauseLease(ctx context.Context, lID v1beta4.LeaseID) error {
+	ret := _m.Called(ctx, lID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PauseLease")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID) error); ok {
+		r0 = rf(ctx, lID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Client_PauseLease_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PauseLease'
+type Client_PauseLease_Call struct {
+	*mock.Call
+}
+
+// PauseLease is a helper method to define mock.On call
+//   - ctx context.Context
+//   - lID v1beta4.LeaseID
+func (_e *Client_Expecter) PauseLease(ctx interface{}, lID interface{}) *Client_PauseLease_Call {
+	return &Client_PauseLease_Call{Call: _e.mock.On("PauseLease", ctx, lID)}
+}
+
+func (_c *Client_PauseLease_Call) Run(run func(ctx context.Context, lID v1beta4.LeaseID)) *Client_PauseLease_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(v1beta4.LeaseID))
+	})
+	return _c
+}
+
+func (_c *Client_PauseLease_Call) Return(_a0 error) *Client_PauseLease_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_PauseLease_Call) RunAndReturn(run func(context.Context, v1beta4.LeaseID) error) *Client_PauseLease_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PortForward provides a mock function with given fields: ctx, lID, service, podIndex, port, stream
+func (_m *Client) PortForward(ctx context.Context, lID v1beta4.LeaseID, service string, podIndex uint, port uint32, stream io.ReadWriter) error {
+	ret := _m.Called(ctx, lID, service, podIndex, port, stream)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PortForward")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID, string, uint, uint32, io.ReadWriter) error); ok {
+		r0 = rf(ctx, lID, service, podIndex, port, stream)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Client_PortForward_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PortForward'
+type Client_PortForward_Call struct {
+	*mock.Call
+}
+
+// PortForward is a helper method to define mock.On call
+//   - ctx context.Context
+//   - lID v1beta4.LeaseID
+//   - service string
+//   - podIndex uint
+//   - port uint32
+//   - stream io.ReadWriter
+func (_e *Client_Expecter) PortForward(ctx interface{}, lID interface{}, service interface{}, podIndex interface{}, port interface{}, stream interface{}) *Client_PortForward_Call {
+	return &Client_PortForward_Call{Call: _e.mock.On("PortForward", ctx, lID, service, podIndex, port, stream)}
+}
+
+func (_c *Client_PortForward_Call) Run(run func(ctx context.Context, lID v1beta4.LeaseID, service string, podIndex uint, port uint32, stream io.ReadWriter)) *Client_PortForward_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(v1beta4.LeaseID), args[2].(string), args[3].(uint), args[4].(uint32), args[5].(io.ReadWriter))
+	})
+	return _c
+}
+
+func (_c *Client_PortForward_Call) Return(_a0 error) *Client_PortForward_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_PortForward_Call) RunAndReturn(run func(context.Context, v1beta4.LeaseID, string, uint, uint32, io.ReadWriter) error) *Client_PortForward_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // PurgeDeclaredHostname provides a mock function with given fields: ctx, lID, _a2
 func (_m *Client) PurgeDeclaredHostname(ctx context.Context, lID v1beta4.LeaseID, _a2 string) error {
 	ret := _m.Called(ctx, lID, _a2)
@@ -1255,6 +1708,53 @@ func (_c *Client_RemoveHostnameFromDeployment_Call) RunAndReturn(run func(contex
 	return _c
 }
 
+// ResumeLease provides a mock function with given fields: ctx, lID
+func (_m *Client) ResumeLease(ctx context.Context, lID v1beta4.LeaseID) error {
+	ret := _m.Called(ctx, lID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResumeLease")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID) error); ok {
+		r0 = rf(ctx, lID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Client_ResumeLease_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResumeLease'
+type Client_ResumeLease_Call struct {
+	*mock.Call
+}
+
+// ResumeLease is a helper method to define mock.On call
+//   - ctx context.Context
+//   - lID v1beta4.LeaseID
+func (_e *Client_Expecter) ResumeLease(ctx interface{}, lID interface{}) *Client_ResumeLease_Call {
+	return &Client_ResumeLease_Call{Call: _e.mock.On("ResumeLease", ctx, lID)}
+}
+
+func (_c *Client_ResumeLease_Call) Run(run func(ctx context.Context, lID v1beta4.LeaseID)) *Client_ResumeLease_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(v1beta4.LeaseID))
+	})
+	return _c
+}
+
+func (_c *Client_ResumeLease_Call) Return(_a0 error) *Client_ResumeLease_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Client_ResumeLease_Call) RunAndReturn(run func(context.Context, v1beta4.LeaseID) error) *Client_ResumeLease_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ServiceStatus provides a mock function with given fields: _a0, _a1, _a2
 func (_m *Client) ServiceStatus(_a0 context.Context, _a1 v1beta4.LeaseID, _a2 string) (*v1beta3.ServiceStatus, error) {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -1315,6 +1815,124 @@ func (_c *Client_ServiceStatus_Call) RunAndReturn(run func(context.Context, v1be
 	return _c
 }
 
+// SnapshotLeaseVolumes provides a mock function with given fields: ctx, lID, name
+func (_m *Client) SnapshotLeaseVolumes(ctx context.Context, lID v1beta4.LeaseID, name string) ([]v1beta3.VolumeSnapshot, error) {
+	ret := _m.Called(ctx, lID, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SnapshotLeaseVolumes")
+	}
+
+	var r0 []v1beta3.VolumeSnapshot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID, string) ([]v1beta3.VolumeSnapshot, error)); ok {
+		return rf(ctx, lID, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID, string) []v1beta3.VolumeSnapshot); ok {
+		r0 = rf(ctx, lID, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]v1beta3.VolumeSnapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, v1beta4.LeaseID, string) error); ok {
+		r1 = rf(ctx, lID, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_SnapshotLeaseVolumes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SnapshotLeaseVolumes'
+type Client_SnapshotLeaseVolumes_Call struct {
+	*mock.Call
+}
+
+// SnapshotLeaseVolumes is a helper method to define mock.On call
+//   - ctx context.Context
+//   - lID v1beta4.LeaseID
+//   - name string
+func (_e *Client_Expecter) SnapshotLeaseVolumes(ctx interface{}, lID interface{}, name interface{}) *Client_SnapshotLeaseVolumes_Call {
+	return &Client_SnapshotLeaseVolumes_Call{Call: _e.mock.On("SnapshotLeaseVolumes", ctx, lID, name)}
+}
+
+func (_c *Client_SnapshotLeaseVolumes_Call) Run(run func(ctx context.Context, lID v1beta4.LeaseID, name string)) *Client_SnapshotLeaseVolumes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(v1beta4.LeaseID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Client_SnapshotLeaseVolumes_Call) Return(_a0 []v1beta3.VolumeSnapshot, _a1 error) *Client_SnapshotLeaseVolumes_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_SnapshotLeaseVolumes_Call) RunAndReturn(run func(context.Context, v1beta4.LeaseID, string) ([]v1beta3.VolumeSnapshot, error)) *Client_SnapshotLeaseVolumes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SnapshotStorage provides a mock function with given fields: _a0
+func (_m *Client) SnapshotStorage(_a0 context.Context) (map[string]int64, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SnapshotStorage")
+	}
+
+	var r0 map[string]int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (map[string]int64, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) map[string]int64); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_SnapshotStorage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SnapshotStorage'
+type Client_SnapshotStorage_Call struct {
+	*mock.Call
+}
+
+// SnapshotStorage is a helper method to define mock.On call
+//   - _a0 context.Context
+func (_e *Client_Expecter) SnapshotStorage(_a0 interface{}) *Client_SnapshotStorage_Call {
+	return &Client_SnapshotStorage_Call{Call: _e.mock.On("SnapshotStorage", _a0)}
+}
+
+func (_c *Client_SnapshotStorage_Call) Run(run func(_a0 context.Context)) *Client_SnapshotStorage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Client_SnapshotStorage_Call) Return(_a0 map[string]int64, _a1 error) *Client_SnapshotStorage_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_SnapshotStorage_Call) RunAndReturn(run func(context.Context) (map[string]int64, error)) *Client_SnapshotStorage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // TeardownLease provides a mock function with given fields: _a0, _a1
 func (_m *Client) TeardownLease(_a0 context.Context, _a1 v1beta4.LeaseID) error {
 	ret := _m.Called(_a0, _a1)
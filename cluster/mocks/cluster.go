@@ -23,6 +23,52 @@ func (_m *Cluster) EXPECT() *Cluster_Expecter {
 	return &Cluster_Expecter{mock: &_m.Mock}
 }
 
+// CheckCapacity provides a mock function with given fields: _a0
+func (_m *Cluster) CheckCapacity(_a0 v1beta3.ResourceGroup) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckCapacity")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(v1beta3.ResourceGroup) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Cluster_CheckCapacity_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckCapacity'
+type Cluster_CheckCapacity_Call struct {
+	*mock.Call
+}
+
+// CheckCapacity is a helper method to define mock.On call
+//   - _a0 v1beta3.ResourceGroup
+func (_e *Cluster_Expecter) CheckCapacity(_a0 interface{}) *Cluster_CheckCapacity_Call {
+	return &Cluster_CheckCapacity_Call{Call: _e.mock.On("CheckCapacity", _a0)}
+}
+
+func (_c *Cluster_CheckCapacity_Call) Run(run func(_a0 v1beta3.ResourceGroup)) *Cluster_CheckCapacity_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(v1beta3.ResourceGroup))
+	})
+	return _c
+}
+
+func (_c *Cluster_CheckCapacity_Call) Return(_a0 error) *Cluster_CheckCapacity_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Cluster_CheckCapacity_Call) RunAndReturn(run func(v1beta3.ResourceGroup) error) *Cluster_CheckCapacity_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Reserve provides a mock function with given fields: _a0, _a1
 func (_m *Cluster) Reserve(_a0 v1beta4.OrderID, _a1 v1beta3.ResourceGroup) (typesv1beta3.Reservation, error) {
 	ret := _m.Called(_a0, _a1)
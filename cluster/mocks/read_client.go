@@ -389,9 +389,9 @@ func (_c *ReadClient_LeaseEvents_Call) RunAndReturn(run func(context.Context, v1
 	return _c
 }
 
-// LeaseLogs provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
-func (_m *ReadClient) LeaseLogs(_a0 context.Context, _a1 v1beta4.LeaseID, _a2 string, _a3 bool, _a4 *int64) ([]*v1beta3.ServiceLog, error) {
-	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
+// LeaseLogs provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *ReadClient) LeaseLogs(_a0 context.Context, _a1 v1beta4.LeaseID, _a2 string, _a3 v1beta3.LeaseLogsOptions) ([]*v1beta3.ServiceLog, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
 
 	if len(ret) == 0 {
 		panic("no return value specified for LeaseLogs")
@@ -399,19 +399,19 @@ func (_m *ReadClient) LeaseLogs(_a0 context.Context, _a1 v1beta4.LeaseID, _a2 st
 
 	var r0 []*v1beta3.ServiceLog
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID, string, bool, *int64) ([]*v1beta3.ServiceLog, error)); ok {
-		return rf(_a0, _a1, _a2, _a3, _a4)
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID, string, v1beta3.LeaseLogsOptions) ([]*v1beta3.ServiceLog, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID, string, bool, *int64) []*v1beta3.ServiceLog); ok {
-		r0 = rf(_a0, _a1, _a2, _a3, _a4)
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID, string, v1beta3.LeaseLogsOptions) []*v1beta3.ServiceLog); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*v1beta3.ServiceLog)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, v1beta4.LeaseID, string, bool, *int64) error); ok {
-		r1 = rf(_a0, _a1, _a2, _a3, _a4)
+	if rf, ok := ret.Get(1).(func(context.Context, v1beta4.LeaseID, string, v1beta3.LeaseLogsOptions) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -428,15 +428,14 @@ type ReadClient_LeaseLogs_Call struct {
 //   - _a0 context.Context
 //   - _a1 v1beta4.LeaseID
 //   - _a2 string
-//   - _a3 bool
-//   - _a4 *int64
-func (_e *ReadClient_Expecter) LeaseLogs(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}, _a4 interface{}) *ReadClient_LeaseLogs_Call {
-	return &ReadClient_LeaseLogs_Call{Call: _e.mock.On("LeaseLogs", _a0, _a1, _a2, _a3, _a4)}
+//   - _a3 v1beta3.LeaseLogsOptions
+func (_e *ReadClient_Expecter) LeaseLogs(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *ReadClient_LeaseLogs_Call {
+	return &ReadClient_LeaseLogs_Call{Call: _e.mock.On("LeaseLogs", _a0, _a1, _a2, _a3)}
 }
 
-func (_c *ReadClient_LeaseLogs_Call) Run(run func(_a0 context.Context, _a1 v1beta4.LeaseID, _a2 string, _a3 bool, _a4 *int64)) *ReadClient_LeaseLogs_Call {
+func (_c *ReadClient_LeaseLogs_Call) Run(run func(_a0 context.Context, _a1 v1beta4.LeaseID, _a2 string, _a3 v1beta3.LeaseLogsOptions)) *ReadClient_LeaseLogs_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(v1beta4.LeaseID), args[2].(string), args[3].(bool), args[4].(*int64))
+		run(args[0].(context.Context), args[1].(v1beta4.LeaseID), args[2].(string), args[3].(v1beta3.LeaseLogsOptions))
 	})
 	return _c
 }
@@ -446,7 +445,182 @@ func (_c *ReadClient_LeaseLogs_Call) Return(_a0 []*v1beta3.ServiceLog, _a1 error
 	return _c
 }
 
-func (_c *ReadClient_LeaseLogs_Call) RunAndReturn(run func(context.Context, v1beta4.LeaseID, string, bool, *int64) ([]*v1beta3.ServiceLog, error)) *ReadClient_LeaseLogs_Call {
+func (_c *ReadClient_LeaseLogs_Call) RunAndReturn(run func(context.Context, v1beta4.LeaseID, string, v1beta3.LeaseLogsOptions) ([]*v1beta3.ServiceLog, error)) *ReadClient_LeaseLogs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LeasePaused provides a mock function with given fields: _a0, _a1
+func (_m *ReadClient) LeasePaused(_a0 context.Context, _a1 v1beta4.LeaseID) (bool, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LeasePaused")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID) (bool, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID) bool); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, v1beta4.LeaseID) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReadClient_LeasePaused_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LeasePaused'
+type ReadClient_LeasePaused_Call struct {
+	*mock.Call
+}
+
+// LeasePaused is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 v1beta4.LeaseID
+func (_e *ReadClient_Expecter) LeasePaused(_a0 interface{}, _a1 interface{}) *ReadClient_LeasePaused_Call {
+	return &ReadClient_LeasePaused_Call{Call: _e.mock.On("LeasePaused", _a0, _a1)}
+}
+
+func (_c *ReadClient_LeasePaused_Call) Run(run func(_a0 context.Context, _a1 v1beta4.LeaseID)) *ReadClient_LeasePaused_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(v1beta4.LeaseID))
+	})
+	return _c
+}
+
+func (_c *ReadClient_LeasePaused_Call) Return(_a0 bool, _a1 error) *ReadClient_LeasePaused_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ReadClient_LeasePaused_Call) RunAndReturn(run func(context.Context, v1beta4.LeaseID) (bool, error)) *ReadClient_LeasePaused_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LeaseProbeFailures provides a mock function with given fields: _a0, _a1
+func (_m *ReadClient) LeaseProbeFailures(_a0 context.Context, _a1 v1beta4.LeaseID) (map[string][]v1beta3.ProbeStatus, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LeaseProbeFailures")
+	}
+
+	var r0 map[string][]v1beta3.ProbeStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID) (map[string][]v1beta3.ProbeStatus, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID) map[string][]v1beta3.ProbeStatus); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string][]v1beta3.ProbeStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, v1beta4.LeaseID) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReadClient_LeaseProbeFailures_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LeaseProbeFailures'
+type ReadClient_LeaseProbeFailures_Call struct {
+	*mock.Call
+}
+
+// LeaseProbeFailures is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 v1beta4.LeaseID
+func (_e *ReadClient_Expecter) LeaseProbeFailures(_a0 interface{}, _a1 interface{}) *ReadClient_LeaseProbeFailures_Call {
+	return &ReadClient_LeaseProbeFailures_Call{Call: _e.mock.On("LeaseProbeFailures", _a0, _a1)}
+}
+
+func (_c *ReadClient_LeaseProbeFailures_Call) Run(run func(_a0 context.Context, _a1 v1beta4.LeaseID)) *ReadClient_LeaseProbeFailures_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(v1beta4.LeaseID))
+	})
+	return _c
+}
+
+func (_c *ReadClient_LeaseProbeFailures_Call) Return(_a0 map[string][]v1beta3.ProbeStatus, _a1 error) *ReadClient_LeaseProbeFailures_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ReadClient_LeaseProbeFailures_Call) RunAndReturn(run func(context.Context, v1beta4.LeaseID) (map[string][]v1beta3.ProbeStatus, error)) *ReadClient_LeaseProbeFailures_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LeaseSnapshots provides a mock function with given fields: _a0, _a1
+func (_m *ReadClient) LeaseSnapshots(_a0 context.Context, _a1 v1beta4.LeaseID) ([]v1beta3.VolumeSnapshot, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LeaseSnapshots")
+	}
+
+	var r0 []v1beta3.VolumeSnapshot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID) ([]v1beta3.VolumeSnapshot, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID) []v1beta3.VolumeSnapshot); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]v1beta3.VolumeSnapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, v1beta4.LeaseID) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReadClient_LeaseSnapshots_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LeaseSnapshots'
+type ReadClient_LeaseSnapshots_Call struct {
+	*mock.Call
+}
+
+// LeaseSnapshots is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 v1beta4.LeaseID
+func (_e *ReadClient_Expecter) LeaseSnapshots(_a0 interface{}, _a1 interface{}) *ReadClient_LeaseSnapshots_Call {
+	return &ReadClient_LeaseSnapshots_Call{Call: _e.mock.On("LeaseSnapshots", _a0, _a1)}
+}
+
+func (_c *ReadClient_LeaseSnapshots_Call) Run(run func(_a0 context.Context, _a1 v1beta4.LeaseID)) *ReadClient_LeaseSnapshots_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(v1beta4.LeaseID))
+	})
+	return _c
+}
+
+func (_c *ReadClient_LeaseSnapshots_Call) Return(_a0 []v1beta3.VolumeSnapshot, _a1 error) *ReadClient_LeaseSnapshots_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ReadClient_LeaseSnapshots_Call) RunAndReturn(run func(context.Context, v1beta4.LeaseID) ([]v1beta3.VolumeSnapshot, error)) *ReadClient_LeaseSnapshots_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -510,6 +684,65 @@ func (_c *ReadClient_LeaseStatus_Call) RunAndReturn(run func(context.Context, v1
 	return _c
 }
 
+// ManifestRevisions provides a mock function with given fields: _a0, _a1
+func (_m *ReadClient) ManifestRevisions(_a0 context.Context, _a1 v1beta4.LeaseID) ([]v1beta3.ManifestRevision, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ManifestRevisions")
+	}
+
+	var r0 []v1beta3.ManifestRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID) ([]v1beta3.ManifestRevision, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID) []v1beta3.ManifestRevision); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]v1beta3.ManifestRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, v1beta4.LeaseID) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReadClient_ManifestRevisions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ManifestRevisions'
+type ReadClient_ManifestRevisions_Call struct {
+	*mock.Call
+}
+
+// ManifestRevisions is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 v1beta4.LeaseID
+func (_e *ReadClient_Expecter) ManifestRevisions(_a0 interface{}, _a1 interface{}) *ReadClient_ManifestRevisions_Call {
+	return &ReadClient_ManifestRevisions_Call{Call: _e.mock.On("ManifestRevisions", _a0, _a1)}
+}
+
+func (_c *ReadClient_ManifestRevisions_Call) Run(run func(_a0 context.Context, _a1 v1beta4.LeaseID)) *ReadClient_ManifestRevisions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(v1beta4.LeaseID))
+	})
+	return _c
+}
+
+func (_c *ReadClient_ManifestRevisions_Call) Return(_a0 []v1beta3.ManifestRevision, _a1 error) *ReadClient_ManifestRevisions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ReadClient_ManifestRevisions_Call) RunAndReturn(run func(context.Context, v1beta4.LeaseID) ([]v1beta3.ManifestRevision, error)) *ReadClient_ManifestRevisions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ObserveHostnameState provides a mock function with given fields: ctx
 func (_m *ReadClient) ObserveHostnameState(ctx context.Context) (<-chan hostname.ResourceEvent, error) {
 	ret := _m.Called(ctx)
@@ -686,6 +919,64 @@ func (_c *ReadClient_ServiceStatus_Call) RunAndReturn(run func(context.Context,
 	return _c
 }
 
+// SnapshotStorage provides a mock function with given fields: _a0
+func (_m *ReadClient) SnapshotStorage(_a0 context.Context) (map[string]int64, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SnapshotStorage")
+	}
+
+	var r0 map[string]int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (map[string]int64, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) map[string]int64); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReadClient_SnapshotStorage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SnapshotStorage'
+type ReadClient_SnapshotStorage_Call struct {
+	*mock.Call
+}
+
+// SnapshotStorage is a helper method to define mock.On call
+//   - _a0 context.Context
+func (_e *ReadClient_Expecter) SnapshotStorage(_a0 interface{}) *ReadClient_SnapshotStorage_Call {
+	return &ReadClient_SnapshotStorage_Call{Call: _e.mock.On("SnapshotStorage", _a0)}
+}
+
+func (_c *ReadClient_SnapshotStorage_Call) Run(run func(_a0 context.Context)) *ReadClient_SnapshotStorage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *ReadClient_SnapshotStorage_Call) Return(_a0 map[string]int64, _a1 error) *ReadClient_SnapshotStorage_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ReadClient_SnapshotStorage_Call) RunAndReturn(run func(context.Context) (map[string]int64, error)) *ReadClient_SnapshotStorage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewReadClient creates a new instance of ReadClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewReadClient(t interface {
@@ -32,6 +32,52 @@ func (_m *Service) EXPECT() *Service_Expecter {
 	return &Service_Expecter{mock: &_m.Mock}
 }
 
+// CheckCapacity provides a mock function with given fields: _a0
+func (_m *Service) CheckCapacity(_a0 deploymentv1beta3.ResourceGroup) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckCapacity")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(deploymentv1beta3.ResourceGroup) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Service_CheckCapacity_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckCapacity'
+type Service_CheckCapacity_Call struct {
+	*mock.Call
+}
+
+// CheckCapacity is a helper method to define mock.On call
+//   - _a0 deploymentv1beta3.ResourceGroup
+func (_e *Service_Expecter) CheckCapacity(_a0 interface{}) *Service_CheckCapacity_Call {
+	return &Service_CheckCapacity_Call{Call: _e.mock.On("CheckCapacity", _a0)}
+}
+
+func (_c *Service_CheckCapacity_Call) Run(run func(_a0 deploymentv1beta3.ResourceGroup)) *Service_CheckCapacity_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(deploymentv1beta3.ResourceGroup))
+	})
+	return _c
+}
+
+func (_c *Service_CheckCapacity_Call) Return(_a0 error) *Service_CheckCapacity_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Service_CheckCapacity_Call) RunAndReturn(run func(deploymentv1beta3.ResourceGroup) error) *Service_CheckCapacity_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Close provides a mock function with given fields:
 func (_m *Service) Close() error {
 	ret := _m.Called()
@@ -350,6 +396,54 @@ func (_c *Service_Reserve_Call) RunAndReturn(run func(v1beta4.OrderID, deploymen
 	return _c
 }
 
+// RollbackLease provides a mock function with given fields: ctx, leaseID, revision
+func (_m *Service) RollbackLease(ctx context.Context, leaseID v1beta4.LeaseID, revision uint64) error {
+	ret := _m.Called(ctx, leaseID, revision)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RollbackLease")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, v1beta4.LeaseID, uint64) error); ok {
+		r0 = rf(ctx, leaseID, revision)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Service_RollbackLease_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RollbackLease'
+type Service_RollbackLease_Call struct {
+	*mock.Call
+}
+
+// RollbackLease is a helper method to define mock.On call
+//   - ctx context.Context
+//   - leaseID v1beta4.LeaseID
+//   - revision uint64
+func (_e *Service_Expecter) RollbackLease(ctx interface{}, leaseID interface{}, revision interface{}) *Service_RollbackLease_Call {
+	return &Service_RollbackLease_Call{Call: _e.mock.On("RollbackLease", ctx, leaseID, revision)}
+}
+
+func (_c *Service_RollbackLease_Call) Run(run func(ctx context.Context, leaseID v1beta4.LeaseID, revision uint64)) *Service_RollbackLease_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(v1beta4.LeaseID), args[2].(uint64))
+	})
+	return _c
+}
+
+func (_c *Service_RollbackLease_Call) Return(_a0 error) *Service_RollbackLease_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Service_RollbackLease_Call) RunAndReturn(run func(context.Context, v1beta4.LeaseID, uint64) error) *Service_RollbackLease_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Status provides a mock function with given fields: _a0
 func (_m *Service) Status(_a0 context.Context) (*v1beta3.Status, error) {
 	ret := _m.Called(_a0)
@@ -188,12 +188,27 @@ func (m *deploymentMonitor) doCheck(ctx context.Context) (bool, error) {
 		}
 	}
 
-	return badsvc == 0, nil
+	if badsvc == 0 {
+		return true, nil
+	}
+
+	// workloads of paused lease are scaled down on purpose
+	paused, err := m.client.LeasePaused(ctx, m.deployment.LeaseID())
+	if err != nil {
+		m.log.Error("lease paused", "err", err)
+		return false, err
+	}
+
+	if paused {
+		m.log.Debug("lease paused, services not checked")
+	}
+
+	return paused, nil
 }
 
 func (m *deploymentMonitor) runCloseLease(ctx context.Context) <-chan runner.Result {
 	return runner.Do(func() runner.Result {
-		// TODO: retry, timeout
+		// retries and timeout of the broadcast are up to the transaction service
 		msg := &mtypes.MsgCloseBid{
 			BidID: m.deployment.LeaseID().BidID(),
 		}
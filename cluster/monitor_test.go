@@ -67,6 +67,7 @@ func TestMonitorSendsClusterDeploymentPending(t *testing.T) {
 
 	statusResult := make(map[string]*ctypes.ServiceStatus)
 	client.On("LeaseStatus", mock.Anything, deployment.LeaseID()).Return(statusResult, nil)
+	client.On("LeasePaused", mock.Anything, deployment.LeaseID()).Return(false, nil)
 	mySession := session.New(myLog, nil, nil, -1)
 
 	sub, err := bus.Subscribe()
@@ -148,3 +149,51 @@ func TestMonitorSendsClusterDeploymentDeployed(t *testing.T) {
 
 	monitor.lc.Shutdown(nil)
 }
+
+func TestMonitorPausedLeaseDeployed(t *testing.T) {
+	const serviceName = "test"
+	myLog := testutil.Logger(t)
+	bus := pubsub.NewBus()
+
+	group := &manifest.Group{}
+	group.Services = make(manifest.Services, 1)
+	group.Services[0].Name = serviceName
+	group.Services[0].Count = 2
+	client := &mocks.Client{}
+	deployment := &ctypes.Deployment{
+		Lid:    testutil.LeaseID(t),
+		MGroup: group,
+	}
+
+	statusResult := make(map[string]*ctypes.ServiceStatus)
+	statusResult[serviceName] = &ctypes.ServiceStatus{
+		Name:      serviceName,
+		Available: 0,
+		Total:     0,
+	}
+	client.On("LeaseStatus", mock.Anything, deployment.LeaseID()).Return(statusResult, nil)
+	client.On("LeasePaused", mock.Anything, deployment.LeaseID()).Return(true, nil)
+	mySession := session.New(myLog, nil, nil, -1)
+
+	sub, err := bus.Subscribe()
+	require.NoError(t, err)
+	lc := lifecycle.New()
+	myDeploymentManager := &deploymentManager{
+		bus:        bus,
+		session:    mySession,
+		client:     client,
+		deployment: deployment,
+		log:        myLog,
+		lc:         lc,
+		config:     NewDefaultConfig(),
+	}
+	monitor := newDeploymentMonitor(myDeploymentManager)
+	require.NotNil(t, monitor)
+
+	ev := <-sub.Events()
+	result := ev.(event.ClusterDeployment)
+	require.Equal(t, deployment.LeaseID(), result.LeaseID)
+	require.Equal(t, event.ClusterDeploymentDeployed, result.Status)
+
+	monitor.lc.Shutdown(nil)
+}
@@ -2,6 +2,7 @@ package cluster
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/boz/go-lifecycle"
 	tpubsub "github.com/troian/pubsub"
@@ -33,6 +34,8 @@ var (
 	ErrNotRunning      = errors.New("not running")
 	ErrInvalidResource = errors.New("invalid resource")
 	errNoManifestGroup = errors.New("no manifest group could be found")
+	// ErrLeaseNotDeployed is the error when lease has no deployment managed by the provider
+	ErrLeaseNotDeployed = errors.New("lease not deployed")
 )
 
 var (
@@ -54,6 +57,7 @@ type service struct {
 	hostnames *hostnameService
 
 	checkDeploymentExistsRequestCh chan checkDeploymentExistsRequest
+	rollbackLeaseRequestCh         chan rollbackLeaseRequest
 	statusch                       chan chan<- *ctypes.Status
 	statusV1ch                     chan chan<- uint32
 	managers                       map[mtypes.LeaseID]*deploymentManager
@@ -76,12 +80,20 @@ type checkDeploymentExistsRequest struct {
 	responseCh chan<- mtypes.LeaseID
 }
 
-// Cluster is the interface that wraps Reserve and Unreserve methods
+type rollbackLeaseRequest struct {
+	deployment ctypes.IDeployment
+
+	responseCh chan<- error
+}
+
+// Cluster is the interface that wraps Reserve, Unreserve and CheckCapacity methods
 //
 //go:generate mockery --name Cluster
 type Cluster interface {
 	Reserve(mtypes.OrderID, dtypes.ResourceGroup) (ctypes.Reservation, error)
 	Unreserve(mtypes.OrderID) error
+	// CheckCapacity verifies resources fit into the cluster without reserving them
+	CheckCapacity(dtypes.ResourceGroup) error
 }
 
 // StatusClient is the interface which includes status of service
@@ -102,6 +114,8 @@ type Service interface {
 	Done() <-chan struct{}
 	HostnameService() ctypes.HostnameServiceClient
 	TransferHostname(ctx context.Context, leaseID mtypes.LeaseID, hostname string, serviceName string, externalPort uint32) error
+	// RollbackLease redeploys lease with manifest group of given revision, zero being the previous one
+	RollbackLease(ctx context.Context, leaseID mtypes.LeaseID, revision uint64) error
 }
 
 // NewService returns new Service instance
@@ -164,6 +178,7 @@ func NewService(
 		managers:                       make(map[mtypes.LeaseID]*deploymentManager),
 		managerch:                      make(chan *deploymentManager),
 		checkDeploymentExistsRequestCh: make(chan checkDeploymentExistsRequest),
+		rollbackLeaseRequestCh:         make(chan rollbackLeaseRequest),
 		log:                            log,
 		lc:                             lc,
 		config:                         cfg,
@@ -214,6 +229,34 @@ func (s *service) FindActiveLease(ctx context.Context, owner sdktypes.Address, d
 	return true, leaseID, mgroup, nil
 }
 
+func (s *service) RollbackLease(ctx context.Context, leaseID mtypes.LeaseID, revision uint64) error {
+	deployment, err := s.client.ManifestRevision(ctx, leaseID, revision)
+	if err != nil {
+		return err
+	}
+
+	response := make(chan error, 1)
+	req := rollbackLeaseRequest{
+		deployment: deployment,
+		responseCh: response,
+	}
+
+	select {
+	case s.rollbackLeaseRequestCh <- req:
+	case <-s.lc.ShuttingDown():
+		return ErrNotRunning
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err = <-response:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (s *service) Close() error {
 	s.lc.Shutdown(nil)
 	return s.lc.Error()
@@ -235,6 +278,10 @@ func (s *service) Unreserve(order mtypes.OrderID) error {
 	return s.inventory.unreserve(order)
 }
 
+func (s *service) CheckCapacity(resources dtypes.ResourceGroup) error {
+	return s.inventory.checkCapacity(resources)
+}
+
 func (s *service) HostnameService() ctypes.HostnameServiceClient {
 	return s.hostnames
 }
@@ -429,6 +476,8 @@ loop:
 			trySignal()
 		case req := <-s.checkDeploymentExistsRequestCh:
 			s.doCheckDeploymentExists(req)
+		case req := <-s.rollbackLeaseRequestCh:
+			req.responseCh <- s.doRollbackLease(req.deployment)
 		}
 		s.updateDeploymentManagerGauge()
 	}
@@ -457,6 +506,19 @@ func (s *service) doCheckDeploymentExists(req checkDeploymentExistsRequest) {
 	close(req.responseCh)
 }
 
+func (s *service) doRollbackLease(deployment ctypes.IDeployment) error {
+	lid := deployment.LeaseID()
+
+	manager := s.managers[lid]
+	if manager == nil {
+		return fmt.Errorf("%w: %s", ErrLeaseNotDeployed, lid)
+	}
+
+	s.log.Info("rolling back lease", "lease", lid, "group-name", deployment.ManifestGroup().Name)
+
+	return manager.update(deployment)
+}
+
 func (s *service) teardownLease(lid mtypes.LeaseID) {
 	if manager := s.managers[lid]; manager != nil {
 		if err := manager.teardown(); err != nil {
@@ -0,0 +1,309 @@
+package v1beta3
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// AttestationMinNonceLen and AttestationMaxNonceLen bound tenant supplied nonce.
+	// upper bound is size of TPM2B_DATA the TPM accepts as qualifying data
+	AttestationMinNonceLen = 8
+	AttestationMaxNonceLen = 64
+
+	// AttestationMaxPCR is the highest PCR index in a PC client TPM
+	AttestationMaxPCR = 23
+
+	tpmGeneratedValue  = 0xff544347
+	tpmSTAttestQuote   = 0x8018
+	tpmAlgSHA1         = 0x0004
+	tpmAlgSHA256       = 0x000b
+	tpmAlgSHA384       = 0x000c
+	tpmClockInfoLength = 17
+)
+
+var (
+	ErrAttestation          = errors.New("attestation")
+	ErrAttestationQuote     = fmt.Errorf("%w: invalid quote", ErrAttestation)
+	ErrAttestationNonce     = fmt.Errorf("%w: nonce mismatch", ErrAttestation)
+	ErrAttestationPCRs      = fmt.Errorf("%w: pcr mismatch", ErrAttestation)
+	ErrAttestationSignature = fmt.Errorf("%w: invalid signature", ErrAttestation)
+)
+
+// DefaultAttestationPCRs are quoted when tenant does not ask for specific PCRs.
+// PCRs 0-7 cover firmware, boot loader and secure boot state
+var DefaultAttestationPCRs = []uint{0, 1, 2, 3, 4, 5, 6, 7}
+
+// AttestationRequest is the tenant request for a quote
+type AttestationRequest struct {
+	Nonce []byte `json:"nonce"`
+	PCRs  []uint `json:"pcrs"`
+}
+
+// AttestationQuote is the signed TPM quote returned by the TEE sidecar.
+// Quote holds raw TPMS_ATTEST structure, Signature is attestation key signature
+// over sha256 digest of the Quote (ASN.1 for ECDSA keys, PKCS#1 v1.5 for RSA keys)
+// and AKPublic is PKIX encoded public part of the attestation key
+type AttestationQuote struct {
+	Service   string          `json:"service"`
+	PodIndex  uint            `json:"pod_index"`
+	HashAlg   string          `json:"hash_alg"`
+	Quote     []byte          `json:"quote"`
+	Signature []byte          `json:"signature"`
+	AKPublic  []byte          `json:"ak_public"`
+	PCRs      map[uint][]byte `json:"pcrs"`
+}
+
+func (r AttestationRequest) Validate() error {
+	if len(r.Nonce) < AttestationMinNonceLen || len(r.Nonce) > AttestationMaxNonceLen {
+		return fmt.Errorf("%w: nonce must be between %d and %d bytes", ErrAttestation, AttestationMinNonceLen, AttestationMaxNonceLen)
+	}
+
+	for _, pcr := range r.PCRs {
+		if pcr > AttestationMaxPCR {
+			return fmt.Errorf("%w: pcr index %d out of range [0, %d]", ErrAttestation, pcr, AttestationMaxPCR)
+		}
+	}
+
+	return nil
+}
+
+// SortedPCRs returns indexes of reported PCRs in ascending order
+func (q AttestationQuote) SortedPCRs() []uint {
+	res := make([]uint, 0, len(q.PCRs))
+	for idx := range q.PCRs {
+		res = append(res, idx)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i] < res[j]
+	})
+
+	return res
+}
+
+// Verify checks quote has been signed by the attestation key, carries given nonce
+// and reported PCR values match digest the TPM has signed.
+// it does not establish trust into attestation key itself
+func (q AttestationQuote) Verify(nonce []byte) error {
+	attest, err := parseTPMSAttest(q.Quote)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(attest.extraData, nonce) {
+		return ErrAttestationNonce
+	}
+
+	if err = q.verifyPCRs(attest); err != nil {
+		return err
+	}
+
+	return q.verifySignature()
+}
+
+func (q AttestationQuote) verifyPCRs(attest tpmsAttest) error {
+	alg, err := tpmHashAlg(q.HashAlg)
+	if err != nil {
+		return err
+	}
+
+	var selected []uint
+	for _, sel := range attest.pcrSelections {
+		if sel.hash != alg {
+			return fmt.Errorf("%w: quote selects pcr bank 0x%04x, expected 0x%04x", ErrAttestationPCRs, sel.hash, alg)
+		}
+
+		selected = append(selected, sel.pcrs...)
+	}
+
+	reported := q.SortedPCRs()
+	if len(selected) != len(reported) {
+		return fmt.Errorf("%w: quote selects %v, reported %v", ErrAttestationPCRs, selected, reported)
+	}
+
+	// TPM concatenates PCR values in selection order, which is ascending for single bank quote
+	var values []byte
+	for i, idx := range selected {
+		if reported[i] != idx {
+			return fmt.Errorf("%w: quote selects %v, reported %v", ErrAttestationPCRs, selected, reported)
+		}
+
+		values = append(values, q.PCRs[idx]...)
+	}
+
+	var hash crypto.Hash
+	switch len(attest.pcrDigest) {
+	case crypto.SHA1.Size():
+		hash = crypto.SHA1
+	case crypto.SHA256.Size():
+		hash = crypto.SHA256
+	case crypto.SHA384.Size():
+		hash = crypto.SHA384
+	default:
+		return fmt.Errorf("%w: unsupported pcr digest size %d", ErrAttestationQuote, len(attest.pcrDigest))
+	}
+
+	h := hash.New()
+	_, _ = h.Write(values)
+
+	if !bytes.Equal(h.Sum(nil), attest.pcrDigest) {
+		return fmt.Errorf("%w: pcr values do not match quoted digest", ErrAttestationPCRs)
+	}
+
+	return nil
+}
+
+func (q AttestationQuote) verifySignature() error {
+	pub, err := x509.ParsePKIXPublicKey(q.AKPublic)
+	if err != nil {
+		return fmt.Errorf("%w: attestation key: %w", ErrAttestationSignature, err)
+	}
+
+	hash := crypto.SHA256.New()
+	_, _ = hash.Write(q.Quote)
+	digest := hash.Sum(nil)
+
+	switch key := pub.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest, q.Signature) {
+			return ErrAttestationSignature
+		}
+	case *rsa.PublicKey:
+		if err = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest, q.Signature); err != nil {
+			return fmt.Errorf("%w: %w", ErrAttestationSignature, err)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported attestation key type %T", ErrAttestationSignature, pub)
+	}
+
+	return nil
+}
+
+func tpmHashAlg(name string) (uint16, error) {
+	switch name {
+	case "sha1":
+		return tpmAlgSHA1, nil
+	case "", "sha256":
+		return tpmAlgSHA256, nil
+	case "sha384":
+		return tpmAlgSHA384, nil
+	default:
+		return 0, fmt.Errorf("%w: unsupported hash algorithm %q", ErrAttestationQuote, name)
+	}
+}
+
+type tpmsPCRSelection struct {
+	hash uint16
+	pcrs []uint
+}
+
+// tpmsAttest holds fields of TPMS_ATTEST quote verification needs
+type tpmsAttest struct {
+	extraData     []byte
+	pcrSelections []tpmsPCRSelection
+	pcrDigest     []byte
+}
+
+// parseTPMSAttest decodes TPMS_ATTEST structure of TPM2_Quote as per
+// TPM 2.0 Library Part 2, sections 10.12.8 and 10.12.12
+func parseTPMSAttest(buf []byte) (tpmsAttest, error) {
+	res := tpmsAttest{}
+	r := bytes.NewReader(buf)
+
+	var magic uint32
+	var tag uint16
+
+	if err := binary.Read(r, binary.BigEndian, &magic); err != nil {
+		return res, fmt.Errorf("%w: %w", ErrAttestationQuote, err)
+	}
+
+	if magic != tpmGeneratedValue {
+		return res, fmt.Errorf("%w: not generated by TPM", ErrAttestationQuote)
+	}
+
+	if err := binary.Read(r, binary.BigEndian, &tag); err != nil {
+		return res, fmt.Errorf("%w: %w", ErrAttestationQuote, err)
+	}
+
+	if tag != tpmSTAttestQuote {
+		return res, fmt.Errorf("%w: unexpected attestation type 0x%04x", ErrAttestationQuote, tag)
+	}
+
+	// qualifiedSigner
+	if _, err := readTPM2B(r); err != nil {
+		return res, err
+	}
+
+	var err error
+	if res.extraData, err = readTPM2B(r); err != nil {
+		return res, err
+	}
+
+	// clockInfo and firmwareVersion
+	if _, err = r.Seek(tpmClockInfoLength+8, io.SeekCurrent); err != nil {
+		return res, fmt.Errorf("%w: %w", ErrAttestationQuote, err)
+	}
+
+	var count uint32
+	if err = binary.Read(r, binary.BigEndian, &count); err != nil {
+		return res, fmt.Errorf("%w: %w", ErrAttestationQuote, err)
+	}
+
+	for i := uint32(0); i < count; i++ {
+		sel := tpmsPCRSelection{}
+		var size uint8
+
+		if err = binary.Read(r, binary.BigEndian, &sel.hash); err != nil {
+			return res, fmt.Errorf("%w: %w", ErrAttestationQuote, err)
+		}
+
+		if err = binary.Read(r, binary.BigEndian, &size); err != nil {
+			return res, fmt.Errorf("%w: %w", ErrAttestationQuote, err)
+		}
+
+		bitmap := make([]byte, size)
+		if _, err = io.ReadFull(r, bitmap); err != nil {
+			return res, fmt.Errorf("%w: %w", ErrAttestationQuote, err)
+		}
+
+		for idx, octet := range bitmap {
+			for bit := 0; bit < 8; bit++ {
+				if octet&(1<<bit) != 0 {
+					sel.pcrs = append(sel.pcrs, uint(idx*8+bit)) // nolint: gosec
+				}
+			}
+		}
+
+		res.pcrSelections = append(res.pcrSelections, sel)
+	}
+
+	if res.pcrDigest, err = readTPM2B(r); err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+func readTPM2B(r *bytes.Reader) ([]byte, error) {
+	var size uint16
+	if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAttestationQuote, err)
+	}
+
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAttestationQuote, err)
+	}
+
+	return buf, nil
+}
@@ -449,6 +449,31 @@ func tryAdjustVolumesAttached(rp *inventoryV1.ResourcePair, res types.ResourceVa
 	return rp.SubNLZ(res)
 }
 
+// ReserveStorage takes quantity of the storage class from cluster storage. storage which does not fit
+// takes all there is left and ErrInsufficientCapacity is returned. storage of unknown class is ignored
+func ReserveStorage(storage inventoryV1.ClusterStorage, class string, quantity int64) error {
+	for idx := range storage {
+		if storage[idx].Info.Class != class {
+			continue
+		}
+
+		if !storage[idx].Quantity.SubNLZ(types.NewResourceValue(uint64(quantity))) { // nolint: gosec
+			allocated := storage[idx].Quantity.Allocatable.DeepCopy()
+			storage[idx].Quantity.Allocated = &allocated
+
+			return fmt.Errorf("%w: storage class %s", ctypes.ErrInsufficientCapacity, class)
+		}
+
+		break
+	}
+
+	return nil
+}
+
+func (inv *inventory) ReserveStorage(class string, quantity int64) error {
+	return ReserveStorage(inv.Storage, class, quantity)
+}
+
 func (inv *inventory) Adjust(reservation ctypes.ReservationGroup, opts ...ctypes.InventoryOption) error {
 	cfg := &ctypes.InventoryOptions{}
 	for _, opt := range opts {
@@ -0,0 +1,232 @@
+package v1beta3
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// HealthCheckEnvName is the service environment variable carrying health checks of the service as JSON object,
+	// e.g. {"liveness":{"http":{"path":"/healthz"}},"readiness":{"tcp":{}}}.
+	// health checks of the service take precedence over provider defaults
+	HealthCheckEnvName = "DOOOR_HEALTHCHECK"
+
+	ProbeLiveness  = "liveness"
+	ProbeReadiness = "readiness"
+	ProbeStartup   = "startup"
+)
+
+var (
+	ErrHealthCheckConfig = errors.New("health check config")
+)
+
+// HTTPHealthCheck checks service responds to HTTP GET with status 2xx or 3xx
+type HTTPHealthCheck struct {
+	Path string `json:"path" yaml:"path"`
+	// Port is container port. zero checks first port service exposes
+	Port uint32 `json:"port,omitempty" yaml:"port,omitempty"`
+}
+
+// TCPHealthCheck checks service accepts TCP connections
+type TCPHealthCheck struct {
+	// Port is container port. zero checks first port service exposes
+	Port uint32 `json:"port,omitempty" yaml:"port,omitempty"`
+}
+
+// ExecHealthCheck checks command run inside the container exits with status 0
+type ExecHealthCheck struct {
+	Command []string `json:"command" yaml:"command"`
+}
+
+// HealthCheck defines single probe of the service. exactly one of HTTP, TCP and Exec must be set,
+// zero thresholds fall back to kubernetes defaults
+type HealthCheck struct {
+	HTTP *HTTPHealthCheck `json:"http,omitempty" yaml:"http,omitempty"`
+	TCP  *TCPHealthCheck  `json:"tcp,omitempty" yaml:"tcp,omitempty"`
+	Exec *ExecHealthCheck `json:"exec,omitempty" yaml:"exec,omitempty"`
+
+	InitialDelaySeconds int32 `json:"initial_delay_seconds,omitempty" yaml:"initial_delay_seconds,omitempty"`
+	PeriodSeconds       int32 `json:"period_seconds,omitempty" yaml:"period_seconds,omitempty"`
+	TimeoutSeconds      int32 `json:"timeout_seconds,omitempty" yaml:"timeout_seconds,omitempty"`
+	SuccessThreshold    int32 `json:"success_threshold,omitempty" yaml:"success_threshold,omitempty"`
+	FailureThreshold    int32 `json:"failure_threshold,omitempty" yaml:"failure_threshold,omitempty"`
+}
+
+// HealthChecks are probes of the service container
+type HealthChecks struct {
+	Liveness  *HealthCheck `json:"liveness,omitempty" yaml:"liveness,omitempty"`
+	Readiness *HealthCheck `json:"readiness,omitempty" yaml:"readiness,omitempty"`
+	Startup   *HealthCheck `json:"startup,omitempty" yaml:"startup,omitempty"`
+}
+
+// HealthCheckConfig is the "health_checks" section of the provider config file.
+// it defines probes of services which do not define their own. services exposing no ports get no default probes
+type HealthCheckConfig struct {
+	// Ports defines probes by container port. entry of the first port service exposes is used
+	Ports map[uint32]HealthChecks `json:"ports" yaml:"ports"`
+	// Default is used when service exposes none of the ports above
+	Default HealthChecks `json:"default" yaml:"default"`
+}
+
+// ReadHealthCheckConfigPath reads "health_checks" section from the provider config file.
+// Empty config, which adds no probes, is returned if file does not have one
+func ReadHealthCheckConfigPath(path string) (HealthCheckConfig, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return HealthCheckConfig{}, err
+	}
+
+	val := struct {
+		HealthChecks *HealthCheckConfig `yaml:"health_checks"`
+	}{}
+
+	if err = yaml.Unmarshal(buf, &val); err != nil {
+		return HealthCheckConfig{}, err
+	}
+
+	if val.HealthChecks == nil {
+		return HealthCheckConfig{}, nil
+	}
+
+	if err = val.HealthChecks.Validate(); err != nil {
+		return HealthCheckConfig{}, err
+	}
+
+	return *val.HealthChecks, nil
+}
+
+func (cfg HealthCheckConfig) Validate() error {
+	for port, checks := range cfg.Ports {
+		if err := checks.Validate(); err != nil {
+			return fmt.Errorf("%w: port %d: %w", ErrHealthCheckConfig, port, err)
+		}
+	}
+
+	if err := cfg.Default.Validate(); err != nil {
+		return fmt.Errorf("%w: default: %w", ErrHealthCheckConfig, err)
+	}
+
+	return nil
+}
+
+// ForPorts returns default probes of the service exposing given container ports
+func (cfg HealthCheckConfig) ForPorts(ports []uint32) HealthChecks {
+	if len(ports) == 0 {
+		return HealthChecks{}
+	}
+
+	for _, port := range ports {
+		if checks, exists := cfg.Ports[port]; exists {
+			return checks
+		}
+	}
+
+	return cfg.Default
+}
+
+func (hc HealthChecks) Empty() bool {
+	return hc.Liveness == nil && hc.Readiness == nil && hc.Startup == nil
+}
+
+func (hc HealthChecks) Validate() error {
+	probes := []struct {
+		name  string
+		check *HealthCheck
+	}{
+		{name: ProbeLiveness, check: hc.Liveness},
+		{name: ProbeReadiness, check: hc.Readiness},
+		{name: ProbeStartup, check: hc.Startup},
+	}
+
+	for _, probe := range probes {
+		if probe.check == nil {
+			continue
+		}
+
+		if err := probe.check.Validate(); err != nil {
+			return fmt.Errorf("%s probe: %w", probe.name, err)
+		}
+	}
+
+	// kubernetes rejects liveness and startup probes which succeed after more than one success
+	if hc.Liveness != nil && hc.Liveness.SuccessThreshold > 1 {
+		return errors.New("liveness probe: success threshold must be 1")
+	}
+
+	if hc.Startup != nil && hc.Startup.SuccessThreshold > 1 {
+		return errors.New("startup probe: success threshold must be 1")
+	}
+
+	return nil
+}
+
+func (hc HealthCheck) Validate() error {
+	handlers := 0
+
+	if hc.HTTP != nil {
+		handlers++
+
+		if !strings.HasPrefix(hc.HTTP.Path, "/") {
+			return fmt.Errorf("invalid http path %q", hc.HTTP.Path)
+		}
+	}
+
+	if hc.TCP != nil {
+		handlers++
+	}
+
+	if hc.Exec != nil {
+		handlers++
+
+		if len(hc.Exec.Command) == 0 {
+			return errors.New("exec command cannot be empty")
+		}
+	}
+
+	if handlers != 1 {
+		return errors.New("exactly one of http, tcp and exec must be set")
+	}
+
+	for name, val := range map[string]int32{
+		"initial delay":     hc.InitialDelaySeconds,
+		"period":            hc.PeriodSeconds,
+		"timeout":           hc.TimeoutSeconds,
+		"success threshold": hc.SuccessThreshold,
+		"failure threshold": hc.FailureThreshold,
+	} {
+		if val < 0 {
+			return fmt.Errorf("%s cannot be negative", name)
+		}
+	}
+
+	return nil
+}
+
+// HealthChecksFromEnv returns health checks defined through service environment variables.
+// found is false if service does not define any
+func HealthChecksFromEnv(env []string) (HealthChecks, bool, error) {
+	for _, line := range env {
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 || parts[0] != HealthCheckEnvName {
+			continue
+		}
+
+		var res HealthChecks
+		if err := json.Unmarshal([]byte(parts[1]), &res); err != nil {
+			return HealthChecks{}, false, fmt.Errorf("%w: %s: %w", ErrHealthCheckConfig, HealthCheckEnvName, err)
+		}
+
+		if err := res.Validate(); err != nil {
+			return HealthChecks{}, false, fmt.Errorf("%w: %s: %w", ErrHealthCheckConfig, HealthCheckEnvName, err)
+		}
+
+		return res, true, nil
+	}
+
+	return HealthChecks{}, false, nil
+}
@@ -0,0 +1,91 @@
+package v1beta3
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	LogOutputStdout = "stdout"
+	LogOutputStderr = "stderr"
+)
+
+var (
+	ErrLeaseLogsOptions = errors.New("lease logs options")
+)
+
+// LeaseLogsOptions selects which logs of lease pods are streamed
+type LeaseLogsOptions struct {
+	Follow bool
+	// TailLines is the number of lines from the end of the logs to stream, all lines if nil
+	TailLines *int64
+	// SinceSeconds and SinceTime stream only logs written after, at most one of them is set
+	SinceSeconds *int64
+	SinceTime    *time.Time
+	Timestamps   bool
+	// Previous streams logs of the previous instance of the container, pods which container has not restarted are skipped
+	Previous bool
+	// Container to stream logs of, service container if empty. pods without the container are skipped
+	Container string
+	// LimitBytes is the number of bytes from the beginning of the logs to stream, all if nil
+	LimitBytes *int64
+}
+
+// SetSince parses val either as duration relative to now or as RFC3339 time
+func (o *LeaseLogsOptions) SetSince(val string) error {
+	if dur, err := time.ParseDuration(val); err == nil {
+		if dur <= 0 {
+			return fmt.Errorf("%w: since duration %q must be positive", ErrLeaseLogsOptions, val)
+		}
+
+		// sub-second durations are rounded up as kubernetes accepts whole seconds only
+		seconds := int64((dur + time.Second - 1) / time.Second)
+
+		o.SinceSeconds = &seconds
+		o.SinceTime = nil
+
+		return nil
+	}
+
+	tm, err := time.Parse(time.RFC3339, val)
+	if err != nil {
+		return fmt.Errorf("%w: since %q is neither duration nor RFC3339 time", ErrLeaseLogsOptions, val)
+	}
+
+	o.SinceTime = &tm
+	o.SinceSeconds = nil
+
+	return nil
+}
+
+// Since returns value of the since bound SetSince parses, empty if none is set
+func (o LeaseLogsOptions) Since() string {
+	switch {
+	case o.SinceSeconds != nil:
+		return strconv.FormatInt(*o.SinceSeconds, 10) + "s"
+	case o.SinceTime != nil:
+		return o.SinceTime.Format(time.RFC3339)
+	}
+
+	return ""
+}
+
+// Validate checks options values are within their bounds
+func (o LeaseLogsOptions) Validate() error {
+	if o.TailLines != nil && *o.TailLines < 0 {
+		return fmt.Errorf("%w: tail lines must not be negative", ErrLeaseLogsOptions)
+	}
+
+	if o.LimitBytes != nil && *o.LimitBytes <= 0 {
+		return fmt.Errorf("%w: limit bytes must be positive", ErrLeaseLogsOptions)
+	}
+
+	if o.SinceSeconds != nil && o.SinceTime != nil {
+		return fmt.Errorf("%w: at most one of since seconds and since time can be set", ErrLeaseLogsOptions)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package v1beta3
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	atypes "github.com/akash-network/akash-api/go/node/types/v1beta3"
+
+	"github.com/akash-network/node/sdl"
+)
+
+const (
+	// SnapshotRestoreEnvName is the service environment variable carrying volumes of the service to restore
+	// as JSON object of volume name to volume snapshot name, e.g. {"data":"nightly-web-data-web-0"}.
+	// volume is restored when it is created, or recreated if the snapshot it has been restored from changes
+	SnapshotRestoreEnvName = "DOOOR_RESTORE_SNAPSHOTS"
+)
+
+var (
+	ErrSnapshotRestoreConfig = errors.New("snapshot restore config")
+)
+
+// VolumeSnapshot is the snapshot of a persistent volume of the lease
+type VolumeSnapshot struct {
+	Name         string    `json:"name"`
+	Snapshot     string    `json:"snapshot"`
+	Service      string    `json:"service"`
+	Volume       string    `json:"volume"`
+	PVC          string    `json:"pvc"`
+	StorageClass string    `json:"storage_class"`
+	ReadyToUse   bool      `json:"ready_to_use"`
+	RestoreSize  int64     `json:"restore_size"`
+	CreatedAt    time.Time `json:"created_at"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// SnapshotRestoresFromEnv returns volume snapshots service volumes are to be restored from
+// as defined through service environment variables
+func SnapshotRestoresFromEnv(env []string) (map[string]string, error) {
+	for _, line := range env {
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 || parts[0] != SnapshotRestoreEnvName {
+			continue
+		}
+
+		res := make(map[string]string)
+		if err := json.Unmarshal([]byte(parts[1]), &res); err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrSnapshotRestoreConfig, SnapshotRestoreEnvName, err)
+		}
+
+		for volume, snapshot := range res {
+			if errs := validation.IsDNS1123Subdomain(snapshot); len(errs) != 0 {
+				return nil, fmt.Errorf("%w: %s: volume %s: invalid snapshot name %q: %s",
+					ErrSnapshotRestoreConfig, SnapshotRestoreEnvName, volume, snapshot, strings.Join(errs, ", "))
+			}
+		}
+
+		return res, nil
+	}
+
+	return nil, nil
+}
+
+// ResourcesSnapshotRestores returns volume snapshots service volumes are to be restored from
+// and checks each of them names persistent volume of the service resources
+func ResourcesSnapshotRestores(env []string, res atypes.Resources) (map[string]string, error) {
+	restores, err := SnapshotRestoresFromEnv(env)
+	if err != nil {
+		return nil, err
+	}
+
+	for volume := range restores {
+		persistent := false
+
+		for _, storage := range res.Storage {
+			if storage.Name == volume {
+				persistent, _ = storage.Attributes.Find(sdl.StorageAttributePersistent).AsBool()
+				break
+			}
+		}
+
+		if !persistent {
+			return nil, fmt.Errorf("%w: %s: %q is not a persistent volume of the service", ErrSnapshotRestoreConfig, SnapshotRestoreEnvName, volume)
+		}
+	}
+
+	return restores, nil
+}
@@ -0,0 +1,311 @@
+package v1beta3
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	dtypes "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
+	atypes "github.com/akash-network/akash-api/go/node/types/v1beta3"
+)
+
+const (
+	// TEEAttributeKey is the CPU attribute a resource unit sets to request a TEE sidecar.
+	// value is either boolean "true" to use provider's default profile or name of the profile
+	TEEAttributeKey = "tee"
+
+	// TEEEnvName is the service environment variable which used to enable TEE sidecar.
+	// it is not honored anymore as orders carry no env to reserve sidecar resources by, TEEAttributeKey must be used instead
+	TEEEnvName = "DOOOR_TEE"
+
+	TEESidecarContainerName = "sidecar-tee"
+	TEEDefaultProfileName   = "default"
+
+	TEEDefaultAttestationPort = 8080
+	TEEDefaultAttestationPath = "/attestation/quote"
+)
+
+const (
+	TEETypeTPM    = "tpm"
+	TEETypeSEV    = "sev"
+	TEETypeSEVSNP = "sev-snp"
+	TEETypeTDX    = "tdx"
+	TEETypeSGX    = "sgx"
+)
+
+var (
+	ErrTEEConfig         = errors.New("tee config")
+	ErrTEEProfileUnknown = errors.New("unknown tee profile")
+)
+
+// TEESidecarResources defines resources requested by TEE sidecar container.
+// Values use kubernetes quantity notation, for example "250m" or "128Mi"
+type TEESidecarResources struct {
+	CPU    string `json:"cpu" yaml:"cpu"`
+	Memory string `json:"memory" yaml:"memory"`
+}
+
+type TEESecurityContext struct {
+	Privileged               bool     `json:"privileged" yaml:"privileged"`
+	AllowPrivilegeEscalation bool     `json:"allow_privilege_escalation" yaml:"allow_privilege_escalation"`
+	RunAsUser                *int64   `json:"run_as_user,omitempty" yaml:"run_as_user,omitempty"`
+	RunAsGroup               *int64   `json:"run_as_group,omitempty" yaml:"run_as_group,omitempty"`
+	CapabilitiesAdd          []string `json:"capabilities_add" yaml:"capabilities_add"`
+	CapabilitiesDrop         []string `json:"capabilities_drop" yaml:"capabilities_drop"`
+}
+
+// TEESidecarProfile describes sidecar container injected into TEE enabled services
+type TEESidecarProfile struct {
+	Image           string              `json:"image" yaml:"image"`
+	PullPolicy      string              `json:"pull_policy" yaml:"pull_policy"`
+	Resources       TEESidecarResources `json:"resources" yaml:"resources"`
+	Devices         []string            `json:"devices" yaml:"devices"`
+	Env             map[string]string   `json:"env" yaml:"env"`
+	SecurityContext TEESecurityContext  `json:"security_context" yaml:"security_context"`
+	Attestation     TEEAttestation      `json:"attestation" yaml:"attestation"`
+}
+
+// TEEAttestation defines endpoint sidecar serves quotes on.
+// zero values fall back to TEEDefaultAttestationPort and TEEDefaultAttestationPath
+type TEEAttestation struct {
+	Port uint16 `json:"port" yaml:"port"`
+	Path string `json:"path" yaml:"path"`
+}
+
+// TEEDevice is a trusted execution device discovered on the node
+type TEEDevice struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Type string `json:"type"`
+}
+
+// TEEInfo lists trusted execution devices discovered on the node
+type TEEInfo struct {
+	Devices []TEEDevice `json:"devices"`
+}
+
+// TEEConfig is the "tee" section of the provider config file
+type TEEConfig struct {
+	DefaultProfile string                       `json:"default_profile" yaml:"default_profile"`
+	Profiles       map[string]TEESidecarProfile `json:"profiles" yaml:"profiles"`
+}
+
+// DefaultTEESidecarProfile is used when provider config does not define any profiles.
+// it mirrors sidecar that used to be hard-coded into the workload builder
+func DefaultTEESidecarProfile() TEESidecarProfile {
+	return TEESidecarProfile{
+		Image:      "brunolaureano/tee-go-api:v1.0.0",
+		PullPolicy: "IfNotPresent",
+		Devices:    []string{"/dev/tpmrm0"},
+		Env: map[string]string{
+			"TPM2TOOLS_TCTI": "device:/dev/tpmrm0",
+		},
+		SecurityContext: TEESecurityContext{
+			Privileged: true,
+		},
+		Attestation: TEEAttestation{
+			Port: TEEDefaultAttestationPort,
+			Path: TEEDefaultAttestationPath,
+		},
+	}
+}
+
+func NewDefaultTEEConfig() TEEConfig {
+	return TEEConfig{
+		DefaultProfile: TEEDefaultProfileName,
+		Profiles: map[string]TEESidecarProfile{
+			TEEDefaultProfileName: DefaultTEESidecarProfile(),
+		},
+	}
+}
+
+// ReadTEEConfigPath reads "tee" section from the provider config file.
+// Default config is returned if file does not have one
+func ReadTEEConfigPath(path string) (TEEConfig, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return TEEConfig{}, err
+	}
+
+	val := struct {
+		TEE *TEEConfig `yaml:"tee"`
+	}{}
+
+	if err = yaml.Unmarshal(buf, &val); err != nil {
+		return TEEConfig{}, err
+	}
+
+	if val.TEE == nil || len(val.TEE.Profiles) == 0 {
+		return NewDefaultTEEConfig(), nil
+	}
+
+	if val.TEE.DefaultProfile == "" {
+		val.TEE.DefaultProfile = TEEDefaultProfileName
+	}
+
+	if err = val.TEE.Validate(); err != nil {
+		return TEEConfig{}, err
+	}
+
+	return *val.TEE, nil
+}
+
+func (cfg TEEConfig) Validate() error {
+	if len(cfg.Profiles) == 0 {
+		return nil
+	}
+
+	if _, exists := cfg.Profiles[cfg.DefaultProfile]; !exists {
+		return fmt.Errorf("%w: default profile %q is not defined", ErrTEEConfig, cfg.DefaultProfile)
+	}
+
+	for name, profile := range cfg.Profiles {
+		if err := profile.Validate(); err != nil {
+			return fmt.Errorf("%w: profile %q: %w", ErrTEEConfig, name, err)
+		}
+	}
+
+	return nil
+}
+
+// Profile returns profile by name. Empty name selects default profile
+func (cfg TEEConfig) Profile(name string) (TEESidecarProfile, error) {
+	if len(cfg.Profiles) == 0 {
+		cfg = NewDefaultTEEConfig()
+	}
+
+	if name == "" {
+		name = cfg.DefaultProfile
+	}
+
+	profile, exists := cfg.Profiles[name]
+	if !exists {
+		return TEESidecarProfile{}, fmt.Errorf("%w: %q", ErrTEEProfileUnknown, name)
+	}
+
+	return profile, nil
+}
+
+func (p TEESidecarProfile) Validate() error {
+	if p.Image == "" {
+		return errors.New("image cannot be empty")
+	}
+
+	switch p.PullPolicy {
+	case "", "Always", "IfNotPresent", "Never":
+	default:
+		return fmt.Errorf("invalid pull policy %q", p.PullPolicy)
+	}
+
+	if _, _, err := p.Resources.Quantities(); err != nil {
+		return err
+	}
+
+	for _, dev := range p.Devices {
+		if !strings.HasPrefix(dev, "/dev/") {
+			return fmt.Errorf("invalid device path %q", dev)
+		}
+	}
+
+	if p.Attestation.Path != "" && !strings.HasPrefix(p.Attestation.Path, "/") {
+		return fmt.Errorf("invalid attestation path %q", p.Attestation.Path)
+	}
+
+	return nil
+}
+
+// Endpoint returns port and path of the sidecar attestation endpoint
+func (a TEEAttestation) Endpoint() (uint16, string) {
+	port := a.Port
+	if port == 0 {
+		port = TEEDefaultAttestationPort
+	}
+
+	path := a.Path
+	if path == "" {
+		path = TEEDefaultAttestationPath
+	}
+
+	return port, path
+}
+
+// Quantities parses cpu and memory values. Empty values are returned as zero quantities
+func (r TEESidecarResources) Quantities() (resource.Quantity, resource.Quantity, error) {
+	var cpu resource.Quantity
+	var memory resource.Quantity
+	var err error
+
+	if r.CPU != "" {
+		if cpu, err = resource.ParseQuantity(r.CPU); err != nil {
+			return cpu, memory, fmt.Errorf("invalid cpu quantity %q: %w", r.CPU, err)
+		}
+	}
+
+	if r.Memory != "" {
+		if memory, err = resource.ParseQuantity(r.Memory); err != nil {
+			return cpu, memory, fmt.Errorf("invalid memory quantity %q: %w", r.Memory, err)
+		}
+	}
+
+	return cpu, memory, nil
+}
+
+// Overhead returns resources sidecar adds to each replica, cpu is in millicpu and memory in bytes
+func (r TEESidecarResources) Overhead() (uint64, uint64) {
+	cpu, memory, err := r.Quantities()
+	if err != nil {
+		return 0, 0
+	}
+
+	return uint64(cpu.MilliValue()), uint64(memory.Value()) // nolint: gosec
+}
+
+// TEEProfileFromAttributes returns TEE profile requested by resource unit's CPU attributes.
+// Empty profile name means provider's default profile
+func TEEProfileFromAttributes(attrs atypes.Attributes) (string, bool) {
+	attr := attrs.Find(TEEAttributeKey)
+
+	if enabled, isBool := attr.AsBool(); isBool {
+		return "", enabled
+	}
+
+	return attr.AsString()
+}
+
+// TEEEnabledByEnv checks if service environment variables request TEE sidecar the legacy way
+func TEEEnabledByEnv(env []string) bool {
+	enabled := false
+
+	for _, line := range env {
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) == 2 && parts[0] == TEEEnvName {
+			enabled = strings.EqualFold(parts[1], "true")
+		}
+	}
+
+	return enabled
+}
+
+// ResourcesTEEProfile returns TEE profile requested by the resources
+func ResourcesTEEProfile(res atypes.Resources) (string, bool) {
+	if res.CPU == nil {
+		return "", false
+	}
+
+	return TEEProfileFromAttributes(res.CPU.Attributes)
+}
+
+// ResourceGroupRequiresTEE checks if any resource unit of the group requests TEE sidecar
+func ResourceGroupRequiresTEE(group dtypes.ResourceGroup) bool {
+	for _, unit := range group.GetResourceUnits() {
+		if _, enabled := ResourcesTEEProfile(unit.Resources); enabled {
+			return true
+		}
+	}
+
+	return false
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"io"
 	"strings"
+	"time"
 
 	inventoryV1 "github.com/akash-network/akash-api/go/inventory/v1"
 	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
@@ -34,6 +35,13 @@ type ServiceLog struct {
 	Name    string
 	Stream  io.ReadCloser
 	Scanner *bufio.Scanner
+	// Service, Replica and Container identify the container of the pod stream is read from
+	Service   string
+	Replica   int
+	Container string
+	// Output is either LogOutputStdout or LogOutputStderr when outputs of the container are streamed separately,
+	// empty when stream carries both
+	Output string
 }
 
 type LeaseEventObject struct {
@@ -66,6 +74,8 @@ func WithDryRun() InventoryOption {
 
 type Inventory interface {
 	Adjust(ReservationGroup, ...InventoryOption) error
+	// ReserveStorage takes storage of the class used outside of reservations, e.g. by volume snapshots
+	ReserveStorage(class string, quantity int64) error
 	Metrics() inventoryV1.Metrics
 	Snapshot() inventoryV1.Cluster
 	Dup() Inventory
@@ -138,6 +148,30 @@ type ServiceStatus struct {
 	UpdatedReplicas    int32 `json:"updated_replicas"`
 	ReadyReplicas      int32 `json:"ready_replicas"`
 	AvailableReplicas  int32 `json:"available_replicas"`
+
+	// RecentProbeFailures lists probes kubelet has reported failing for current replicas of the service.
+	// it is built from cluster events, so it covers only as long as cluster retains them, an hour by default,
+	// and does not tell if probe passes now. set by provider gateway only
+	RecentProbeFailures []ProbeStatus `json:"recent_probe_failures,omitempty"`
+}
+
+// ProbeStatus summarizes recent failures of the probe across replicas of the service
+type ProbeStatus struct {
+	// Probe is liveness, readiness or startup
+	Probe string `json:"probe"`
+	// Failures is number of failures reported within events cluster retains
+	Failures    int32     `json:"failures"`
+	LastFailure time.Time `json:"last_failure"`
+	Message     string    `json:"message"`
+}
+
+// ManifestRevision describes manifest group deployed to the lease, lease can be rolled back to any of them
+type ManifestRevision struct {
+	Revision   uint64    `json:"revision"`
+	DeployedAt time.Time `json:"deployed_at"`
+	// Current is set on revision lease runs now
+	Current  bool     `json:"current"`
+	Services []string `json:"services"`
 }
 
 type ForwardedPortStatus struct {
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	sdkclient "github.com/cosmos/cosmos-sdk/client"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	cutils "github.com/akash-network/node/x/cert/utils"
+
+	"github.com/akash-network/provider/bidengine/journal"
+	aclient "github.com/akash-network/provider/client"
+	gwrest "github.com/akash-network/provider/gateway/rest"
+)
+
+const (
+	flagOwner    = "owner"
+	flagLimit    = "limit"
+	flagInFlight = "in-flight"
+)
+
+func bidHistoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "bid-history",
+		Short:        "get orders provider has reserved resources and bid for",
+		Long:         "get orders provider has reserved resources and bid for. tenants see only their own orders, provider account sees all of them",
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+		PreRunE:      checkOutputFormat,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return doBidHistory(cmd)
+		},
+	}
+
+	addProviderFlags(cmd)
+	cmd.Flags().String(flagOwner, "", "show orders of given deployment owner only. has effect for provider account only")
+	cmd.Flags().Uint(flagLimit, 100, "max number of most recent orders to show, 0 shows all")
+	cmd.Flags().Bool(flagInFlight, false, "show orders which are not closed yet only")
+	cmd.Flags().StringP(flagOutput, "o", outputText, "output format text|json|yaml. default text")
+
+	return cmd
+}
+
+func doBidHistory(cmd *cobra.Command) error {
+	cctx, err := sdkclient.GetClientTxContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+
+	cl, err := aclient.DiscoverQueryClient(ctx, cctx)
+	if err != nil {
+		return err
+	}
+
+	prov, err := providerFromFlags(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	var opts journal.ListOptions
+
+	if opts.Owner, err = cmd.Flags().GetString(flagOwner); err != nil {
+		return err
+	}
+
+	limit, err := cmd.Flags().GetUint(flagLimit)
+	if err != nil {
+		return err
+	}
+
+	opts.Limit = int(limit) // nolint: gosec
+
+	if opts.InFlight, err = cmd.Flags().GetBool(flagInFlight); err != nil {
+		return err
+	}
+
+	cert, err := cutils.LoadAndQueryCertificateForAccount(cmd.Context(), cctx, nil)
+	if err != nil {
+		return markRPCServerError(err)
+	}
+
+	gclient, err := gwrest.NewClient(ctx, cl, prov, []tls.Certificate{cert})
+	if err != nil {
+		return err
+	}
+
+	entries, err := gclient.BidHistory(ctx, opts)
+	if err != nil {
+		return showErrorToUser(err)
+	}
+
+	buf := &bytes.Buffer{}
+
+	switch cmd.Flag(flagOutput).Value.String() {
+	case outputText:
+		for _, entry := range entries {
+			oid := entry.OrderID
+			state := "in-flight"
+			if entry.Closed {
+				state = "closed: " + entry.CloseReason
+			}
+
+			_, _ = fmt.Fprintf(buf, "order: %s/%d/%d/%d\n\tstate:        %s\n", oid.Owner, oid.DSeq, oid.GSeq, oid.OSeq, state)
+			if entry.Price != nil {
+				_, _ = fmt.Fprintf(buf, "\tprice:        %s\n", entry.Price)
+			}
+			if entry.BidPlaced() {
+				_, _ = fmt.Fprintf(buf, "\tbid tx:       %s\n", entry.BidTx)
+			}
+			if !entry.BidDeadline.IsZero() {
+				_, _ = fmt.Fprintf(buf, "\tbid deadline: %s\n", entry.BidDeadline.Format(time.RFC3339))
+			}
+			_, _ = fmt.Fprintf(buf, "\tevents:\n")
+			for _, ev := range entry.Events {
+				_, _ = fmt.Fprintf(buf, "\t\t%s %-8s %s\n", ev.Time.Format(time.RFC3339), ev.Type, ev.Detail)
+			}
+		}
+	case outputJSON:
+		err = json.NewEncoder(buf).Encode(entries)
+	case outputYAML:
+		// entries carry json tags only, convert them so yaml keys match json output
+		var data []byte
+		if data, err = json.Marshal(entries); err != nil {
+			return err
+		}
+
+		var obj interface{}
+		if err = json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+
+		err = yaml.NewEncoder(buf).Encode(obj)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprint(cmd.OutOrStdout(), buf.String())
+
+	return err
+}
@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	sdkclient "github.com/cosmos/cosmos-sdk/client"
+	"github.com/spf13/cobra"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
+	cutils "github.com/akash-network/node/x/cert/utils"
+
+	aclient "github.com/akash-network/provider/client"
+	gwrest "github.com/akash-network/provider/gateway/rest"
+	"github.com/akash-network/provider/ledger"
+)
+
+const (
+	flagSince       = "since"
+	flagUntil       = "until"
+	flagWithdrawals = "withdrawals"
+
+	outputCSV = "csv"
+
+	dateLayout = "2006-01-02"
+)
+
+func earningsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "earnings",
+		Short: "get amounts provider has withdrawn from leases and leases have accrued",
+		Long: "get amounts provider has withdrawn from leases and leases have accrued, per lease, per owner and in total. " +
+			"tenants see only their own leases, provider account sees all of them",
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			format := cmd.Flag(flagOutput).Value.String()
+			switch format {
+			case outputText:
+			case outputJSON:
+			case outputCSV:
+			default:
+				return fmt.Errorf("invalid output format \"%s\", expected text|json|csv", format) // nolint: err113
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return doEarnings(cmd)
+		},
+	}
+
+	addProviderFlags(cmd)
+	cmd.Flags().String(flagOwner, "", "show leases of given deployment owner only. has effect for provider account only")
+	cmd.Flags().Uint64(FlagDSeq, 0, "show leases of given deployment only")
+	cmd.Flags().String(flagSince, "", "count withdrawals made since given time, RFC3339 or YYYY-MM-DD")
+	cmd.Flags().String(flagUntil, "", "count withdrawals made before given time, RFC3339 or YYYY-MM-DD")
+	cmd.Flags().Bool(flagWithdrawals, false, "list individual withdrawals")
+	cmd.Flags().StringP(flagOutput, "o", outputText, "output format text|json|csv. default text")
+
+	return cmd
+}
+
+func doEarnings(cmd *cobra.Command) error {
+	cctx, err := sdkclient.GetClientTxContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+
+	cl, err := aclient.DiscoverQueryClient(ctx, cctx)
+	if err != nil {
+		return err
+	}
+
+	prov, err := providerFromFlags(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	var query ledger.Query
+
+	if query.Owner, err = cmd.Flags().GetString(flagOwner); err != nil {
+		return err
+	}
+
+	if query.DSeq, err = cmd.Flags().GetUint64(FlagDSeq); err != nil {
+		return err
+	}
+
+	if query.From, err = timeFromFlag(cmd, flagSince); err != nil {
+		return err
+	}
+
+	if query.To, err = timeFromFlag(cmd, flagUntil); err != nil {
+		return err
+	}
+
+	if query.Withdrawals, err = cmd.Flags().GetBool(flagWithdrawals); err != nil {
+		return err
+	}
+
+	cert, err := cutils.LoadAndQueryCertificateForAccount(cmd.Context(), cctx, nil)
+	if err != nil {
+		return markRPCServerError(err)
+	}
+
+	gclient, err := gwrest.NewClient(ctx, cl, prov, []tls.Certificate{cert})
+	if err != nil {
+		return err
+	}
+
+	report, err := gclient.Earnings(ctx, query)
+	if err != nil {
+		return showErrorToUser(err)
+	}
+
+	buf := &bytes.Buffer{}
+
+	switch cmd.Flag(flagOutput).Value.String() {
+	case outputText:
+		_, _ = fmt.Fprintf(buf, "total:\n")
+		writeEarningsText(buf, report.Total)
+
+		for _, owner := range report.Owners {
+			_, _ = fmt.Fprintf(buf, "owner: %s\n\tleases:      %d\n", owner.Owner, owner.Leases)
+			writeEarningsText(buf, owner.Earnings)
+		}
+
+		for _, lease := range report.Leases {
+			_, _ = fmt.Fprintf(buf, "lease: %s\n", leaseIDText(lease.LeaseID))
+			writeEarningsText(buf, lease.Earnings)
+		}
+
+		for _, withdrawal := range report.Withdrawals {
+			_, _ = fmt.Fprintf(buf, "withdrawal: %s %s %s height %d tx %s\n",
+				withdrawal.Time.Format(time.RFC3339),
+				leaseIDText(withdrawal.LeaseID),
+				withdrawal.Amount,
+				withdrawal.Height,
+				withdrawal.TxHash)
+		}
+	case outputJSON:
+		err = json.NewEncoder(buf).Encode(report)
+	case outputCSV:
+		if query.Withdrawals {
+			err = writeWithdrawalsCSV(buf, report.Withdrawals)
+		} else {
+			err = writeEarningsCSV(buf, report.Leases)
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprint(cmd.OutOrStdout(), buf.String())
+
+	return err
+}
+
+// timeFromFlag parses RFC3339 time or date at UTC midnight
+func timeFromFlag(cmd *cobra.Command, name string) (time.Time, error) {
+	val, err := cmd.Flags().GetString(name)
+	if err != nil || val == "" {
+		return time.Time{}, err
+	}
+
+	if tm, err := time.Parse(time.RFC3339, val); err == nil {
+		return tm, nil
+	}
+
+	tm, err := time.Parse(dateLayout, val)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s \"%s\", expected RFC3339 time or YYYY-MM-DD date", name, val) // nolint: err113
+	}
+
+	return tm, nil
+}
+
+func writeEarningsText(buf *bytes.Buffer, earnings ledger.Earnings) {
+	_, _ = fmt.Fprintf(buf, "\twithdrawn:   %s\n\taccrued:     %s\n\twithdrawals: %d\n",
+		earnings.Withdrawn, earnings.Accrued, earnings.Withdrawals)
+}
+
+func leaseIDText(lid mtypes.LeaseID) string {
+	return fmt.Sprintf("%s/%d/%d/%d", lid.Owner, lid.DSeq, lid.GSeq, lid.OSeq)
+}
+
+// writeEarningsCSV writes row per lease and denomination it has earned in
+func writeEarningsCSV(buf *bytes.Buffer, leases []ledger.LeaseEarnings) error {
+	w := csv.NewWriter(buf)
+
+	if err := w.Write([]string{"owner", "dseq", "gseq", "oseq", "provider", "denom", "withdrawn", "accrued", "withdrawals"}); err != nil {
+		return err
+	}
+
+	for _, lease := range leases {
+		denoms := make(map[string]bool)
+		var order []string
+
+		for _, coin := range lease.Withdrawn {
+			if !denoms[coin.Denom] {
+				denoms[coin.Denom] = true
+				order = append(order, coin.Denom)
+			}
+		}
+
+		for _, coin := range lease.Accrued {
+			if !denoms[coin.Denom] {
+				denoms[coin.Denom] = true
+				order = append(order, coin.Denom)
+			}
+		}
+
+		lid := lease.LeaseID
+
+		for _, denom := range order {
+			err := w.Write([]string{
+				lid.Owner,
+				strconv.FormatUint(lid.DSeq, 10),
+				strconv.FormatUint(uint64(lid.GSeq), 10),
+				strconv.FormatUint(uint64(lid.OSeq), 10),
+				lid.Provider,
+				denom,
+				lease.Withdrawn.AmountOf(denom).String(),
+				lease.Accrued.AmountOf(denom).String(),
+				strconv.Itoa(lease.Withdrawals),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	w.Flush()
+
+	return w.Error()
+}
+
+func writeWithdrawalsCSV(buf *bytes.Buffer, withdrawals []ledger.Withdrawal) error {
+	w := csv.NewWriter(buf)
+
+	if err := w.Write([]string{"time", "height", "tx_hash", "owner", "dseq", "gseq", "oseq", "provider", "denom", "amount"}); err != nil {
+		return err
+	}
+
+	for _, withdrawal := range withdrawals {
+		lid := withdrawal.LeaseID
+
+		err := w.Write([]string{
+			withdrawal.Time.Format(time.RFC3339),
+			strconv.FormatInt(withdrawal.Height, 10),
+			withdrawal.TxHash,
+			lid.Owner,
+			strconv.FormatUint(lid.DSeq, 10),
+			strconv.FormatUint(uint64(lid.GSeq), 10),
+			strconv.FormatUint(uint64(lid.OSeq), 10),
+			lid.Provider,
+			withdrawal.Amount.Denom,
+			withdrawal.Amount.Amount.String(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+
+	return w.Error()
+}
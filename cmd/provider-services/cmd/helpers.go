@@ -55,6 +55,36 @@ func addCmdFlags(cmd *cobra.Command) {
 	}
 }
 
+// addProviderFlags adds flags of the commands querying provider on behalf of the account
+func addProviderFlags(cmd *cobra.Command) {
+	cmd.Flags().String(FlagProvider, "", "provider")
+	cmd.Flags().String(flags.FlagHome, app.DefaultHome, "the application home directory")
+	cmd.Flags().String(flags.FlagFrom, "", "name or address of private key with which to sign")
+	cmd.Flags().String(flags.FlagKeyringBackend, flags.DefaultKeyringBackend, "select keyring's backend (os|file|kwallet|pass|test)")
+
+	if err := cmd.MarkFlagRequired(FlagProvider); err != nil {
+		panic(err.Error())
+	}
+
+	if err := cmd.MarkFlagRequired(flags.FlagFrom); err != nil {
+		panic(err.Error())
+	}
+}
+
+// checkOutputFormat validates output flag of the commands supporting text|json|yaml formats
+func checkOutputFormat(cmd *cobra.Command, _ []string) error {
+	format := cmd.Flag(flagOutput).Value.String()
+	switch format {
+	case outputText:
+	case outputJSON:
+	case outputYAML:
+	default:
+		return fmt.Errorf("invalid output format \"%s\", expected text|json|yaml", format) // nolint: err113
+	}
+
+	return nil
+}
+
 func addManifestFlags(cmd *cobra.Command) {
 	addCmdFlags(cmd)
 
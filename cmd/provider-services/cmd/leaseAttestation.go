@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+
+	sdkclient "github.com/cosmos/cosmos-sdk/client"
+	"github.com/spf13/cobra"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
+	cmdcommon "github.com/akash-network/node/cmd/common"
+	cutils "github.com/akash-network/node/x/cert/utils"
+	dcli "github.com/akash-network/node/x/deployment/client/cli"
+	mcli "github.com/akash-network/node/x/market/client/cli"
+
+	aclient "github.com/akash-network/provider/client"
+	cltypes "github.com/akash-network/provider/cluster/types/v1beta3"
+	gwrest "github.com/akash-network/provider/gateway/rest"
+)
+
+const (
+	flagNonce = "nonce"
+	flagPCRs  = "pcrs"
+
+	defaultNonceLen = 32
+)
+
+type leaseAttestationResult struct {
+	LeaseID     mtypes.LeaseID  `json:"lease_id"`
+	Service     string          `json:"service"`
+	PodIndex    uint            `json:"pod_index"`
+	Nonce       string          `json:"nonce"`
+	HashAlg     string          `json:"hash_alg"`
+	PCRs        map[uint]string `json:"pcrs"`
+	AKPublic    string          `json:"ak_public"`
+	Certificate string          `json:"certificate"`
+	Verified    bool            `json:"verified"`
+}
+
+func leaseAttestationCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "lease-attestation",
+		Short:        "get TEE quote of the lease service and verify it locally",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return doLeaseAttestation(cmd)
+		},
+	}
+
+	addServiceFlags(cmd)
+	if err := cmd.MarkFlagRequired(FlagService); err != nil {
+		panic(err.Error())
+	}
+
+	cmd.Flags().Uint(FlagReplicaIndex, 0, "replica index to attest")
+	cmd.Flags().String(flagNonce, "", fmt.Sprintf("hex encoded nonce, random %d bytes are used if not set", defaultNonceLen))
+	cmd.Flags().UintSlice(flagPCRs, nil, "PCRs to quote, provider defaults are used if not set")
+
+	return cmd
+}
+
+func doLeaseAttestation(cmd *cobra.Command) error {
+	cctx, err := sdkclient.GetClientTxContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+
+	svcName, err := cmd.Flags().GetString(FlagService)
+	if err != nil {
+		return err
+	}
+
+	podIndex, err := cmd.Flags().GetUint(FlagReplicaIndex)
+	if err != nil {
+		return err
+	}
+
+	pcrs, err := cmd.Flags().GetUintSlice(flagPCRs)
+	if err != nil {
+		return err
+	}
+
+	snonce, err := cmd.Flags().GetString(flagNonce)
+	if err != nil {
+		return err
+	}
+
+	var nonce []byte
+	if snonce != "" {
+		if nonce, err = hex.DecodeString(snonce); err != nil {
+			return fmt.Errorf("invalid nonce: %w", err)
+		}
+	} else {
+		nonce = make([]byte, defaultNonceLen)
+		if _, err = rand.Read(nonce); err != nil {
+			return err
+		}
+	}
+
+	if err = (cltypes.AttestationRequest{Nonce: nonce, PCRs: pcrs}).Validate(); err != nil {
+		return err
+	}
+
+	cl, err := aclient.DiscoverQueryClient(ctx, cctx)
+	if err != nil {
+		return err
+	}
+
+	prov, err := providerFromFlags(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	bid, err := mcli.BidIDFromFlags(cmd.Flags(), dcli.WithOwner(cctx.FromAddress))
+	if err != nil {
+		return err
+	}
+
+	cert, err := cutils.LoadAndQueryCertificateForAccount(cmd.Context(), cctx, nil)
+	if err != nil {
+		return markRPCServerError(err)
+	}
+
+	gclient, err := gwrest.NewClient(ctx, cl, prov, []tls.Certificate{cert})
+	if err != nil {
+		return err
+	}
+
+	lid := bid.LeaseID()
+
+	attestation, err := gclient.LeaseAttestation(ctx, lid, svcName, podIndex, nonce, pcrs)
+	if err != nil {
+		return showErrorToUser(err)
+	}
+
+	if err = attestation.Verify(lid, nonce); err != nil {
+		return err
+	}
+
+	result := leaseAttestationResult{
+		LeaseID:     lid,
+		Service:     attestation.Quote.Service,
+		PodIndex:    attestation.Quote.PodIndex,
+		Nonce:       hex.EncodeToString(nonce),
+		HashAlg:     attestation.Quote.HashAlg,
+		PCRs:        make(map[uint]string, len(attestation.Quote.PCRs)),
+		AKPublic:    hex.EncodeToString(attestation.Quote.AKPublic),
+		Certificate: string(attestation.Certificate),
+		Verified:    true,
+	}
+
+	for idx, val := range attestation.Quote.PCRs {
+		result.PCRs[idx] = hex.EncodeToString(val)
+	}
+
+	return cmdcommon.PrintJSON(cctx, result)
+}
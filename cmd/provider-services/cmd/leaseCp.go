@@ -0,0 +1,491 @@
+package cmd
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	sdkclient "github.com/cosmos/cosmos-sdk/client"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	cutils "github.com/akash-network/node/x/cert/utils"
+	dcli "github.com/akash-network/node/x/deployment/client/cli"
+	mcli "github.com/akash-network/node/x/market/client/cli"
+
+	aclient "github.com/akash-network/provider/client"
+	gwrest "github.com/akash-network/provider/gateway/rest"
+)
+
+const (
+	FlagCopyProgress = "progress"
+)
+
+var (
+	errLeaseCopyArgs  = errors.New("exactly one of source and destination must be remote path <service>:<path>")
+	errLeaseCopyEntry = errors.New("archive entry outside of destination")
+
+	// leaseCopyProgressInterval is how often lease-cp reports number of bytes copied
+	leaseCopyProgressInterval = 500 * time.Millisecond
+)
+
+func leaseCopyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lease-cp <src> <dst>",
+		Short: "copy files and directories to and from lease containers",
+		Long: `copy files and directories to and from lease containers.
+remote path is given as <service>:<absolute path>, either source or destination must be remote:
+  lease-cp ./site web:/var/www/html     upload directory ./site as /var/www/html
+  lease-cp ./app.conf web:/etc/app/     upload file into directory /etc/app
+  lease-cp web:/var/log/app.log ./      download file into current directory
+container image must provide tar`,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE:         doLeaseCopy,
+	}
+
+	addLeaseFlags(cmd)
+	cmd.Flags().Uint(FlagReplicaIndex, 0, "replica index to copy to or from")
+	cmd.Flags().Bool(FlagCopyProgress, true, "report number of bytes copied to stderr")
+
+	return cmd
+}
+
+// leaseCopyRemote is path in the container of the service
+type leaseCopyRemote struct {
+	service string
+	path    string
+}
+
+// parseLeaseCopyPath returns remote path the argument is in form <service>:<path>, nil if it is local path
+func parseLeaseCopyPath(arg string) (*leaseCopyRemote, error) {
+	idx := strings.IndexByte(arg, ':')
+	if idx <= 0 {
+		return nil, nil
+	}
+
+	service := arg[:idx]
+	if errs := validation.IsDNS1123Label(service); len(errs) != 0 {
+		return nil, nil
+	}
+
+	fpath := arg[idx+1:]
+	if !path.IsAbs(fpath) {
+		return nil, fmt.Errorf("remote path %q must be absolute", arg)
+	}
+
+	return &leaseCopyRemote{
+		service: service,
+		path:    fpath,
+	}, nil
+}
+
+func doLeaseCopy(cmd *cobra.Command, args []string) error {
+	src, err := parseLeaseCopyPath(args[0])
+	if err != nil {
+		return err
+	}
+
+	dst, err := parseLeaseCopyPath(args[1])
+	if err != nil {
+		return err
+	}
+
+	if (src == nil) == (dst == nil) {
+		return errLeaseCopyArgs
+	}
+
+	podIndex, err := cmd.Flags().GetUint(FlagReplicaIndex)
+	if err != nil {
+		return err
+	}
+
+	showProgress, err := cmd.Flags().GetBool(FlagCopyProgress)
+	if err != nil {
+		return err
+	}
+
+	cctx, err := sdkclient.GetClientTxContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+
+	cl, err := aclient.DiscoverQueryClient(ctx, cctx)
+	if err != nil {
+		return err
+	}
+
+	prov, err := providerFromFlags(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	bidID, err := mcli.BidIDFromFlags(cmd.Flags(), dcli.WithOwner(cctx.FromAddress))
+	if err != nil {
+		return err
+	}
+	lID := bidID.LeaseID()
+
+	cert, err := cutils.LoadAndQueryCertificateForAccount(ctx, cctx, nil)
+	if err != nil {
+		return markRPCServerError(err)
+	}
+
+	gclient, err := gwrest.NewClient(ctx, cl, prov, []tls.Certificate{cert})
+	if err != nil {
+		return err
+	}
+
+	stderr := cmd.ErrOrStderr()
+
+	progress := newLeaseCopyProgress(stderr, showProgress)
+	go progress.run(ctx)
+
+	if dst != nil {
+		err = leaseUpload(args[0], dst, progress, func(dir string, archive io.Reader) error {
+			return gclient.LeaseUpload(ctx, lID, dst.service, podIndex, dir, archive, stderr)
+		})
+	} else {
+		err = leaseDownload(src, args[1], progress, func(archive io.Writer) error {
+			return gclient.LeaseDownload(ctx, lID, src.service, podIndex, path.Clean(src.path), archive, stderr)
+		})
+	}
+
+	progress.stop()
+
+	if err != nil {
+		return showErrorToUser(err)
+	}
+
+	return nil
+}
+
+// leaseUpload streams archive of local path src to upload which extracts it to the directory in the container.
+// src is copied as the destination path, or into it when the destination ends with slash
+func leaseUpload(src string, dst *leaseCopyRemote, progress io.Writer,
+	upload func(dir string, archive io.Reader) error) error {
+	src, err := filepath.Abs(src)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Lstat(src); err != nil {
+		return err
+	}
+
+	dir, name := path.Dir(path.Clean(dst.path)), path.Base(dst.path)
+	if strings.HasSuffix(dst.path, "/") {
+		dir, name = path.Clean(dst.path), filepath.Base(src)
+	}
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		_ = pw.CloseWithError(writeLeaseCopyArchive(pw, src, name))
+	}()
+
+	err = upload(dir, io.TeeReader(pr, progress))
+
+	// unblock archive writer when upload has not read the whole archive
+	_ = pr.CloseWithError(io.ErrClosedPipe)
+
+	return err
+}
+
+// leaseDownload extracts archive download streams to local path dst.
+// the remote path is copied as dst, or into it when dst is an existing directory
+func leaseDownload(src *leaseCopyRemote, dst string, progress io.Writer, download func(archive io.Writer) error) error {
+	// archive entries are named relative to the directory of the remote path, see gateway leaseCopyCommand
+	prefix := path.Base(path.Clean(src.path))
+	if prefix == "/" {
+		prefix = "."
+	}
+
+	if info, err := os.Stat(dst); err == nil && info.IsDir() {
+		dst = filepath.Join(dst, prefix)
+	}
+
+	pr, pw := io.Pipe()
+	errch := make(chan error, 1)
+
+	go func() {
+		err := download(io.MultiWriter(pw, progress))
+		_ = pw.CloseWithError(err)
+		errch <- err
+	}()
+
+	err := extractLeaseCopyArchive(pr, prefix, dst)
+	if err == nil {
+		// drain padding trailing the archive so the download completes
+		_, err = io.Copy(io.Discard, pr)
+	}
+
+	_ = pr.CloseWithError(err)
+
+	derr := <-errch
+	if err != nil {
+		return err
+	}
+
+	return derr
+}
+
+// writeLeaseCopyArchive writes tar archive of local path src to w with entries named under name
+func writeLeaseCopyArchive(w io.Writer, src string, name string) error {
+	tw := tar.NewWriter(w)
+
+	err := filepath.Walk(src, func(fpath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, fpath)
+		if err != nil {
+			return err
+		}
+
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(fpath); err != nil {
+				return err
+			}
+		}
+
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+
+		hdr.Name = path.Join(name, filepath.ToSlash(rel))
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(fpath)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = file.Close()
+		}()
+
+		_, err = io.Copy(tw, file)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// leaseCopyEntryPath returns path of the archive entry relative to the prefix it must be under
+func leaseCopyEntryPath(name string, prefix string) (string, bool) {
+	name = path.Clean(name)
+	prefix = path.Clean(prefix)
+
+	rel := name
+	if prefix != "." {
+		switch {
+		case name == prefix:
+			rel = "."
+		case strings.HasPrefix(name, prefix+"/"):
+			rel = name[len(prefix)+1:]
+		default:
+			return "", false
+		}
+	}
+
+	if path.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", false
+	}
+
+	return rel, true
+}
+
+// extractLeaseCopyArchive extracts entries of tar archive under the prefix to local path dst.
+// entries and symbolic links leading outside of dst are refused
+func extractLeaseCopyArchive(r io.Reader, prefix string, dst string) error {
+	tr := tar.NewReader(r)
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		rel, valid := leaseCopyEntryPath(hdr.Name, prefix)
+		if !valid {
+			return fmt.Errorf("%w: %q", errLeaseCopyEntry, hdr.Name)
+		}
+
+		// links created by earlier entries are checked against the path they are created at,
+		// following one of them to write later entry might lead outside of destination
+		if link := leaseCopyEntryLink(dst, rel); link != "" {
+			return fmt.Errorf("%w: %q is written through symbolic link %q", errLeaseCopyEntry, hdr.Name, link)
+		}
+
+		target := filepath.Join(dst, filepath.FromSlash(rel))
+		mode := hdr.FileInfo().Mode().Perm()
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, mode|0o700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+
+			if err := extractLeaseCopyFile(tr, target, mode); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			linkPath := filepath.Join(filepath.Dir(rel), filepath.FromSlash(hdr.Linkname))
+			if filepath.IsAbs(hdr.Linkname) || linkPath == ".." || strings.HasPrefix(linkPath, ".."+string(filepath.Separator)) {
+				return fmt.Errorf("%w: %q links to %q", errLeaseCopyEntry, hdr.Name, hdr.Linkname)
+			}
+
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+
+			if err := os.Symlink(hdr.Linkname, target); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// leaseCopyEntryLink returns first existing symbolic link on the path of the entry under dst, entry itself included
+func leaseCopyEntryLink(dst string, rel string) string {
+	curr := dst
+
+	for _, elem := range strings.Split(rel, "/") {
+		if elem == "." {
+			continue
+		}
+
+		curr = filepath.Join(curr, elem)
+
+		info, err := os.Lstat(curr)
+		if err != nil {
+			// path does not exist past this point, it will be created as directories
+			return ""
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			return curr
+		}
+	}
+
+	return ""
+}
+
+func extractLeaseCopyFile(r io.Reader, target string, mode os.FileMode) error {
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(file, r); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// leaseCopyProgress counts bytes written to it and reports them to out until stopped
+type leaseCopyProgress struct {
+	out     io.Writer
+	enabled bool
+	copied  int64
+	donech  chan struct{}
+	stopped chan struct{}
+}
+
+func newLeaseCopyProgress(out io.Writer, enabled bool) *leaseCopyProgress {
+	return &leaseCopyProgress{
+		out:     out,
+		enabled: enabled,
+		donech:  make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+}
+
+func (p *leaseCopyProgress) Write(buf []byte) (int, error) {
+	atomic.AddInt64(&p.copied, int64(len(buf)))
+	return len(buf), nil
+}
+
+func (p *leaseCopyProgress) run(ctx context.Context) {
+	defer close(p.stopped)
+
+	ticker := time.NewTicker(leaseCopyProgressInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.report("")
+		case <-ctx.Done():
+			return
+		case <-p.donech:
+			p.report("\n")
+			return
+		}
+	}
+}
+
+func (p *leaseCopyProgress) stop() {
+	close(p.donech)
+	<-p.stopped
+}
+
+func (p *leaseCopyProgress) report(end string) {
+	if !p.enabled {
+		return
+	}
+
+	_, _ = fmt.Fprintf(p.out, "\rcopied %s%s", formatCopyBytes(atomic.LoadInt64(&p.copied)), end)
+}
+
+func formatCopyBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
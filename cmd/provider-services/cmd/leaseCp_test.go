@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLeaseCopyPath(t *testing.T) {
+	remote, err := parseLeaseCopyPath("web:/var/www")
+	require.NoError(t, err)
+	require.Equal(t, &leaseCopyRemote{service: "web", path: "/var/www"}, remote)
+
+	for _, local := range []string{"./web:/var/www", "site", "/tmp/a:b", "Web:/var"} {
+		remote, err = parseLeaseCopyPath(local)
+		require.NoError(t, err)
+		require.Nil(t, remote, local)
+	}
+
+	_, err = parseLeaseCopyPath("web:var/www")
+	require.Error(t, err)
+}
+
+func TestLeaseCopyUploadDownload(t *testing.T) {
+	src := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "site", "css"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "site", "index.html"), []byte("index"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "site", "css", "main.css"), []byte("css"), 0o600))
+	require.NoError(t, os.Symlink("index.html", filepath.Join(src, "site", "home.html")))
+
+	archive := &bytes.Buffer{}
+	progress := newLeaseCopyProgress(io.Discard, false)
+
+	err := leaseUpload(filepath.Join(src, "site"), &leaseCopyRemote{service: "web", path: "/var/www/html"}, progress,
+		func(dir string, r io.Reader) error {
+			require.Equal(t, "/var/www", dir)
+			_, err := io.Copy(archive, r)
+			return err
+		})
+	require.NoError(t, err)
+	require.Equal(t, int64(archive.Len()), progress.copied)
+
+	// download of /var/www/html gets archive of ./html, as the gateway names entries
+	dst := t.TempDir()
+	err = leaseDownload(&leaseCopyRemote{service: "web", path: "/var/www/html"}, dst, progress,
+		func(w io.Writer) error {
+			return writeLeaseCopyArchive(w, filepath.Join(src, "site"), "./html")
+		})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dst, "html", "css", "main.css"))
+	require.NoError(t, err)
+	require.Equal(t, "css", string(data))
+
+	info, err := os.Stat(filepath.Join(dst, "html", "css", "main.css"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	link, err := os.Readlink(filepath.Join(dst, "html", "home.html"))
+	require.NoError(t, err)
+	require.Equal(t, "index.html", link)
+
+	// file is copied as the destination which does not exist
+	err = leaseDownload(&leaseCopyRemote{service: "web", path: "/var/www/html/index.html"}, filepath.Join(dst, "copy.html"), progress,
+		func(w io.Writer) error {
+			return writeLeaseCopyArchive(w, filepath.Join(src, "site", "index.html"), "./index.html")
+		})
+	require.NoError(t, err)
+
+	data, err = os.ReadFile(filepath.Join(dst, "copy.html"))
+	require.NoError(t, err)
+	require.Equal(t, "index", string(data))
+}
+
+func TestLeaseCopyRefusesEscapingEntries(t *testing.T) {
+	entries := []tar.Header{
+		{Name: "./logs/../../escape", Typeflag: tar.TypeReg},
+		{Name: "./other", Typeflag: tar.TypeReg},
+		{Name: "./logs/link", Typeflag: tar.TypeSymlink, Linkname: "../../etc"},
+		{Name: "./logs/abs", Typeflag: tar.TypeSymlink, Linkname: "/etc"},
+	}
+
+	for _, hdr := range entries {
+		archive := &bytes.Buffer{}
+		tw := tar.NewWriter(archive)
+		require.NoError(t, tw.WriteHeader(&hdr))
+		require.NoError(t, tw.Close())
+
+		err := extractLeaseCopyArchive(archive, "./logs", filepath.Join(t.TempDir(), "logs"))
+		require.ErrorIs(t, err, errLeaseCopyEntry, hdr.Name)
+	}
+}
+
+func TestLeaseCopyRefusesSymlinkedParents(t *testing.T) {
+	archive := &bytes.Buffer{}
+	tw := tar.NewWriter(archive)
+
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "out/a", Typeflag: tar.TypeSymlink, Linkname: "."}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "out/a/b", Typeflag: tar.TypeSymlink, Linkname: ".."}))
+
+	data := []byte("escaped")
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "out/a/b/escaped.txt", Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(data))}))
+	_, err := tw.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	dir := t.TempDir()
+
+	err = extractLeaseCopyArchive(archive, "out", filepath.Join(dir, "out"))
+	require.ErrorIs(t, err, errLeaseCopyEntry)
+
+	require.NoFileExists(t, filepath.Join(dir, "escaped.txt"))
+	require.NoFileExists(t, filepath.Join(dir, "out", "escaped.txt"))
+	require.NoFileExists(t, filepath.Join(dir, "out", "b", "escaped.txt"))
+}
+
+func TestFormatCopyBytes(t *testing.T) {
+	require.Equal(t, "512 B", formatCopyBytes(512))
+	require.Equal(t, "1.5 KiB", formatCopyBytes(1536))
+	require.Equal(t, "2.0 MiB", formatCopyBytes(2<<20))
+}
@@ -3,7 +3,9 @@ package cmd
 import (
 	"crypto/tls"
 	"fmt"
+	"regexp"
 	"sync"
+	"time"
 
 	sdkclient "github.com/cosmos/cosmos-sdk/client"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -16,9 +18,20 @@ import (
 	cutils "github.com/akash-network/node/x/cert/utils"
 
 	aclient "github.com/akash-network/provider/client"
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	gwrest "github.com/akash-network/provider/gateway/rest"
 )
 
+const (
+	flagTimestamps = "timestamps"
+	flagPrevious   = "previous"
+	flagContainer  = "container"
+	flagLimitBytes = "limit-bytes"
+	flagMerge      = "merge"
+	flagGrep       = "grep"
+	flagRegex      = "regex"
+)
+
 func leaseLogsCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:          "lease-logs",
@@ -35,6 +48,14 @@ func leaseLogsCmd() *cobra.Command {
 	cmd.Flags().BoolP(flagFollow, "f", false, "Specify if the logs should be streamed. Defaults to false")
 	cmd.Flags().Int64P(flagTail, "t", -1, "The number of lines from the end of the logs to show. Defaults to -1")
 	cmd.Flags().StringP(flagOutput, "o", outputText, "Output format text|json. Defaults to text")
+	cmd.Flags().String(flagSince, "", "Show logs newer than relative duration like 5m or RFC3339 time")
+	cmd.Flags().Bool(flagTimestamps, false, "Prefix each log line with its timestamp")
+	cmd.Flags().Bool(flagPrevious, false, "Show logs of the previous instance of containers which have restarted")
+	cmd.Flags().String(flagContainer, "", "Container to show logs of, e.g. sidecar-tee. Defaults to service container")
+	cmd.Flags().Int64(flagLimitBytes, 0, "Maximum bytes of logs to show per pod. Defaults to no limit")
+	cmd.Flags().Bool(flagMerge, false, "Merge logs of all pods ordered by their timestamps")
+	cmd.Flags().String(flagGrep, "", "Show only lines containing the string")
+	cmd.Flags().String(flagRegex, "", "Show only lines matching the regular expression")
 
 	return cmd
 }
@@ -98,6 +119,70 @@ func doLeaseLogs(cmd *cobra.Command) error {
 		return errors.Errorf("tail flag supplied with invalid value. must be >= -1")
 	}
 
+	opts := ctypes.LeaseLogsOptions{
+		Follow: follow,
+	}
+
+	if tailLines > -1 {
+		opts.TailLines = &tailLines
+	}
+
+	since, err := cmd.Flags().GetString(flagSince)
+	if err != nil {
+		return err
+	}
+
+	if since != "" {
+		if err = opts.SetSince(since); err != nil {
+			return err
+		}
+	}
+
+	if opts.Timestamps, err = cmd.Flags().GetBool(flagTimestamps); err != nil {
+		return err
+	}
+
+	if opts.Previous, err = cmd.Flags().GetBool(flagPrevious); err != nil {
+		return err
+	}
+
+	if opts.Container, err = cmd.Flags().GetString(flagContainer); err != nil {
+		return err
+	}
+
+	limitBytes, err := cmd.Flags().GetInt64(flagLimitBytes)
+	if err != nil {
+		return err
+	}
+
+	if limitBytes < 0 {
+		return errors.Errorf("limit-bytes flag supplied with invalid value. must be >= 0")
+	}
+
+	if limitBytes > 0 {
+		opts.LimitBytes = &limitBytes
+	}
+
+	sopts := gwrest.LogStreamOptions{}
+
+	if sopts.Merge, err = cmd.Flags().GetBool(flagMerge); err != nil {
+		return err
+	}
+
+	if sopts.Grep, err = cmd.Flags().GetString(flagGrep); err != nil {
+		return err
+	}
+
+	if sopts.Regex, err = cmd.Flags().GetString(flagRegex); err != nil {
+		return err
+	}
+
+	if sopts.Regex != "" {
+		if _, err = regexp.Compile(sopts.Regex); err != nil {
+			return errors.Wrap(err, "regex flag supplied with invalid expression")
+		}
+	}
+
 	type result struct {
 		lid    mtypes.LeaseID
 		error  error
@@ -111,7 +196,7 @@ func doLeaseLogs(cmd *cobra.Command) error {
 		prov, _ := sdk.AccAddressFromBech32(lid.Provider)
 		gclient, err := gwrest.NewClient(ctx, cl, prov, []tls.Certificate{cert})
 		if err == nil {
-			stream.stream, stream.error = gclient.LeaseLogs(ctx, lid, svcs, follow, tailLines)
+			stream.stream, stream.error = gclient.LeaseLogs(ctx, lid, svcs, opts, sopts)
 		} else {
 			stream.error = err
 		}
@@ -129,7 +214,17 @@ func doLeaseLogs(cmd *cobra.Command) error {
 	outch := make(chan logEntry)
 
 	printFn := func(evt logEntry) {
-		fmt.Printf("[%s][%s] %s\n", evt.Lid, evt.Name, evt.Message)
+		source := evt.Name
+		if evt.Stream != "" {
+			source += "][" + evt.Stream
+		}
+
+		if evt.Timestamp != nil {
+			fmt.Printf("[%s][%s] %s %s\n", evt.Lid, source, evt.Timestamp.Format(time.RFC3339Nano), evt.Message)
+			return
+		}
+
+		fmt.Printf("[%s][%s] %s\n", evt.Lid, source, evt.Message)
 	}
 
 	if outputFormat == "json" {
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+
+	sdkclient "github.com/cosmos/cosmos-sdk/client"
+	"github.com/spf13/cobra"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
+	cutils "github.com/akash-network/node/x/cert/utils"
+	dcli "github.com/akash-network/node/x/deployment/client/cli"
+	mcli "github.com/akash-network/node/x/market/client/cli"
+
+	aclient "github.com/akash-network/provider/client"
+	gwrest "github.com/akash-network/provider/gateway/rest"
+)
+
+func leasePauseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "lease-pause",
+		Short:        "scale workloads of the lease to zero keeping its volumes and hostnames",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return doLeaseAction(cmd, gwrest.Client.LeasePause)
+		},
+	}
+
+	addLeaseFlags(cmd)
+
+	return cmd
+}
+
+func leaseResumeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "lease-resume",
+		Short:        "scale workloads of the paused lease back to service counts of its manifest",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return doLeaseAction(cmd, gwrest.Client.LeaseResume)
+		},
+	}
+
+	addLeaseFlags(cmd)
+
+	return cmd
+}
+
+func doLeaseAction(cmd *cobra.Command, action func(gwrest.Client, context.Context, mtypes.LeaseID) error) error {
+	cctx, err := sdkclient.GetClientTxContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+
+	cl, err := aclient.DiscoverQueryClient(ctx, cctx)
+	if err != nil {
+		return err
+	}
+
+	prov, err := providerFromFlags(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	bid, err := mcli.BidIDFromFlags(cmd.Flags(), dcli.WithOwner(cctx.FromAddress))
+	if err != nil {
+		return err
+	}
+
+	cert, err := cutils.LoadAndQueryCertificateForAccount(cmd.Context(), cctx, nil)
+	if err != nil {
+		return markRPCServerError(err)
+	}
+
+	gclient, err := gwrest.NewClient(ctx, cl, prov, []tls.Certificate{cert})
+	if err != nil {
+		return err
+	}
+
+	if err = action(gclient, cmd.Context(), bid.LeaseID()); err != nil {
+		return showErrorToUser(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	sdkclient "github.com/cosmos/cosmos-sdk/client"
+	"github.com/spf13/cobra"
+
+	cutils "github.com/akash-network/node/x/cert/utils"
+	dcli "github.com/akash-network/node/x/deployment/client/cli"
+	mcli "github.com/akash-network/node/x/market/client/cli"
+
+	aclient "github.com/akash-network/provider/client"
+	gwrest "github.com/akash-network/provider/gateway/rest"
+)
+
+const (
+	flagPortForwardAddress = "address"
+)
+
+// leasePortForwardSpec is local port forwarded to the remote port, zero local port is picked by the system
+type leasePortForwardSpec struct {
+	local  uint16
+	remote uint32
+}
+
+func leasePortForwardCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lease-port-forward [local-port:]remote-port...",
+		Short: "forward local ports to ports of the lease service",
+		Long: `forward local ports to ports of the lease service, ports do not need to be exposed by the service.
+each connection is tunneled over the provider gateway until either side closes it:
+  lease-port-forward --service web 5000:5000     forward local port 5000 to port 5000 of the service
+  lease-port-forward --service web 6060          forward local port 6060 to port 6060 of the service
+  lease-port-forward --service web :5432         forward random local port to port 5432 of the service`,
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		RunE:         doLeasePortForward,
+	}
+
+	addServiceFlags(cmd)
+	if err := cmd.MarkFlagRequired(FlagService); err != nil {
+		panic(err.Error())
+	}
+
+	cmd.Flags().Uint(FlagReplicaIndex, 0, "replica index to forward to")
+	cmd.Flags().String(flagPortForwardAddress, "127.0.0.1", "local address to listen on")
+
+	return cmd
+}
+
+func parseLeasePortForwardSpec(arg string) (leasePortForwardSpec, error) {
+	local, remote := arg, arg
+	if idx := strings.IndexByte(arg, ':'); idx != -1 {
+		local, remote = arg[:idx], arg[idx+1:]
+	}
+
+	if local == "" {
+		local = "0"
+	}
+
+	localPort, err := strconv.ParseUint(local, 10, 16)
+	if err != nil {
+		return leasePortForwardSpec{}, fmt.Errorf("invalid local port in %q", arg)
+	}
+
+	remotePort, err := strconv.ParseUint(remote, 10, 16)
+	if err != nil || remotePort == 0 {
+		return leasePortForwardSpec{}, fmt.Errorf("invalid remote port in %q", arg)
+	}
+
+	return leasePortForwardSpec{
+		local:  uint16(localPort),
+		remote: uint32(remotePort),
+	}, nil
+}
+
+func doLeasePortForward(cmd *cobra.Command, args []string) error {
+	specs := make([]leasePortForwardSpec, 0, len(args))
+	for _, arg := range args {
+		spec, err := parseLeasePortForwardSpec(arg)
+		if err != nil {
+			return err
+		}
+
+		specs = append(specs, spec)
+	}
+
+	svcName, err := cmd.Flags().GetString(FlagService)
+	if err != nil {
+		return err
+	}
+
+	podIndex, err := cmd.Flags().GetUint(FlagReplicaIndex)
+	if err != nil {
+		return err
+	}
+
+	address, err := cmd.Flags().GetString(flagPortForwardAddress)
+	if err != nil {
+		return err
+	}
+
+	cctx, err := sdkclient.GetClientTxContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+
+	cl, err := aclient.DiscoverQueryClient(ctx, cctx)
+	if err != nil {
+		return err
+	}
+
+	prov, err := providerFromFlags(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	bidID, err := mcli.BidIDFromFlags(cmd.Flags(), dcli.WithOwner(cctx.FromAddress))
+	if err != nil {
+		return err
+	}
+	lID := bidID.LeaseID()
+
+	cert, err := cutils.LoadAndQueryCertificateForAccount(ctx, cctx, nil)
+	if err != nil {
+		return markRPCServerError(err)
+	}
+
+	gclient, err := gwrest.NewClient(ctx, cl, prov, []tls.Certificate{cert})
+	if err != nil {
+		return err
+	}
+
+	listeners := make([]net.Listener, 0, len(specs))
+	defer func() {
+		for _, listener := range listeners {
+			_ = listener.Close()
+		}
+	}()
+
+	for _, spec := range specs {
+		listener, err := net.Listen("tcp", net.JoinHostPort(address, strconv.FormatUint(uint64(spec.local), 10)))
+		if err != nil {
+			return err
+		}
+
+		listeners = append(listeners, listener)
+
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Forwarding from %s -> %d\n", listener.Addr(), spec.remote)
+	}
+
+	stderr := cmd.ErrOrStderr()
+	wg := &sync.WaitGroup{}
+
+	for idx, listener := range listeners {
+		remote := specs[idx].remote
+
+		wg.Add(1)
+		go func(listener net.Listener) {
+			defer wg.Done()
+
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					// listener is closed once the command is interrupted
+					return
+				}
+
+				_, _ = fmt.Fprintf(stderr, "Handling connection for %d\n", remote)
+
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer func() {
+						_ = conn.Close()
+					}()
+
+					if err := gclient.LeasePortForward(ctx, lID, svcName, podIndex, remote, conn); err != nil && ctx.Err() == nil {
+						_, _ = fmt.Fprintf(stderr, "error forwarding port %d: %s\n", remote, showErrorToUser(err))
+					}
+				}()
+			}
+		}(listener)
+	}
+
+	<-ctx.Done()
+
+	for _, listener := range listeners {
+		_ = listener.Close()
+	}
+
+	wg.Wait()
+
+	return nil
+}
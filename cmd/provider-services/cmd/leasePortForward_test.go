@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLeasePortForwardSpec(t *testing.T) {
+	tests := []struct {
+		arg  string
+		spec leasePortForwardSpec
+	}{
+		{"5000", leasePortForwardSpec{local: 5000, remote: 5000}},
+		{"8080:5000", leasePortForwardSpec{local: 8080, remote: 5000}},
+		{":5432", leasePortForwardSpec{local: 0, remote: 5432}},
+		{"0:5432", leasePortForwardSpec{local: 0, remote: 5432}},
+	}
+
+	for _, tt := range tests {
+		spec, err := parseLeasePortForwardSpec(tt.arg)
+		require.NoError(t, err, tt.arg)
+		require.Equal(t, tt.spec, spec, tt.arg)
+	}
+
+	for _, arg := range []string{"", "0", "5000:", "web:5000", "5000:70000", "-1:5000"} {
+		_, err := parseLeasePortForwardSpec(arg)
+		require.Error(t, err, arg)
+	}
+}
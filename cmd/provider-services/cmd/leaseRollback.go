@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"crypto/tls"
+
+	sdkclient "github.com/cosmos/cosmos-sdk/client"
+	"github.com/spf13/cobra"
+
+	cmdcommon "github.com/akash-network/node/cmd/common"
+	cutils "github.com/akash-network/node/x/cert/utils"
+	dcli "github.com/akash-network/node/x/deployment/client/cli"
+	mcli "github.com/akash-network/node/x/market/client/cli"
+
+	aclient "github.com/akash-network/provider/client"
+	gwrest "github.com/akash-network/provider/gateway/rest"
+)
+
+const (
+	FlagRevision = "revision"
+)
+
+func leaseRevisionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "lease-revisions",
+		Short:        "list manifest revisions lease has been deployed with",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return doLeaseRevisions(cmd)
+		},
+	}
+
+	addLeaseFlags(cmd)
+
+	return cmd
+}
+
+func leaseRollbackCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "lease-rollback",
+		Short:        "roll lease back to manifest revision it has been deployed with",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return doLeaseRollback(cmd)
+		},
+	}
+
+	addLeaseFlags(cmd)
+	cmd.Flags().Uint64(FlagRevision, 0, "revision to roll back to, previous revision if not set")
+
+	return cmd
+}
+
+func doLeaseRevisions(cmd *cobra.Command) error {
+	cctx, err := sdkclient.GetClientTxContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+
+	cl, err := aclient.DiscoverQueryClient(ctx, cctx)
+	if err != nil {
+		return err
+	}
+
+	prov, err := providerFromFlags(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	bid, err := mcli.BidIDFromFlags(cmd.Flags(), dcli.WithOwner(cctx.FromAddress))
+	if err != nil {
+		return err
+	}
+
+	cert, err := cutils.LoadAndQueryCertificateForAccount(cmd.Context(), cctx, nil)
+	if err != nil {
+		return markRPCServerError(err)
+	}
+
+	gclient, err := gwrest.NewClient(ctx, cl, prov, []tls.Certificate{cert})
+	if err != nil {
+		return err
+	}
+
+	result, err := gclient.LeaseRevisions(cmd.Context(), bid.LeaseID())
+	if err != nil {
+		return showErrorToUser(err)
+	}
+
+	return cmdcommon.PrintJSON(cctx, result)
+}
+
+func doLeaseRollback(cmd *cobra.Command) error {
+	cctx, err := sdkclient.GetClientTxContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+
+	revision, err := cmd.Flags().GetUint64(FlagRevision)
+	if err != nil {
+		return err
+	}
+
+	cl, err := aclient.DiscoverQueryClient(ctx, cctx)
+	if err != nil {
+		return err
+	}
+
+	prov, err := providerFromFlags(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	bid, err := mcli.BidIDFromFlags(cmd.Flags(), dcli.WithOwner(cctx.FromAddress))
+	if err != nil {
+		return err
+	}
+
+	cert, err := cutils.LoadAndQueryCertificateForAccount(cmd.Context(), cctx, nil)
+	if err != nil {
+		return markRPCServerError(err)
+	}
+
+	gclient, err := gwrest.NewClient(ctx, cl, prov, []tls.Certificate{cert})
+	if err != nil {
+		return err
+	}
+
+	if err = gclient.LeaseRollback(cmd.Context(), bid.LeaseID(), revision); err != nil {
+		return showErrorToUser(err)
+	}
+
+	return nil
+}
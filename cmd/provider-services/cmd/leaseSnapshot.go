@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"crypto/tls"
+
+	sdkclient "github.com/cosmos/cosmos-sdk/client"
+	"github.com/spf13/cobra"
+
+	cmdcommon "github.com/akash-network/node/cmd/common"
+	cutils "github.com/akash-network/node/x/cert/utils"
+	dcli "github.com/akash-network/node/x/deployment/client/cli"
+	mcli "github.com/akash-network/node/x/market/client/cli"
+
+	aclient "github.com/akash-network/provider/client"
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+	gwrest "github.com/akash-network/provider/gateway/rest"
+)
+
+const (
+	FlagSnapshotName = "name"
+)
+
+func leaseSnapshotCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "lease-snapshot",
+		Short:        "snapshot persistent volumes of the lease",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			name, err := cmd.Flags().GetString(FlagSnapshotName)
+			if err != nil {
+				return err
+			}
+
+			return doLeaseSnapshots(cmd, name)
+		},
+	}
+
+	addLeaseFlags(cmd)
+	cmd.Flags().String(FlagSnapshotName, "", "name of the snapshot, volumes are restored from <name>-<claim> snapshots")
+
+	if err := cmd.MarkFlagRequired(FlagSnapshotName); err != nil {
+		panic(err.Error())
+	}
+
+	return cmd
+}
+
+func leaseSnapshotsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "lease-snapshots",
+		Short:        "list snapshots of persistent volumes of the lease",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return doLeaseSnapshots(cmd, "")
+		},
+	}
+
+	addLeaseFlags(cmd)
+
+	return cmd
+}
+
+// doLeaseSnapshots snapshots volumes of the lease when name is set, lists its snapshots otherwise
+func doLeaseSnapshots(cmd *cobra.Command, name string) error {
+	cctx, err := sdkclient.GetClientTxContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+
+	cl, err := aclient.DiscoverQueryClient(ctx, cctx)
+	if err != nil {
+		return err
+	}
+
+	prov, err := providerFromFlags(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	bid, err := mcli.BidIDFromFlags(cmd.Flags(), dcli.WithOwner(cctx.FromAddress))
+	if err != nil {
+		return err
+	}
+
+	cert, err := cutils.LoadAndQueryCertificateForAccount(cmd.Context(), cctx, nil)
+	if err != nil {
+		return markRPCServerError(err)
+	}
+
+	gclient, err := gwrest.NewClient(ctx, cl, prov, []tls.Certificate{cert})
+	if err != nil {
+		return err
+	}
+
+	var result []ctypes.VolumeSnapshot
+	if name != "" {
+		result, err = gclient.LeaseSnapshot(cmd.Context(), bid.LeaseID(), name)
+	} else {
+		result, err = gclient.LeaseSnapshots(cmd.Context(), bid.LeaseID())
+	}
+
+	if err != nil {
+		return showErrorToUser(err)
+	}
+
+	return cmdcommon.PrintJSON(cctx, result)
+}
@@ -41,12 +41,25 @@ func NewRootCmd() *cobra.Command {
 
 	cmd.AddCommand(ManifestCmds()...)
 	cmd.AddCommand(statusCmd())
+	cmd.AddCommand(ValidateCmd())
+	cmd.AddCommand(bidHistoryCmd())
+	cmd.AddCommand(earningsCmd())
+	cmd.AddCommand(shellSessionsCmd())
 	cmd.AddCommand(leaseStatusCmd())
 	cmd.AddCommand(leaseEventsCmd())
 	cmd.AddCommand(leaseLogsCmd())
+	cmd.AddCommand(leaseAttestationCmd())
+	cmd.AddCommand(leaseRevisionsCmd())
+	cmd.AddCommand(leaseRollbackCmd())
+	cmd.AddCommand(leasePauseCmd())
+	cmd.AddCommand(leaseResumeCmd())
+	cmd.AddCommand(leaseSnapshotCmd())
+	cmd.AddCommand(leaseSnapshotsCmd())
 	cmd.AddCommand(serviceStatusCmd())
 	cmd.AddCommand(RunCmd())
 	cmd.AddCommand(LeaseShellCmd())
+	cmd.AddCommand(leaseCopyCmd())
+	cmd.AddCommand(leasePortForwardCmd())
 	cmd.AddCommand(hostname.Cmd())
 	cmd.AddCommand(ip.Cmd())
 	cmd.AddCommand(AuthServerCmd())
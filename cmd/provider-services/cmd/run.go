@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -20,6 +22,7 @@ import (
 	"github.com/tendermint/tendermint/libs/log"
 	tpubsub "github.com/troian/pubsub"
 	"golang.org/x/sync/errgroup"
+	"gopkg.in/yaml.v3"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/cosmos/cosmos-sdk/client/flags"
@@ -37,6 +40,10 @@ import (
 
 	"github.com/akash-network/provider"
 	"github.com/akash-network/provider/bidengine"
+	"github.com/akash-network/provider/bidengine/journal"
+	"github.com/akash-network/provider/bidengine/oracle"
+	"github.com/akash-network/provider/bidengine/policy"
+	"github.com/akash-network/provider/broadcaster"
 	"github.com/akash-network/provider/client"
 	"github.com/akash-network/provider/cluster"
 	"github.com/akash-network/provider/cluster/kube"
@@ -45,12 +52,15 @@ import (
 	kubehostname "github.com/akash-network/provider/cluster/kube/operators/clients/hostname"
 	kubeinventory "github.com/akash-network/provider/cluster/kube/operators/clients/inventory"
 	kubeip "github.com/akash-network/provider/cluster/kube/operators/clients/ip"
+	clustertypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	cip "github.com/akash-network/provider/cluster/types/v1beta3/clients/ip"
 	clfromctx "github.com/akash-network/provider/cluster/types/v1beta3/fromctx"
 	providerflags "github.com/akash-network/provider/cmd/provider-services/cmd/flags"
 	cmdutil "github.com/akash-network/provider/cmd/provider-services/cmd/util"
 	gwgrpc "github.com/akash-network/provider/gateway/grpc"
 	gwrest "github.com/akash-network/provider/gateway/rest"
+	"github.com/akash-network/provider/gateway/shellaudit"
+	"github.com/akash-network/provider/ledger"
 	"github.com/akash-network/provider/operator/waiter"
 	akashclientset "github.com/akash-network/provider/pkg/client/clientset/versioned"
 	"github.com/akash-network/provider/session"
@@ -68,11 +78,40 @@ const (
 	FlagBidPriceCPUScale                 = "bid-price-cpu-scale"
 	FlagBidPriceMemoryScale              = "bid-price-memory-scale"
 	FlagBidPriceStorageScale             = "bid-price-storage-scale"
+	FlagBidPriceGPUScale                 = "bid-price-gpu-scale"
 	FlagBidPriceEndpointScale            = "bid-price-endpoint-scale"
 	FlagBidPriceScriptPath               = "bid-price-script-path"
 	FlagBidPriceScriptProcessLimit       = "bid-price-script-process-limit"
 	FlagBidPriceScriptTimeout            = "bid-price-script-process-timeout"
+	FlagBidPriceWebhookURL               = "bid-price-webhook-url"
+	FlagBidPriceWebhookTimeout           = "bid-price-webhook-timeout"
+	FlagBidPriceWebhookRetries           = "bid-price-webhook-retries"
+	FlagBidPriceWebhookRetryBackoff      = "bid-price-webhook-retry-backoff"
+	FlagBidPriceWebhookCacheTTL          = "bid-price-webhook-cache-ttl"
+	FlagBidPriceWebhookBreakerThreshold  = "bid-price-webhook-breaker-threshold"
+	FlagBidPriceWebhookBreakerCooldown   = "bid-price-webhook-breaker-cooldown"
+	FlagBidPriceWebhookFallback          = "bid-price-webhook-fallback-strategy"
+	FlagBidPriceDenom                    = "bid-price-denom"
+	FlagBidPriceDenomScales              = "bid-price-denom-scales"
+	FlagBidPriceOracle                   = "bid-price-oracle"
+	FlagBidPriceOracleTimeout            = "bid-price-oracle-timeout"
+	FlagBidPriceOracleDenoms             = "bid-price-oracle-denoms"
+	FlagBidPriceOracleCacheTTL           = "bid-price-oracle-cache-ttl"
+	FlagBidPriceSurge                    = "bid-price-surge"
+	FlagBidPriceSurgeCurve               = "bid-price-surge-curve"
+	FlagBidPriceSurgeTarget              = "bid-price-surge-target"
+	FlagBidPriceSurgeFloor               = "bid-price-surge-floor"
+	FlagBidPriceSurgeCeiling             = "bid-price-surge-ceiling"
+	FlagBidPriceSurgeWindow              = "bid-price-surge-window"
 	FlagBidDeposit                       = "bid-deposit"
+	FlagBidPolicy                        = "bid-policy"
+	FlagBidJournal                       = "bid-journal"
+	FlagBidJournalRetention              = "bid-journal-retention"
+	FlagEarningsLedger                   = "earnings-ledger"
+	FlagShellAuditLog                    = "shell-audit-log"
+	FlagShellAuditRecordings             = "shell-audit-recordings"
+	FlagShellAuditRecordingMaxBytes      = "shell-audit-recording-max-bytes"
+	FlagShellAuditRetention              = "shell-audit-retention"
 	FlagClusterPublicHostname            = "cluster-public-hostname"
 	FlagClusterNodePortQuantity          = "cluster-node-port-quantity"
 	FlagClusterWaitReadyDuration         = "cluster-wait-ready-duration"
@@ -82,6 +121,8 @@ const (
 	FlagDeploymentIngressDomain          = "deployment-ingress-domain"
 	FlagDeploymentIngressExposeLBHosts   = "deployment-ingress-expose-lb-hosts"
 	FlagDeploymentNetworkPoliciesEnabled = "deployment-network-policies-enabled"
+	FlagDeploymentStagedUpdate           = "deployment-staged-update"
+	FlagDeploymentRolloutTimeout         = "deployment-rollout-timeout"
 	FlagDockerImagePullSecretsName       = "docker-image-pull-secrets-name" // nolint: gosec
 	FlagOvercommitPercentMemory          = "overcommit-pct-mem"
 	FlagOvercommitPercentCPU             = "overcommit-pct-cpu"
@@ -89,18 +130,30 @@ const (
 	FlagDeploymentBlockedHostnames       = "deployment-blocked-hostnames"
 	FlagAuthPem                          = "auth-pem"
 	FlagDeploymentRuntimeClass           = "deployment-runtime-class"
+	FlagVolumeSnapshotClass              = "volume-snapshot-class"
+	FlagDeploymentLogSplitStreams        = "deployment-log-split-streams"
 	FlagBidTimeout                       = "bid-timeout"
 	FlagManifestTimeout                  = "manifest-timeout"
 	FlagMetricsListener                  = "metrics-listener"
 	FlagWithdrawalPeriod                 = "withdrawal-period"
 	FlagLeaseFundsMonitorInterval        = "lease-funds-monitor-interval"
+	FlagWithdrawalBatchSize              = "withdrawal-batch-size"
+	FlagWithdrawalBatchWindow            = "withdrawal-batch-window"
+	FlagLeaseFundsWarningThreshold       = "lease-funds-warning-threshold"
+	FlagLeaseFundsWarningBlocks          = "lease-funds-warning-blocks"
+	FlagLeaseFundsWarningWebhook         = "lease-funds-warning-webhook"
 	FlagMinimumBalance                   = "minimum-balance"
+	FlagWalletCheckInterval              = "wallet-check-interval"
 	FlagProviderConfig                   = "provider-config"
 	FlagCachedResultMaxAge               = "cached-result-max-age"
 	FlagRPCQueryTimeout                  = "rpc-query-timeout"
 	FlagBidPriceIPScale                  = "bid-price-ip-scale"
+	FlagBidPriceSnapshotScale            = "bid-price-snapshot-scale"
 	FlagEnableIPOperator                 = "ip-operator"
 	FlagTxBroadcastTimeout               = "tx-broadcast-timeout"
+	FlagTxEstimateGas                    = "tx-estimate-gas"
+	FlagTxMaxRetries                     = "tx-max-retries"
+	FlagTxRetryBackoff                   = "tx-retry-backoff"
 	FlagMonitorMaxRetries                = "monitor-max-retries"
 	FlagMonitorRetryPeriod               = "monitor-retry-period"
 	FlagMonitorRetryPeriodJitter         = "monitor-retry-period-jitter"
@@ -135,6 +188,16 @@ func RunCmd() *cobra.Command {
 				return errors.Errorf(`flag "%s" value must be > "%s"`, FlagWithdrawalPeriod, FlagLeaseFundsMonitorInterval) // nolint: err113
 			}
 
+			if viper.GetInt64(FlagLeaseFundsWarningBlocks) < 0 || viper.GetDuration(FlagLeaseFundsWarningThreshold) < 0 {
+				return errors.Errorf(`flags "%s" and "%s" cannot be negative`, FlagLeaseFundsWarningBlocks, FlagLeaseFundsWarningThreshold) // nolint: err113
+			}
+
+			if webhook := viper.GetString(FlagLeaseFundsWarningWebhook); webhook != "" {
+				if u, err := url.Parse(webhook); err != nil || !u.IsAbs() || (u.Scheme != "http" && u.Scheme != "https") {
+					return errors.Errorf(`flag "%s" must be absolute http(s) url`, FlagLeaseFundsWarningWebhook) // nolint: err113
+				}
+			}
+
 			if viper.GetDuration(FlagMonitorRetryPeriod) < 4*time.Second {
 				return errors.Errorf(`flag "%s" value must be > "%s"`, FlagMonitorRetryPeriod, 4*time.Second) // nolint: err113
 			}
@@ -238,11 +301,16 @@ func RunCmd() *cobra.Command {
 		panic(err)
 	}
 
-	cmd.Flags().String(FlagBidPriceStorageScale, "0", "storage pricing scale in uakt per megabyte")
+	cmd.Flags().String(FlagBidPriceStorageScale, "0", "storage pricing scale in uakt per megabyte, comma separated <class>=<price> pairs")
 	if err := viper.BindPFlag(FlagBidPriceStorageScale, cmd.Flags().Lookup(FlagBidPriceStorageScale)); err != nil {
 		panic(err)
 	}
 
+	cmd.Flags().String(FlagBidPriceGPUScale, "", "gpu pricing scale in uakt per gpu unit, comma separated <vendor>/<model>[/ram/<size>][/interface/<pcie|sxm>]=<price> pairs. model * matches any model of the vendor, key * matches any gpu")
+	if err := viper.BindPFlag(FlagBidPriceGPUScale, cmd.Flags().Lookup(FlagBidPriceGPUScale)); err != nil {
+		panic(err)
+	}
+
 	cmd.Flags().String(FlagBidPriceEndpointScale, "0", "endpoint pricing scale in uakt")
 	if err := viper.BindPFlag(FlagBidPriceEndpointScale, cmd.Flags().Lookup(FlagBidPriceEndpointScale)); err != nil {
 		panic(err)
@@ -253,6 +321,12 @@ func RunCmd() *cobra.Command {
 		panic(err)
 	}
 
+	cmd.Flags().String(FlagBidPriceSnapshotScale, "", "volume snapshot pricing scale in uakt per megabyte of persistent storage. "+
+		"charged on every persistent volume of the order as lease price is fixed at bid time, requires --"+FlagVolumeSnapshotClass)
+	if err := viper.BindPFlag(FlagBidPriceSnapshotScale, cmd.Flags().Lookup(FlagBidPriceSnapshotScale)); err != nil {
+		panic(err)
+	}
+
 	cmd.Flags().String(FlagBidPriceScriptPath, "", "path to script to run for computing bid price")
 	if err := viper.BindPFlag(FlagBidPriceScriptPath, cmd.Flags().Lookup(FlagBidPriceScriptPath)); err != nil {
 		panic(err)
@@ -268,11 +342,153 @@ func RunCmd() *cobra.Command {
 		panic(err)
 	}
 
+	cmd.Flags().String(FlagBidPriceWebhookURL, "", "url of the http service to POST bid pricing requests to")
+	if err := viper.BindPFlag(FlagBidPriceWebhookURL, cmd.Flags().Lookup(FlagBidPriceWebhookURL)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().Duration(FlagBidPriceWebhookTimeout, time.Second*5, "timeout of the single bid pricing webhook request")
+	if err := viper.BindPFlag(FlagBidPriceWebhookTimeout, cmd.Flags().Lookup(FlagBidPriceWebhookTimeout)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().Uint(FlagBidPriceWebhookRetries, 2, "number of retries of the failed bid pricing webhook request")
+	if err := viper.BindPFlag(FlagBidPriceWebhookRetries, cmd.Flags().Lookup(FlagBidPriceWebhookRetries)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().Duration(FlagBidPriceWebhookRetryBackoff, time.Millisecond*200, "initial delay between bid pricing webhook retries")
+	if err := viper.BindPFlag(FlagBidPriceWebhookRetryBackoff, cmd.Flags().Lookup(FlagBidPriceWebhookRetryBackoff)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().Duration(FlagBidPriceWebhookCacheTTL, time.Minute, "duration bid pricing webhook response is reused for the same request. 0 disables cache")
+	if err := viper.BindPFlag(FlagBidPriceWebhookCacheTTL, cmd.Flags().Lookup(FlagBidPriceWebhookCacheTTL)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().Uint(FlagBidPriceWebhookBreakerThreshold, 5, "consecutive bid pricing webhook failures to stop calling it for cooldown period. 0 disables circuit breaker")
+	if err := viper.BindPFlag(FlagBidPriceWebhookBreakerThreshold, cmd.Flags().Lookup(FlagBidPriceWebhookBreakerThreshold)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().Duration(FlagBidPriceWebhookBreakerCooldown, time.Second*30, "duration bid pricing webhook is not called once circuit breaker opens")
+	if err := viper.BindPFlag(FlagBidPriceWebhookBreakerCooldown, cmd.Flags().Lookup(FlagBidPriceWebhookBreakerCooldown)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().String(FlagBidPriceWebhookFallback, "", fmt.Sprintf("pricing strategy used when bid pricing webhook is not available. Allowed: %v", []string{bidPricingStrategyScale, bidPricingStrategyRandomRange, bidPricingStrategyShellScript}))
+	if err := viper.BindPFlag(FlagBidPriceWebhookFallback, cmd.Flags().Lookup(FlagBidPriceWebhookFallback)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().String(FlagBidPriceDenom, "uakt", "denomination bid pricing strategy prices in. orders in other denominations are priced by --bid-price-denom-scales tables or converted with --bid-price-oracle rates")
+	if err := viper.BindPFlag(FlagBidPriceDenom, cmd.Flags().Lookup(FlagBidPriceDenom)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().String(FlagBidPriceDenomScales, "", "path to the yaml file with scale pricing tables of additional denominations")
+	if err := viper.BindPFlag(FlagBidPriceDenomScales, cmd.Flags().Lookup(FlagBidPriceDenomScales)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().String(FlagBidPriceOracle, "", "path to the yaml file with exchange rates or url of the http service to convert bid price into denomination of the order with")
+	if err := viper.BindPFlag(FlagBidPriceOracle, cmd.Flags().Lookup(FlagBidPriceOracle)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().StringSlice(FlagBidPriceOracleDenoms, nil, "denominations http price oracle is asked to convert bid price into. required when --bid-price-oracle is url")
+	if err := viper.BindPFlag(FlagBidPriceOracleDenoms, cmd.Flags().Lookup(FlagBidPriceOracleDenoms)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().Duration(FlagBidPriceOracleTimeout, time.Second*5, "timeout of the single price oracle request")
+	if err := viper.BindPFlag(FlagBidPriceOracleTimeout, cmd.Flags().Lookup(FlagBidPriceOracleTimeout)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().Duration(FlagBidPriceOracleCacheTTL, time.Minute, "duration price oracle rate is reused for. 0 disables cache")
+	if err := viper.BindPFlag(FlagBidPriceOracleCacheTTL, cmd.Flags().Lookup(FlagBidPriceOracleCacheTTL)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().Bool(FlagBidPriceSurge, false, "adjust price of the bid pricing strategy by cluster utilization")
+	if err := viper.BindPFlag(FlagBidPriceSurge, cmd.Flags().Lookup(FlagBidPriceSurge)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().String(FlagBidPriceSurgeCurve, bidengine.SurgeCurveLinear, fmt.Sprintf("shape of the surge price change. Allowed: %v", []string{bidengine.SurgeCurveLinear, bidengine.SurgeCurveQuadratic, bidengine.SurgeCurveExponential}))
+	if err := viper.BindPFlag(FlagBidPriceSurgeCurve, cmd.Flags().Lookup(FlagBidPriceSurgeCurve)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().Float64(FlagBidPriceSurgeTarget, 0.5, "cluster utilization bid pricing strategy price is used as is at")
+	if err := viper.BindPFlag(FlagBidPriceSurgeTarget, cmd.Flags().Lookup(FlagBidPriceSurgeTarget)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().Float64(FlagBidPriceSurgeFloor, 0.8, "price multiplier of the idle cluster")
+	if err := viper.BindPFlag(FlagBidPriceSurgeFloor, cmd.Flags().Lookup(FlagBidPriceSurgeFloor)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().Float64(FlagBidPriceSurgeCeiling, 2, "price multiplier of the fully utilized cluster")
+	if err := viper.BindPFlag(FlagBidPriceSurgeCeiling, cmd.Flags().Lookup(FlagBidPriceSurgeCeiling)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().Duration(FlagBidPriceSurgeWindow, 10*time.Minute, "time window cluster utilization is smoothed over. 0 disables smoothing")
+	if err := viper.BindPFlag(FlagBidPriceSurgeWindow, cmd.Flags().Lookup(FlagBidPriceSurgeWindow)); err != nil {
+		panic(err)
+	}
+
 	cmd.Flags().String(FlagBidDeposit, cfg.BidDeposit.String(), "Bid deposit amount")
 	if err := viper.BindPFlag(FlagBidDeposit, cmd.Flags().Lookup(FlagBidDeposit)); err != nil {
 		panic(err)
 	}
 
+	cmd.Flags().String(FlagBidPolicy, "", "path to the yaml file with bid policy rules. file is reloaded on change")
+	if err := viper.BindPFlag(FlagBidPolicy, cmd.Flags().Lookup(FlagBidPolicy)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().String(FlagBidJournal, "", "path to the bid journal file. defaults to provider/bid-journal.db in the home directory")
+	if err := viper.BindPFlag(FlagBidJournal, cmd.Flags().Lookup(FlagBidJournal)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().Duration(FlagBidJournalRetention, 7*24*time.Hour, "how long closed orders are kept in the bid journal")
+	if err := viper.BindPFlag(FlagBidJournalRetention, cmd.Flags().Lookup(FlagBidJournalRetention)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().String(FlagEarningsLedger, "", "path to the earnings ledger file. defaults to provider/earnings.db in the home directory")
+	if err := viper.BindPFlag(FlagEarningsLedger, cmd.Flags().Lookup(FlagEarningsLedger)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().String(FlagShellAuditLog, "", "path to the audit log of lease shell sessions. defaults to provider/shell-audit.db in the home directory")
+	if err := viper.BindPFlag(FlagShellAuditLog, cmd.Flags().Lookup(FlagShellAuditLog)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().String(FlagShellAuditRecordings, "", "where asciicast recordings of lease shell sessions are stored, "+
+		"either local directory or s3://bucket/prefix?endpoint=<url>&region=<region> with credentials in AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY. "+
+		"sessions are not recorded if not set")
+	if err := viper.BindPFlag(FlagShellAuditRecordings, cmd.Flags().Lookup(FlagShellAuditRecordings)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().Int64(FlagShellAuditRecordingMaxBytes, shellaudit.DefaultMaxRecordingBytes, "size lease shell session recordings are truncated at")
+	if err := viper.BindPFlag(FlagShellAuditRecordingMaxBytes, cmd.Flags().Lookup(FlagShellAuditRecordingMaxBytes)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().Duration(FlagShellAuditRetention, 90*24*time.Hour, "how long lease shell sessions and their recordings are kept. 0 keeps them forever")
+	if err := viper.BindPFlag(FlagShellAuditRetention, cmd.Flags().Lookup(FlagShellAuditRetention)); err != nil {
+		panic(err)
+	}
+
 	cmd.Flags().String(FlagClusterPublicHostname, "", "The public IP of the Kubernetes cluster")
 	if err := viper.BindPFlag(FlagClusterPublicHostname, cmd.Flags().Lookup(FlagClusterPublicHostname)); err != nil {
 		panic(err)
@@ -318,6 +534,16 @@ func RunCmd() *cobra.Command {
 		panic(err)
 	}
 
+	cmd.Flags().Bool(FlagDeploymentStagedUpdate, false, "Wait for updated deployments to roll out and roll the lease back to previous revision if they do not")
+	if err := viper.BindPFlag(FlagDeploymentStagedUpdate, cmd.Flags().Lookup(FlagDeploymentStagedUpdate)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().Duration(FlagDeploymentRolloutTimeout, 5*time.Minute, "Time updated deployment is given to roll out when staged update is enabled")
+	if err := viper.BindPFlag(FlagDeploymentRolloutTimeout, cmd.Flags().Lookup(FlagDeploymentRolloutTimeout)); err != nil {
+		panic(err)
+	}
+
 	cmd.Flags().String(FlagDockerImagePullSecretsName, "", "Name of the local image pull secret configured with kubectl")
 	if err := viper.BindPFlag(FlagDockerImagePullSecretsName, cmd.Flags().Lookup(FlagDockerImagePullSecretsName)); err != nil {
 		panic(err)
@@ -354,6 +580,16 @@ func RunCmd() *cobra.Command {
 		panic(err)
 	}
 
+	cmd.Flags().String(FlagVolumeSnapshotClass, "", "CSI volume snapshot class lease volumes are snapshotted with, snapshots are disabled when not set")
+	if err := viper.BindPFlag(FlagVolumeSnapshotClass, cmd.Flags().Lookup(FlagVolumeSnapshotClass)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().Bool(FlagDeploymentLogSplitStreams, false, "stream stdout and stderr of lease containers separately, requires PodLogsQuerySplitStreams feature gate in the cluster")
+	if err := viper.BindPFlag(FlagDeploymentLogSplitStreams, cmd.Flags().Lookup(FlagDeploymentLogSplitStreams)); err != nil {
+		panic(err)
+	}
+
 	cmd.Flags().Duration(FlagBidTimeout, 5*time.Minute, "time after which bids are cancelled if no lease is created")
 	if err := viper.BindPFlag(FlagBidTimeout, cmd.Flags().Lookup(FlagBidTimeout)); err != nil {
 		panic(err)
@@ -379,11 +615,41 @@ func RunCmd() *cobra.Command {
 		panic(err)
 	}
 
-	cmd.Flags().Uint64(FlagMinimumBalance, mparams.DefaultBidMinDeposit.Amount.Mul(sdk.NewIntFromUint64(2)).Uint64(), "minimum account balance at which withdrawal is started")
+	cmd.Flags().Uint(FlagWithdrawalBatchSize, 20, "max number of leases withdrawn from in single transaction")
+	if err := viper.BindPFlag(FlagWithdrawalBatchSize, cmd.Flags().Lookup(FlagWithdrawalBatchSize)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().Duration(FlagWithdrawalBatchWindow, time.Second*30, "time lease withdrawal waits for other leases to be withdrawn from in the same transaction. 0 sends withdrawals right away")
+	if err := viper.BindPFlag(FlagWithdrawalBatchWindow, cmd.Flags().Lookup(FlagWithdrawalBatchWindow)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().Duration(FlagLeaseFundsWarningThreshold, 0, "lease runway tenant is warned about deployment escrow running out of funds at. 0 disables")
+	if err := viper.BindPFlag(FlagLeaseFundsWarningThreshold, cmd.Flags().Lookup(FlagLeaseFundsWarningThreshold)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().Int64(FlagLeaseFundsWarningBlocks, 0, "lease runway in blocks tenant is warned about deployment escrow running out of funds at. 0 disables")
+	if err := viper.BindPFlag(FlagLeaseFundsWarningBlocks, cmd.Flags().Lookup(FlagLeaseFundsWarningBlocks)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().String(FlagLeaseFundsWarningWebhook, "", "url to POST lease low balance warnings to")
+	if err := viper.BindPFlag(FlagLeaseFundsWarningWebhook, cmd.Flags().Lookup(FlagLeaseFundsWarningWebhook)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().Uint64(FlagMinimumBalance, mparams.DefaultBidMinDeposit.Amount.Mul(sdk.NewIntFromUint64(2)).Uint64(), "minimum provider account balance in bid deposit denomination below which bidding is paused. bidding is paused also when balance can't cover bid deposit")
 	if err := viper.BindPFlag(FlagMinimumBalance, cmd.Flags().Lookup(FlagMinimumBalance)); err != nil {
 		panic(err)
 	}
 
+	cmd.Flags().Duration(FlagWalletCheckInterval, time.Minute, "how often provider account balance is checked")
+	if err := viper.BindPFlag(FlagWalletCheckInterval, cmd.Flags().Lookup(FlagWalletCheckInterval)); err != nil {
+		panic(err)
+	}
+
 	cmd.Flags().String(FlagProviderConfig, "", "provider configuration file path")
 	if err := viper.BindPFlag(FlagProviderConfig, cmd.Flags().Lookup(FlagProviderConfig)); err != nil {
 		panic(err)
@@ -409,6 +675,21 @@ func RunCmd() *cobra.Command {
 		panic(err)
 	}
 
+	cmd.Flags().Bool(FlagTxEstimateGas, true, "estimate gas of transactions by simulating them. has no effect when --gas is set")
+	if err := viper.BindPFlag(FlagTxEstimateGas, cmd.Flags().Lookup(FlagTxEstimateGas)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().Uint(FlagTxMaxRetries, 5, "max count of transaction retries on account sequence mismatch or full mempool. defaults to 5")
+	if err := viper.BindPFlag(FlagTxMaxRetries, cmd.Flags().Lookup(FlagTxMaxRetries)); err != nil {
+		panic(err)
+	}
+
+	cmd.Flags().Duration(FlagTxRetryBackoff, time.Second, "delay before the first transaction retry, doubled with every next one. defaults to 1s")
+	if err := viper.BindPFlag(FlagTxRetryBackoff, cmd.Flags().Lookup(FlagTxRetryBackoff)); err != nil {
+		panic(err)
+	}
+
 	cmd.Flags().Uint(FlagMonitorMaxRetries, 40, "max count of status retries before closing the lease. defaults to 40")
 	if err := viper.BindPFlag(FlagMonitorMaxRetries, cmd.Flags().Lookup(FlagMonitorMaxRetries)); err != nil {
 		panic(err)
@@ -449,12 +730,14 @@ const (
 	bidPricingStrategyScale       = "scale"
 	bidPricingStrategyRandomRange = "randomRange"
 	bidPricingStrategyShellScript = "shellScript"
+	bidPricingStrategyWebhook     = "webhook"
 )
 
 var allowedBidPricingStrategies = [...]string{
 	bidPricingStrategyScale,
 	bidPricingStrategyRandomRange,
 	bidPricingStrategyShellScript,
+	bidPricingStrategyWebhook,
 }
 
 var errNoSuchBidPricingStrategy = fmt.Errorf("No such bid pricing strategy. Allowed: %v", allowedBidPricingStrategies)
@@ -474,47 +757,171 @@ func strToBidPriceScale(val string) (decimal.Decimal, error) {
 	return v, nil
 }
 
-func createBidPricingStrategy(strategy string) (bidengine.BidPricingStrategy, error) {
-	if strategy == bidPricingStrategyScale {
-		cpuScale, err := strToBidPriceScale(viper.GetString(FlagBidPriceCPUScale))
-		if err != nil {
-			return nil, err
+// bidPriceScales holds scale pricing settings in the format of the --bid-price-*-scale flags
+type bidPriceScales struct {
+	CPU      string `yaml:"cpu"`
+	Memory   string `yaml:"memory"`
+	Storage  string `yaml:"storage"`
+	GPU      string `yaml:"gpu"`
+	Endpoint string `yaml:"endpoint"`
+	IP       string `yaml:"ip"`
+	Snapshot string `yaml:"snapshot"`
+}
+
+// bidPriceDenomScales is the file with scale pricing tables of additional denominations
+type bidPriceDenomScales struct {
+	Denoms map[string]bidPriceScales `yaml:"denoms"`
+}
+
+func strToBidPriceScaleOrZero(val string) (decimal.Decimal, error) {
+	if val == "" {
+		return decimal.Zero, nil
+	}
+
+	return strToBidPriceScale(val)
+}
+
+func makeScalePricing(scales bidPriceScales) (bidengine.BidPricingStrategy, error) {
+	cpuScale, err := strToBidPriceScaleOrZero(scales.CPU)
+	if err != nil {
+		return nil, err
+	}
+	memoryScale, err := strToBidPriceScaleOrZero(scales.Memory)
+	if err != nil {
+		return nil, err
+	}
+	storageScale := make(bidengine.Storage)
+
+	storageScales := strings.Split(scales.Storage, ",")
+	for _, scalePair := range storageScales {
+		vals := strings.Split(scalePair, "=")
+
+		name := sdl.StorageEphemeral
+		scaleVal := vals[0]
+
+		if len(vals) == 2 {
+			name = vals[0]
+			scaleVal = vals[1]
 		}
-		memoryScale, err := strToBidPriceScale(viper.GetString(FlagBidPriceMemoryScale))
+
+		storageScale[name], err = strToBidPriceScaleOrZero(scaleVal)
 		if err != nil {
 			return nil, err
 		}
-		storageScale := make(bidengine.Storage)
+	}
 
-		storageScales := strings.Split(viper.GetString(FlagBidPriceStorageScale), ",")
-		for _, scalePair := range storageScales {
-			vals := strings.Split(scalePair, "=")
+	gpuScale := make(bidengine.GPU)
 
-			name := sdl.StorageEphemeral
-			scaleVal := vals[0]
+	if val := scales.GPU; val != "" {
+		for _, scalePair := range strings.Split(val, ",") {
+			vals := strings.Split(scalePair, "=")
+			if len(vals) != 2 {
+				return nil, fmt.Errorf("%w: %s", errInvalidValueForBidPrice, scalePair)
+			}
 
-			if len(vals) == 2 {
-				name = vals[0]
-				scaleVal = vals[1]
+			key, err := bidengine.ParseGPUScaleKey(vals[0])
+			if err != nil {
+				return nil, err
 			}
 
-			storageScale[name], err = strToBidPriceScale(scaleVal)
+			gpuScale[key], err = strToBidPriceScale(vals[1])
 			if err != nil {
 				return nil, err
 			}
 		}
+	}
+
+	endpointScale, err := strToBidPriceScaleOrZero(scales.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	ipScale, err := strToBidPriceScaleOrZero(scales.IP)
+	if err != nil {
+		return nil, err
+	}
 
-		endpointScale, err := strToBidPriceScale(viper.GetString(FlagBidPriceEndpointScale))
+	snapshotScale, err := strToBidPriceScaleOrZero(scales.Snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	// without snapshot class tenants can't take snapshots, so they must not be charged for them
+	if !snapshotScale.IsZero() && viper.GetString(FlagVolumeSnapshotClass) == "" {
+		return nil, fmt.Errorf("%w: snapshot pricing scale requires --%s", errInvalidConfig, FlagVolumeSnapshotClass)
+	}
+
+	return bidengine.MakeScalePricing(cpuScale, memoryScale, gpuScale, storageScale, endpointScale, ipScale, snapshotScale)
+}
+
+// createDenomPricing makes base pricing strategy bid on orders in denominations of scale tables file
+// and ones price oracle has rates for. base strategy is returned as is if neither is configured
+func createDenomPricing(base bidengine.BidPricingStrategy) (bidengine.BidPricingStrategy, error) {
+	scalesFile := viper.GetString(FlagBidPriceDenomScales)
+	oracleSource := viper.GetString(FlagBidPriceOracle)
+
+	if scalesFile == "" && oracleSource == "" {
+		return base, nil
+	}
+
+	cfg := bidengine.DenomPricingConfig{
+		Denom:      viper.GetString(FlagBidPriceDenom),
+		Base:       base,
+		Strategies: make(map[string]bidengine.BidPricingStrategy),
+	}
+
+	if scalesFile != "" {
+		data, err := os.ReadFile(scalesFile)
 		if err != nil {
 			return nil, err
 		}
 
-		ipScale, err := strToBidPriceScale(viper.GetString(FlagBidPriceIPScale))
+		scales := bidPriceDenomScales{}
+
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+
+		if err = dec.Decode(&scales); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%s: %w", scalesFile, err)
+		}
+
+		for denom, denomScales := range scales.Denoms {
+			if cfg.Strategies[denom], err = makeScalePricing(denomScales); err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", scalesFile, denom, err)
+			}
+		}
+	}
+
+	if strings.HasPrefix(oracleSource, "http://") || strings.HasPrefix(oracleSource, "https://") {
+		httpOracle, err := oracle.NewHTTP(oracleSource, viper.GetStringSlice(FlagBidPriceOracleDenoms), viper.GetDuration(FlagBidPriceOracleTimeout), viper.GetDuration(FlagBidPriceOracleCacheTTL))
+		if err != nil {
+			return nil, err
+		}
+
+		cfg.Oracle = httpOracle
+	} else if oracleSource != "" {
+		staticOracle, err := oracle.ReadFile(oracleSource)
 		if err != nil {
 			return nil, err
 		}
 
-		return bidengine.MakeScalePricing(cpuScale, memoryScale, storageScale, endpointScale, ipScale)
+		cfg.Oracle = staticOracle
+	}
+
+	return bidengine.MakeDenomPricing(cfg)
+}
+
+func createBidPricingStrategy(strategy string) (bidengine.BidPricingStrategy, error) {
+	if strategy == bidPricingStrategyScale {
+		return makeScalePricing(bidPriceScales{
+			CPU:      viper.GetString(FlagBidPriceCPUScale),
+			Memory:   viper.GetString(FlagBidPriceMemoryScale),
+			Storage:  viper.GetString(FlagBidPriceStorageScale),
+			GPU:      viper.GetString(FlagBidPriceGPUScale),
+			Endpoint: viper.GetString(FlagBidPriceEndpointScale),
+			IP:       viper.GetString(FlagBidPriceIPScale),
+			Snapshot: viper.GetString(FlagBidPriceSnapshotScale),
+		})
 	}
 
 	if strategy == bidPricingStrategyRandomRange {
@@ -528,6 +935,31 @@ func createBidPricingStrategy(strategy string) (bidengine.BidPricingStrategy, er
 		return bidengine.MakeShellScriptPricing(scriptPath, processLimit, runtimeLimit)
 	}
 
+	if strategy == bidPricingStrategyWebhook {
+		cfg := bidengine.WebhookPricingConfig{
+			URL:              viper.GetString(FlagBidPriceWebhookURL),
+			Timeout:          viper.GetDuration(FlagBidPriceWebhookTimeout),
+			Retries:          viper.GetUint(FlagBidPriceWebhookRetries),
+			RetryBackoff:     viper.GetDuration(FlagBidPriceWebhookRetryBackoff),
+			CacheTTL:         viper.GetDuration(FlagBidPriceWebhookCacheTTL),
+			BreakerThreshold: viper.GetUint(FlagBidPriceWebhookBreakerThreshold),
+			BreakerCooldown:  viper.GetDuration(FlagBidPriceWebhookBreakerCooldown),
+		}
+
+		if fallback := viper.GetString(FlagBidPriceWebhookFallback); fallback != "" {
+			if fallback == bidPricingStrategyWebhook {
+				return nil, fmt.Errorf("%w: webhook cannot be fallback of itself", errNoSuchBidPricingStrategy)
+			}
+
+			var err error
+			if cfg.Fallback, err = createBidPricingStrategy(fallback); err != nil {
+				return nil, fmt.Errorf("webhook fallback: %w", err)
+			}
+		}
+
+		return bidengine.MakeWebhookPricing(cfg)
+	}
+
 	return nil, errNoSuchBidPricingStrategy
 }
 
@@ -542,6 +974,8 @@ func doRunCmd(ctx context.Context, cmd *cobra.Command, _ []string) error {
 	deploymentIngressStaticHosts := viper.GetBool(FlagDeploymentIngressStaticHosts)
 	deploymentIngressDomain := viper.GetString(FlagDeploymentIngressDomain)
 	deploymentNetworkPoliciesEnabled := viper.GetBool(FlagDeploymentNetworkPoliciesEnabled)
+	deploymentStagedUpdate := viper.GetBool(FlagDeploymentStagedUpdate)
+	deploymentRolloutTimeout := viper.GetDuration(FlagDeploymentRolloutTimeout)
 	dockerImagePullSecretsName := viper.GetString(FlagDockerImagePullSecretsName)
 	strategy := viper.GetString(FlagBidPricingStrategy)
 	deploymentIngressExposeLBHosts := viper.GetBool(FlagDeploymentIngressExposeLBHosts)
@@ -552,6 +986,8 @@ func doRunCmd(ctx context.Context, cmd *cobra.Command, _ []string) error {
 	overcommitPercentMemory := 1.0 + float64(viper.GetUint64(FlagOvercommitPercentMemory)/100.0)
 	blockedHostnames := viper.GetStringSlice(FlagDeploymentBlockedHostnames)
 	deploymentRuntimeClass := viper.GetString(FlagDeploymentRuntimeClass)
+	volumeSnapshotClass := viper.GetString(FlagVolumeSnapshotClass)
+	deploymentLogSplitStreams := viper.GetBool(FlagDeploymentLogSplitStreams)
 	bidTimeout := viper.GetDuration(FlagBidTimeout)
 	manifestTimeout := viper.GetDuration(FlagManifestTimeout)
 	metricsListener := viper.GetString(FlagMetricsListener)
@@ -570,6 +1006,11 @@ func doRunCmd(ctx context.Context, cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
+	pricing, err = createDenomPricing(pricing)
+	if err != nil {
+		return err
+	}
+
 	logger := fromctx.LogcFromCtx(cmd.Context())
 
 	var metricsRouter http.Handler
@@ -596,6 +1037,18 @@ func doRunCmd(ctx context.Context, cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
+	txService := broadcaster.NewService(ctx, logger, cl.Tx(), broadcaster.Config{
+		EstimateGas:  viper.GetBool(FlagTxEstimateGas) && !cmd.Flags().Changed(flags.FlagGas),
+		Timeout:      viper.GetDuration(FlagTxBroadcastTimeout),
+		MaxRetries:   viper.GetUint(FlagTxMaxRetries),
+		RetryBackoff: viper.GetDuration(FlagTxRetryBackoff),
+	})
+	defer func() {
+		_ = txService.Close()
+	}()
+
+	cl = broadcaster.WrapClient(cl, txService)
+
 	gwaddr := viper.GetString(FlagGatewayListenAddress)
 	grpcaddr := viper.GetString(FlagGatewayGRPCListenAddress)
 
@@ -640,19 +1093,39 @@ func doRunCmd(ctx context.Context, cmd *cobra.Command, _ []string) error {
 
 	pinfo := &res.Provider
 
+	teeConfig := clustertypes.NewDefaultTEEConfig()
+	var healthChecks clustertypes.HealthCheckConfig
+	if len(providerConfig) != 0 {
+		teeConfig, err = clustertypes.ReadTEEConfigPath(providerConfig)
+		if err != nil {
+			return err
+		}
+
+		healthChecks, err = clustertypes.ReadHealthCheckConfigPath(providerConfig)
+		if err != nil {
+			return err
+		}
+	}
+
 	// k8s client creation
 	kubeSettings := builder.NewDefaultSettings()
 	kubeSettings.DeploymentIngressDomain = deploymentIngressDomain
 	kubeSettings.DeploymentIngressExposeLBHosts = deploymentIngressExposeLBHosts
 	kubeSettings.DeploymentIngressStaticHosts = deploymentIngressStaticHosts
 	kubeSettings.NetworkPoliciesEnabled = deploymentNetworkPoliciesEnabled
+	kubeSettings.DeploymentStagedUpdate = deploymentStagedUpdate
+	kubeSettings.DeploymentRolloutTimeout = deploymentRolloutTimeout
 	kubeSettings.ClusterPublicHostname = clusterPublicHostname
 	kubeSettings.CPUCommitLevel = overcommitPercentCPU
 	kubeSettings.GPUCommitLevel = overcommitPercentGPU
 	kubeSettings.MemoryCommitLevel = overcommitPercentMemory
 	kubeSettings.StorageCommitLevel = overcommitPercentStorage
 	kubeSettings.DeploymentRuntimeClass = deploymentRuntimeClass
+	kubeSettings.VolumeSnapshotClass = volumeSnapshotClass
+	kubeSettings.LogSplitStreams = deploymentLogSplitStreams
 	kubeSettings.DockerImagePullSecretsName = strings.TrimSpace(dockerImagePullSecretsName)
+	kubeSettings.TEE = teeConfig
+	kubeSettings.HealthChecks = healthChecks
 
 	if err := builder.ValidateSettings(kubeSettings); err != nil {
 		return err
@@ -703,6 +1176,8 @@ func doRunCmd(ctx context.Context, cmd *cobra.Command, _ []string) error {
 	config.MonitorRetryPeriodJitter = monitorRetryPeriodJitter
 	config.MonitorHealthcheckPeriod = monitorHealthcheckPeriod
 	config.MonitorHealthcheckPeriodJitter = monitorHealthcheckPeriodJitter
+	config.TEE = teeConfig
+	config.VolumeSnapshots = volumeSnapshotClass != ""
 
 	if len(providerConfig) != 0 {
 		pConf, err := config2.ReadConfigPath(providerConfig)
@@ -718,9 +1193,24 @@ func doRunCmd(ctx context.Context, cmd *cobra.Command, _ []string) error {
 	config.BalanceCheckerCfg = provider.BalanceCheckerConfig{
 		WithdrawalPeriod:        viper.GetDuration(FlagWithdrawalPeriod),
 		LeaseFundsCheckInterval: viper.GetDuration(FlagLeaseFundsMonitorInterval),
+		WithdrawalBatchSize:     int(viper.GetUint(FlagWithdrawalBatchSize)), // nolint: gosec
+		WithdrawalBatchWindow:   viper.GetDuration(FlagWithdrawalBatchWindow),
+		LowBalanceThreshold:     viper.GetDuration(FlagLeaseFundsWarningThreshold),
+		LowBalanceBlocks:        viper.GetInt64(FlagLeaseFundsWarningBlocks),
+		LowBalanceWebhook:       viper.GetString(FlagLeaseFundsWarningWebhook),
 	}
 
 	config.BidPricingStrategy = pricing
+	if viper.GetBool(FlagBidPriceSurge) {
+		config.BidSurgePricing = &bidengine.SurgePricingConfig{
+			Curve:   viper.GetString(FlagBidPriceSurgeCurve),
+			Target:  viper.GetFloat64(FlagBidPriceSurgeTarget),
+			Floor:   viper.GetFloat64(FlagBidPriceSurgeFloor),
+			Ceiling: viper.GetFloat64(FlagBidPriceSurgeCeiling),
+			Window:  viper.GetDuration(FlagBidPriceSurgeWindow),
+		}
+	}
+
 	config.ClusterSettings = clusterSettings
 
 	bidDeposit, err := sdk.ParseCoinNormalized(viper.GetString(FlagBidDeposit))
@@ -728,9 +1218,86 @@ func doRunCmd(ctx context.Context, cmd *cobra.Command, _ []string) error {
 		return err
 	}
 	config.BidDeposit = bidDeposit
+	config.BidWallet = &bidengine.WalletConfig{
+		MinimumBalance: sdk.NewCoin(bidDeposit.Denom, sdk.NewIntFromUint64(viper.GetUint64(FlagMinimumBalance))),
+		CheckInterval:  viper.GetDuration(FlagWalletCheckInterval),
+	}
 	config.RPCQueryTimeout = rpcQueryTimeout
 	config.CachedResultMaxAge = cachedResultMaxAge
 
+	if file := viper.GetString(FlagBidPolicy); file != "" {
+		policyWatcher, err := policy.NewWatcher(logger, file)
+		if err != nil {
+			return fmt.Errorf("loading bid policy: %w", err)
+		}
+
+		config.BidPolicy = policyWatcher
+
+		group.Go(func() error {
+			return policyWatcher.Run(ctx)
+		})
+	}
+
+	journalFile := viper.GetString(FlagBidJournal)
+	if journalFile == "" {
+		journalFile = filepath.Join(cctx.HomeDir, "provider", "bid-journal.db")
+	}
+
+	bidJournal, err := journal.Open(journalFile)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = bidJournal.Close()
+	}()
+
+	config.BidJournal = bidJournal
+	config.BidJournalRetention = viper.GetDuration(FlagBidJournalRetention)
+
+	ledgerFile := viper.GetString(FlagEarningsLedger)
+	if ledgerFile == "" {
+		ledgerFile = filepath.Join(cctx.HomeDir, "provider", "earnings.db")
+	}
+
+	earningsLedger, err := ledger.Open(ledgerFile)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = earningsLedger.Close()
+	}()
+
+	config.EarningsLedger = earningsLedger
+
+	shellAuditCfg := shellaudit.Config{
+		Retention:         viper.GetDuration(FlagShellAuditRetention),
+		MaxRecordingBytes: viper.GetInt64(FlagShellAuditRecordingMaxBytes),
+	}
+
+	if recordings := viper.GetString(FlagShellAuditRecordings); recordings != "" {
+		shellAuditCfg.Sink, err = shellaudit.NewSink(recordings)
+		if err != nil {
+			return err
+		}
+	}
+
+	shellAuditFile := viper.GetString(FlagShellAuditLog)
+	if shellAuditFile == "" {
+		shellAuditFile = filepath.Join(cctx.HomeDir, "provider", "shell-audit.db")
+	}
+
+	shellAudit, err := shellaudit.Open(shellAuditFile, shellAuditCfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = shellAudit.Close()
+	}()
+
+	group.Go(func() error {
+		return shellAudit.Run(ctx, logger)
+	})
+
 	// This value can be nil, the operator is not mandatory
 	var ipOperatorClient cip.Client
 	if enableIPOperator {
@@ -755,7 +1322,7 @@ func doRunCmd(ctx context.Context, cmd *cobra.Command, _ []string) error {
 
 	ctx = context.WithValue(ctx, clfromctx.CtxKeyClientHostname, hostnameOperatorClient)
 
-	inventory, err := kubeinventory.NewClient(ctx)
+	inventory, err := kubeinventory.NewClient(ctx, teeConfig)
 	if err != nil {
 		return err
 	}
@@ -778,6 +1345,7 @@ func doRunCmd(ctx context.Context, cmd *cobra.Command, _ []string) error {
 	}
 
 	ctx = context.WithValue(ctx, fromctx.CtxKeyErrGroup, group)
+	ctx = context.WithValue(ctx, shellaudit.CtxKeyLog, shellAudit)
 
 	gwRest, err := gwrest.NewServer(
 		ctx,
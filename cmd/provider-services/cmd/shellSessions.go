@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	sdkclient "github.com/cosmos/cosmos-sdk/client"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	cutils "github.com/akash-network/node/x/cert/utils"
+
+	aclient "github.com/akash-network/provider/client"
+	gwrest "github.com/akash-network/provider/gateway/rest"
+	"github.com/akash-network/provider/gateway/shellaudit"
+)
+
+func shellSessionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "shell-sessions",
+		Short: "get shell sessions opened into lease containers",
+		Long: "get shell sessions opened into lease containers with lease-shell and lease-cp, most recent first. " +
+			"tenants see only their own sessions, provider account sees all of them",
+		Args:         cobra.ExactArgs(0),
+		SilenceUsage: true,
+		PreRunE:      checkOutputFormat,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return doShellSessions(cmd)
+		},
+	}
+
+	addProviderFlags(cmd)
+	cmd.Flags().String(flagOwner, "", "show sessions of given deployment owner only. has effect for provider account only")
+	cmd.Flags().Uint64(FlagDSeq, 0, "show sessions of given deployment only")
+	cmd.Flags().Uint(flagLimit, 100, "max number of most recent sessions to show, 0 shows all")
+	cmd.Flags().StringP(flagOutput, "o", outputText, "output format text|json|yaml. default text")
+
+	return cmd
+}
+
+func doShellSessions(cmd *cobra.Command) error {
+	cctx, err := sdkclient.GetClientTxContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+
+	cl, err := aclient.DiscoverQueryClient(ctx, cctx)
+	if err != nil {
+		return err
+	}
+
+	prov, err := providerFromFlags(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	var opts shellaudit.ListOptions
+
+	if opts.Owner, err = cmd.Flags().GetString(flagOwner); err != nil {
+		return err
+	}
+
+	if opts.DSeq, err = cmd.Flags().GetUint64(FlagDSeq); err != nil {
+		return err
+	}
+
+	limit, err := cmd.Flags().GetUint(flagLimit)
+	if err != nil {
+		return err
+	}
+
+	opts.Limit = int(limit) // nolint: gosec
+
+	cert, err := cutils.LoadAndQueryCertificateForAccount(cmd.Context(), cctx, nil)
+	if err != nil {
+		return markRPCServerError(err)
+	}
+
+	gclient, err := gwrest.NewClient(ctx, cl, prov, []tls.Certificate{cert})
+	if err != nil {
+		return err
+	}
+
+	sessions, err := gclient.ShellSessions(ctx, opts)
+	if err != nil {
+		return showErrorToUser(err)
+	}
+
+	buf := &bytes.Buffer{}
+
+	switch cmd.Flag(flagOutput).Value.String() {
+	case outputText:
+		for _, sess := range sessions {
+			lid := sess.LeaseID
+
+			_, _ = fmt.Fprintf(buf, "session: %s\n", sess.ID)
+			_, _ = fmt.Fprintf(buf, "\tlease:     %s/%d/%d/%d\n", lid.Owner, lid.DSeq, lid.GSeq, lid.OSeq)
+			_, _ = fmt.Fprintf(buf, "\tservice:   %s, replica %d\n", sess.Service, sess.PodIndex)
+			_, _ = fmt.Fprintf(buf, "\tcommand:   %s\n", strings.Join(sess.Command, " "))
+			_, _ = fmt.Fprintf(buf, "\tstarted:   %s\n", sess.StartedAt.Format(time.RFC3339))
+
+			switch {
+			case sess.EndedAt == nil:
+				_, _ = fmt.Fprintf(buf, "\tstate:     running\n")
+			case sess.ExitCode != nil:
+				_, _ = fmt.Fprintf(buf, "\tended:     %s\n\texit code: %d\n", sess.EndedAt.Format(time.RFC3339), *sess.ExitCode)
+			default:
+				_, _ = fmt.Fprintf(buf, "\tended:     %s\n\terror:     %s\n", sess.EndedAt.Format(time.RFC3339), sess.Error)
+			}
+
+			if sess.Recording != "" {
+				recording := sess.Recording
+				if sess.RecordingTruncated {
+					recording += " (truncated)"
+				}
+
+				_, _ = fmt.Fprintf(buf, "\trecording: %s\n", recording)
+			}
+		}
+	case outputJSON:
+		err = json.NewEncoder(buf).Encode(sessions)
+	case outputYAML:
+		// sessions carry json tags only, convert them so yaml keys match json output
+		var data []byte
+		if data, err = json.Marshal(sessions); err != nil {
+			return err
+		}
+
+		var obj interface{}
+		if err = json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+
+		err = yaml.NewEncoder(buf).Encode(obj)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprint(cmd.OutOrStdout(), buf.String())
+
+	return err
+}
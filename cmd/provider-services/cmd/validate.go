@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	sdkclient "github.com/cosmos/cosmos-sdk/client"
+
+	"github.com/akash-network/node/sdl"
+	cutils "github.com/akash-network/node/x/cert/utils"
+
+	"github.com/akash-network/provider/bidengine"
+	aclient "github.com/akash-network/provider/client"
+	gwrest "github.com/akash-network/provider/gateway/rest"
+)
+
+var (
+	errValidateDeclined = errors.New("provider declines to bid on some of the deployment groups")
+)
+
+// ValidateCmd asks provider whether it would bid on deployment groups of the SDL
+// and why it would not
+func ValidateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "validate <sdl-path>",
+		Args:         cobra.ExactArgs(1),
+		Short:        "Check if provider would bid on deployment groups and explain why not",
+		SilenceUsage: true,
+		PreRunE:      checkOutputFormat,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return doValidate(cmd, args[0])
+		},
+	}
+
+	addProviderFlags(cmd)
+	cmd.Flags().StringP(flagOutput, "o", outputText, "output format text|json|yaml. default text")
+
+	return cmd
+}
+
+func doValidate(cmd *cobra.Command, sdlpath string) error {
+	cctx, err := sdkclient.GetClientTxContext(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+
+	cl, err := aclient.DiscoverQueryClient(ctx, cctx)
+	if err != nil {
+		return err
+	}
+
+	prov, err := providerFromFlags(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	sdl, err := sdl.ReadFile(sdlpath)
+	if err != nil {
+		return err
+	}
+
+	groups, err := sdl.DeploymentGroups()
+	if err != nil {
+		return err
+	}
+
+	cert, err := cutils.LoadAndQueryCertificateForAccount(cmd.Context(), cctx, nil)
+	if err != nil {
+		return markRPCServerError(err)
+	}
+
+	gclient, err := gwrest.NewClient(ctx, cl, prov, []tls.Certificate{cert})
+	if err != nil {
+		return err
+	}
+
+	type result struct {
+		Group       string                    `json:"group" yaml:"group"`
+		Status      string                    `json:"status" yaml:"status"`
+		MinBidPrice string                    `json:"min_bid_price,omitempty" yaml:"min_bid_price,omitempty"`
+		Declines    []bidengine.DeclineReason `json:"declines,omitempty" yaml:"declines,omitempty"`
+	}
+
+	results := make([]result, 0, len(groups))
+
+	declined := false
+
+	for _, gspec := range groups {
+		vres, err := gclient.Validate(ctx, *gspec)
+		if err != nil {
+			return showErrorToUser(err)
+		}
+
+		res := result{
+			Group:    gspec.Name,
+			Status:   "BID",
+			Declines: vres.Declines,
+		}
+
+		if vres.MinBidPrice.IsValid() {
+			res.MinBidPrice = vres.MinBidPrice.String()
+		}
+
+		if len(vres.Declines) != 0 {
+			res.Status = "DECLINE"
+			declined = true
+		}
+
+		results = append(results, res)
+	}
+
+	buf := &bytes.Buffer{}
+
+	switch cmd.Flag(flagOutput).Value.String() {
+	case outputText:
+		for _, res := range results {
+			_, _ = fmt.Fprintf(buf, "group: %s\n\tstatus:        %s\n", res.Group, res.Status)
+			if res.MinBidPrice != "" {
+				_, _ = fmt.Fprintf(buf, "\tmin bid price: %s\n", res.MinBidPrice)
+			}
+			if len(res.Declines) != 0 {
+				_, _ = fmt.Fprintf(buf, "\tdeclines:\n")
+			}
+			for _, decline := range res.Declines {
+				_, _ = fmt.Fprintf(buf, "\t\t%s\n", decline)
+			}
+		}
+	case outputJSON:
+		err = json.NewEncoder(buf).Encode(results)
+	case outputYAML:
+		err = yaml.NewEncoder(buf).Encode(results)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprint(cmd.OutOrStdout(), buf.String())
+	if err != nil {
+		return err
+	}
+
+	if declined {
+		return errValidateDeclined
+	}
+
+	return nil
+}
@@ -10,7 +10,10 @@ import (
 	types "github.com/akash-network/akash-api/go/node/types/v1beta3"
 
 	"github.com/akash-network/provider/bidengine"
+	"github.com/akash-network/provider/bidengine/journal"
+	"github.com/akash-network/provider/bidengine/policy"
 	"github.com/akash-network/provider/cluster"
+	"github.com/akash-network/provider/ledger"
 )
 
 type Config struct {
@@ -18,10 +21,16 @@ type Config struct {
 	ClusterPublicHostname       string
 	ClusterExternalPortQuantity uint
 	BidPricingStrategy          bidengine.BidPricingStrategy
+	BidSurgePricing             *bidengine.SurgePricingConfig
 	BidDeposit                  sdk.Coin
+	BidWallet                   *bidengine.WalletConfig
 	BidTimeout                  time.Duration
+	BidPolicy                   policy.Source
+	BidJournal                  *journal.Journal
+	BidJournalRetention         time.Duration
 	ManifestTimeout             time.Duration
 	BalanceCheckerCfg           BalanceCheckerConfig
+	EarningsLedger              *ledger.Ledger
 	Attributes                  types.Attributes
 	MaxGroupVolumes             int
 	RPCQueryTimeout             time.Duration
@@ -36,8 +45,11 @@ func NewDefaultConfig() Config {
 		BalanceCheckerCfg: BalanceCheckerConfig{
 			LeaseFundsCheckInterval: 1 * time.Minute,
 			WithdrawalPeriod:        24 * time.Hour,
+			WithdrawalBatchSize:     20,
+			WithdrawalBatchWindow:   30 * time.Second,
 		},
-		MaxGroupVolumes: constants.DefaultMaxGroupVolumes,
-		Config:          cluster.NewDefaultConfig(),
+		BidJournalRetention: 7 * 24 * time.Hour,
+		MaxGroupVolumes:     constants.DefaultMaxGroupVolumes,
+		Config:              cluster.NewDefaultConfig(),
 	}
 }
@@ -1,6 +1,8 @@
 package event
 
 import (
+	"time"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
 	mani "github.com/akash-network/akash-api/go/manifest/v2beta2"
@@ -46,6 +48,8 @@ const (
 	ClusterDeploymentPending ClusterDeploymentStatus = "pending"
 	// ClusterDeploymentDeployed is used when cluster deployment status is deployed
 	ClusterDeploymentDeployed ClusterDeploymentStatus = "deployed"
+	// ClusterDeploymentRolledBack is used when updated deployment did not become ready and the previous one was restored
+	ClusterDeploymentRolledBack ClusterDeploymentStatus = "rolled-back"
 )
 
 // ClusterDeployment stores leaseID, group details and deployment status
@@ -63,3 +67,11 @@ type LeaseAddFundsMonitor struct {
 type LeaseRemoveFundsMonitor struct {
 	mtypes.LeaseID
 }
+
+// LeaseLowBalance is published when funds left in deployment escrow
+// pay for the lease for less than configured warning threshold
+type LeaseLowBalance struct {
+	mtypes.LeaseID
+	BlocksRemaining int64
+	Runway          time.Duration
+}
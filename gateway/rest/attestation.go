@@ -0,0 +1,142 @@
+package rest
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
+
+	cltypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+const attestationBindingDomain = "akash-lease-attestation-v1"
+
+var (
+	ErrAttestationCertificate = errors.New("attestation: invalid provider certificate")
+	ErrAttestationBinding     = errors.New("attestation: provider signature does not match quote")
+)
+
+// LeaseAttestation is the TEE quote of the lease service bound to the provider.
+// Signature is made with the provider certificate key over the lease id, nonce and
+// every field of the quote, so quote cannot be replayed for another lease or provider
+type LeaseAttestation struct {
+	LeaseID     mtypes.LeaseID           `json:"lease_id"`
+	Nonce       []byte                   `json:"nonce"`
+	Quote       cltypes.AttestationQuote `json:"quote"`
+	Certificate []byte                   `json:"certificate"`
+	Signature   []byte                   `json:"signature"`
+}
+
+func newLeaseAttestation(lid mtypes.LeaseID, nonce []byte, quote cltypes.AttestationQuote, cert tls.Certificate) (LeaseAttestation, error) {
+	if len(cert.Certificate) == 0 {
+		return LeaseAttestation{}, ErrAttestationCertificate
+	}
+
+	signer, valid := cert.PrivateKey.(crypto.Signer)
+	if !valid {
+		return LeaseAttestation{}, fmt.Errorf("%w: private key cannot sign", ErrAttestationCertificate)
+	}
+
+	res := LeaseAttestation{
+		LeaseID: lid,
+		Nonce:   nonce,
+		Quote:   quote,
+		Certificate: pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: cert.Certificate[0],
+		}),
+	}
+
+	digest := sha256.Sum256(res.payload())
+
+	var err error
+	res.Signature, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return LeaseAttestation{}, err
+	}
+
+	return res, nil
+}
+
+// ProviderCertificate decodes certificate attestation has been signed with
+func (a LeaseAttestation) ProviderCertificate() (*x509.Certificate, error) {
+	blk, _ := pem.Decode(a.Certificate)
+	if blk == nil || blk.Type != "CERTIFICATE" {
+		return nil, ErrAttestationCertificate
+	}
+
+	return x509.ParseCertificate(blk.Bytes)
+}
+
+// Verify checks provider signature binds the quote to the lease and given nonce,
+// then verifies the quote itself. It does not check provider certificate is valid on chain,
+// Client.LeaseAttestation does it before returning the attestation
+func (a LeaseAttestation) Verify(lid mtypes.LeaseID, nonce []byte) error {
+	if !a.LeaseID.Equals(lid) {
+		return fmt.Errorf("%w: lease id %s, expected %s", ErrAttestationBinding, a.LeaseID, lid)
+	}
+
+	if !bytes.Equal(a.Nonce, nonce) {
+		return fmt.Errorf("%w: nonce mismatch", ErrAttestationBinding)
+	}
+
+	cert, err := a.ProviderCertificate()
+	if err != nil {
+		return err
+	}
+
+	var algo x509.SignatureAlgorithm
+	switch cert.PublicKey.(type) {
+	case *ecdsa.PublicKey:
+		algo = x509.ECDSAWithSHA256
+	case *rsa.PublicKey:
+		algo = x509.SHA256WithRSA
+	default:
+		return fmt.Errorf("%w: unsupported key type %T", ErrAttestationCertificate, cert.PublicKey)
+	}
+
+	if err = cert.CheckSignature(algo, a.payload(), a.Signature); err != nil {
+		return fmt.Errorf("%w: %w", ErrAttestationBinding, err)
+	}
+
+	return a.Quote.Verify(nonce)
+}
+
+// payload serializes fields covered by provider signature.
+// variable length fields are length prefixed to keep encoding unambiguous
+func (a LeaseAttestation) payload() []byte {
+	var buf []byte
+
+	appendField := func(val []byte) {
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(val))) // nolint: gosec
+		buf = append(buf, val...)
+	}
+
+	appendField([]byte(attestationBindingDomain))
+	appendField([]byte(a.LeaseID.String()))
+	appendField(a.Nonce)
+	appendField([]byte(a.Quote.Service))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(a.Quote.PodIndex))
+	appendField([]byte(a.Quote.HashAlg))
+	appendField(a.Quote.Quote)
+	appendField(a.Quote.Signature)
+	appendField(a.Quote.AKPublic)
+
+	for _, idx := range a.Quote.SortedPCRs() {
+		buf = binary.BigEndian.AppendUint64(buf, uint64(idx))
+		appendField(a.Quote.PCRs[idx])
+	}
+
+	return buf
+}
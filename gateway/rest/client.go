@@ -6,6 +6,7 @@ import (
 	"crypto/ecdsa"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -35,7 +36,10 @@ import (
 	cutils "github.com/akash-network/node/x/cert/utils"
 
 	"github.com/akash-network/provider"
+	"github.com/akash-network/provider/bidengine/journal"
 	cltypes "github.com/akash-network/provider/cluster/types/v1beta3"
+	"github.com/akash-network/provider/gateway/shellaudit"
+	"github.com/akash-network/provider/ledger"
 )
 
 const (
@@ -47,11 +51,20 @@ const (
 type Client interface {
 	Status(ctx context.Context) (*provider.Status, error)
 	Validate(ctx context.Context, gspec dtypes.GroupSpec) (provider.ValidateGroupSpecResult, error)
+	BidHistory(ctx context.Context, opts journal.ListOptions) ([]journal.Entry, error)
+	Earnings(ctx context.Context, query ledger.Query) (ledger.Report, error)
+	ShellSessions(ctx context.Context, opts shellaudit.ListOptions) ([]shellaudit.Session, error)
 	SubmitManifest(ctx context.Context, dseq uint64, mani manifest.Manifest) error
 	GetManifest(ctx context.Context, id mtypes.LeaseID) (manifest.Manifest, error)
 	LeaseStatus(ctx context.Context, id mtypes.LeaseID) (LeaseStatus, error)
+	LeaseRevisions(ctx context.Context, id mtypes.LeaseID) ([]cltypes.ManifestRevision, error)
+	LeaseRollback(ctx context.Context, id mtypes.LeaseID, revision uint64) error
+	LeasePause(ctx context.Context, id mtypes.LeaseID) error
+	LeaseResume(ctx context.Context, id mtypes.LeaseID) error
+	LeaseSnapshot(ctx context.Context, id mtypes.LeaseID, name string) ([]cltypes.VolumeSnapshot, error)
+	LeaseSnapshots(ctx context.Context, id mtypes.LeaseID) ([]cltypes.VolumeSnapshot, error)
 	LeaseEvents(ctx context.Context, id mtypes.LeaseID, services string, follow bool) (*LeaseKubeEvents, error)
-	LeaseLogs(ctx context.Context, id mtypes.LeaseID, services string, follow bool, tailLines int64) (*ServiceLogs, error)
+	LeaseLogs(ctx context.Context, id mtypes.LeaseID, services string, opts cltypes.LeaseLogsOptions, sopts LogStreamOptions) (*ServiceLogs, error)
 	ServiceStatus(ctx context.Context, id mtypes.LeaseID, service string) (*cltypes.ServiceStatus, error)
 	LeaseShell(ctx context.Context, id mtypes.LeaseID, service string, podIndex uint, cmd []string,
 		stdin io.Reader,
@@ -59,6 +72,10 @@ type Client interface {
 		stderr io.Writer,
 		tty bool,
 		tsq <-chan remotecommand.TerminalSize) error
+	LeaseUpload(ctx context.Context, id mtypes.LeaseID, service string, podIndex uint, dir string, archive io.Reader, stderr io.Writer) error
+	LeaseDownload(ctx context.Context, id mtypes.LeaseID, service string, podIndex uint, path string, archive io.Writer, stderr io.Writer) error
+	LeasePortForward(ctx context.Context, id mtypes.LeaseID, service string, podIndex uint, port uint32, conn io.ReadWriter) error
+	LeaseAttestation(ctx context.Context, id mtypes.LeaseID, service string, podIndex uint, nonce []byte, pcrs []uint) (*LeaseAttestation, error)
 	MigrateHostnames(ctx context.Context, hostnames []string, dseq uint64, gseq uint32) error
 	MigrateEndpoints(ctx context.Context, endpoints []string, dseq uint64, gseq uint32) error
 }
@@ -72,9 +89,26 @@ type LeaseKubeEvent struct {
 	Message string `json:"message"`
 }
 
+// LogStreamOptions configure how gateway streams lines of the logs
+type LogStreamOptions struct {
+	// Merge orders lines of all pods by their timestamps
+	Merge bool
+	// Grep and Regex filter lines containing the substring and matching the expression
+	Grep  string
+	Regex string
+}
+
+// ServiceLogMessage is the line of the log, Name is the name of the pod line is read from
 type ServiceLogMessage struct {
-	Name    string `json:"name"`
-	Message string `json:"message"`
+	Name      string `json:"name"`
+	Message   string `json:"message"`
+	Service   string `json:"service,omitempty"`
+	Replica   int    `json:"replica"`
+	Container string `json:"container,omitempty"`
+	// Stream is either stdout or stderr when provider streams them separately
+	Stream string `json:"stream,omitempty"`
+	// Timestamp is set in merged mode only, lines are ordered by it
+	Timestamp *time.Time `json:"timestamp,omitempty"`
 }
 
 type LeaseKubeEvents struct {
@@ -389,6 +423,102 @@ func (c *client) Validate(ctx context.Context, gspec dtypes.GroupSpec) (provider
 	return obj, nil
 }
 
+func (c *client) BidHistory(ctx context.Context, opts journal.ListOptions) ([]journal.Entry, error) {
+	uri, err := makeURI(c.host, bidHistoryPath())
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	if opts.Owner != "" {
+		query.Set("owner", opts.Owner)
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.InFlight {
+		query.Set("in_flight", "true")
+	}
+
+	if len(query) != 0 {
+		uri = uri + "?" + query.Encode()
+	}
+
+	var obj []journal.Entry
+
+	if err := c.getStatus(ctx, uri, &obj); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+func (c *client) Earnings(ctx context.Context, q ledger.Query) (ledger.Report, error) {
+	uri, err := makeURI(c.host, earningsPath())
+	if err != nil {
+		return ledger.Report{}, err
+	}
+
+	query := url.Values{}
+	if q.Owner != "" {
+		query.Set("owner", q.Owner)
+	}
+	if q.DSeq != 0 {
+		query.Set("dseq", strconv.FormatUint(q.DSeq, 10))
+	}
+	if !q.From.IsZero() {
+		query.Set("from", q.From.Format(time.RFC3339))
+	}
+	if !q.To.IsZero() {
+		query.Set("to", q.To.Format(time.RFC3339))
+	}
+	if q.Withdrawals {
+		query.Set("withdrawals", "true")
+	}
+
+	if len(query) != 0 {
+		uri = uri + "?" + query.Encode()
+	}
+
+	var obj ledger.Report
+
+	if err := c.getStatus(ctx, uri, &obj); err != nil {
+		return ledger.Report{}, err
+	}
+
+	return obj, nil
+}
+
+func (c *client) ShellSessions(ctx context.Context, opts shellaudit.ListOptions) ([]shellaudit.Session, error) {
+	uri, err := makeURI(c.host, shellSessionsPath())
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	if opts.Owner != "" {
+		query.Set("owner", opts.Owner)
+	}
+	if opts.DSeq != 0 {
+		query.Set("dseq", strconv.FormatUint(opts.DSeq, 10))
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+
+	if len(query) != 0 {
+		uri = uri + "?" + query.Encode()
+	}
+
+	var obj []shellaudit.Session
+
+	if err := c.getStatus(ctx, uri, &obj); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
 func (c *client) SubmitManifest(ctx context.Context, dseq uint64, mani manifest.Manifest) error {
 	uri, err := makeURI(c.host, submitManifestPath(dseq))
 	if err != nil {
@@ -562,6 +692,151 @@ func (c *client) LeaseStatus(ctx context.Context, id mtypes.LeaseID) (LeaseStatu
 	return obj, nil
 }
 
+func (c *client) LeaseRevisions(ctx context.Context, id mtypes.LeaseID) ([]cltypes.ManifestRevision, error) {
+	uri, err := makeURI(c.host, leaseRevisionsPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	var obj []cltypes.ManifestRevision
+	if err := c.getStatus(ctx, uri, &obj); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+func (c *client) LeaseRollback(ctx context.Context, id mtypes.LeaseID, revision uint64) error {
+	uri, err := makeURI(c.host, leaseRollbackPath(id))
+	if err != nil {
+		return err
+	}
+
+	buf, err := json.Marshal(leaseRollbackRequestBody{Revision: revision})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uri, bytes.NewReader(buf))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentTypeJSON)
+
+	rCl := c.newReqClient(ctx)
+	resp, err := rCl.hclient.Do(req)
+	if err != nil {
+		return err
+	}
+	responseBuf := &bytes.Buffer{}
+	_, err = io.Copy(responseBuf, resp.Body)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if err != nil {
+		return err
+	}
+
+	return createClientResponseErrorIfNotOK(resp, responseBuf)
+}
+
+func (c *client) LeasePause(ctx context.Context, id mtypes.LeaseID) error {
+	return c.postLease(ctx, leasePausePath(id))
+}
+
+func (c *client) LeaseResume(ctx context.Context, id mtypes.LeaseID) error {
+	return c.postLease(ctx, leaseResumePath(id))
+}
+
+func (c *client) LeaseSnapshot(ctx context.Context, id mtypes.LeaseID, name string) ([]cltypes.VolumeSnapshot, error) {
+	uri, err := makeURI(c.host, leaseSnapshotsPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := json.Marshal(leaseSnapshotRequestBody{Name: name})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uri, bytes.NewReader(buf))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentTypeJSON)
+
+	rCl := c.newReqClient(ctx)
+	resp, err := rCl.hclient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	responseBuf := &bytes.Buffer{}
+	_, err = io.Copy(responseBuf, resp.Body)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = createClientResponseErrorIfNotOK(resp, responseBuf); err != nil {
+		return nil, err
+	}
+
+	var obj []cltypes.VolumeSnapshot
+	if err = json.NewDecoder(responseBuf).Decode(&obj); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+func (c *client) LeaseSnapshots(ctx context.Context, id mtypes.LeaseID) ([]cltypes.VolumeSnapshot, error) {
+	uri, err := makeURI(c.host, leaseSnapshotsPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	var obj []cltypes.VolumeSnapshot
+	if err := c.getStatus(ctx, uri, &obj); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// postLease requests lease action which takes no parameters
+func (c *client) postLease(ctx context.Context, path string) error {
+	uri, err := makeURI(c.host, path)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uri, nil)
+	if err != nil {
+		return err
+	}
+
+	rCl := c.newReqClient(ctx)
+	resp, err := rCl.hclient.Do(req)
+	if err != nil {
+		return err
+	}
+	responseBuf := &bytes.Buffer{}
+	_, err = io.Copy(responseBuf, resp.Body)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if err != nil {
+		return err
+	}
+
+	return createClientResponseErrorIfNotOK(resp, responseBuf)
+}
+
 func (c *client) LeaseEvents(ctx context.Context, id mtypes.LeaseID, _ string, follow bool) (*LeaseKubeEvents, error) {
 	endpoint, err := url.Parse(c.host.String() + "/" + leaseEventsPath(id))
 	if err != nil {
@@ -673,6 +948,52 @@ func (c *client) ServiceStatus(ctx context.Context, id mtypes.LeaseID, service s
 	return &obj, nil
 }
 
+// LeaseAttestation fetches TEE quote of the service pod. Certificate attestation is signed with
+// must be valid on chain and belong to the provider, caller is expected to Verify the result
+func (c *client) LeaseAttestation(ctx context.Context, id mtypes.LeaseID, service string, podIndex uint, nonce []byte, pcrs []uint) (*LeaseAttestation, error) {
+	endpoint, err := url.Parse(c.host.String() + "/" + leaseAttestationPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("service", service)
+	query.Set("podIndex", strconv.FormatUint(uint64(podIndex), 10))
+	query.Set("nonce", hex.EncodeToString(nonce))
+
+	if len(pcrs) != 0 {
+		spcrs := make([]string, 0, len(pcrs))
+		for _, pcr := range pcrs {
+			spcrs = append(spcrs, strconv.FormatUint(uint64(pcr), 10))
+		}
+
+		query.Set("pcrs", strings.Join(spcrs, ","))
+	}
+
+	endpoint.RawQuery = query.Encode()
+
+	var obj LeaseAttestation
+	if err := c.getStatus(ctx, endpoint.String(), &obj); err != nil {
+		return nil, err
+	}
+
+	cert, err := obj.ProviderCertificate()
+	if err != nil {
+		return nil, err
+	}
+
+	owner, _, err := atls.ValidatePeerCertificates(ctx, c.cclient, []*x509.Certificate{cert}, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAttestationCertificate, err)
+	}
+
+	if !owner.Equals(c.addr) {
+		return nil, fmt.Errorf("%w: certificate belongs to %s", ErrAttestationCertificate, owner)
+	}
+
+	return &obj, nil
+}
+
 func (c *client) getStatus(ctx context.Context, uri string, obj interface{}) error {
 	req, err := http.NewRequestWithContext(ctx, "GET", uri, nil)
 	if err != nil {
@@ -730,8 +1051,11 @@ func makeURI(uri *url.URL, path string) (string, error) {
 func (c *client) LeaseLogs(ctx context.Context,
 	id mtypes.LeaseID,
 	services string,
-	follow bool,
-	_ int64) (*ServiceLogs, error) {
+	opts cltypes.LeaseLogsOptions,
+	sopts LogStreamOptions) (*ServiceLogs, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
 
 	endpoint, err := url.Parse(c.host.String() + "/" + serviceLogsPath(id))
 	if err != nil {
@@ -747,10 +1071,46 @@ func (c *client) LeaseLogs(ctx context.Context,
 
 	query := url.Values{}
 
-	query.Set("follow", strconv.FormatBool(follow))
+	query.Set("follow", strconv.FormatBool(opts.Follow))
 
 	if services != "" {
-		query.Set("services", services)
+		query.Set("service", services)
+	}
+
+	if opts.TailLines != nil {
+		query.Set("tail", strconv.FormatInt(*opts.TailLines, 10))
+	}
+
+	if since := opts.Since(); since != "" {
+		query.Set("since", since)
+	}
+
+	if opts.Timestamps {
+		query.Set("timestamps", "true")
+	}
+
+	if opts.Previous {
+		query.Set("previous", "true")
+	}
+
+	if opts.Container != "" {
+		query.Set("container", opts.Container)
+	}
+
+	if opts.LimitBytes != nil {
+		query.Set("limitBytes", strconv.FormatInt(*opts.LimitBytes, 10))
+	}
+
+	if sopts.Merge {
+		query.Set("merge", "true")
+	}
+
+	if sopts.Grep != "" {
+		query.Set("grep", sopts.Grep)
+	}
+
+	if sopts.Regex != "" {
+		query.Set("regex", sopts.Regex)
 	}
 
 	endpoint.RawQuery = query.Encode()
@@ -0,0 +1,57 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
+)
+
+// LeaseUpload extracts tar archive read from archive into directory dir of the service container
+func (c *client) LeaseUpload(ctx context.Context, lID mtypes.LeaseID, service string, podIndex uint, dir string,
+	archive io.Reader, stderr io.Writer) error {
+	endpoint, err := c.leaseCopyEndpoint(lID, service, podIndex, LeaseCopyUpload, dir)
+	if err != nil {
+		return err
+	}
+
+	return c.execWebsocket(ctx, endpoint, archive, true, io.Discard, stderr, false, nil)
+}
+
+// LeaseDownload writes tar archive of the path in the service container to archive,
+// archive entries are named relative to the directory of the path
+func (c *client) LeaseDownload(ctx context.Context, lID mtypes.LeaseID, service string, podIndex uint, fpath string,
+	archive io.Writer, stderr io.Writer) error {
+	endpoint, err := c.leaseCopyEndpoint(lID, service, podIndex, LeaseCopyDownload, fpath)
+	if err != nil {
+		return err
+	}
+
+	return c.execWebsocket(ctx, endpoint, nil, false, archive, stderr, false, nil)
+}
+
+func (c *client) leaseCopyEndpoint(lID mtypes.LeaseID, service string, podIndex uint, direction string, fpath string) (*url.URL, error) {
+	endpoint, err := url.Parse(c.host.String() + "/" + leaseCopyPath(lID))
+	if err != nil {
+		return nil, err
+	}
+
+	switch endpoint.Scheme {
+	case schemeWSS, schemeHTTPS:
+		endpoint.Scheme = schemeWSS
+	default:
+		return nil, fmt.Errorf("%w: invalid uri scheme %q", errLeaseShell, endpoint.Scheme)
+	}
+
+	query := url.Values{}
+	query.Set("service", service)
+	query.Set("podIndex", fmt.Sprintf("%d", podIndex))
+	query.Set("direction", direction)
+	query.Set("path", fpath)
+
+	endpoint.RawQuery = query.Encode()
+
+	return endpoint, nil
+}
@@ -0,0 +1,89 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/gorilla/websocket"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
+)
+
+var (
+	errLeasePortForward = errors.New("lease port forward failed")
+)
+
+// LeasePortForward tunnels conn to the port of the service replica until either side closes the connection
+func (c *client) LeasePortForward(ctx context.Context, lID mtypes.LeaseID, service string, podIndex uint, port uint32, conn io.ReadWriter) error {
+	endpoint, err := url.Parse(c.host.String() + "/" + leasePortForwardPath(lID))
+	if err != nil {
+		return err
+	}
+
+	switch endpoint.Scheme {
+	case schemeWSS, schemeHTTPS:
+		endpoint.Scheme = schemeWSS
+	default:
+		return fmt.Errorf("%w: invalid uri scheme %q", errLeasePortForward, endpoint.Scheme)
+	}
+
+	query := url.Values{}
+	query.Set("service", service)
+	query.Set("podIndex", fmt.Sprintf("%d", podIndex))
+	query.Set("port", fmt.Sprintf("%d", port))
+
+	endpoint.RawQuery = query.Encode()
+
+	subctx, subcancel := context.WithCancel(ctx)
+	defer subcancel()
+
+	rCl := c.newReqClient(ctx)
+	ws, response, err := rCl.wsclient.DialContext(subctx, endpoint.String(), nil)
+	if err != nil {
+		if errors.Is(err, websocket.ErrBadHandshake) {
+			buf := &bytes.Buffer{}
+			_, _ = io.Copy(buf, response.Body)
+
+			return ClientResponseError{
+				Status:  response.StatusCode,
+				Message: buf.String(),
+			}
+		}
+		return err
+	}
+
+	go func() {
+		<-subctx.Done()
+		_ = ws.Close()
+	}()
+
+	stream := newWsPortForwardStream(ws)
+
+	go func() {
+		if _, err := io.Copy(stream, conn); err != nil {
+			subcancel()
+			return
+		}
+
+		// local side is done sending, response still can be received
+		_ = stream.CloseWrite()
+	}()
+
+	_, err = io.Copy(conn, stream)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		cerr := &websocket.CloseError{}
+		if errors.As(err, &cerr) {
+			return fmt.Errorf("%w: %s", errLeasePortForward, cerr.Text)
+		}
+	}
+
+	return err
+}
@@ -62,6 +62,19 @@ func (c *client) LeaseShell(ctx context.Context, lID mtypes.LeaseID, service str
 	}
 
 	endpoint.RawQuery = query.Encode()
+
+	return c.execWebsocket(ctx, endpoint, stdin, false, stdout, stderr, tty, terminalResize)
+}
+
+// execWebsocket streams input and outputs of the command provider runs for the request to the endpoint.
+// closeStdin closes stdin of the command once stdin reaches EOF
+func (c *client) execWebsocket(ctx context.Context, endpoint *url.URL,
+	stdin io.Reader,
+	closeStdin bool,
+	stdout io.Writer,
+	stderr io.Writer,
+	tty bool,
+	terminalResize <-chan remotecommand.TerminalSize) error {
 	subctx, subcancel := context.WithCancel(ctx)
 
 	rCl := c.newReqClient(ctx)
@@ -106,9 +119,15 @@ func (c *client) LeaseShell(ctx context.Context, lID mtypes.LeaseID, service str
 
 	if stdin != nil {
 		stdinWriter := wsutil.NewWsWriterWrapper(conn, LeaseShellCodeStdin, l)
+
+		var stdinCloser io.Writer
+		if closeStdin {
+			stdinCloser = wsutil.NewWsWriterWrapper(conn, LeaseShellCodeStdinClose, l)
+		}
+
 		// This goroutine is orphaned. There is no universal way to cancel a read from stdin
 		// at this time
-		go handleStdin(subctx, stdin, stdinWriter, saveError)
+		go handleStdin(subctx, stdin, stdinWriter, stdinCloser, saveError)
 	}
 
 	if tty && terminalResize != nil {
@@ -195,12 +214,19 @@ func processRemoteError(input io.Reader) error {
 	return nil
 }
 
-func handleStdin(ctx context.Context, input io.Reader, output io.Writer, saveError func(string, error)) {
+func handleStdin(ctx context.Context, input io.Reader, output io.Writer, closer io.Writer, saveError func(string, error)) {
 	data := make([]byte, 4096)
 
 	for {
 		n, err := input.Read(data)
 		if err != nil {
+			if errors.Is(err, io.EOF) && closer != nil {
+				if _, err = closer.Write([]byte{}); err != nil {
+					saveError("closing remote stdin", err)
+				}
+				return
+			}
+
 			saveError("reading from stdin", err)
 			return
 		}
@@ -39,7 +39,7 @@ func TestHandleStdinCopiesData(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	saveError := func(string, error) {}
 
-	handleStdin(ctx, reader, writer, saveError)
+	handleStdin(ctx, reader, writer, nil, saveError)
 	cancel()
 
 	require.Equal(t, writer.String(), testMsg)
@@ -54,7 +54,7 @@ func TestHandleStdinHalts(t *testing.T) {
 
 	cancel()
 	// Context is closed, so this just returns
-	handleStdin(ctx, reader, pipeOut, saveError)
+	handleStdin(ctx, reader, pipeOut, nil, saveError)
 
 	require.NoError(t, pipeOut.Close())
 	data, err := io.ReadAll(pipeIn)
@@ -7,4 +7,6 @@ const (
 	LeaseShellCodeFailure        = 103
 	LeaseShellCodeStdin          = 104
 	LeaseShellCodeTerminalResize = 105
+	// LeaseShellCodeStdinClose closes stdin of the remote command, e.g. once archive lease-cp uploads has been sent
+	LeaseShellCodeStdinClose = 106
 )
@@ -4,9 +4,11 @@ package rest
 import (
 	"context"
 	"crypto/tls"
+	"net/http"
 	"testing"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	gcontext "github.com/gorilla/context"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -22,6 +24,7 @@ import (
 	pcmock "github.com/akash-network/provider/cluster/mocks"
 	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	clmocks "github.com/akash-network/provider/cluster/types/v1beta3/mocks"
+	"github.com/akash-network/provider/gateway/shellaudit"
 	pmmock "github.com/akash-network/provider/manifest/mocks"
 	pmock "github.com/akash-network/provider/mocks"
 	"github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
@@ -206,6 +209,9 @@ func Test_router_LeaseStatus(t *testing.T) {
 		mocks := createMocks()
 
 		mockManifestGroups(mocks, id)
+		mocks.pcclient.On("LeaseProbeFailures", mock.Anything, id).Return(nil, nil)
+		// lease balance has not been checked yet
+		mocks.pclient.On("LeaseRunway", mock.Anything, id).Return(nil, nil)
 
 		withServer(t, paddr, mocks.pclient, mocks.qclient, nil, func(_ string) {
 			cert := testutil.Certificate(t, caddr, testutil.CertificateOptionMocks(mocks.qclient))
@@ -270,6 +276,7 @@ func Test_router_ServiceStatus(t *testing.T) {
 		mocks := createMocks()
 
 		mocks.pcclient.On("ServiceStatus", mock.Anything, id, service).Return(expected, nil)
+		mocks.pcclient.On("LeaseProbeFailures", mock.Anything, id).Return(nil, nil)
 		withServer(t, paddr, mocks.pclient, mocks.qclient, nil, func(_ string) {
 			cert := testutil.Certificate(t, caddr, testutil.CertificateOptionMocks(mocks.qclient))
 			client, err := NewClient(context.Background(), mocks.qclient, paddr, cert.Cert)
@@ -350,6 +357,21 @@ func withServer(
 ) {
 	t.Helper()
 
+	withAuditServer(t, addr, pclient, qclient, certs, nil, fn)
+}
+
+// withAuditServer runs server which logs shell sessions to audit, they are not logged if it is nil
+func withAuditServer(
+	t testing.TB,
+	addr sdk.Address,
+	pclient provider.Client,
+	qclient *qmock.QueryClient,
+	certs []tls.Certificate,
+	audit *shellaudit.Log,
+	fn func(string),
+) {
+	t.Helper()
+
 	// ctx := context.Background()
 	// kcfg := kfake.NewSimpleClientset()
 	//
@@ -362,8 +384,6 @@ func withServer(
 	// ctx = context.WithValue(ctx, fromctx.CtxKeyKubeClientSet, kubernetes.Interface(kc))
 	// ctx = context.WithValue(ctx, fromctx.CtxKeyAkashClientSet, akashclient.Interface(ac))
 	//
-	router := newRouter(testutil.Logger(t), addr, pclient, map[interface{}]interface{}{})
-
 	if len(certs) == 0 {
 		crt := testutil.Certificate(
 			t,
@@ -375,6 +395,16 @@ func withServer(
 		certs = append(certs, crt.Cert...)
 	}
 
+	router := newRouter(testutil.Logger(t), addr, pclient, map[interface{}]interface{}{}, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gcontext.Set(r, providerCertContextKey, certs[0])
+			if audit != nil {
+				gcontext.Set(r, shellAuditContextKey, audit)
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+
 	server := testutilrest.NewServer(t, qclient, router, certs)
 	defer server.Close()
 
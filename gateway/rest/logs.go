@@ -0,0 +1,263 @@
+package rest
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	cltypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+var (
+	// logMergeWindow is how long merged stream holds a line waiting for streams
+	// which have not sent a line yet before it gives up on ordering the line with them
+	logMergeWindow = time.Second
+	// logMergeBufferLines is the most lines merged stream holds, it bounds memory quiet streams can pin
+	logMergeBufferLines = 1024
+	// logWriteWait is how long client is given to accept a line before the stream is closed
+	logWriteWait = 10 * time.Second
+)
+
+// logStreamParams are parameters of the logs stream handled by the gateway itself
+type logStreamParams struct {
+	// merge orders lines of all streams by their timestamps
+	merge bool
+	// grep and regex drop lines which do not contain the substring and do not match the expression
+	grep  string
+	regex *regexp.Regexp
+}
+
+func parseLogStreamParams(req *http.Request) (logStreamParams, error) {
+	vars := req.URL.Query()
+	params := logStreamParams{
+		grep: vars.Get("grep"),
+	}
+
+	var err error
+
+	if val := vars.Get("merge"); val != "" {
+		if params.merge, err = strconv.ParseBool(val); err != nil {
+			return params, errors.Errorf("parameter \"merge\" contains invalid value")
+		}
+	}
+
+	if val := vars.Get("regex"); val != "" {
+		if params.regex, err = regexp.Compile(val); err != nil {
+			return params, errors.Errorf("parameter \"regex\" contains invalid expression: %s", err)
+		}
+	}
+
+	return params, nil
+}
+
+func (p logStreamParams) matches(line string) bool {
+	if p.grep != "" && !strings.Contains(line, p.grep) {
+		return false
+	}
+
+	if p.regex != nil && !p.regex.MatchString(line) {
+		return false
+	}
+
+	return true
+}
+
+// splitLogTimestamp splits timestamp kubernetes prefixes log lines with when requested
+func splitLogTimestamp(line string) (*time.Time, string) {
+	idx := strings.IndexByte(line, ' ')
+	if idx == -1 {
+		return nil, line
+	}
+
+	ts, err := time.Parse(time.RFC3339Nano, line[:idx])
+	if err != nil {
+		return nil, line
+	}
+
+	return &ts, line[idx+1:]
+}
+
+// scanServiceLog sends lines of the stream matching params to ch until stream ends or ctx is done,
+// and closure of the stream after them
+func scanServiceLog(ctx context.Context, lg *cltypes.ServiceLog, params logStreamParams, ch chan<- logMergeEvent, idx int) {
+	defer func() {
+		select {
+		case ch <- logMergeEvent{stream: idx}:
+		case <-ctx.Done():
+		}
+	}()
+
+	scan := lg.Scanner
+	if scan == nil {
+		scan = bufio.NewScanner(lg.Stream)
+	}
+
+	for scan.Scan() && ctx.Err() == nil {
+		msg := ServiceLogMessage{
+			Name:      lg.Name,
+			Message:   scan.Text(),
+			Service:   lg.Service,
+			Replica:   lg.Replica,
+			Container: lg.Container,
+			Stream:    lg.Output,
+		}
+
+		if params.merge {
+			msg.Timestamp, msg.Message = splitLogTimestamp(msg.Message)
+		}
+
+		if !params.matches(msg.Message) {
+			continue
+		}
+
+		select {
+		case ch <- logMergeEvent{stream: idx, msg: &msg}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// logMergeEvent carries line of the stream, or closure of the stream when msg is nil
+type logMergeEvent struct {
+	stream int
+	msg    *ServiceLogMessage
+}
+
+type logMergeLine struct {
+	msg      ServiceLogMessage
+	ts       time.Time
+	received time.Time
+}
+
+// logMerger orders lines of log streams by their timestamps.
+// lines of each stream are ordered already, so line is sent once every open stream has a line to compare it with,
+// it has been held for the merge window, or buffer is full. zero window sends lines in order they are received
+type logMerger struct {
+	window time.Duration
+	queues [][]logMergeLine
+	open   []bool
+	nopen  int
+	queued int
+}
+
+func newLogMerger(streams int, window time.Duration) *logMerger {
+	m := &logMerger{
+		window: window,
+		queues: make([][]logMergeLine, streams),
+		open:   make([]bool, streams),
+		nopen:  streams,
+	}
+
+	for i := range m.open {
+		m.open[i] = true
+	}
+
+	return m
+}
+
+func (m *logMerger) push(evt logMergeEvent, now time.Time) {
+	if evt.msg == nil {
+		if m.open[evt.stream] {
+			m.open[evt.stream] = false
+			m.nopen--
+		}
+		return
+	}
+
+	line := logMergeLine{
+		msg:      *evt.msg,
+		ts:       now,
+		received: now,
+	}
+
+	// lines without timestamp are ordered by time they have been received at
+	if evt.msg.Timestamp != nil {
+		line.ts = *evt.msg.Timestamp
+	}
+
+	m.queues[evt.stream] = append(m.queues[evt.stream], line)
+	m.queued++
+}
+
+// pop returns next line in order, if it is ready to be sent
+func (m *logMerger) pop(now time.Time) (ServiceLogMessage, bool) {
+	next := -1
+	complete := true
+
+	for i, queue := range m.queues {
+		if len(queue) == 0 {
+			if m.open[i] {
+				complete = false
+			}
+			continue
+		}
+
+		if next == -1 || queue[0].ts.Before(m.queues[next][0].ts) {
+			next = i
+		}
+	}
+
+	if next == -1 {
+		return ServiceLogMessage{}, false
+	}
+
+	line := m.queues[next][0]
+
+	if !complete && m.queued < logMergeBufferLines && now.Sub(line.received) < m.window {
+		return ServiceLogMessage{}, false
+	}
+
+	m.queues[next] = m.queues[next][1:]
+	m.queued--
+
+	return line.msg, true
+}
+
+func (m *logMerger) done() bool {
+	return m.nopen == 0 && m.queued == 0
+}
+
+// mergeLogStreams sends lines of streams received from in to out until every stream is closed or ctx is done.
+// lines are ordered by their timestamps within the window.
+// sending to out blocks, which in turn stops streams from being read when client does not keep up
+func mergeLogStreams(ctx context.Context, streams int, window time.Duration, in <-chan logMergeEvent, out chan<- ServiceLogMessage) {
+	m := newLogMerger(streams, window)
+
+	var tickch <-chan time.Time
+	if window > 0 {
+		ticker := time.NewTicker(window / 2)
+		defer ticker.Stop()
+
+		tickch = ticker.C
+	}
+
+	for !m.done() {
+		select {
+		case evt := <-in:
+			m.push(evt, time.Now())
+		case <-tickch:
+		case <-ctx.Done():
+			return
+		}
+
+		for {
+			msg, ready := m.pop(time.Now())
+			if !ready {
+				break
+			}
+
+			select {
+			case out <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
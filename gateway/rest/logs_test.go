@@ -0,0 +1,132 @@
+package rest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/akash-network/provider/cluster"
+)
+
+func logMergeTestEvent(stream int, ts time.Time, msg string) logMergeEvent {
+	return logMergeEvent{
+		stream: stream,
+		msg: &ServiceLogMessage{
+			Name:      "pod",
+			Message:   msg,
+			Timestamp: &ts,
+		},
+	}
+}
+
+func TestLogMergerOrdersStreams(t *testing.T) {
+	base := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Now()
+
+	m := newLogMerger(2, time.Second)
+
+	m.push(logMergeTestEvent(0, base.Add(2*time.Millisecond), "second"), now)
+	m.push(logMergeTestEvent(0, base.Add(3*time.Millisecond), "third"), now)
+
+	// line is held until every open stream has sent line to order it with
+	_, ready := m.pop(now)
+	require.False(t, ready)
+
+	m.push(logMergeTestEvent(1, base.Add(time.Millisecond), "first"), now)
+	m.push(logMergeTestEvent(1, base.Add(4*time.Millisecond), "fourth"), now)
+
+	for _, expected := range []string{"first", "second", "third"} {
+		msg, ready := m.pop(now)
+		require.True(t, ready)
+		require.Equal(t, expected, msg.Message)
+	}
+
+	// stream 0 is empty but open, remaining line is held until the window passes
+	_, ready = m.pop(now)
+	require.False(t, ready)
+
+	msg, ready := m.pop(now.Add(time.Second))
+	require.True(t, ready)
+	require.Equal(t, "fourth", msg.Message)
+
+	m.push(logMergeEvent{stream: 0}, now)
+	m.push(logMergeEvent{stream: 1}, now)
+	require.True(t, m.done())
+}
+
+func TestLogMergerBufferLimit(t *testing.T) {
+	prev := logMergeBufferLines
+	logMergeBufferLines = 2
+	defer func() {
+		logMergeBufferLines = prev
+	}()
+
+	base := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Now()
+
+	m := newLogMerger(2, time.Hour)
+
+	m.push(logMergeTestEvent(0, base, "first"), now)
+
+	_, ready := m.pop(now)
+	require.False(t, ready)
+
+	// quiet stream does not make merger hold more lines than the buffer fits
+	m.push(logMergeTestEvent(0, base.Add(time.Millisecond), "second"), now)
+
+	msg, ready := m.pop(now)
+	require.True(t, ready)
+	require.Equal(t, "first", msg.Message)
+
+	_, ready = m.pop(now)
+	require.False(t, ready)
+}
+
+func TestMergeLogStreamsFilters(t *testing.T) {
+	params, err := parseLogStreamParams(httptest.NewRequest(http.MethodGet, "/logs?merge=true&grep=error&regex=code%3D%5B0-9%5D%2B", nil))
+	require.NoError(t, err)
+	require.True(t, params.merge)
+
+	_, err = parseLogStreamParams(httptest.NewRequest(http.MethodGet, "/logs?regex=%28", nil))
+	require.Error(t, err)
+
+	logs := []string{
+		"2026-01-01T00:00:00.000000003Z error code=3\n2026-01-01T00:00:00.000000004Z info code=4\n",
+		"2026-01-01T00:00:00.000000001Z error code=1\n2026-01-01T00:00:00.000000002Z error without code\n",
+	}
+
+	ctx := context.Background()
+
+	evtch := make(chan logMergeEvent)
+	logch := make(chan ServiceLogMessage, 10)
+
+	for i, lg := range logs {
+		slog := cluster.NewServiceLog("web-0", io.NopCloser(strings.NewReader(lg)))
+		slog.Service = "web"
+		slog.Replica = i
+
+		go scanServiceLog(ctx, slog, params, evtch, i)
+	}
+
+	mergeLogStreams(ctx, len(logs), time.Second, evtch, logch)
+	close(logch)
+
+	var res []ServiceLogMessage
+	for msg := range logch {
+		res = append(res, msg)
+	}
+
+	require.Len(t, res, 2)
+	require.Equal(t, "error code=1", res[0].Message)
+	require.Equal(t, 1, res[0].Replica)
+	require.Equal(t, "error code=3", res[1].Message)
+	require.Equal(t, 0, res[1].Replica)
+	require.Equal(t, "web", res[1].Service)
+	require.Equal(t, time.Date(2026, time.January, 1, 0, 0, 0, 3, time.UTC), *res[1].Timestamp)
+}
@@ -2,6 +2,7 @@ package rest
 
 import (
 	"crypto/ecdsa"
+	"crypto/tls"
 	"net/http"
 	"strconv"
 	"strings"
@@ -18,6 +19,9 @@ import (
 	dtypes "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
 	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
 	mquery "github.com/akash-network/node/x/market/query"
+
+	cltypes "github.com/akash-network/provider/cluster/types/v1beta3"
+	"github.com/akash-network/provider/gateway/shellaudit"
 )
 
 type contextKey int
@@ -26,11 +30,13 @@ const (
 	leaseContextKey contextKey = iota + 1
 	deploymentContextKey
 	logFollowContextKey
-	tailLinesContextKey
 	serviceContextKey
 	ownerContextKey
 	providerContextKey
 	servicesContextKey
+	providerCertContextKey
+	logOptionsContextKey
+	shellAuditContextKey
 )
 
 func requestLeaseID(req *http.Request) mtypes.LeaseID {
@@ -41,8 +47,8 @@ func requestLogFollow(req *http.Request) bool {
 	return context.Get(req, logFollowContextKey).(bool)
 }
 
-func requestLogTailLines(req *http.Request) *int64 {
-	return context.Get(req, tailLinesContextKey).(*int64)
+func requestLogOptions(req *http.Request) cltypes.LeaseLogsOptions {
+	return context.Get(req, logOptionsContextKey).(cltypes.LeaseLogsOptions)
 }
 
 func requestService(req *http.Request) string {
@@ -57,6 +63,17 @@ func requestProvider(req *http.Request) sdk.Address {
 	return context.Get(req, providerContextKey).(sdk.Address)
 }
 
+func requestProviderCert(req *http.Request) (tls.Certificate, bool) {
+	cert, valid := context.Get(req, providerCertContextKey).(tls.Certificate)
+	return cert, valid
+}
+
+// requestShellAudit returns audit log of shell sessions, nil if sessions are not audited
+func requestShellAudit(req *http.Request) *shellaudit.Log {
+	audit, _ := context.Get(req, shellAuditContextKey).(*shellaudit.Log)
+	return audit
+}
+
 func requestOwner(req *http.Request) sdk.Address {
 	return context.Get(req, ownerContextKey).(sdk.Address)
 }
@@ -204,8 +221,48 @@ func requestStreamParams() mux.MiddlewareFunc {
 				tailLines = vl
 			}
 
+			logOpts := cltypes.LeaseLogsOptions{
+				Follow:    follow,
+				TailLines: tailLines,
+				Container: vars.Get("container"),
+			}
+
+			if val := vars.Get("since"); val != "" {
+				if err = logOpts.SetSince(val); err != nil {
+					return
+				}
+			}
+
+			if val := vars.Get("timestamps"); val != "" {
+				logOpts.Timestamps, err = strconv.ParseBool(val)
+				if err != nil {
+					return
+				}
+			}
+
+			if val := vars.Get("previous"); val != "" {
+				logOpts.Previous, err = strconv.ParseBool(val)
+				if err != nil {
+					return
+				}
+			}
+
+			if val := vars.Get("limitBytes"); val != "" {
+				limit := new(int64)
+				*limit, err = strconv.ParseInt(val, 10, 64)
+				if err != nil {
+					return
+				}
+
+				logOpts.LimitBytes = limit
+			}
+
+			if err = logOpts.Validate(); err != nil {
+				return
+			}
+
 			context.Set(req, logFollowContextKey, follow)
-			context.Set(req, tailLinesContextKey, tailLines)
+			context.Set(req, logOptionsContextKey, logOpts)
 			context.Set(req, servicesContextKey, services)
 
 			next.ServeHTTP(w, req)
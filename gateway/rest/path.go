@@ -26,6 +26,18 @@ func validatePath() string {
 	return "validate"
 }
 
+func bidHistoryPath() string {
+	return "bid-history"
+}
+
+func earningsPath() string {
+	return "earnings"
+}
+
+func shellSessionsPath() string {
+	return "shell-sessions"
+}
+
 func leasePath(id mtypes.LeaseID) string {
 	return fmt.Sprintf("lease/%d/%d/%d", id.DSeq, id.GSeq, id.OSeq)
 }
@@ -46,6 +58,38 @@ func leaseShellPath(lID mtypes.LeaseID) string {
 	return fmt.Sprintf("%s/shell", leasePath(lID))
 }
 
+func leaseCopyPath(lID mtypes.LeaseID) string {
+	return fmt.Sprintf("%s/cp", leasePath(lID))
+}
+
+func leasePortForwardPath(lID mtypes.LeaseID) string {
+	return fmt.Sprintf("%s/port-forward", leasePath(lID))
+}
+
+func leaseAttestationPath(id mtypes.LeaseID) string {
+	return fmt.Sprintf("%s/attestation", leasePath(id))
+}
+
+func leaseRevisionsPath(id mtypes.LeaseID) string {
+	return fmt.Sprintf("%s/revisions", leasePath(id))
+}
+
+func leaseRollbackPath(id mtypes.LeaseID) string {
+	return fmt.Sprintf("%s/rollback", leasePath(id))
+}
+
+func leasePausePath(id mtypes.LeaseID) string {
+	return fmt.Sprintf("%s/pause", leasePath(id))
+}
+
+func leaseResumePath(id mtypes.LeaseID) string {
+	return fmt.Sprintf("%s/resume", leasePath(id))
+}
+
+func leaseSnapshotsPath(id mtypes.LeaseID) string {
+	return fmt.Sprintf("%s/snapshots", leasePath(id))
+}
+
 func leaseEventsPath(id mtypes.LeaseID) string {
 	return fmt.Sprintf("%s/kubeevents", leasePath(id))
 }
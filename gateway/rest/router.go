@@ -1,7 +1,6 @@
 package rest
 
 import (
-	"bufio"
 	"context"
 	"crypto/ecdsa"
 	"encoding/json"
@@ -35,12 +34,16 @@ import (
 	"github.com/akash-network/node/util/wsutil"
 
 	"github.com/akash-network/provider"
+	"github.com/akash-network/provider/bidengine/journal"
+	"github.com/akash-network/provider/bidengine/policy"
 	"github.com/akash-network/provider/cluster"
 	"github.com/akash-network/provider/cluster/kube/builder"
 	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
 	cltypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	cip "github.com/akash-network/provider/cluster/types/v1beta3/clients/ip"
 	clfromctx "github.com/akash-network/provider/cluster/types/v1beta3/fromctx"
+	"github.com/akash-network/provider/gateway/shellaudit"
+	"github.com/akash-network/provider/ledger"
 	pmanifest "github.com/akash-network/provider/manifest"
 	"github.com/akash-network/provider/tools/fromctx"
 	"github.com/akash-network/provider/version"
@@ -73,7 +76,8 @@ type wsStreamConfig struct {
 	lid       mtypes.LeaseID
 	services  string
 	follow    bool
-	tailLines *int64
+	logOpts   cltypes.LeaseLogsOptions
+	logParams logStreamParams
 	log       log.Logger
 	client    cluster.ReadClient
 }
@@ -125,6 +129,24 @@ func newRouter(log log.Logger, addr sdk.Address, pclient provider.Client, ctxCon
 		validateHandler(log, pclient)).
 		Methods("GET")
 
+	// GET /bid-history
+	// orders provider has reserved resources and bid for. tenants see only their own orders
+	vrouter.HandleFunc("/bid-history",
+		bidHistoryHandler(log, pclient)).
+		Methods("GET")
+
+	// GET /earnings
+	// amounts provider has withdrawn from leases and leases have accrued. tenants see only their own leases
+	vrouter.HandleFunc("/earnings",
+		earningsHandler(log, pclient, addr)).
+		Methods("GET")
+
+	// GET /shell-sessions
+	// audit log of shell sessions opened into lease containers. tenants see only their own sessions
+	vrouter.HandleFunc("/shell-sessions",
+		shellSessionsHandler(log, addr)).
+		Methods("GET")
+
 	hostnameRouter := router.PathPrefix(hostnamePrefix).Subrouter()
 	hostnameRouter.Use(requireOwner())
 	hostnameRouter.HandleFunc(migratePathPrefix,
@@ -161,9 +183,39 @@ func newRouter(log log.Logger, addr sdk.Address, pclient provider.Client, ctxCon
 
 	// GET /lease/<lease-id>/status
 	lrouter.HandleFunc("/status",
-		leaseStatusHandler(log, pclient.Cluster(), ctxConfig)).
+		leaseStatusHandler(log, pclient.Cluster(), pclient, ctxConfig)).
+		Methods(http.MethodGet)
+
+	// GET /lease/<lease-id>/revisions
+	lrouter.HandleFunc("/revisions",
+		leaseRevisionsHandler(log, pclient.Cluster())).
+		Methods(http.MethodGet)
+
+	// POST /lease/<lease-id>/rollback
+	lrouter.HandleFunc("/rollback",
+		leaseRollbackHandler(log, pclient.ClusterService())).
+		Methods(http.MethodPost)
+
+	// POST /lease/<lease-id>/pause
+	lrouter.HandleFunc("/pause",
+		leasePauseHandler(log, pclient.Cluster())).
+		Methods(http.MethodPost)
+
+	// POST /lease/<lease-id>/resume
+	lrouter.HandleFunc("/resume",
+		leaseResumeHandler(log, pclient.Cluster())).
+		Methods(http.MethodPost)
+
+	// GET /lease/<lease-id>/snapshots
+	lrouter.HandleFunc("/snapshots",
+		leaseSnapshotsHandler(log, pclient.Cluster())).
 		Methods(http.MethodGet)
 
+	// POST /lease/<lease-id>/snapshots
+	lrouter.HandleFunc("/snapshots",
+		leaseSnapshotHandler(log, pclient.Cluster(), ctxConfig)).
+		Methods(http.MethodPost)
+
 	// GET /lease/<lease-id>/kubeevents
 	eventsRouter := lrouter.PathPrefix("/kubeevents").Subrouter()
 	eventsRouter.Use(
@@ -180,7 +232,7 @@ func newRouter(log log.Logger, addr sdk.Address, pclient provider.Client, ctxCon
 
 	// GET /lease/<lease-id>/logs
 	logRouter.HandleFunc("",
-		leaseLogsHandler(log, pclient.Cluster())).
+		leaseLogsHandler(log, pclient.Cluster(), ctxConfig)).
 		Methods("GET")
 
 	srouter := lrouter.PathPrefix("/service/{serviceName}").Subrouter()
@@ -197,6 +249,19 @@ func newRouter(log log.Logger, addr sdk.Address, pclient provider.Client, ctxCon
 	lrouter.HandleFunc("/shell",
 		leaseShellHandler(log, pclient.Cluster()))
 
+	// GET /lease/<lease-id>/cp
+	lrouter.HandleFunc("/cp",
+		leaseCopyHandler(log, pclient.Cluster()))
+
+	// GET /lease/<lease-id>/port-forward
+	lrouter.HandleFunc("/port-forward",
+		leasePortForwardHandler(log, pclient.Cluster()))
+
+	// GET /lease/<lease-id>/attestation
+	lrouter.HandleFunc("/attestation",
+		leaseAttestationHandler(log, pclient.Cluster(), ctxConfig)).
+		Methods(http.MethodGet)
+
 	return router
 }
 
@@ -373,118 +438,184 @@ func leaseShellHandler(log log.Logger, cclient cluster.Client) http.HandlerFunc
 		}
 		podIndex := uint(podIndex64)
 
-		upgrader := websocket.Upgrader{
-			ReadBufferSize:  0,
-			WriteBufferSize: 0,
-		}
+		leaseExecWebsocket(localLog, cclient, rw, req, leaseID, service, podIndex, cmd, connectStdin, isTty, true)
+	}
+}
 
-		shellWs, err := upgrader.Upgrade(rw, req, nil)
-		if err != nil {
-			// At this point the connection either has a response sent already
-			// or it has been closed
-			localLog.Error("failed handshake", "err", err)
-			return
-		}
+// leaseExecWebsocket runs cmd in the container of the service replica, streaming its input and outputs
+// over the websocket request is upgraded to. session is logged to the shell audit log when gateway has one,
+// its streams are recorded as well if record is set
+func leaseExecWebsocket(localLog log.Logger, cclient cluster.Client, rw http.ResponseWriter, req *http.Request,
+	leaseID mtypes.LeaseID, service string, podIndex uint, cmd []string, connectStdin bool, isTty bool, record bool) {
+	owner := requestOwner(req).String()
+
+	rec, err := requestShellAudit(req).Start(shellaudit.Session{
+		Owner:      owner,
+		LeaseID:    leaseID,
+		Service:    service,
+		PodIndex:   podIndex,
+		Command:    cmd,
+		TTY:        isTty,
+		Stdin:      connectStdin,
+		RemoteAddr: req.RemoteAddr,
+	}, record)
+	if err != nil {
+		// sessions which cannot be audited are refused
+		localLog.Error("starting shell audit", "err", err)
+		http.Error(rw, "shell session could not be audited", http.StatusInternalServerError)
+		return
+	}
 
-		var stdinPipeOut *io.PipeWriter
-		var stdinPipeIn *io.PipeReader
-		wg := &sync.WaitGroup{}
+	if rec != nil {
+		localLog = localLog.With("session", rec.Session().ID)
+		localLog.Info("lease shell started", "owner", owner, "service", service, "pod-index", podIndex, "cmd", strings.Join(cmd, " "))
+	}
 
-		var tsq remotecommand.TerminalSizeQueue
-		var terminalSizeUpdate chan remotecommand.TerminalSize
-		if isTty {
-			terminalSizeUpdate = make(chan remotecommand.TerminalSize, 1)
-			tsq = channelToTerminalSizeQueue(terminalSizeUpdate)
-		}
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  0,
+		WriteBufferSize: 0,
+	}
 
-		if connectStdin {
-			stdinPipeIn, stdinPipeOut = io.Pipe()
+	shellWs, err := upgrader.Upgrade(rw, req, nil)
+	if err != nil {
+		// At this point the connection either has a response sent already
+		// or it has been closed
+		localLog.Error("failed handshake", "err", err)
+		finishShellAudit(localLog, rec, nil, "failed handshake")
+		return
+	}
 
-			wg.Add(1)
-			go leaseShellWebsocketHandler(localLog, wg, shellWs, stdinPipeOut, terminalSizeUpdate)
-		}
+	var stdinPipeOut *io.PipeWriter
+	var stdinPipeIn *io.PipeReader
+	wg := &sync.WaitGroup{}
 
-		responseData := leaseShellResponse{}
-		l := &sync.Mutex{}
+	var tsq remotecommand.TerminalSizeQueue
+	var terminalSizeUpdate chan remotecommand.TerminalSize
+	if isTty {
+		terminalSizeUpdate = make(chan remotecommand.TerminalSize, 1)
+		tsq = rec.TerminalSizeQueue(channelToTerminalSizeQueue(terminalSizeUpdate))
+	}
 
-		resultWriter := wsutil.NewWsWriterWrapper(shellWs, LeaseShellCodeResult, l)
+	if connectStdin {
+		stdinPipeIn, stdinPipeOut = io.Pipe()
 
-		encodeData := true
+		wg.Add(1)
+		go leaseShellWebsocketHandler(localLog, wg, shellWs, stdinPipeOut, terminalSizeUpdate)
+	}
 
-		status, err := cclient.ServiceStatus(req.Context(), leaseID, service)
-		if err != nil {
-			if cluster.ErrorIsOkToSendToClient(err) || errors.Is(err, kubeclienterrors.ErrNoServiceForLease) {
-				responseData.Message = err.Error()
-			} else {
-				http.Error(rw, err.Error(), http.StatusInternalServerError)
-			}
-		}
+	responseData := leaseShellResponse{}
+	l := &sync.Mutex{}
+
+	resultWriter := wsutil.NewWsWriterWrapper(shellWs, LeaseShellCodeResult, l)
+
+	encodeData := true
 
-		if err == nil && status.ReadyReplicas == 0 {
-			err = errors.New("no active replicase for service")
+	status, err := cclient.ServiceStatus(req.Context(), leaseID, service)
+	if err != nil {
+		if cluster.ErrorIsOkToSendToClient(err) || errors.Is(err, kubeclienterrors.ErrNoServiceForLease) {
 			responseData.Message = err.Error()
+		} else {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
 		}
+	}
 
-		if err == nil {
-			stdout := wsutil.NewWsWriterWrapper(shellWs, LeaseShellCodeStdout, l)
-			stderr := wsutil.NewWsWriterWrapper(shellWs, LeaseShellCodeStderr, l)
+	if err == nil && status.ReadyReplicas == 0 {
+		err = errors.New("no active replicase for service")
+		responseData.Message = err.Error()
+	}
 
-			subctx, subcancel := context.WithCancel(req.Context())
-			wg.Add(1)
-			go leaseShellPingHandler(subctx, wg, shellWs)
+	var exitCode *int
 
-			var stdinForExec io.Reader
-			if connectStdin {
-				stdinForExec = stdinPipeIn
-			}
-			result, err := cclient.Exec(subctx, leaseID, service, podIndex, cmd, stdinForExec, stdout, stderr, isTty, tsq)
-			subcancel()
+	if err == nil {
+		var stdout, stderr io.Writer = wsutil.NewWsWriterWrapper(shellWs, LeaseShellCodeStdout, l),
+			wsutil.NewWsWriterWrapper(shellWs, LeaseShellCodeStderr, l)
+
+		if rec != nil {
+			stdout = io.MultiWriter(stdout, rec.Output())
+			stderr = io.MultiWriter(stderr, rec.Output())
+		}
 
-			if result != nil {
-				responseData.ExitCode = result.ExitCode()
+		subctx, subcancel := context.WithCancel(req.Context())
+		wg.Add(1)
+		go leaseShellPingHandler(subctx, wg, shellWs)
 
-				localLog.Info("lease shell completed", "exitcode", result.ExitCode())
-			} else {
-				if cluster.ErrorIsOkToSendToClient(err) {
-					responseData.Message = err.Error()
-				} else {
-					resultWriter = wsutil.NewWsWriterWrapper(shellWs, LeaseShellCodeFailure, l)
-					// Don't return errors like this to the client, they could contain information
-					// that should not be let out
-					encodeData = false
-
-					localLog.Error("lease exec failed", "err", err)
-				}
+		var stdinForExec io.Reader
+		if connectStdin {
+			stdinForExec = stdinPipeIn
+			if rec != nil {
+				stdinForExec = io.TeeReader(stdinPipeIn, rec.Input())
 			}
 		}
+		result, err := cclient.Exec(subctx, leaseID, service, podIndex, cmd, stdinForExec, stdout, stderr, isTty, tsq)
+		subcancel()
+
+		if result != nil {
+			responseData.ExitCode = result.ExitCode()
+			exitCode = &responseData.ExitCode
 
-		if encodeData {
-			encoder := json.NewEncoder(resultWriter)
-			err = encoder.Encode(responseData)
+			localLog.Info("lease shell completed", "exitcode", result.ExitCode())
 		} else {
-			// Just send an empty message so the remote knows things are over
-			_, err = resultWriter.Write([]byte{})
+			if cluster.ErrorIsOkToSendToClient(err) {
+				responseData.Message = err.Error()
+			} else {
+				resultWriter = wsutil.NewWsWriterWrapper(shellWs, LeaseShellCodeFailure, l)
+				// Don't return errors like this to the client, they could contain information
+				// that should not be let out
+				encodeData = false
+
+				localLog.Error("lease exec failed", "err", err)
+			}
 		}
+	}
 
-		_ = shellWs.Close()
+	if encodeData {
+		encoder := json.NewEncoder(resultWriter)
+		err = encoder.Encode(responseData)
+	} else {
+		// Just send an empty message so the remote knows things are over
+		_, err = resultWriter.Write([]byte{})
+	}
 
-		if err != nil {
-			localLog.Error("failed writing response to client after exec", "err", err)
-		}
+	_ = shellWs.Close()
+
+	if err != nil {
+		localLog.Error("failed writing response to client after exec", "err", err)
+	}
 
-		wg.Wait()
+	wg.Wait()
 
-		if stdinPipeOut != nil {
-			_ = stdinPipeOut.Close()
-		}
-		if stdinPipeIn != nil {
-			_ = stdinPipeIn.Close()
-		}
+	if stdinPipeOut != nil {
+		_ = stdinPipeOut.Close()
+	}
+	if stdinPipeIn != nil {
+		_ = stdinPipeIn.Close()
+	}
 
-		if terminalSizeUpdate != nil {
-			close(terminalSizeUpdate)
-		}
+	if terminalSizeUpdate != nil {
+		close(terminalSizeUpdate)
+	}
+
+	// internal errors are not sent to the client, nor are they put into the audit log tenant can list
+	auditMessage := responseData.Message
+	if exitCode == nil && auditMessage == "" {
+		auditMessage = "provider error"
+	}
+
+	finishShellAudit(localLog, rec, exitCode, auditMessage)
+}
+
+func finishShellAudit(log log.Logger, rec *shellaudit.Recorder, exitCode *int, errMsg string) {
+	if rec == nil {
+		return
+	}
+
+	if err := rec.Finish(exitCode, errMsg); err != nil {
+		log.Error("finishing shell audit", "err", err)
+		return
 	}
+
+	sess := rec.Session()
+	log.Info("lease shell finished", "owner", sess.Owner, "duration", sess.EndedAt.Sub(sess.StartedAt), "recording", sess.Recording)
 }
 
 func createAddressHandler(log log.Logger, providerAddr sdk.Address) http.HandlerFunc {
@@ -567,6 +698,115 @@ func validateHandler(log log.Logger, cl provider.ValidateClient) http.HandlerFun
 	}
 }
 
+func bidHistoryHandler(log log.Logger, cl provider.BidHistoryClient) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		vars := req.URL.Query()
+
+		opts := journal.ListOptions{
+			Owner: vars.Get("owner"),
+		}
+
+		if val := vars.Get("limit"); val != "" {
+			limit, err := strconv.ParseUint(val, 10, 31)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			opts.Limit = int(limit)
+		}
+
+		if val := vars.Get("in_flight"); val != "" {
+			inFlight, err := strconv.ParseBool(val)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			opts.InFlight = inFlight
+		}
+
+		entries, err := cl.BidHistory(req.Context(), requestOwner(req), opts)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if entries == nil {
+			entries = []journal.Entry{}
+		}
+
+		writeJSON(log, w, entries)
+	}
+}
+
+func earningsHandler(log log.Logger, cl provider.EarningsClient, providerAddr sdk.Address) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		vars := req.URL.Query()
+
+		query := ledger.Query{
+			Owner: vars.Get("owner"),
+		}
+
+		if owner := requestOwner(req); owner.String() != providerAddr.String() {
+			query.Owner = owner.String()
+		}
+
+		if val := vars.Get("dseq"); val != "" {
+			dseq, err := strconv.ParseUint(val, 10, 64)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if query.Owner == "" {
+				http.Error(w, "parameter dseq requires owner", http.StatusBadRequest)
+				return
+			}
+
+			query.DSeq = dseq
+		}
+
+		if val := vars.Get("from"); val != "" {
+			from, err := time.Parse(time.RFC3339, val)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			query.From = from
+		}
+
+		if val := vars.Get("to"); val != "" {
+			to, err := time.Parse(time.RFC3339, val)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			query.To = to
+		}
+
+		if val := vars.Get("withdrawals"); val != "" {
+			withdrawals, err := strconv.ParseBool(val)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			query.Withdrawals = withdrawals
+		}
+
+		report, err := cl.Earnings(req.Context(), requestOwner(req), query)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(log, w, report)
+	}
+}
+
 func createManifestHandler(log log.Logger, mclient pmanifest.Client) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		var mani manifest.Manifest
@@ -587,10 +827,18 @@ func createManifestHandler(log log.Logger, mclient pmanifest.Client) http.Handle
 				http.Error(w, err.Error(), http.StatusUnprocessableEntity)
 				return
 			}
+			if errors.Is(err, pmanifest.ErrInvalidServiceEnv) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
 			if errors.Is(err, pmanifest.ErrNoLeaseForDeployment) {
 				http.Error(w, err.Error(), http.StatusNotFound)
 				return
 			}
+			if errors.Is(err, policy.ErrDeclined) {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
 			log.Error("manifest submit failed", "err", err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -645,7 +893,7 @@ func leaseKubeEventsHandler(log log.Logger, cclient cluster.ReadClient) http.Han
 	}
 }
 
-func leaseStatusHandler(log log.Logger, cclient cluster.ReadClient, clusterSettings map[interface{}]interface{}) http.HandlerFunc {
+func leaseStatusHandler(log log.Logger, cclient cluster.ReadClient, rclient provider.LeaseRunwayClient, clusterSettings map[interface{}]interface{}) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		ctx := fromctx.ApplyToContext(req.Context(), clusterSettings)
 
@@ -741,13 +989,37 @@ func leaseStatusHandler(log log.Logger, cclient cluster.ReadClient, clusterSetti
 			return
 		}
 
+		setRecentProbeFailures(ctx, log, cclient, leaseID, result.Services)
+
+		result.Funds, err = rclient.LeaseRunway(ctx, leaseID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
 		writeJSON(log, w, result)
 	}
 }
 
+// setRecentProbeFailures adds probes recently reported failing to service statuses.
+// status is still reported if probes can't be checked
+func setRecentProbeFailures(ctx context.Context, log log.Logger, cclient cluster.ReadClient, leaseID mtypes.LeaseID, statuses map[string]*cltypes.ServiceStatus) {
+	failures, err := cclient.LeaseProbeFailures(ctx, leaseID)
+	if err != nil {
+		log.Error("checking probe failures", "lease", leaseID, "err", err)
+		return
+	}
+
+	for name, status := range statuses {
+		status.RecentProbeFailures = failures[name]
+	}
+}
+
 func leaseServiceStatusHandler(log log.Logger, cclient cluster.ReadClient) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		status, err := cclient.ServiceStatus(req.Context(), requestLeaseID(req), requestService(req))
+		leaseID := requestLeaseID(req)
+
+		status, err := cclient.ServiceStatus(req.Context(), leaseID, requestService(req))
 		if err != nil {
 			if errors.Is(err, kubeclienterrors.ErrNoDeploymentForLease) {
 				http.Error(w, err.Error(), http.StatusNotFound)
@@ -764,12 +1036,21 @@ func leaseServiceStatusHandler(log log.Logger, cclient cluster.ReadClient) http.
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+
+		setRecentProbeFailures(req.Context(), log, cclient, leaseID, map[string]*cltypes.ServiceStatus{requestService(req): status})
+
 		writeJSON(log, w, status)
 	}
 }
 
-func leaseLogsHandler(log log.Logger, cclient cluster.ReadClient) http.HandlerFunc {
+func leaseLogsHandler(log log.Logger, cclient cluster.ReadClient, clusterSettings map[interface{}]interface{}) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		params, err := parseLogStreamParams(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
 		upgrader := websocket.Upgrader{
 			ReadBufferSize:  1024,
 			WriteBufferSize: 1024,
@@ -782,11 +1063,11 @@ func leaseLogsHandler(log log.Logger, cclient cluster.ReadClient) http.HandlerFu
 			return
 		}
 
-		wsLogWriter(r.Context(), ws, wsStreamConfig{
+		wsLogWriter(fromctx.ApplyToContext(r.Context(), clusterSettings), ws, wsStreamConfig{
 			lid:       requestLeaseID(r),
 			services:  requestServices(r),
-			follow:    requestLogFollow(r),
-			tailLines: requestLogTailLines(r),
+			logOpts:   requestLogOptions(r),
+			logParams: params,
 			log:       log,
 			client:    cclient,
 		})
@@ -836,7 +1117,14 @@ func wsLogWriter(ctx context.Context, ws *websocket.Conn, cfg wsStreamConfig) {
 		_ = ws.Close()
 	}()
 
-	logs, err := cfg.client.LeaseLogs(cctx, cfg.lid, cfg.services, cfg.follow, cfg.tailLines)
+	window := time.Duration(0)
+	if cfg.logParams.merge {
+		// lines are ordered by timestamps kubernetes prefixes them with
+		cfg.logOpts.Timestamps = true
+		window = logMergeWindow
+	}
+
+	logs, err := cfg.client.LeaseLogs(cctx, cfg.lid, cfg.services, cfg.logOpts)
 	if err != nil {
 		cfg.log.Error("couldn't fetch logs", "error", err.Error())
 		err = ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocketInternalServerErrorCode, ""))
@@ -859,26 +1147,22 @@ func wsLogWriter(ctx context.Context, ws *websocket.Conn, cfg wsStreamConfig) {
 
 	var scanners sync.WaitGroup
 
+	evtch := make(chan logMergeEvent)
 	logch := make(chan ServiceLogMessage)
 
 	scanners.Add(len(logs))
 
-	for _, lg := range logs {
-		go func(name string, scan *bufio.Scanner) {
+	for i, lg := range logs {
+		go func(idx int, lg *cltypes.ServiceLog) {
 			defer scanners.Done()
-
-			for scan.Scan() && ctx.Err() == nil {
-				logch <- ServiceLogMessage{
-					Name:    name,
-					Message: scan.Text(),
-				}
-			}
-		}(lg.Name, lg.Scanner)
+			scanServiceLog(cctx, lg, cfg.logParams, evtch, idx)
+		}(i, lg)
 	}
 
 	donech := make(chan struct{})
 
 	go func() {
+		mergeLogStreams(cctx, len(logs), window, evtch, logch)
 		scanners.Wait()
 		close(donech)
 	}()
@@ -887,6 +1171,10 @@ done:
 	for {
 		select {
 		case line := <-logch:
+			// client which does not keep up is disconnected rather than having lines pile up for it
+			if err = ws.SetWriteDeadline(time.Now().Add(logWriteWait)); err != nil {
+				break done
+			}
 			if err = ws.WriteJSON(line); err != nil {
 				break done
 			}
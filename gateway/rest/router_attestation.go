@@ -0,0 +1,103 @@
+package rest
+
+import (
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	kubeErrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/akash-network/provider/cluster"
+	cltypes "github.com/akash-network/provider/cluster/types/v1beta3"
+	"github.com/akash-network/provider/tools/fromctx"
+)
+
+func leaseAttestationHandler(log log.Logger, cclient cluster.Client, clusterSettings map[interface{}]interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		ctx := fromctx.ApplyToContext(req.Context(), clusterSettings)
+
+		leaseID := requestLeaseID(req)
+		vars := req.URL.Query()
+
+		service := vars.Get("service")
+		if len(service) == 0 {
+			http.Error(w, "missing parameter service", http.StatusBadRequest)
+			return
+		}
+
+		podIndex := uint64(0)
+		if val := vars.Get("podIndex"); len(val) != 0 {
+			var err error
+			if podIndex, err = strconv.ParseUint(val, 0, 31); err != nil {
+				http.Error(w, "parameter podIndex invalid", http.StatusBadRequest)
+				return
+			}
+		}
+
+		areq := cltypes.AttestationRequest{}
+
+		var err error
+		if areq.Nonce, err = hex.DecodeString(vars.Get("nonce")); err != nil {
+			http.Error(w, "parameter nonce must be hex encoded", http.StatusBadRequest)
+			return
+		}
+
+		if val := vars.Get("pcrs"); len(val) != 0 {
+			for _, sidx := range strings.Split(val, ",") {
+				idx, err := strconv.ParseUint(strings.TrimSpace(sidx), 10, 8)
+				if err != nil {
+					http.Error(w, "parameter pcrs invalid", http.StatusBadRequest)
+					return
+				}
+
+				areq.PCRs = append(areq.PCRs, uint(idx))
+			}
+		}
+
+		if err = areq.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		cert, valid := requestProviderCert(req)
+		if !valid {
+			log.Error("provider certificate is not available to sign attestation")
+			http.Error(w, "attestation is not available", http.StatusServiceUnavailable)
+			return
+		}
+
+		quote, err := cclient.LeaseAttestation(ctx, leaseID, service, uint(podIndex), areq)
+		if err != nil {
+			switch {
+			case errors.Is(err, cluster.ErrExecNoServiceWithName),
+				kubeErrors.IsNotFound(err):
+				http.Error(w, err.Error(), http.StatusNotFound)
+			case errors.Is(err, cluster.ErrExecPodIndexOutOfRange),
+				errors.Is(err, cluster.ErrAttestationNotEnabled),
+				errors.Is(err, cltypes.ErrAttestation):
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			case errors.Is(err, cluster.ErrExecServiceNotRunning):
+				http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			case errors.Is(err, cluster.ErrAttestationSidecar):
+				http.Error(w, err.Error(), http.StatusBadGateway)
+			default:
+				log.Error("lease attestation failed", "lease", leaseID, "err", err)
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+
+		result, err := newLeaseAttestation(leaseID, areq.Nonce, *quote, cert)
+		if err != nil {
+			log.Error("signing lease attestation", "lease", leaseID, "err", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(log, w, result)
+	}
+}
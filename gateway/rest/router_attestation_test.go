@@ -0,0 +1,158 @@
+package rest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/akash-network/akash-api/go/testutil"
+
+	"github.com/akash-network/provider/cluster"
+	cltypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+// testAttestationQuote builds quote the way TPM2_Quote does over sha256 bank of given PCRs
+func testAttestationQuote(t *testing.T, nonce []byte, pcrs map[uint][]byte) cltypes.AttestationQuote {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	akPublic, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	quote := cltypes.AttestationQuote{
+		HashAlg:  "sha256",
+		AKPublic: akPublic,
+		PCRs:     pcrs,
+	}
+
+	bitmap := make([]byte, 3)
+	var values []byte
+	for _, idx := range quote.SortedPCRs() {
+		bitmap[idx/8] |= 1 << (idx % 8)
+		values = append(values, pcrs[idx]...)
+	}
+
+	pcrDigest := sha256.Sum256(values)
+
+	var buf []byte
+	buf = binary.BigEndian.AppendUint32(buf, 0xff544347)
+	buf = binary.BigEndian.AppendUint16(buf, 0x8018)
+	buf = binary.BigEndian.AppendUint16(buf, 0) // qualifiedSigner
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(nonce)))
+	buf = append(buf, nonce...)
+	buf = append(buf, make([]byte, 17+8)...) // clockInfo, firmwareVersion
+	buf = binary.BigEndian.AppendUint32(buf, 1)
+	buf = binary.BigEndian.AppendUint16(buf, 0x000b)
+	buf = append(buf, byte(len(bitmap)))
+	buf = append(buf, bitmap...)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(pcrDigest)))
+	buf = append(buf, pcrDigest[:]...)
+
+	quote.Quote = buf
+
+	digest := sha256.Sum256(buf)
+	quote.Signature, err = ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+
+	return quote
+}
+
+func TestRouteLeaseAttestationOK(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		leaseID := testutil.LeaseID(t)
+		leaseID.Owner = test.caddr.String()
+		leaseID.Provider = test.paddr.String()
+
+		nonce := []byte("0123456789abcdef")
+		quote := testAttestationQuote(t, nonce, map[uint][]byte{
+			0: make([]byte, sha256.Size),
+			7: []byte("0123456789abcdef0123456789abcdef"),
+		})
+		quote.Service = serviceName
+
+		test.pcclient.On("LeaseAttestation", mock.Anything, leaseID, serviceName, uint(0), cltypes.AttestationRequest{
+			Nonce: nonce,
+			PCRs:  []uint{0, 7},
+		}).Return(&quote, nil)
+
+		attestation, err := test.gwclient.LeaseAttestation(context.Background(), leaseID, serviceName, 0, nonce, []uint{0, 7})
+		require.NoError(t, err)
+		require.NoError(t, attestation.Verify(leaseID, nonce))
+
+		// quote is bound to the nonce and lease it has been requested for
+		require.ErrorIs(t, attestation.Verify(leaseID, []byte("fedcba9876543210")), ErrAttestationBinding)
+
+		otherLease := leaseID
+		otherLease.OSeq++
+		require.ErrorIs(t, attestation.Verify(otherLease, nonce), ErrAttestationBinding)
+
+		// provider signature covers reported PCR values
+		attestation.Quote.PCRs[7] = make([]byte, sha256.Size)
+		require.ErrorIs(t, attestation.Verify(leaseID, nonce), ErrAttestationBinding)
+	})
+}
+
+func TestRouteLeaseAttestationNotEnabled(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		leaseID := testutil.LeaseID(t)
+		leaseID.Owner = test.caddr.String()
+		leaseID.Provider = test.paddr.String()
+
+		nonce := []byte("0123456789abcdef")
+
+		test.pcclient.On("LeaseAttestation", mock.Anything, leaseID, serviceName, uint(0), mock.Anything).
+			Return(nil, cluster.ErrAttestationNotEnabled)
+
+		_, err := test.gwclient.LeaseAttestation(context.Background(), leaseID, serviceName, 0, nonce, nil)
+		require.Error(t, err)
+
+		var cerr ClientResponseError
+		require.ErrorAs(t, err, &cerr)
+		require.Equal(t, http.StatusBadRequest, cerr.Status)
+
+		// nonce too short to be accepted
+		_, err = test.gwclient.LeaseAttestation(context.Background(), leaseID, serviceName, 0, []byte("abc"), nil)
+		require.ErrorAs(t, err, &cerr)
+		require.Equal(t, http.StatusBadRequest, cerr.Status)
+	})
+}
+
+func TestAttestationQuoteVerify(t *testing.T) {
+	nonce := []byte("0123456789abcdef")
+	quote := testAttestationQuote(t, nonce, map[uint][]byte{
+		0: make([]byte, sha256.Size),
+		1: make([]byte, sha256.Size),
+	})
+
+	require.NoError(t, quote.Verify(nonce))
+	require.ErrorIs(t, quote.Verify([]byte("fedcba9876543210")), cltypes.ErrAttestationNonce)
+
+	tampered := quote
+	tampered.PCRs = map[uint][]byte{
+		0: make([]byte, sha256.Size),
+		1: []byte("0123456789abcdef0123456789abcdef"),
+	}
+	require.ErrorIs(t, tampered.Verify(nonce), cltypes.ErrAttestationPCRs)
+
+	tampered = quote
+	tampered.PCRs = map[uint][]byte{
+		0: make([]byte, sha256.Size),
+	}
+	require.ErrorIs(t, tampered.Verify(nonce), cltypes.ErrAttestationPCRs)
+
+	tampered = quote
+	tampered.Signature = append([]byte{}, quote.Signature...)
+	tampered.Signature[len(tampered.Signature)-1] ^= 0xff
+	require.ErrorIs(t, tampered.Verify(nonce), cltypes.ErrAttestationSignature)
+}
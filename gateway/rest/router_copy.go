@@ -0,0 +1,77 @@
+package rest
+
+import (
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/akash-network/provider/cluster"
+)
+
+const (
+	LeaseCopyUpload   = "upload"
+	LeaseCopyDownload = "download"
+)
+
+// leaseCopyCommand returns tar command streaming archive of the path in the container out of it on download,
+// or extracting archive streamed into it to the path on upload
+func leaseCopyCommand(direction string, fpath string) ([]string, bool) {
+	// relative name keeps tar from taking names starting with dash for its options
+	dir, name := path.Split(fpath)
+	name = "./" + name
+
+	switch direction {
+	case LeaseCopyUpload:
+		return []string{"tar", "xf", "-", "-C", fpath}, true
+	case LeaseCopyDownload:
+		if fpath == "/" {
+			dir, name = "/", "."
+		}
+
+		return []string{"tar", "cf", "-", "-C", dir, name}, false
+	}
+
+	return nil, false
+}
+
+func leaseCopyHandler(log log.Logger, cclient cluster.Client) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		leaseID := requestLeaseID(req)
+
+		localLog := log.With("lease", leaseID.String(), "action", "cp")
+
+		vars := req.URL.Query()
+
+		service := vars.Get("service")
+		if len(service) == 0 {
+			http.Error(rw, "missing parameter service", http.StatusBadRequest)
+			return
+		}
+
+		podIndex := uint64(0)
+		if val := vars.Get("podIndex"); len(val) != 0 {
+			var err error
+			if podIndex, err = strconv.ParseUint(val, 0, 31); err != nil {
+				http.Error(rw, "parameter podIndex invalid", http.StatusBadRequest)
+				return
+			}
+		}
+
+		fpath := vars.Get("path")
+		if !path.IsAbs(fpath) {
+			http.Error(rw, "parameter path must be absolute", http.StatusBadRequest)
+			return
+		}
+
+		cmd, connectStdin := leaseCopyCommand(vars.Get("direction"), path.Clean(fpath))
+		if len(cmd) == 0 {
+			http.Error(rw, "parameter direction must be either upload or download", http.StatusBadRequest)
+			return
+		}
+
+		// copies are audited, archives they stream are not recorded
+		leaseExecWebsocket(localLog, cclient, rw, req, leaseID, service, uint(podIndex), cmd, connectStdin, false, false)
+	}
+}
@@ -0,0 +1,28 @@
+package rest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaseCopyCommand(t *testing.T) {
+	tests := []struct {
+		direction string
+		path      string
+		cmd       []string
+		stdin     bool
+	}{
+		{LeaseCopyUpload, "/data", []string{"tar", "xf", "-", "-C", "/data"}, true},
+		{LeaseCopyDownload, "/data/logs", []string{"tar", "cf", "-", "-C", "/data/", "./logs"}, false},
+		{LeaseCopyDownload, "/-rf", []string{"tar", "cf", "-", "-C", "/", "./-rf"}, false},
+		{LeaseCopyDownload, "/", []string{"tar", "cf", "-", "-C", "/", "."}, false},
+		{"sideways", "/data", nil, false},
+	}
+
+	for _, tt := range tests {
+		cmd, stdin := leaseCopyCommand(tt.direction, tt.path)
+		require.Equal(t, tt.cmd, cmd, "%s %s", tt.direction, tt.path)
+		require.Equal(t, tt.stdin, stdin, "%s %s", tt.direction, tt.path)
+	}
+}
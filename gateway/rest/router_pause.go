@@ -0,0 +1,43 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/akash-network/provider/cluster"
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
+)
+
+func leasePauseHandler(log log.Logger, cclient cluster.Client) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		lid := requestLeaseID(req)
+
+		if err := cclient.PauseLease(req.Context(), lid); err != nil {
+			log.Error("lease pause failed", "lease", lid, "err", err)
+			http.Error(w, err.Error(), pauseErrorStatus(err))
+			return
+		}
+	}
+}
+
+func leaseResumeHandler(log log.Logger, cclient cluster.Client) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		lid := requestLeaseID(req)
+
+		if err := cclient.ResumeLease(req.Context(), lid); err != nil {
+			log.Error("lease resume failed", "lease", lid, "err", err)
+			http.Error(w, err.Error(), pauseErrorStatus(err))
+			return
+		}
+	}
+}
+
+func pauseErrorStatus(err error) int {
+	if errors.Is(err, kubeclienterrors.ErrLeaseNotFound) {
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,176 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/akash-network/provider/cluster"
+)
+
+const (
+	// portForwardCloseWait is how long the peer is given to receive close message of the port forward websocket
+	portForwardCloseWait = 5 * time.Second
+	// portForwardMaxCloseReason is the most bytes reason of close message fits
+	portForwardMaxCloseReason = 123
+)
+
+// wsPortForwardStream carries data of port forwarded connection in binary messages of the websocket.
+// empty message tells the peer no more data is sent, while data still can be received
+type wsPortForwardStream struct {
+	ws     *websocket.Conn
+	reader io.Reader
+	// empty is set until data of the message being read is received
+	empty bool
+}
+
+func newWsPortForwardStream(ws *websocket.Conn) *wsPortForwardStream {
+	return &wsPortForwardStream{
+		ws: ws,
+	}
+}
+
+func (s *wsPortForwardStream) Read(buf []byte) (int, error) {
+	for {
+		if s.reader == nil {
+			msgType, reader, err := s.ws.NextReader()
+			if err != nil {
+				if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+					return 0, io.EOF
+				}
+				return 0, err
+			}
+
+			// Just ignore anything not a binary message
+			if msgType != websocket.BinaryMessage {
+				continue
+			}
+
+			s.reader = reader
+			s.empty = true
+		}
+
+		n, err := s.reader.Read(buf)
+		if n > 0 {
+			s.empty = false
+		}
+
+		if errors.Is(err, io.EOF) {
+			s.reader = nil
+			if s.empty {
+				return 0, io.EOF
+			}
+
+			if n == 0 {
+				continue
+			}
+
+			err = nil
+		}
+
+		return n, err
+	}
+}
+
+func (s *wsPortForwardStream) Write(buf []byte) (int, error) {
+	if len(buf) == 0 {
+		return 0, nil
+	}
+
+	if err := s.ws.WriteMessage(websocket.BinaryMessage, buf); err != nil {
+		return 0, err
+	}
+
+	return len(buf), nil
+}
+
+// CloseWrite tells the peer no more data is sent
+func (s *wsPortForwardStream) CloseWrite() error {
+	return s.ws.WriteMessage(websocket.BinaryMessage, nil)
+}
+
+func leasePortForwardHandler(log log.Logger, cclient cluster.Client) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		leaseID := requestLeaseID(req)
+
+		vars := req.URL.Query()
+
+		service := vars.Get("service")
+		if len(service) == 0 {
+			http.Error(rw, "missing parameter service", http.StatusBadRequest)
+			return
+		}
+
+		podIndex := uint64(0)
+		if val := vars.Get("podIndex"); len(val) != 0 {
+			var err error
+			if podIndex, err = strconv.ParseUint(val, 0, 31); err != nil {
+				http.Error(rw, "parameter podIndex invalid", http.StatusBadRequest)
+				return
+			}
+		}
+
+		port, err := strconv.ParseUint(vars.Get("port"), 10, 16)
+		if err != nil || port == 0 {
+			http.Error(rw, "parameter port must be between 1 and 65535", http.StatusBadRequest)
+			return
+		}
+
+		localLog := log.With("lease", leaseID.String(), "service", service, "port", port, "action", "port-forward")
+
+		upgrader := websocket.Upgrader{
+			ReadBufferSize:  0,
+			WriteBufferSize: 0,
+		}
+
+		ws, err := upgrader.Upgrade(rw, req, nil)
+		if err != nil {
+			// At this point the connection either has a response sent already
+			// or it has been closed
+			localLog.Error("failed handshake", "err", err)
+			return
+		}
+
+		defer func() {
+			_ = ws.Close()
+		}()
+
+		ctx, cancel := context.WithCancel(req.Context())
+		wg := &sync.WaitGroup{}
+
+		wg.Add(1)
+		go leaseShellPingHandler(ctx, wg, ws)
+
+		err = cclient.PortForward(ctx, leaseID, service, uint(podIndex), uint32(port), newWsPortForwardStream(ws))
+
+		cancel()
+		wg.Wait()
+
+		closeMsg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+		if err != nil {
+			msg := "port forward failed"
+			if cluster.ErrorIsOkToSendToClient(err) {
+				msg = err.Error()
+			} else {
+				// Don't return errors like this to the client, they could contain information
+				// that should not be let out
+				localLog.Error("port forward failed", "err", err)
+			}
+
+			if len(msg) > portForwardMaxCloseReason {
+				msg = msg[:portForwardMaxCloseReason]
+			}
+
+			closeMsg = websocket.FormatCloseMessage(websocket.CloseInternalServerErr, msg)
+		}
+
+		_ = ws.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(portForwardCloseWait))
+	}
+}
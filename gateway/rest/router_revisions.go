@@ -0,0 +1,63 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/tendermint/tendermint/libs/log"
+	kubeErrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/akash-network/provider/cluster"
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
+)
+
+type leaseRollbackRequestBody struct {
+	// Revision to roll the lease back to, zero rolls back to the previous one
+	Revision uint64 `json:"revision"`
+}
+
+func leaseRevisionsHandler(log log.Logger, cclient cluster.ReadClient) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		revisions, err := cclient.ManifestRevisions(req.Context(), requestLeaseID(req))
+		if err != nil {
+			http.Error(w, err.Error(), revisionErrorStatus(err))
+			return
+		}
+
+		writeJSON(log, w, revisions)
+	}
+}
+
+func leaseRollbackHandler(log log.Logger, clusterService cluster.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		body := leaseRollbackRequestBody{}
+
+		if req.ContentLength != 0 {
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		lid := requestLeaseID(req)
+
+		if err := clusterService.RollbackLease(req.Context(), lid, body.Revision); err != nil {
+			log.Error("lease rollback failed", "lease", lid, "revision", body.Revision, "err", err)
+			http.Error(w, err.Error(), revisionErrorStatus(err))
+			return
+		}
+	}
+}
+
+func revisionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, kubeclienterrors.ErrNoManifestRevision),
+		errors.Is(err, kubeclienterrors.ErrLeaseNotFound),
+		errors.Is(err, cluster.ErrLeaseNotDeployed),
+		kubeErrors.IsNotFound(err):
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
@@ -57,6 +57,12 @@ func leaseShellWebsocketHandler(log log.Logger, wg *sync.WaitGroup, shellWs *web
 			if err != nil {
 				return
 			}
+		case LeaseShellCodeStdinClose:
+			if closer, valid := stdinPipeOut.(io.Closer); valid {
+				if err := closer.Close(); err != nil {
+					return
+				}
+			}
 		case LeaseShellCodeTerminalResize:
 			var size remotecommand.TerminalSize
 			r := bytes.NewReader(msg)
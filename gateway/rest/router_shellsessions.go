@@ -0,0 +1,65 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/akash-network/provider/gateway/shellaudit"
+)
+
+// shellSessionsHandler lists audited shell sessions, most recent first. provider sees sessions of all
+// tenants and may filter them by owner, tenants see only their own sessions
+func shellSessionsHandler(log log.Logger, providerAddr sdk.Address) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		vars := req.URL.Query()
+
+		opts := shellaudit.ListOptions{
+			Owner: vars.Get("owner"),
+		}
+
+		if owner := requestOwner(req); owner.String() != providerAddr.String() {
+			opts.Owner = owner.String()
+		}
+
+		if val := vars.Get("dseq"); val != "" {
+			dseq, err := strconv.ParseUint(val, 10, 64)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if opts.Owner == "" {
+				http.Error(w, "parameter dseq requires owner", http.StatusBadRequest)
+				return
+			}
+
+			opts.DSeq = dseq
+		}
+
+		if val := vars.Get("limit"); val != "" {
+			limit, err := strconv.ParseUint(val, 10, 31)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			opts.Limit = int(limit)
+		}
+
+		sessions, err := requestShellAudit(req).List(opts)
+		if err != nil {
+			log.Error("listing shell sessions", "err", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if sessions == nil {
+			sessions = []shellaudit.Session{}
+		}
+
+		writeJSON(log, w, sessions)
+	}
+}
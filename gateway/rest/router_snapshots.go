@@ -0,0 +1,73 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/akash-network/provider/cluster"
+	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
+	"github.com/akash-network/provider/tools/fromctx"
+)
+
+type leaseSnapshotRequestBody struct {
+	// Name of the snapshot, volume snapshots are named <name>-<claim>
+	Name string `json:"name"`
+}
+
+func leaseSnapshotsHandler(log log.Logger, cclient cluster.ReadClient) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		snapshots, err := cclient.LeaseSnapshots(req.Context(), requestLeaseID(req))
+		if err != nil {
+			http.Error(w, err.Error(), snapshotErrorStatus(err))
+			return
+		}
+
+		writeJSON(log, w, snapshots)
+	}
+}
+
+func leaseSnapshotHandler(log log.Logger, cclient cluster.Client, clusterSettings map[interface{}]interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		ctx := fromctx.ApplyToContext(req.Context(), clusterSettings)
+
+		body := leaseSnapshotRequestBody{}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if len(body.Name) == 0 {
+			http.Error(w, "missing snapshot name", http.StatusBadRequest)
+			return
+		}
+
+		lid := requestLeaseID(req)
+
+		snapshots, err := cclient.SnapshotLeaseVolumes(ctx, lid, body.Name)
+		if err != nil {
+			log.Error("lease snapshot failed", "lease", lid, "snapshot", body.Name, "err", err)
+			http.Error(w, err.Error(), snapshotErrorStatus(err))
+			return
+		}
+
+		writeJSON(log, w, snapshots)
+	}
+}
+
+func snapshotErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, kubeclienterrors.ErrVolumeSnapshotsDisabled),
+		errors.Is(err, kubeclienterrors.ErrInvalidVolumeSnapshotName):
+		return http.StatusBadRequest
+	case errors.Is(err, kubeclienterrors.ErrLeaseNotFound),
+		errors.Is(err, kubeclienterrors.ErrNoPersistentVolumes):
+		return http.StatusNotFound
+	case errors.Is(err, kubeclienterrors.ErrVolumeSnapshotExists):
+		return http.StatusConflict
+	}
+
+	return http.StatusInternalServerError
+}
@@ -7,10 +7,14 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	gcontext "github.com/gorilla/context"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -29,11 +33,14 @@ import (
 	"github.com/akash-network/node/sdl"
 
 	"github.com/akash-network/provider"
+	"github.com/akash-network/provider/cluster"
 	kubeclienterrors "github.com/akash-network/provider/cluster/kube/errors"
 	pcmock "github.com/akash-network/provider/cluster/mocks"
 	clustertypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	clmocks "github.com/akash-network/provider/cluster/types/v1beta3/mocks"
+	"github.com/akash-network/provider/gateway/shellaudit"
+	"github.com/akash-network/provider/ledger"
 	pmmock "github.com/akash-network/provider/manifest/mocks"
 	pmock "github.com/akash-network/provider/mocks"
 	"github.com/akash-network/provider/pkg/apis/akash.network/v2beta2"
@@ -79,6 +86,12 @@ type routerTest struct {
 func runRouterTest(t *testing.T, authClient bool, fn func(*routerTest)) {
 	t.Helper()
 
+	runAuditRouterTest(t, authClient, nil, fn)
+}
+
+func runAuditRouterTest(t *testing.T, authClient bool, audit *shellaudit.Log, fn func(*routerTest)) {
+	t.Helper()
+
 	mocks := createMocks()
 
 	mf := &routerTest{
@@ -104,7 +117,7 @@ func runRouterTest(t *testing.T, authClient bool, fn func(*routerTest)) {
 		certs = mf.ccert.Cert
 	}
 
-	withServer(t, mf.paddr, mocks.pclient, mocks.qclient, mf.pcert.Cert, func(host string) {
+	withAuditServer(t, mf.paddr, mocks.pclient, mocks.qclient, mf.pcert.Cert, audit, func(host string) {
 		var err error
 		mf.host, err = url.Parse(host)
 		require.NoError(t, err)
@@ -536,6 +549,79 @@ func TestRouteValidateFailsEmptyBody(t *testing.T) {
 	})
 }
 
+func TestRouteEarningsOK(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		report := ledger.Report{
+			From: from,
+			Total: ledger.Earnings{
+				Withdrawn:   sdk.NewCoins(sdk.NewInt64Coin("uakt", 100)),
+				Withdrawals: 1,
+			},
+		}
+
+		query := ledger.Query{
+			DSeq:        7,
+			From:        from,
+			Withdrawals: true,
+		}
+
+		test.pclient.On("Earnings", mock.Anything, mock.Anything, mock.MatchedBy(func(q ledger.Query) bool {
+			return q.DSeq == query.DSeq && q.From.Equal(query.From) && q.To.IsZero() && q.Withdrawals
+		})).Return(report, nil)
+
+		res, err := test.gwclient.Earnings(context.Background(), query)
+		require.NoError(t, err)
+		require.Equal(t, report.Total.Withdrawn, res.Total.Withdrawn)
+		require.Equal(t, 1, res.Total.Withdrawals)
+		require.True(t, from.Equal(res.From))
+	})
+}
+
+func TestRouteEarningsInvalidQuery(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		uri, err := makeURI(test.host, earningsPath())
+		require.NoError(t, err)
+
+		req, err := http.NewRequest("GET", uri+"?from=yesterday", nil)
+		require.NoError(t, err)
+
+		rCl := test.gwclient.newReqClient(context.Background())
+		resp, err := rCl.hclient.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestEarningsHandlerDSeqRequiresOwner(t *testing.T) {
+	paddr := testutil.AccAddress(t)
+	owner := testutil.AccAddress(t)
+
+	pclient := &pmock.Client{}
+	pclient.On("Earnings", mock.Anything, paddr, ledger.Query{Owner: owner.String(), DSeq: 7}).
+		Return(ledger.Report{}, nil)
+
+	handler := earningsHandler(testutil.Logger(t), pclient, paddr)
+
+	get := func(query string) int {
+		req := httptest.NewRequest("GET", "/earnings?"+query, nil)
+		gcontext.Set(req, ownerContextKey, paddr)
+		defer gcontext.Clear(req)
+
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+
+		return rec.Code
+	}
+
+	// provider queries leases of all tenants, deployment can't be selected without its owner
+	require.Equal(t, http.StatusBadRequest, get("dseq=7"))
+	require.Equal(t, http.StatusOK, get("owner="+owner.String()+"&dseq=7"))
+
+	pclient.AssertExpectations(t)
+}
+
 func TestRoutePutManifestOK(t *testing.T) {
 	runRouterTest(t, true, func(test *routerTest) {
 		dseq := uint64(testutil.RandRangeInt(1, 1000)) // nolint: gosec
@@ -684,6 +770,14 @@ func TestRouteLeaseStatusOk(t *testing.T) {
 		leaseID.Owner = test.caddr.String()
 		leaseID.Provider = test.paddr.String()
 		mockManifestGroupsForRouterTest(test, leaseID)
+		test.pcclient.On("LeaseProbeFailures", mock.Anything, leaseID).Return(map[string][]ctypes.ProbeStatus{
+			testServiceName: {{Probe: ctypes.ProbeReadiness, Failures: 3, Message: "Readiness probe failed"}},
+		}, nil)
+		test.pclient.On("LeaseRunway", mock.Anything, leaseID).Return(&provider.LeaseRunway{
+			BlocksRemaining: 100,
+			Runway:          "10m0s",
+			LowBalance:      true,
+		}, nil)
 
 		uri, err := makeURI(test.host, leaseStatusPath(leaseID))
 		require.NoError(t, err)
@@ -707,10 +801,18 @@ func TestRouteLeaseStatusOk(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, http.StatusOK, resp.StatusCode)
 
-		data := make(map[string]interface{})
+		data := LeaseStatus{}
 		dec := json.NewDecoder(resp.Body)
 		err = dec.Decode(&data)
 		require.NoError(t, err)
+		require.NotNil(t, data.Funds)
+		require.Equal(t, int64(100), data.Funds.BlocksRemaining)
+		require.True(t, data.Funds.LowBalance)
+
+		require.Contains(t, data.Services, testServiceName)
+		require.Equal(t, []ctypes.ProbeStatus{
+			{Probe: ctypes.ProbeReadiness, Failures: 3, Message: "Readiness probe failed"},
+		}, data.Services[testServiceName].RecentProbeFailures)
 	})
 }
 
@@ -809,6 +911,8 @@ func TestRouteServiceStatusOK(t *testing.T) {
 			OSeq:     oseq,
 			Provider: test.paddr.String(),
 		}, serviceName).Return(status, nil)
+		// probe failures are not required to report service status
+		test.pcclient.On("LeaseProbeFailures", mock.Anything, mock.Anything).Return(nil, errors.New("events-list failed"))
 
 		lid := types.LeaseID{
 			DSeq:     dseq,
@@ -968,3 +1072,494 @@ func TestRouteServiceStatusError(t *testing.T) {
 		require.Regexp(t, "^generic test error(?s:.)*$", string(data))
 	})
 }
+
+func TestRouteLeaseRevisionsOK(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		lid := types.LeaseID{
+			Owner:    test.caddr.String(),
+			DSeq:     uint64(testutil.RandRangeInt(1, 1000)),    // nolint: gosec
+			GSeq:     uint32(testutil.RandRangeInt(4000, 5000)), // nolint: gosec
+			OSeq:     uint32(testutil.RandRangeInt(2000, 3000)), // nolint: gosec
+			Provider: test.paddr.String(),
+		}
+
+		revisions := []ctypes.ManifestRevision{
+			{Revision: 1, DeployedAt: time.Unix(100, 0).UTC(), Services: []string{"web"}},
+			{Revision: 2, DeployedAt: time.Unix(200, 0).UTC(), Current: true, Services: []string{"web", "db"}},
+		}
+
+		test.pcclient.On("ManifestRevisions", mock.Anything, lid).Return(revisions, nil)
+
+		result, err := test.gwclient.LeaseRevisions(context.Background(), lid)
+		require.NoError(t, err)
+		require.Equal(t, revisions, result)
+	})
+}
+
+func TestRouteLeaseRollbackOK(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		lid := types.LeaseID{
+			Owner:    test.caddr.String(),
+			DSeq:     uint64(testutil.RandRangeInt(1, 1000)),    // nolint: gosec
+			GSeq:     uint32(testutil.RandRangeInt(4000, 5000)), // nolint: gosec
+			OSeq:     uint32(testutil.RandRangeInt(2000, 3000)), // nolint: gosec
+			Provider: test.paddr.String(),
+		}
+
+		test.clusterService.On("RollbackLease", mock.Anything, lid, uint64(3)).Return(nil)
+
+		require.NoError(t, test.gwclient.LeaseRollback(context.Background(), lid, 3))
+		test.clusterService.AssertExpectations(t)
+	})
+}
+
+func TestRouteLeaseRollbackNoRevision(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		lid := types.LeaseID{
+			Owner:    test.caddr.String(),
+			DSeq:     uint64(testutil.RandRangeInt(1, 1000)),    // nolint: gosec
+			GSeq:     uint32(testutil.RandRangeInt(4000, 5000)), // nolint: gosec
+			OSeq:     uint32(testutil.RandRangeInt(2000, 3000)), // nolint: gosec
+			Provider: test.paddr.String(),
+		}
+
+		test.clusterService.On("RollbackLease", mock.Anything, lid, uint64(0)).
+			Return(kubeclienterrors.ErrNoManifestRevision)
+
+		err := test.gwclient.LeaseRollback(context.Background(), lid, 0)
+
+		var rerr ClientResponseError
+		require.ErrorAs(t, err, &rerr)
+		require.Equal(t, http.StatusNotFound, rerr.Status)
+	})
+}
+
+func TestRouteLeasePauseResumeOK(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		lid := types.LeaseID{
+			Owner:    test.caddr.String(),
+			DSeq:     uint64(testutil.RandRangeInt(1, 1000)),    // nolint: gosec
+			GSeq:     uint32(testutil.RandRangeInt(4000, 5000)), // nolint: gosec
+			OSeq:     uint32(testutil.RandRangeInt(2000, 3000)), // nolint: gosec
+			Provider: test.paddr.String(),
+		}
+
+		test.pcclient.On("PauseLease", mock.Anything, lid).Return(nil)
+		test.pcclient.On("ResumeLease", mock.Anything, lid).Return(nil)
+
+		require.NoError(t, test.gwclient.LeasePause(context.Background(), lid))
+		require.NoError(t, test.gwclient.LeaseResume(context.Background(), lid))
+		test.pcclient.AssertExpectations(t)
+	})
+}
+
+func TestRouteLeasePauseNotFound(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		lid := types.LeaseID{
+			Owner:    test.caddr.String(),
+			DSeq:     uint64(testutil.RandRangeInt(1, 1000)),    // nolint: gosec
+			GSeq:     uint32(testutil.RandRangeInt(4000, 5000)), // nolint: gosec
+			OSeq:     uint32(testutil.RandRangeInt(2000, 3000)), // nolint: gosec
+			Provider: test.paddr.String(),
+		}
+
+		test.pcclient.On("PauseLease", mock.Anything, lid).Return(kubeclienterrors.ErrLeaseNotFound)
+
+		err := test.gwclient.LeasePause(context.Background(), lid)
+
+		var rerr ClientResponseError
+		require.ErrorAs(t, err, &rerr)
+		require.Equal(t, http.StatusNotFound, rerr.Status)
+	})
+}
+
+func TestRouteLeaseSnapshotOK(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		lid := types.LeaseID{
+			Owner:    test.caddr.String(),
+			DSeq:     uint64(testutil.RandRangeInt(1, 1000)),    // nolint: gosec
+			GSeq:     uint32(testutil.RandRangeInt(4000, 5000)), // nolint: gosec
+			OSeq:     uint32(testutil.RandRangeInt(2000, 3000)), // nolint: gosec
+			Provider: test.paddr.String(),
+		}
+
+		snapshots := []ctypes.VolumeSnapshot{
+			{
+				Name:         "nightly-web-data-web-0",
+				Snapshot:     "nightly",
+				Service:      "web",
+				Volume:       "data",
+				PVC:          "web-data-web-0",
+				StorageClass: "beta2",
+				RestoreSize:  1024,
+				CreatedAt:    time.Unix(100, 0).UTC(),
+			},
+		}
+
+		test.pcclient.On("SnapshotLeaseVolumes", mock.Anything, lid, "nightly").Return(snapshots, nil)
+		test.pcclient.On("LeaseSnapshots", mock.Anything, lid).Return(snapshots, nil)
+
+		result, err := test.gwclient.LeaseSnapshot(context.Background(), lid, "nightly")
+		require.NoError(t, err)
+		require.Equal(t, snapshots, result)
+
+		result, err = test.gwclient.LeaseSnapshots(context.Background(), lid)
+		require.NoError(t, err)
+		require.Equal(t, snapshots, result)
+		test.pcclient.AssertExpectations(t)
+	})
+}
+
+func TestRouteLeaseSnapshotErrors(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		lid := types.LeaseID{
+			Owner:    test.caddr.String(),
+			DSeq:     uint64(testutil.RandRangeInt(1, 1000)),    // nolint: gosec
+			GSeq:     uint32(testutil.RandRangeInt(4000, 5000)), // nolint: gosec
+			OSeq:     uint32(testutil.RandRangeInt(2000, 3000)), // nolint: gosec
+			Provider: test.paddr.String(),
+		}
+
+		test.pcclient.On("SnapshotLeaseVolumes", mock.Anything, lid, "nightly").
+			Return(nil, kubeclienterrors.ErrVolumeSnapshotExists)
+		test.pcclient.On("SnapshotLeaseVolumes", mock.Anything, lid, "weekly").
+			Return(nil, kubeclienterrors.ErrVolumeSnapshotsDisabled)
+
+		var rerr ClientResponseError
+
+		_, err := test.gwclient.LeaseSnapshot(context.Background(), lid, "nightly")
+		require.ErrorAs(t, err, &rerr)
+		require.Equal(t, http.StatusConflict, rerr.Status)
+
+		_, err = test.gwclient.LeaseSnapshot(context.Background(), lid, "weekly")
+		require.ErrorAs(t, err, &rerr)
+		require.Equal(t, http.StatusBadRequest, rerr.Status)
+
+		_, err = test.gwclient.LeaseSnapshot(context.Background(), lid, "")
+		require.ErrorAs(t, err, &rerr)
+		require.Equal(t, http.StatusBadRequest, rerr.Status)
+	})
+}
+
+func TestRouteLeaseLogsOptions(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		lid := types.LeaseID{
+			Owner:    test.caddr.String(),
+			DSeq:     uint64(testutil.RandRangeInt(1, 1000)),    // nolint: gosec
+			GSeq:     uint32(testutil.RandRangeInt(4000, 5000)), // nolint: gosec
+			OSeq:     uint32(testutil.RandRangeInt(2000, 3000)), // nolint: gosec
+			Provider: test.paddr.String(),
+		}
+
+		tail := int64(25)
+		limit := int64(1024)
+		since := time.Date(2026, time.January, 2, 3, 4, 5, 0, time.UTC)
+
+		opts := ctypes.LeaseLogsOptions{
+			TailLines:  &tail,
+			SinceTime:  &since,
+			Timestamps: true,
+			Previous:   true,
+			Container:  ctypes.TEESidecarContainerName,
+			LimitBytes: &limit,
+		}
+
+		test.pcclient.On("LeaseLogs", mock.Anything, lid, "web", opts).Return([]*ctypes.ServiceLog{
+			cluster.NewServiceLog("web-0", io.NopCloser(bytes.NewBufferString("crashed\n"))),
+		}, nil)
+
+		logs, err := test.gwclient.LeaseLogs(context.Background(), lid, "web", opts, LogStreamOptions{})
+		require.NoError(t, err)
+
+		msg, open := <-logs.Stream
+		require.True(t, open)
+		require.Equal(t, ServiceLogMessage{Name: "web-0", Message: "crashed"}, msg)
+
+		test.pcclient.AssertExpectations(t)
+	})
+}
+
+func TestRouteLeaseLogsMerged(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		lid := types.LeaseID{
+			Owner:    test.caddr.String(),
+			DSeq:     uint64(testutil.RandRangeInt(1, 1000)),    // nolint: gosec
+			GSeq:     uint32(testutil.RandRangeInt(4000, 5000)), // nolint: gosec
+			OSeq:     uint32(testutil.RandRangeInt(2000, 3000)), // nolint: gosec
+			Provider: test.paddr.String(),
+		}
+
+		web := cluster.NewServiceLog("web-0", io.NopCloser(bytes.NewBufferString(
+			"2026-01-01T00:00:02Z request failed\n2026-01-01T00:00:04Z request served\n")))
+		web.Service = "web"
+		web.Container = "web"
+		web.Output = ctypes.LogOutputStderr
+
+		db := cluster.NewServiceLog("db-0", io.NopCloser(bytes.NewBufferString(
+			"2026-01-01T00:00:01Z connection failed\n2026-01-01T00:00:03Z query failed\n")))
+		db.Service = "db"
+		db.Container = "db"
+
+		// merged stream requests timestamps to order lines with
+		test.pcclient.On("LeaseLogs", mock.Anything, lid, "", ctypes.LeaseLogsOptions{Timestamps: true}).
+			Return([]*ctypes.ServiceLog{web, db}, nil)
+
+		logs, err := test.gwclient.LeaseLogs(context.Background(), lid, "", ctypes.LeaseLogsOptions{}, LogStreamOptions{
+			Merge: true,
+			Grep:  "failed",
+		})
+		require.NoError(t, err)
+
+		var res []ServiceLogMessage
+		for msg := range logs.Stream {
+			res = append(res, msg)
+		}
+
+		require.Len(t, res, 3)
+		require.Equal(t, "connection failed", res[0].Message)
+		require.Equal(t, "db", res[0].Service)
+		require.Equal(t, "request failed", res[1].Message)
+		require.Equal(t, ctypes.LogOutputStderr, res[1].Stream)
+		require.Equal(t, time.Date(2026, time.January, 1, 0, 0, 2, 0, time.UTC), res[1].Timestamp.UTC())
+		require.Equal(t, "query failed", res[2].Message)
+	})
+}
+
+type testExecResult int
+
+func (r testExecResult) ExitCode() int {
+	return int(r)
+}
+
+// readerAfter reads r once ch is closed
+type readerAfter struct {
+	ch <-chan struct{}
+	r  io.Reader
+}
+
+func (r readerAfter) Read(buf []byte) (int, error) {
+	<-r.ch
+	return r.r.Read(buf)
+}
+
+func TestRouteLeaseCopy(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		lid := types.LeaseID{
+			Owner:    test.caddr.String(),
+			DSeq:     uint64(testutil.RandRangeInt(1, 1000)),    // nolint: gosec
+			GSeq:     uint32(testutil.RandRangeInt(4000, 5000)), // nolint: gosec
+			OSeq:     uint32(testutil.RandRangeInt(2000, 3000)), // nolint: gosec
+			Provider: test.paddr.String(),
+		}
+
+		test.pcclient.On("ServiceStatus", mock.Anything, lid, "web").Return(&ctypes.ServiceStatus{
+			Name:          "web",
+			ReadyReplicas: 1,
+		}, nil)
+
+		var uploaded []byte
+		// archive is sent once exec has started, mock formats stdin pipe it is written to otherwise
+		started := make(chan struct{})
+		test.pcclient.On("Exec", mock.Anything, lid, "web", uint(1), []string{"tar", "xf", "-", "-C", "/data"},
+			mock.Anything, mock.Anything, mock.Anything, false, mock.Anything).
+			Run(func(args mock.Arguments) {
+				close(started)
+
+				// upload is read until the client closes stdin
+				var err error
+				uploaded, err = io.ReadAll(args.Get(5).(io.Reader))
+				require.NoError(t, err)
+			}).Return(testExecResult(0), nil)
+
+		test.pcclient.On("Exec", mock.Anything, lid, "web", uint(0), []string{"tar", "cf", "-", "-C", "/data/", "./logs"},
+			nil, mock.Anything, mock.Anything, false, mock.Anything).
+			Run(func(args mock.Arguments) {
+				_, err := args.Get(6).(io.Writer).Write([]byte("archive"))
+				require.NoError(t, err)
+			}).Return(testExecResult(0), nil)
+
+		test.pcclient.On("Exec", mock.Anything, lid, "web", uint(0), []string{"tar", "cf", "-", "-C", "/", "./missing"},
+			nil, mock.Anything, mock.Anything, false, mock.Anything).
+			Return(testExecResult(2), nil)
+
+		err := test.gwclient.LeaseUpload(context.Background(), lid, "web", 1, "/data",
+			readerAfter{ch: started, r: bytes.NewBufferString("archive")}, io.Discard)
+		require.NoError(t, err)
+		require.Equal(t, "archive", string(uploaded))
+
+		downloaded := &bytes.Buffer{}
+		err = test.gwclient.LeaseDownload(context.Background(), lid, "web", 0, "/data/logs/", downloaded, io.Discard)
+		require.NoError(t, err)
+		require.Equal(t, "archive", downloaded.String())
+
+		err = test.gwclient.LeaseDownload(context.Background(), lid, "web", 0, "/missing", io.Discard, io.Discard)
+		require.ErrorContains(t, err, "exited with code 2")
+
+		err = test.gwclient.LeaseDownload(context.Background(), lid, "web", 0, "relative", io.Discard, io.Discard)
+		require.Error(t, err)
+
+		test.pcclient.AssertExpectations(t)
+	})
+}
+
+func TestRouteLeasePortForward(t *testing.T) {
+	runRouterTest(t, true, func(test *routerTest) {
+		lid := types.LeaseID{
+			Owner:    test.caddr.String(),
+			DSeq:     uint64(testutil.RandRangeInt(1, 1000)),    // nolint: gosec
+			GSeq:     uint32(testutil.RandRangeInt(4000, 5000)), // nolint: gosec
+			OSeq:     uint32(testutil.RandRangeInt(2000, 3000)), // nolint: gosec
+			Provider: test.paddr.String(),
+		}
+
+		test.pcclient.On("PortForward", mock.Anything, lid, "web", uint(1), uint32(5000), mock.Anything).
+			Run(func(args mock.Arguments) {
+				stream := args.Get(5).(io.ReadWriter)
+
+				// request is read until the client is done sending, then response is sent back
+				req, err := io.ReadAll(stream)
+				require.NoError(t, err)
+
+				_, err = stream.Write(bytes.ToUpper(req))
+				require.NoError(t, err)
+			}).Return(nil)
+
+		test.pcclient.On("PortForward", mock.Anything, lid, "web", uint(0), uint32(6000), mock.Anything).
+			Return(cluster.ErrExecServiceNotRunning)
+
+		res := &bytes.Buffer{}
+		conn := struct {
+			io.Reader
+			io.Writer
+		}{
+			Reader: bytes.NewBufferString("ping"),
+			Writer: res,
+		}
+
+		err := test.gwclient.LeasePortForward(context.Background(), lid, "web", 1, 5000, conn)
+		require.NoError(t, err)
+		require.Equal(t, "PING", res.String())
+
+		err = test.gwclient.LeasePortForward(context.Background(), lid, "web", 0, 6000, conn)
+		require.ErrorIs(t, err, errLeasePortForward)
+		require.ErrorContains(t, err, "service with that name is not running")
+
+		err = test.gwclient.LeasePortForward(context.Background(), lid, "web", 0, 0, conn)
+		cerr := ClientResponseError{}
+		require.ErrorAs(t, err, &cerr)
+		require.Equal(t, http.StatusBadRequest, cerr.Status)
+
+		test.pcclient.AssertExpectations(t)
+	})
+}
+
+func TestRouteShellSessions(t *testing.T) {
+	dir := t.TempDir()
+
+	sink, err := shellaudit.NewDirSink(filepath.Join(dir, "recordings"))
+	require.NoError(t, err)
+
+	audit, err := shellaudit.Open(filepath.Join(dir, "shell-audit.db"), shellaudit.Config{Sink: sink})
+	require.NoError(t, err)
+	defer func() {
+		_ = audit.Close()
+	}()
+
+	runAuditRouterTest(t, true, audit, func(test *routerTest) {
+		lid := types.LeaseID{
+			Owner:    test.caddr.String(),
+			DSeq:     uint64(testutil.RandRangeInt(1, 1000)),    // nolint: gosec
+			GSeq:     uint32(testutil.RandRangeInt(4000, 5000)), // nolint: gosec
+			OSeq:     uint32(testutil.RandRangeInt(2000, 3000)), // nolint: gosec
+			Provider: test.paddr.String(),
+		}
+
+		// session of another tenant
+		other := lid
+		other.Owner = testutil.AccAddress(t).String()
+
+		rec, err := audit.Start(shellaudit.Session{Owner: other.Owner, LeaseID: other, Service: "web"}, false)
+		require.NoError(t, err)
+		require.NoError(t, rec.Finish(nil, ""))
+
+		test.pcclient.On("ServiceStatus", mock.Anything, lid, "web").Return(&ctypes.ServiceStatus{
+			Name:          "web",
+			ReadyReplicas: 1,
+		}, nil)
+
+		cmd := []string{"/bin/sh", "-c", "echo hello"}
+
+		test.pcclient.On("Exec", mock.Anything, lid, "web", uint(1), cmd,
+			nil, mock.Anything, mock.Anything, false, mock.Anything).
+			Run(func(args mock.Arguments) {
+				_, err := args.Get(6).(io.Writer).Write([]byte("hello\n"))
+				require.NoError(t, err)
+			}).Return(testExecResult(3), nil)
+
+		test.pcclient.On("Exec", mock.Anything, lid, "web", uint(0), []string{"tar", "cf", "-", "-C", "/", "./data"},
+			nil, mock.Anything, mock.Anything, false, mock.Anything).
+			Run(func(args mock.Arguments) {
+				_, err := args.Get(6).(io.Writer).Write([]byte("archive"))
+				require.NoError(t, err)
+			}).Return(testExecResult(0), nil)
+
+		stdout := &bytes.Buffer{}
+		err = test.gwclient.LeaseShell(context.Background(), lid, "web", 1, cmd, nil, stdout, io.Discard, false, nil)
+		require.ErrorContains(t, err, "remote process exited with code 3")
+		require.Equal(t, "hello\n", stdout.String())
+
+		err = test.gwclient.LeaseDownload(context.Background(), lid, "web", 0, "/data", io.Discard, io.Discard)
+		require.NoError(t, err)
+
+		sessions, err := test.gwclient.ShellSessions(context.Background(), shellaudit.ListOptions{Owner: other.Owner})
+		require.NoError(t, err)
+		require.Len(t, sessions, 2)
+
+		// copies are logged without recording
+		require.Equal(t, []string{"tar", "cf", "-", "-C", "/", "./data"}, sessions[0].Command)
+		require.Empty(t, sessions[0].Recording)
+		require.NotNil(t, sessions[0].ExitCode)
+		require.Equal(t, 0, *sessions[0].ExitCode)
+
+		sess := sessions[1]
+		require.Equal(t, test.caddr.String(), sess.Owner)
+		require.Equal(t, lid, sess.LeaseID)
+		require.Equal(t, cmd, sess.Command)
+		require.Equal(t, uint(1), sess.PodIndex)
+		require.NotNil(t, sess.EndedAt)
+		require.NotNil(t, sess.ExitCode)
+		require.Equal(t, 3, *sess.ExitCode)
+		require.NotEmpty(t, sess.Recording)
+
+		recording, err := os.ReadFile(filepath.Join(dir, "recordings", filepath.FromSlash(sess.Recording)))
+		require.NoError(t, err)
+		require.Contains(t, string(recording), `"o","hello\n"]`)
+
+		sessions, err = test.gwclient.ShellSessions(context.Background(), shellaudit.ListOptions{DSeq: lid.DSeq + 1})
+		require.NoError(t, err)
+		require.Empty(t, sessions)
+
+		// provider sees sessions of all tenants
+		pclient, err := NewClient(context.Background(), test.qclient, test.paddr, test.pcert.Cert)
+		require.NoError(t, err)
+
+		sessions, err = pclient.ShellSessions(context.Background(), shellaudit.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, sessions, 3)
+
+		sessions, err = pclient.ShellSessions(context.Background(), shellaudit.ListOptions{Owner: other.Owner, Limit: 5})
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+
+		uri, err := makeURI(test.host, shellSessionsPath())
+		require.NoError(t, err)
+
+		req, err := http.NewRequest("GET", uri+"?limit=many", nil)
+		require.NoError(t, err)
+
+		rCl := test.gwclient.newReqClient(context.Background())
+		resp, err := rCl.hclient.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/akash-network/provider"
 	clfromctx "github.com/akash-network/provider/cluster/types/v1beta3/fromctx"
+	"github.com/akash-network/provider/gateway/shellaudit"
 	gwutils "github.com/akash-network/provider/gateway/utils"
 	"github.com/akash-network/provider/tools/fromctx"
 )
@@ -43,6 +44,15 @@ func NewServer(
 				gcontext.Set(r, clfromctx.CtxKeyClientIP, ip)
 			}
 
+			// attestation responses are signed with the certificate gateway serves
+			if len(certs) > 0 {
+				gcontext.Set(r, providerCertContextKey, certs[0])
+			}
+
+			if audit := shellaudit.FromContext(ctx); audit != nil {
+				gcontext.Set(r, shellAuditContextKey, audit)
+			}
+
 			next.ServeHTTP(w, r)
 		})
 	}
@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"github.com/akash-network/provider"
 	cltypes "github.com/akash-network/provider/cluster/types/v1beta3"
 )
 
@@ -15,4 +16,6 @@ type LeaseStatus struct {
 	Services       map[string]*cltypes.ServiceStatus        `json:"services"`
 	ForwardedPorts map[string][]cltypes.ForwardedPortStatus `json:"forwarded_ports"` // Container services that are externally accessible
 	IPs            map[string][]LeasedIPStatus              `json:"ips"`
+	// Funds is runway of the deployment escrow, omitted until provider has checked lease balance
+	Funds *provider.LeaseRunway `json:"funds,omitempty"`
 }
@@ -0,0 +1,317 @@
+package shellaudit
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tendermint/tendermint/libs/log"
+	bolt "go.etcd.io/bbolt"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
+
+	"github.com/akash-network/provider/tools/boltdb"
+)
+
+const (
+	pruneInterval = time.Hour
+
+	// DefaultMaxRecordingBytes is the size recordings are truncated at unless configured otherwise
+	DefaultMaxRecordingBytes = 64 << 20
+)
+
+type CtxKey string
+
+const (
+	CtxKeyLog = CtxKey("shell-audit-log")
+)
+
+var (
+	sessionsBucket = []byte("sessions")
+)
+
+// FromContext returns audit log set in the ctx, nil if there is none
+func FromContext(ctx context.Context) *Log {
+	val := ctx.Value(CtxKeyLog)
+	if val == nil {
+		return nil
+	}
+
+	return val.(*Log)
+}
+
+// Session is the audit record of command tenant has run in the lease container through the gateway
+type Session struct {
+	ID       string         `json:"id"`
+	Owner    string         `json:"owner"`
+	LeaseID  mtypes.LeaseID `json:"lease_id"`
+	Service  string         `json:"service"`
+	PodIndex uint           `json:"pod_index"`
+	Command  []string       `json:"command"`
+	TTY      bool           `json:"tty"`
+	Stdin    bool           `json:"stdin"`
+	// RemoteAddr is address the session has been opened from
+	RemoteAddr string     `json:"remote_addr,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	EndedAt    *time.Time `json:"ended_at,omitempty"`
+	// ExitCode of the command, nil while session is running or when command could not be run
+	ExitCode *int `json:"exit_code,omitempty"`
+	// Error is the message tenant has been sent when command could not be run
+	Error string `json:"error,omitempty"`
+	// Recording is name of the session recording in the sink, empty when session has not been recorded
+	Recording string `json:"recording,omitempty"`
+	// RecordingTruncated is set when recording has been cut at size limit
+	RecordingTruncated bool `json:"recording_truncated,omitempty"`
+}
+
+// ListOptions filters sessions
+type ListOptions struct {
+	// Owner limits sessions to those of given deployment owner
+	Owner string
+	// DSeq limits sessions to those of given deployment, requires Owner
+	DSeq uint64
+	// Limit is max number of most recently started sessions to return. 0 returns all
+	Limit int
+}
+
+// Config of the audit log
+type Config struct {
+	// Sink stores asciicast recordings of session streams. sessions are not recorded when nil
+	Sink Sink
+	// Retention is how long sessions and their recordings are kept, forever if 0
+	Retention time.Duration
+	// MaxRecordingBytes is the size recording is truncated at, DefaultMaxRecordingBytes if 0
+	MaxRecordingBytes int64
+}
+
+// Log keeps audit records of shell sessions on disk and records their streams to the sink.
+// all methods of nil Log are no-op
+type Log struct {
+	db  *bolt.DB
+	cfg Config
+	now func() time.Time
+}
+
+// Open opens audit log file, creating it if does not exist
+func Open(path string, cfg Config) (*Log, error) {
+	db, err := boltdb.Open(path, sessionsBucket)
+	if err != nil {
+		return nil, fmt.Errorf("shell audit: %w", err)
+	}
+
+	if cfg.MaxRecordingBytes == 0 {
+		cfg.MaxRecordingBytes = DefaultMaxRecordingBytes
+	}
+
+	return &Log{
+		db:  db,
+		cfg: cfg,
+		now: time.Now,
+	}, nil
+}
+
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+
+	return l.db.Close()
+}
+
+// Start records session has started and returns its recorder, which must be finished once session is over.
+// ID and StartedAt of the session are set by the log. streams of the session are recorded
+// when record is set and log has sink
+func (l *Log) Start(sess Session, record bool) (*Recorder, error) {
+	if l == nil {
+		return nil, nil
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	sess.ID = hex.EncodeToString(id)
+	sess.StartedAt = l.now().UTC()
+	sess.EndedAt = nil
+	sess.ExitCode = nil
+
+	rec := &Recorder{
+		log:  l,
+		sess: sess,
+		key:  sessionKey(sess),
+	}
+
+	if err := l.put(rec.key, sess); err != nil {
+		return nil, err
+	}
+
+	if record && l.cfg.Sink != nil {
+		if err := rec.startRecording(); err != nil {
+			return nil, err
+		}
+	}
+
+	return rec, nil
+}
+
+// List returns sessions matching options, most recently started first
+func (l *Log) List(opts ListOptions) ([]Session, error) {
+	if l == nil {
+		return nil, nil
+	}
+
+	var prefix []byte
+	if opts.Owner != "" {
+		prefix = []byte(opts.Owner + "/")
+
+		if opts.DSeq != 0 {
+			prefix = []byte(fmt.Sprintf("%s/%d/", opts.Owner, opts.DSeq))
+		}
+	}
+
+	var sessions []Session
+
+	err := l.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(sessionsBucket).Cursor()
+
+		for key, data := c.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, data = c.Next() {
+			var sess Session
+			if err := json.Unmarshal(data, &sess); err != nil {
+				return fmt.Errorf("shell audit: decoding %s: %w", key, err)
+			}
+
+			sessions = append(sessions, sess)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(sessions, func(i, k int) bool {
+		return sessions[i].StartedAt.After(sessions[k].StartedAt)
+	})
+
+	if opts.Limit > 0 && len(sessions) > opts.Limit {
+		sessions = sessions[:opts.Limit]
+	}
+
+	return sessions, nil
+}
+
+// Prune removes sessions started before given time along with their recordings
+func (l *Log) Prune(ctx context.Context, before time.Time) (int, error) {
+	if l == nil {
+		return 0, nil
+	}
+
+	var pruned []Session
+
+	err := l.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(sessionsBucket).ForEach(func(key, data []byte) error {
+			var sess Session
+			if err := json.Unmarshal(data, &sess); err != nil {
+				return fmt.Errorf("shell audit: decoding %s: %w", key, err)
+			}
+
+			if sess.StartedAt.Before(before) {
+				pruned = append(pruned, sess)
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for idx, sess := range pruned {
+		// record is kept until its recording is gone, so failed deletion is retried with next prune
+		if sess.Recording != "" && l.cfg.Sink != nil {
+			if err := l.cfg.Sink.Delete(ctx, sess.Recording); err != nil {
+				return idx, fmt.Errorf("shell audit: deleting recording %s: %w", sess.Recording, err)
+			}
+		}
+
+		err := l.db.Update(func(tx *bolt.Tx) error {
+			return tx.Bucket(sessionsBucket).Delete(sessionKey(sess))
+		})
+		if err != nil {
+			return idx, err
+		}
+	}
+
+	return len(pruned), nil
+}
+
+// Run prunes sessions older than retention until ctx is done
+func (l *Log) Run(ctx context.Context, logger log.Logger) error {
+	if l == nil || l.cfg.Retention <= 0 {
+		<-ctx.Done()
+		return nil
+	}
+
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		pruned, err := l.Prune(ctx, l.now().Add(-l.cfg.Retention))
+		if err != nil {
+			logger.Error("pruning shell audit log", "err", err)
+		} else if pruned > 0 {
+			logger.Info("pruned shell audit log", "sessions", pruned)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (l *Log) put(key []byte, sess Session) error {
+	data, err := json.Marshal(sess)
+	if err != nil {
+		return err
+	}
+
+	return l.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(sessionsBucket).Put(key, data)
+	})
+}
+
+// update stores session unless it has been pruned already
+func (l *Log) update(key []byte, sess Session) error {
+	data, err := json.Marshal(sess)
+	if err != nil {
+		return err
+	}
+
+	return l.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(sessionsBucket)
+		if bucket.Get(key) == nil {
+			return nil
+		}
+
+		return bucket.Put(key, data)
+	})
+}
+
+func sessionKey(sess Session) []byte {
+	lid := sess.LeaseID
+	return []byte(fmt.Sprintf("%s/%d/%d/%d/%020d/%s", sess.Owner, lid.DSeq, lid.GSeq, lid.OSeq, sess.StartedAt.UnixNano(), sess.ID))
+}
+
+// recordingName returns name session recording is stored under in the sink
+func recordingName(sess Session) string {
+	lid := sess.LeaseID
+	return fmt.Sprintf("%s/%d/%d/%d/%s.cast", sess.Owner, lid.DSeq, lid.GSeq, lid.OSeq, sess.ID)
+}
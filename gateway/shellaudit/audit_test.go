@@ -0,0 +1,332 @@
+package shellaudit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/remotecommand"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
+
+	"github.com/akash-network/node/testutil"
+)
+
+func openTestLog(t *testing.T, cfg Config) (*Log, *time.Time) {
+	t.Helper()
+
+	l, err := Open(filepath.Join(t.TempDir(), "audit", "shell-audit.db"), cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = l.Close()
+	})
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l.now = func() time.Time {
+		return now
+	}
+
+	return l, &now
+}
+
+func testSession(t *testing.T, lid mtypes.LeaseID) Session {
+	t.Helper()
+
+	return Session{
+		Owner:    lid.Owner,
+		LeaseID:  lid,
+		Service:  "web",
+		PodIndex: 1,
+		Command:  []string{"/bin/sh"},
+		TTY:      true,
+		Stdin:    true,
+	}
+}
+
+type testSizeQueue []remotecommand.TerminalSize
+
+func (q *testSizeQueue) Next() *remotecommand.TerminalSize {
+	if len(*q) == 0 {
+		return nil
+	}
+
+	size := (*q)[0]
+	*q = (*q)[1:]
+
+	return &size
+}
+
+func readRecording(t *testing.T, path string) (asciicastHeader, [][]interface{}) {
+	t.Helper()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer func() {
+		_ = file.Close()
+	}()
+
+	scanner := bufio.NewScanner(file)
+
+	require.True(t, scanner.Scan())
+
+	var header asciicastHeader
+	require.NoError(t, json.Unmarshal(scanner.Bytes(), &header))
+
+	var events [][]interface{}
+	for scanner.Scan() {
+		var event []interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event)
+	}
+	require.NoError(t, scanner.Err())
+
+	return header, events
+}
+
+func TestLogSessionLifecycle(t *testing.T) {
+	l, now := openTestLog(t, Config{})
+
+	lid := testutil.LeaseID(t)
+
+	rec, err := l.Start(testSession(t, lid), true)
+	require.NoError(t, err)
+	require.NotEmpty(t, rec.Session().ID)
+
+	sessions, err := l.List(ListOptions{Owner: lid.Owner})
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Nil(t, sessions[0].EndedAt)
+	require.Nil(t, sessions[0].ExitCode)
+	require.True(t, now.Equal(sessions[0].StartedAt))
+
+	// streams are not recorded without sink
+	_, err = rec.Output().Write([]byte("output"))
+	require.NoError(t, err)
+
+	*now = now.Add(time.Minute)
+	exitCode := 3
+	require.NoError(t, rec.Finish(&exitCode, ""))
+
+	sessions, err = l.List(ListOptions{Owner: lid.Owner})
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+
+	sess := sessions[0]
+	require.Equal(t, rec.Session().ID, sess.ID)
+	require.Equal(t, lid, sess.LeaseID)
+	require.Equal(t, "web", sess.Service)
+	require.Equal(t, uint(1), sess.PodIndex)
+	require.Equal(t, []string{"/bin/sh"}, sess.Command)
+	require.NotNil(t, sess.EndedAt)
+	require.True(t, now.Equal(*sess.EndedAt))
+	require.NotNil(t, sess.ExitCode)
+	require.Equal(t, 3, *sess.ExitCode)
+	require.Empty(t, sess.Recording)
+
+	rec, err = l.Start(testSession(t, lid), true)
+	require.NoError(t, err)
+	require.NoError(t, rec.Finish(nil, "provider error"))
+
+	sessions, err = l.List(ListOptions{Owner: lid.Owner, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, rec.Session().ID, sessions[0].ID)
+	require.Nil(t, sessions[0].ExitCode)
+	require.Equal(t, "provider error", sessions[0].Error)
+}
+
+func TestLogList(t *testing.T) {
+	l, now := openTestLog(t, Config{})
+
+	lid1 := testutil.LeaseID(t)
+	lid2 := testutil.LeaseID(t)
+	lid2.Owner = lid1.Owner
+	lid3 := testutil.LeaseID(t)
+
+	for _, lid := range []mtypes.LeaseID{lid1, lid2, lid3} {
+		*now = now.Add(time.Minute)
+		_, err := l.Start(testSession(t, lid), true)
+		require.NoError(t, err)
+	}
+
+	sessions, err := l.List(ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, sessions, 3)
+	require.Equal(t, lid3, sessions[0].LeaseID)
+	require.Equal(t, lid1, sessions[2].LeaseID)
+
+	sessions, err = l.List(ListOptions{Owner: lid1.Owner})
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	require.Equal(t, lid2, sessions[0].LeaseID)
+	require.Equal(t, lid1, sessions[1].LeaseID)
+
+	sessions, err = l.List(ListOptions{Owner: lid1.Owner, DSeq: lid1.DSeq})
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, lid1, sessions[0].LeaseID)
+
+	sessions, err = l.List(ListOptions{Owner: lid3.Owner, DSeq: lid1.DSeq})
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+}
+
+func TestLogRecording(t *testing.T) {
+	dir := t.TempDir()
+
+	sink, err := NewDirSink(dir)
+	require.NoError(t, err)
+
+	l, now := openTestLog(t, Config{Sink: sink})
+
+	lid := testutil.LeaseID(t)
+
+	rec, err := l.Start(testSession(t, lid), true)
+	require.NoError(t, err)
+
+	tsq := rec.TerminalSizeQueue(&testSizeQueue{{Width: 120, Height: 40}, {Width: 100, Height: 30}})
+
+	require.NotNil(t, tsq.Next())
+
+	*now = now.Add(time.Second)
+	_, err = rec.Input().Write([]byte("ls\n"))
+	require.NoError(t, err)
+
+	*now = now.Add(500 * time.Millisecond)
+	_, err = rec.Output().Write([]byte("bin etc\r\n"))
+	require.NoError(t, err)
+
+	require.NotNil(t, tsq.Next())
+	require.Nil(t, tsq.Next())
+
+	exitCode := 0
+	require.NoError(t, rec.Finish(&exitCode, ""))
+
+	sess := rec.Session()
+	require.Equal(t, recordingName(sess), sess.Recording)
+	require.False(t, sess.RecordingTruncated)
+
+	sessions, err := l.List(ListOptions{Owner: lid.Owner})
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, sess.Recording, sessions[0].Recording)
+
+	header, events := readRecording(t, filepath.Join(dir, filepath.FromSlash(sess.Recording)))
+	require.Equal(t, 2, header.Version)
+	require.Equal(t, uint16(120), header.Width)
+	require.Equal(t, uint16(40), header.Height)
+	require.Equal(t, "/bin/sh", header.Command)
+	require.True(t, strings.HasPrefix(header.Title, lid.Owner))
+
+	require.Equal(t, [][]interface{}{
+		{1.0, "i", "ls\n"},
+		{1.5, "o", "bin etc\r\n"},
+		{1.5, "r", "100x30"},
+	}, events)
+
+	// sessions which are not to be recorded are only logged
+	rec, err = l.Start(testSession(t, lid), false)
+	require.NoError(t, err)
+
+	_, err = rec.Output().Write([]byte("output"))
+	require.NoError(t, err)
+	require.NoError(t, rec.Finish(&exitCode, ""))
+	require.Empty(t, rec.Session().Recording)
+}
+
+func TestLogRecordingTruncated(t *testing.T) {
+	dir := t.TempDir()
+
+	sink, err := NewDirSink(dir)
+	require.NoError(t, err)
+
+	l, _ := openTestLog(t, Config{Sink: sink, MaxRecordingBytes: 512})
+
+	rec, err := l.Start(testSession(t, testutil.LeaseID(t)), true)
+	require.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		_, err = rec.Output().Write([]byte("0123456789"))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, rec.Finish(nil, ""))
+
+	sess := rec.Session()
+	require.True(t, sess.RecordingTruncated)
+
+	fpath := filepath.Join(dir, filepath.FromSlash(sess.Recording))
+
+	info, err := os.Stat(fpath)
+	require.NoError(t, err)
+	require.LessOrEqual(t, info.Size(), int64(512))
+
+	_, events := readRecording(t, fpath)
+	require.NotEmpty(t, events)
+	require.Less(t, len(events), 100)
+}
+
+func TestLogPrune(t *testing.T) {
+	dir := t.TempDir()
+
+	sink, err := NewDirSink(dir)
+	require.NoError(t, err)
+
+	l, now := openTestLog(t, Config{Sink: sink})
+
+	lid := testutil.LeaseID(t)
+
+	old, err := l.Start(testSession(t, lid), true)
+	require.NoError(t, err)
+	require.NoError(t, old.Finish(nil, ""))
+
+	*now = now.Add(time.Hour)
+
+	recent, err := l.Start(testSession(t, lid), true)
+	require.NoError(t, err)
+	require.NoError(t, recent.Finish(nil, ""))
+
+	pruned, err := l.Prune(context.Background(), now.Add(-time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, 1, pruned)
+
+	_, err = os.Stat(filepath.Join(dir, filepath.FromSlash(old.Session().Recording)))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = os.Stat(filepath.Join(dir, filepath.FromSlash(recent.Session().Recording)))
+	require.NoError(t, err)
+
+	sessions, err := l.List(ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, recent.Session().ID, sessions[0].ID)
+}
+
+func TestLogNil(t *testing.T) {
+	var l *Log
+
+	rec, err := l.Start(testSession(t, testutil.LeaseID(t)), true)
+	require.NoError(t, err)
+	require.Nil(t, rec)
+
+	_, err = rec.Input().Write([]byte("data"))
+	require.NoError(t, err)
+	require.Nil(t, rec.TerminalSizeQueue(nil))
+	require.NoError(t, rec.Finish(nil, ""))
+
+	sessions, err := l.List(ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+
+	pruned, err := l.Prune(context.Background(), time.Now())
+	require.NoError(t, err)
+	require.Zero(t, pruned)
+
+	require.NoError(t, l.Close())
+}
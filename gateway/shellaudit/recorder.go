@@ -0,0 +1,258 @@
+package shellaudit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+const (
+	// recordingStoreTimeout bounds upload of the recording once session is over
+	recordingStoreTimeout = time.Minute
+
+	defaultRecordingWidth  = 80
+	defaultRecordingHeight = 24
+)
+
+// asciicastHeader is the first line of asciicast v2 recording
+type asciicastHeader struct {
+	Version   int    `json:"version"`
+	Width     uint16 `json:"width"`
+	Height    uint16 `json:"height"`
+	Timestamp int64  `json:"timestamp"`
+	Command   string `json:"command,omitempty"`
+	Title     string `json:"title,omitempty"`
+}
+
+// Recorder tracks running session and records its streams in asciicast v2 format when log has sink.
+// recording is staged in temporary file and stored to the sink once session is finished.
+// all methods of nil Recorder are no-op
+type Recorder struct {
+	log  *Log
+	sess Session
+	key  []byte
+
+	mtx sync.Mutex
+	// file is nil unless session is being recorded
+	file      *os.File
+	start     time.Time
+	header    bool
+	width     uint16
+	height    uint16
+	written   int64
+	truncated bool
+}
+
+func (r *Recorder) startRecording() error {
+	file, err := os.CreateTemp("", "shell-session-*.cast")
+	if err != nil {
+		return fmt.Errorf("shell audit: staging recording: %w", err)
+	}
+
+	r.file = file
+	r.start = r.log.now()
+	r.width = defaultRecordingWidth
+	r.height = defaultRecordingHeight
+
+	return nil
+}
+
+// Session returns audit record of the session
+func (r *Recorder) Session() Session {
+	if r == nil {
+		return Session{}
+	}
+
+	return r.sess
+}
+
+// Input returns writer recording data sent to the command
+func (r *Recorder) Input() io.Writer {
+	if r == nil {
+		return io.Discard
+	}
+
+	return recorderStream{rec: r, kind: "i"}
+}
+
+// Output returns writer recording data the command has written
+func (r *Recorder) Output() io.Writer {
+	if r == nil {
+		return io.Discard
+	}
+
+	return recorderStream{rec: r, kind: "o"}
+}
+
+// TerminalSizeQueue returns queue recording terminal sizes tsq yields
+func (r *Recorder) TerminalSizeQueue(tsq remotecommand.TerminalSizeQueue) remotecommand.TerminalSizeQueue {
+	if r == nil || tsq == nil {
+		return tsq
+	}
+
+	return recorderSizeQueue{rec: r, tsq: tsq}
+}
+
+// Finish records session has ended and stores its recording. exitCode is nil when the command could not be run,
+// errMsg is the message tenant has been sent then
+func (r *Recorder) Finish(exitCode *int, errMsg string) error {
+	if r == nil {
+		return nil
+	}
+
+	r.mtx.Lock()
+	file := r.file
+	r.file = nil
+	if file != nil && !r.header {
+		r.writeHeader(file)
+	}
+	truncated := r.truncated
+	r.mtx.Unlock()
+
+	endedAt := r.log.now().UTC()
+
+	sess := r.sess
+	sess.EndedAt = &endedAt
+	sess.ExitCode = exitCode
+	sess.Error = errMsg
+
+	var rerr error
+	if file != nil {
+		name := recordingName(sess)
+
+		if rerr = r.storeRecording(file, name); rerr == nil {
+			sess.Recording = name
+			sess.RecordingTruncated = truncated
+		}
+	}
+
+	r.sess = sess
+
+	if err := r.log.update(r.key, sess); err != nil {
+		return err
+	}
+
+	return rerr
+}
+
+func (r *Recorder) storeRecording(file *os.File, name string) error {
+	defer func() {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}()
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), recordingStoreTimeout)
+	defer cancel()
+
+	if err = r.log.cfg.Sink.Store(ctx, name, file, size); err != nil {
+		return fmt.Errorf("shell audit: storing recording %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// writeHeader writes asciicast header, mtx must be held
+func (r *Recorder) writeHeader(file *os.File) {
+	lid := r.sess.LeaseID
+
+	data, _ := json.Marshal(asciicastHeader{
+		Version:   2,
+		Width:     r.width,
+		Height:    r.height,
+		Timestamp: r.start.Unix(),
+		Command:   strings.Join(r.sess.Command, " "),
+		Title:     fmt.Sprintf("%s/%d/%d/%d %s/%d", lid.Owner, lid.DSeq, lid.GSeq, lid.OSeq, r.sess.Service, r.sess.PodIndex),
+	})
+
+	data = append(data, '\n')
+	n, _ := file.Write(data)
+
+	r.header = true
+	r.written += int64(n)
+}
+
+func (r *Recorder) event(kind string, data string) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if r.file == nil || r.truncated {
+		return
+	}
+
+	if !r.header {
+		r.writeHeader(r.file)
+	}
+
+	elapsed := float64(r.log.now().Sub(r.start).Microseconds()) / 1e6
+
+	line, err := json.Marshal([]interface{}{elapsed, kind, data})
+	if err != nil {
+		return
+	}
+
+	line = append(line, '\n')
+
+	if r.written+int64(len(line)) > r.log.cfg.MaxRecordingBytes {
+		r.truncated = true
+		return
+	}
+
+	n, _ := r.file.Write(line)
+	r.written += int64(n)
+}
+
+func (r *Recorder) resize(width uint16, height uint16) {
+	r.mtx.Lock()
+
+	// size the terminal has been opened with goes to the header
+	if !r.header {
+		r.width, r.height = width, height
+		r.mtx.Unlock()
+		return
+	}
+
+	r.mtx.Unlock()
+
+	r.event("r", fmt.Sprintf("%dx%d", width, height))
+}
+
+// recorderStream records data written to it as events of given kind, it never fails the write
+type recorderStream struct {
+	rec  *Recorder
+	kind string
+}
+
+func (s recorderStream) Write(data []byte) (int, error) {
+	s.rec.event(s.kind, string(data))
+	return len(data), nil
+}
+
+type recorderSizeQueue struct {
+	rec *Recorder
+	tsq remotecommand.TerminalSizeQueue
+}
+
+func (q recorderSizeQueue) Next() *remotecommand.TerminalSize {
+	size := q.tsq.Next()
+	if size != nil {
+		q.rec.resize(size.Width, size.Height)
+	}
+
+	return size
+}
@@ -0,0 +1,235 @@
+package shellaudit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	s3EnvAccessKey    = "AWS_ACCESS_KEY_ID"
+	s3EnvSecretKey    = "AWS_SECRET_ACCESS_KEY"
+	s3DefaultRegion   = "us-east-1"
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3TimeFormat      = "20060102T150405Z"
+	s3DateFormat      = "20060102"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	// s3ErrorBodyLimit is the most bytes of error response put into the error
+	s3ErrorBodyLimit = 1024
+
+	recordingContentType = "application/x-asciicast"
+)
+
+type s3Sink struct {
+	endpoint  *url.URL
+	bucket    string
+	prefix    string
+	region    string
+	accessKey string
+	secretKey string
+	client    *http.Client
+	now       func() time.Time
+}
+
+// NewS3Sink creates sink storing recordings as objects in the bucket of S3 compatible storage.
+// u is s3://<bucket>/<prefix>?region=<region>&endpoint=<url>, endpoint defaults to AWS S3 in the region.
+// objects are addressed path style so storages such as MinIO work with it,
+// credentials are read from AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables
+func NewS3Sink(u *url.URL) (Sink, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("%w: bucket is not set", ErrInvalidSink)
+	}
+
+	query := u.Query()
+
+	region := query.Get("region")
+	if region == "" {
+		region = s3DefaultRegion
+	}
+
+	endpoint := query.Get("endpoint")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+
+	ep, err := url.Parse(endpoint)
+	if err != nil || (ep.Scheme != "http" && ep.Scheme != "https") || ep.Host == "" {
+		return nil, fmt.Errorf("%w: endpoint %q must be http or https URL", ErrInvalidSink, endpoint)
+	}
+
+	accessKey, secretKey := os.Getenv(s3EnvAccessKey), os.Getenv(s3EnvSecretKey)
+	if accessKey == "" || secretKey == "" {
+		return nil, fmt.Errorf("%w: %s and %s must be set", ErrInvalidSink, s3EnvAccessKey, s3EnvSecretKey)
+	}
+
+	return &s3Sink{
+		endpoint:  ep,
+		bucket:    u.Host,
+		prefix:    strings.Trim(u.Path, "/"),
+		region:    region,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		client:    &http.Client{},
+		now:       time.Now,
+	}, nil
+}
+
+func (s *s3Sink) Store(ctx context.Context, name string, r io.Reader, size int64) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(name).String(), r)
+	if err != nil {
+		return err
+	}
+
+	req.ContentLength = size
+	req.Header.Set("Content-Type", recordingContentType)
+
+	return s.do(req, http.StatusOK)
+}
+
+func (s *s3Sink) Delete(ctx context.Context, name string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(name).String(), nil)
+	if err != nil {
+		return err
+	}
+
+	return s.do(req, http.StatusNoContent, http.StatusOK, http.StatusNotFound)
+}
+
+func (s *s3Sink) do(req *http.Request, expected ...int) error {
+	// recording is streamed from the staging file, payload is left out of the signature not to read it twice
+	s.sign(req, s3UnsignedPayload)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	for _, status := range expected {
+		if resp.StatusCode == status {
+			return nil
+		}
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, s3ErrorBodyLimit))
+
+	return fmt.Errorf("%s %s: unexpected status %d: %s", req.Method, req.URL.Path, resp.StatusCode, body)
+}
+
+func (s *s3Sink) objectURL(name string) *url.URL {
+	key := name
+	if s.prefix != "" {
+		key = s.prefix + "/" + name
+	}
+
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.bucket + "/" + key
+	u.RawPath = s3EscapePath(u.Path)
+
+	return &u
+}
+
+// sign adds AWS signature version 4 of the request to its headers, every header set on request is signed
+func (s *s3Sink) sign(req *http.Request, payloadHash string) {
+	now := s.now().UTC()
+	amzDate := now.Format(s3TimeFormat)
+	date := now.Format(s3DateFormat)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	names := []string{"host"}
+	values := map[string]string{
+		"host": host,
+	}
+
+	for name, vals := range req.Header {
+		lname := strings.ToLower(name)
+		names = append(names, lname)
+		values[lname] = strings.TrimSpace(strings.Join(vals, ","))
+	}
+
+	sort.Strings(names)
+
+	canonicalHeaders := &strings.Builder{}
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + values[name] + "\n")
+	}
+
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		amzDate,
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	key := s3HMAC([]byte("AWS4"+s.secretKey), date)
+	key = s3HMAC(key, s.region)
+	key = s3HMAC(key, "s3")
+	key = s3HMAC(key, "aws4_request")
+
+	signature := hex.EncodeToString(s3HMAC(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.accessKey, scope, signedHeaders, signature))
+}
+
+func s3HMAC(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
+
+// s3EscapePath encodes path the way signature expects it, every byte but unreserved characters and slashes is escaped
+func s3EscapePath(path string) string {
+	const hexDigits = "0123456789ABCDEF"
+
+	res := &strings.Builder{}
+
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			res.WriteByte(c)
+		default:
+			res.WriteByte('%')
+			res.WriteByte(hexDigits[c>>4])
+			res.WriteByte(hexDigits[c&0x0f])
+		}
+	}
+
+	return res.String()
+}
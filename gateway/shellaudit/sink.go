@@ -0,0 +1,118 @@
+package shellaudit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrInvalidSink = errors.New("shell audit: invalid recordings sink")
+)
+
+// Sink stores session recordings
+type Sink interface {
+	// Store saves size bytes read from r as recording with given name, replacing existing one
+	Store(ctx context.Context, name string, r io.Reader, size int64) error
+	// Delete removes recording with given name, recordings which do not exist are ignored
+	Delete(ctx context.Context, name string) error
+}
+
+// NewSink creates sink from uri, which is either local directory path, file:// URL,
+// or s3://<bucket>/<prefix> URL of S3 compatible storage, see NewS3Sink for its parameters
+func NewSink(uri string) (Sink, error) {
+	if !strings.Contains(uri, "://") {
+		return NewDirSink(uri)
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSink, err)
+	}
+
+	switch u.Scheme {
+	case "file":
+		return NewDirSink(u.Path)
+	case "s3":
+		return NewS3Sink(u)
+	}
+
+	return nil, fmt.Errorf("%w: unsupported scheme %q", ErrInvalidSink, u.Scheme)
+}
+
+type dirSink struct {
+	dir string
+}
+
+// NewDirSink creates sink storing recordings in the local directory
+func NewDirSink(dir string) (Sink, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("%w: directory is not set", ErrInvalidSink)
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &dirSink{
+		dir: dir,
+	}, nil
+}
+
+func (s *dirSink) Store(_ context.Context, name string, r io.Reader, _ int64) error {
+	fpath, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(fpath), 0o700); err != nil {
+		return err
+	}
+
+	// recording shows up complete or not at all
+	file, err := os.CreateTemp(filepath.Dir(fpath), ".recording-*")
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(file, r); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return err
+	}
+
+	if err = file.Close(); err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+
+	return os.Rename(file.Name(), fpath)
+}
+
+func (s *dirSink) Delete(_ context.Context, name string) error {
+	fpath, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(fpath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// path returns path of the recording, refusing names leading out of the directory
+func (s *dirSink) path(name string) (string, error) {
+	rel := filepath.Clean(filepath.FromSlash(name))
+	if filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: recording name %q", ErrInvalidSink, name)
+	}
+
+	return filepath.Join(s.dir, rel), nil
+}
@@ -36,6 +36,7 @@ require (
 	github.com/tendermint/tendermint v0.34.27
 	github.com/troian/pubsub v0.1.2
 	github.com/vektra/mockery/v2 v2.40.2
+	go.etcd.io/bbolt v1.3.6
 	go.uber.org/zap v1.27.0
 	golang.org/x/net v0.30.0
 	golang.org/x/sync v0.8.0
@@ -244,7 +245,6 @@ require (
 	github.com/x448/float16 v0.8.4 // indirect
 	github.com/zondax/hid v0.9.1 // indirect
 	github.com/zondax/ledger-go v0.14.1 // indirect
-	go.etcd.io/bbolt v1.3.6 // indirect
 	go.step.sm/crypto v0.44.6 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
 	golang.org/x/crypto v0.28.0 // indirect
@@ -0,0 +1,376 @@
+package ledger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	bolt "go.etcd.io/bbolt"
+
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
+
+	"github.com/akash-network/provider/tools/boltdb"
+)
+
+var (
+	ErrNotFound = errors.New("earnings ledger: lease not found")
+
+	leasesBucket      = []byte("leases")
+	withdrawalsBucket = []byte("withdrawals")
+)
+
+// Withdrawal is the record of funds provider has withdrawn from the lease
+type Withdrawal struct {
+	LeaseID mtypes.LeaseID `json:"lease_id"`
+	Amount  sdk.Coin       `json:"amount"`
+	Height  int64          `json:"height"`
+	TxHash  string         `json:"tx_hash"`
+	Time    time.Time      `json:"time"`
+}
+
+// Lease is the ledger state of the lease as of its last balance check or withdrawal
+type Lease struct {
+	LeaseID mtypes.LeaseID `json:"lease_id"`
+	// Withdrawn is total amount ever withdrawn from the lease as reported by the chain
+	Withdrawn sdk.Coin `json:"withdrawn"`
+	// Accrued is amount lease has earned but provider has not withdrawn yet
+	Accrued   sdk.DecCoin `json:"accrued"`
+	Height    int64       `json:"height"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// Earnings sums withdrawn and accrued amounts
+type Earnings struct {
+	Withdrawn   sdk.Coins    `json:"withdrawn"`
+	Accrued     sdk.DecCoins `json:"accrued"`
+	Withdrawals int          `json:"withdrawals"`
+}
+
+func (e *Earnings) add(other Earnings) {
+	e.Withdrawn = e.Withdrawn.Add(other.Withdrawn...)
+	e.Accrued = e.Accrued.Add(other.Accrued...)
+	e.Withdrawals += other.Withdrawals
+}
+
+// LeaseEarnings are earnings of the single lease
+type LeaseEarnings struct {
+	LeaseID mtypes.LeaseID `json:"lease_id"`
+	Earnings
+}
+
+// OwnerEarnings are earnings of all leases of the deployment owner
+type OwnerEarnings struct {
+	Owner  string `json:"owner"`
+	Leases int    `json:"leases"`
+	Earnings
+}
+
+// Report is earnings of the leases matching the query.
+// withdrawn amounts cover withdrawals of the queried period, accrued amounts are as of the last balance check
+type Report struct {
+	From        time.Time       `json:"from,omitempty"`
+	To          time.Time       `json:"to,omitempty"`
+	Total       Earnings        `json:"total"`
+	Owners      []OwnerEarnings `json:"owners"`
+	Leases      []LeaseEarnings `json:"leases"`
+	Withdrawals []Withdrawal    `json:"withdrawals,omitempty"`
+}
+
+// Query selects leases and period of the report
+type Query struct {
+	// Owner limits report to leases of given deployment owner
+	Owner string
+	// DSeq limits report to leases of given deployment. requires Owner
+	DSeq uint64
+	// From and To limit withdrawals to given period. zero value leaves the period open on that side
+	From time.Time
+	To   time.Time
+	// Withdrawals includes individual withdrawals into the report
+	Withdrawals bool
+}
+
+func (q Query) prefix() []byte {
+	if q.Owner == "" {
+		return nil
+	}
+
+	if q.DSeq == 0 {
+		return []byte(q.Owner + "/")
+	}
+
+	return []byte(fmt.Sprintf("%s/%d/", q.Owner, q.DSeq))
+}
+
+func (q Query) inPeriod(tm time.Time) bool {
+	if !q.From.IsZero() && tm.Before(q.From) {
+		return false
+	}
+
+	if !q.To.IsZero() && !tm.Before(q.To) {
+		return false
+	}
+
+	return true
+}
+
+// Ledger keeps record of withdrawals provider makes from leases and of amounts leases have accrued,
+// so earnings can be accounted for without external indexers.
+// all methods of nil Ledger are no-op
+type Ledger struct {
+	db  *bolt.DB
+	now func() time.Time
+}
+
+// Open opens ledger file, creating it if does not exist
+func Open(path string) (*Ledger, error) {
+	db, err := boltdb.Open(path, leasesBucket, withdrawalsBucket)
+	if err != nil {
+		return nil, fmt.Errorf("earnings ledger: %w", err)
+	}
+
+	return &Ledger{
+		db:  db,
+		now: time.Now,
+	}, nil
+}
+
+func (l *Ledger) Close() error {
+	if l == nil {
+		return nil
+	}
+
+	return l.db.Close()
+}
+
+// Accrued records state of the lease as of given block height
+func (l *Ledger) Accrued(lid mtypes.LeaseID, withdrawn sdk.Coin, accrued sdk.DecCoin, height int64) error {
+	if l == nil {
+		return nil
+	}
+
+	return l.db.Update(func(tx *bolt.Tx) error {
+		_, err := l.putLease(tx, lid, withdrawn, accrued, height)
+		return err
+	})
+}
+
+// Withdrawn records withdrawal transaction of the lease. withdrawn is total amount withdrawn from the lease after
+// the transaction, amount of the withdrawal is its difference to the total recorded previously.
+// returns nil if nothing has been withdrawn
+func (l *Ledger) Withdrawn(lid mtypes.LeaseID, withdrawn sdk.Coin, accrued sdk.DecCoin, height int64, txHash string) (*Withdrawal, error) {
+	if l == nil {
+		return nil, nil
+	}
+
+	var res *Withdrawal
+
+	err := l.db.Update(func(tx *bolt.Tx) error {
+		prev, err := l.putLease(tx, lid, withdrawn, accrued, height)
+		if err != nil {
+			return err
+		}
+
+		amount := withdrawn
+		if prev != nil && prev.Withdrawn.Denom == withdrawn.Denom {
+			if !prev.Withdrawn.IsLT(withdrawn) {
+				return nil
+			}
+
+			amount = withdrawn.Sub(prev.Withdrawn)
+		}
+
+		if !amount.IsPositive() {
+			return nil
+		}
+
+		res = &Withdrawal{
+			LeaseID: lid,
+			Amount:  amount,
+			Height:  height,
+			TxHash:  txHash,
+			Time:    l.now().UTC(),
+		}
+
+		data, err := json.Marshal(res)
+		if err != nil {
+			return err
+		}
+
+		return tx.Bucket(withdrawalsBucket).Put(withdrawalKey(lid, height, txHash), data)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Lease returns ledger state of the lease
+func (l *Ledger) Lease(lid mtypes.LeaseID) (Lease, error) {
+	if l == nil {
+		return Lease{}, ErrNotFound
+	}
+
+	var res Lease
+
+	err := l.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(leasesBucket).Get(leaseKey(lid))
+		if data == nil {
+			return ErrNotFound
+		}
+
+		return json.Unmarshal(data, &res)
+	})
+
+	return res, err
+}
+
+// Report sums earnings of the leases matching the query per lease, per owner and in total
+func (l *Ledger) Report(q Query) (Report, error) {
+	res := Report{
+		From:   q.From,
+		To:     q.To,
+		Owners: []OwnerEarnings{},
+		Leases: []LeaseEarnings{},
+	}
+
+	if l == nil {
+		return res, nil
+	}
+
+	leases := make(map[string]*LeaseEarnings)
+	var keys []string
+
+	entry := func(lid mtypes.LeaseID) *LeaseEarnings {
+		key := string(leaseKey(lid))
+
+		val, exists := leases[key]
+		if !exists {
+			val = &LeaseEarnings{LeaseID: lid}
+			leases[key] = val
+			keys = append(keys, key)
+		}
+
+		return val
+	}
+
+	prefix := q.prefix()
+
+	err := l.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(leasesBucket).Cursor()
+
+		for key, data := c.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, data = c.Next() {
+			var lease Lease
+			if err := json.Unmarshal(data, &lease); err != nil {
+				return fmt.Errorf("earnings ledger: decoding %s: %w", key, err)
+			}
+
+			le := entry(lease.LeaseID)
+			le.Accrued = le.Accrued.Add(lease.Accrued)
+		}
+
+		c = tx.Bucket(withdrawalsBucket).Cursor()
+
+		for key, data := c.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, data = c.Next() {
+			var withdrawal Withdrawal
+			if err := json.Unmarshal(data, &withdrawal); err != nil {
+				return fmt.Errorf("earnings ledger: decoding %s: %w", key, err)
+			}
+
+			if !q.inPeriod(withdrawal.Time) {
+				continue
+			}
+
+			le := entry(withdrawal.LeaseID)
+			le.Withdrawn = le.Withdrawn.Add(withdrawal.Amount)
+			le.Withdrawals++
+
+			if q.Withdrawals {
+				res.Withdrawals = append(res.Withdrawals, withdrawal)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return Report{}, err
+	}
+
+	sort.Strings(keys)
+
+	owners := make(map[string]*OwnerEarnings)
+	var ownerNames []string
+
+	for _, key := range keys {
+		le := leases[key]
+
+		res.Leases = append(res.Leases, *le)
+		res.Total.add(le.Earnings)
+
+		owner, exists := owners[le.LeaseID.Owner]
+		if !exists {
+			owner = &OwnerEarnings{Owner: le.LeaseID.Owner}
+			owners[le.LeaseID.Owner] = owner
+			ownerNames = append(ownerNames, le.LeaseID.Owner)
+		}
+
+		owner.Leases++
+		owner.add(le.Earnings)
+	}
+
+	for _, name := range ownerNames {
+		res.Owners = append(res.Owners, *owners[name])
+	}
+
+	sort.SliceStable(res.Withdrawals, func(i, k int) bool {
+		return res.Withdrawals[i].Time.Before(res.Withdrawals[k].Time)
+	})
+
+	return res, nil
+}
+
+// putLease stores lease state, returning the state it replaces
+func (l *Ledger) putLease(tx *bolt.Tx, lid mtypes.LeaseID, withdrawn sdk.Coin, accrued sdk.DecCoin, height int64) (*Lease, error) {
+	bucket := tx.Bucket(leasesBucket)
+	key := leaseKey(lid)
+
+	var prev *Lease
+
+	if data := bucket.Get(key); data != nil {
+		prev = &Lease{}
+		if err := json.Unmarshal(data, prev); err != nil {
+			return nil, fmt.Errorf("earnings ledger: decoding %s: %w", key, err)
+		}
+
+		// results of older block may arrive late, keep the latest state
+		if prev.Height > height {
+			return prev, nil
+		}
+	}
+
+	data, err := json.Marshal(Lease{
+		LeaseID:   lid,
+		Withdrawn: withdrawn,
+		Accrued:   accrued,
+		Height:    height,
+		UpdatedAt: l.now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return prev, bucket.Put(key, data)
+}
+
+func leaseKey(lid mtypes.LeaseID) []byte {
+	return []byte(fmt.Sprintf("%s/%d/%d/%d/%s", lid.Owner, lid.DSeq, lid.GSeq, lid.OSeq, lid.Provider))
+}
+
+// withdrawalKey orders withdrawals of the lease by block height
+func withdrawalKey(lid mtypes.LeaseID, height int64, txHash string) []byte {
+	return []byte(fmt.Sprintf("%s/%020d/%s", leaseKey(lid), height, txHash))
+}
@@ -0,0 +1,172 @@
+package ledger
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/akash-network/node/testutil"
+)
+
+func openTestLedger(t *testing.T) (*Ledger, *time.Time) {
+	t.Helper()
+
+	l, err := Open(filepath.Join(t.TempDir(), "ledger", "earnings.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = l.Close()
+	})
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l.now = func() time.Time {
+		return now
+	}
+
+	return l, &now
+}
+
+func uakt(amount int64) sdk.Coin {
+	return sdk.NewInt64Coin(testutil.CoinDenom, amount)
+}
+
+func decUakt(amount int64) sdk.DecCoin {
+	return sdk.NewInt64DecCoin(testutil.CoinDenom, amount)
+}
+
+func TestLedgerWithdrawn(t *testing.T) {
+	l, _ := openTestLedger(t)
+
+	lid := testutil.LeaseID(t)
+
+	_, err := l.Lease(lid)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, l.Accrued(lid, uakt(100), decUakt(40), 10))
+
+	withdrawal, err := l.Withdrawn(lid, uakt(150), decUakt(0), 20, "TX1")
+	require.NoError(t, err)
+	require.NotNil(t, withdrawal)
+	require.Equal(t, uakt(50), withdrawal.Amount)
+	require.Equal(t, int64(20), withdrawal.Height)
+	require.Equal(t, "TX1", withdrawal.TxHash)
+
+	lease, err := l.Lease(lid)
+	require.NoError(t, err)
+	require.Equal(t, uakt(150), lease.Withdrawn)
+	require.True(t, lease.Accrued.IsZero())
+	require.Equal(t, int64(20), lease.Height)
+
+	// nothing withdrawn
+	withdrawal, err = l.Withdrawn(lid, uakt(150), decUakt(1), 21, "TX2")
+	require.NoError(t, err)
+	require.Nil(t, withdrawal)
+
+	// state of older block does not override newer one
+	require.NoError(t, l.Accrued(lid, uakt(100), decUakt(40), 15))
+
+	lease, err = l.Lease(lid)
+	require.NoError(t, err)
+	require.Equal(t, uakt(150), lease.Withdrawn)
+	require.Equal(t, int64(21), lease.Height)
+}
+
+func TestLedgerReport(t *testing.T) {
+	l, now := openTestLedger(t)
+
+	lid1 := testutil.LeaseID(t)
+	lid2 := lid1
+	lid2.GSeq++
+	lid3 := testutil.LeaseID(t)
+
+	require.NoError(t, l.Accrued(lid1, uakt(0), decUakt(5), 1))
+	require.NoError(t, l.Accrued(lid2, uakt(0), decUakt(7), 1))
+	require.NoError(t, l.Accrued(lid3, uakt(0), decUakt(11), 1))
+
+	_, err := l.Withdrawn(lid1, uakt(100), decUakt(1), 10, "TX1")
+	require.NoError(t, err)
+
+	*now = now.Add(24 * time.Hour)
+	_, err = l.Withdrawn(lid1, uakt(130), decUakt(2), 20, "TX2")
+	require.NoError(t, err)
+	_, err = l.Withdrawn(lid3, uakt(40), decUakt(3), 20, "TX2")
+	require.NoError(t, err)
+
+	report, err := l.Report(Query{})
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewCoins(uakt(170)), report.Total.Withdrawn)
+	require.Equal(t, sdk.NewDecCoins(decUakt(12)), report.Total.Accrued)
+	require.Equal(t, 3, report.Total.Withdrawals)
+	require.Len(t, report.Leases, 3)
+	require.Len(t, report.Owners, 2)
+	require.Empty(t, report.Withdrawals)
+
+	report, err = l.Report(Query{Owner: lid1.Owner, Withdrawals: true})
+	require.NoError(t, err)
+	require.Len(t, report.Owners, 1)
+	require.Equal(t, 2, report.Owners[0].Leases)
+	require.Equal(t, sdk.NewCoins(uakt(130)), report.Owners[0].Withdrawn)
+	require.Equal(t, sdk.NewDecCoins(decUakt(9)), report.Owners[0].Accrued)
+	require.Len(t, report.Withdrawals, 2)
+	require.Equal(t, "TX1", report.Withdrawals[0].TxHash)
+
+	// withdrawals of the second day only
+	report, err = l.Report(Query{Owner: lid1.Owner, DSeq: lid1.DSeq, From: *now})
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewCoins(uakt(30)), report.Total.Withdrawn)
+	require.Equal(t, 1, report.Total.Withdrawals)
+
+	report, err = l.Report(Query{To: *now})
+	require.NoError(t, err)
+	require.Equal(t, sdk.NewCoins(uakt(100)), report.Total.Withdrawn)
+}
+
+func TestLedgerReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "earnings.db")
+
+	l, err := Open(path)
+	require.NoError(t, err)
+
+	lid := testutil.LeaseID(t)
+	require.NoError(t, l.Accrued(lid, uakt(0), decUakt(0), 1))
+
+	_, err = l.Withdrawn(lid, uakt(10), decUakt(0), 2, "TX")
+	require.NoError(t, err)
+	require.NoError(t, l.Close())
+
+	l, err = Open(path)
+	require.NoError(t, err)
+	defer func() {
+		_ = l.Close()
+	}()
+
+	report, err := l.Report(Query{Withdrawals: true})
+	require.NoError(t, err)
+	require.Len(t, report.Withdrawals, 1)
+	require.Equal(t, lid, report.Withdrawals[0].LeaseID)
+	require.Equal(t, uakt(10), report.Withdrawals[0].Amount)
+	require.Equal(t, "TX", report.Withdrawals[0].TxHash)
+}
+
+func TestNilLedger(t *testing.T) {
+	var l *Ledger
+
+	lid := testutil.LeaseID(t)
+
+	require.NoError(t, l.Accrued(lid, uakt(1), decUakt(1), 1))
+
+	withdrawal, err := l.Withdrawn(lid, uakt(1), decUakt(1), 1, "TX")
+	require.NoError(t, err)
+	require.Nil(t, withdrawal)
+
+	_, err = l.Lease(lid)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	report, err := l.Report(Query{})
+	require.NoError(t, err)
+	require.Empty(t, report.Leases)
+
+	require.NoError(t, l.Close())
+}
@@ -0,0 +1,33 @@
+package manifest
+
+import (
+	"fmt"
+
+	maniv2beta2 "github.com/akash-network/akash-api/go/manifest/v2beta2"
+
+	clustertypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+// checkServiceEnv validates provider specific settings services pass through their environment,
+// so they are rejected with the manifest rather than failing the deployment
+func checkServiceEnv(requestManifest maniv2beta2.Manifest) error {
+	for _, group := range requestManifest.GetGroups() {
+		for _, svc := range group.Services {
+			// sidecar resources are reserved from the order, so TEE can't be enabled only once manifest is submitted
+			if _, enabled := clustertypes.ResourcesTEEProfile(svc.Resources); !enabled && clustertypes.TEEEnabledByEnv(svc.Env) {
+				return fmt.Errorf("%w: service %q: %s is no longer supported, request TEE with %q cpu attribute",
+					ErrInvalidServiceEnv, svc.Name, clustertypes.TEEEnvName, clustertypes.TEEAttributeKey)
+			}
+
+			if _, _, err := clustertypes.HealthChecksFromEnv(svc.Env); err != nil {
+				return fmt.Errorf("%w: service %q: %w", ErrInvalidServiceEnv, svc.Name, err)
+			}
+
+			if _, err := clustertypes.ResourcesSnapshotRestores(svc.Env, svc.Resources); err != nil {
+				return fmt.Errorf("%w: service %q: %w", ErrInvalidServiceEnv, svc.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	maniv2beta2 "github.com/akash-network/akash-api/go/manifest/v2beta2"
+	atypes "github.com/akash-network/akash-api/go/node/types/v1beta3"
+
+	"github.com/akash-network/node/sdl"
+
+	clustertypes "github.com/akash-network/provider/cluster/types/v1beta3"
+)
+
+func envTestManifest(t *testing.T) maniv2beta2.Manifest {
+	t.Helper()
+
+	sdlDoc, err := sdl.ReadFile("../testdata/deployment/deployment.yaml")
+	require.NoError(t, err)
+
+	mani, err := sdlDoc.Manifest()
+	require.NoError(t, err)
+
+	return mani
+}
+
+func TestCheckServiceEnv(t *testing.T) {
+	mani := envTestManifest(t)
+	require.NoError(t, checkServiceEnv(mani))
+}
+
+func TestCheckServiceEnvTEE(t *testing.T) {
+	mani := envTestManifest(t)
+	svc := &mani[0].Services[0]
+
+	svc.Env = append(svc.Env, clustertypes.TEEEnvName+"=false")
+	require.NoError(t, checkServiceEnv(mani))
+
+	// sidecar resources could not have been reserved for the order
+	svc.Env = append(svc.Env, clustertypes.TEEEnvName+"=true")
+	require.ErrorIs(t, checkServiceEnv(mani), ErrInvalidServiceEnv)
+
+	svc.Resources.CPU.Attributes = atypes.Attributes{
+		{Key: clustertypes.TEEAttributeKey, Value: "true"},
+	}
+	require.NoError(t, checkServiceEnv(mani))
+}
+
+func TestCheckServiceEnvHealthCheck(t *testing.T) {
+	mani := envTestManifest(t)
+	svc := &mani[0].Services[0]
+
+	env := svc.Env
+
+	svc.Env = append(env, clustertypes.HealthCheckEnvName+`={"liveness":{"http":{"path":"/healthz"}}}`)
+	require.NoError(t, checkServiceEnv(mani))
+
+	for _, val := range []string{
+		`{"liveness":`,
+		`{"liveness":{"http":{"path":"healthz"}}}`,
+		`{"readiness":{"tcp":{},"exec":{"command":["true"]}}}`,
+		`{"startup":{"tcp":{},"success_threshold":2}}`,
+	} {
+		svc.Env = append(env, clustertypes.HealthCheckEnvName+"="+val)
+
+		err := checkServiceEnv(mani)
+		require.ErrorIs(t, err, ErrInvalidServiceEnv, val)
+		require.ErrorIs(t, err, clustertypes.ErrHealthCheckConfig, val)
+	}
+}
+
+func TestCheckServiceEnvSnapshotRestores(t *testing.T) {
+	mani := envTestManifest(t)
+	svc := &mani[0].Services[0]
+
+	svc.Resources.Storage = append(svc.Resources.Storage, atypes.Storage{
+		Name:     "data",
+		Quantity: atypes.NewResourceValue(128 * 1024 * 1024),
+		Attributes: atypes.Attributes{
+			{Key: "persistent", Value: "true"},
+		},
+	})
+
+	env := svc.Env
+
+	svc.Env = append(env, clustertypes.SnapshotRestoreEnvName+`={"data":"nightly-web-data-web-0"}`)
+	require.NoError(t, checkServiceEnv(mani))
+
+	for _, val := range []string{
+		`{"data":`,
+		`{"data":"Nightly_Snapshot"}`,
+		// ephemeral volume
+		`{"default":"nightly-web-default-web-0"}`,
+		// no such volume
+		`{"logs":"nightly-web-logs-web-0"}`,
+	} {
+		svc.Env = append(env, clustertypes.SnapshotRestoreEnvName+"="+val)
+
+		err := checkServiceEnv(mani)
+		require.ErrorIs(t, err, ErrInvalidServiceEnv, val)
+		require.ErrorIs(t, err, clustertypes.ErrSnapshotRestoreConfig, val)
+	}
+}
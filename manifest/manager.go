@@ -35,8 +35,10 @@ var (
 	ErrManifestVersion         = errors.New("manifest version validation failed")
 	ErrNoManifestForDeployment = errors.New("manifest not yet received for that deployment")
 	ErrNoLeaseForDeployment    = errors.New("no lease for deployment")
-	errNoGroupForLease         = errors.New("group not found")
-	errManifestRejected        = errors.New("manifest rejected")
+	// ErrInvalidServiceEnv indicates provider specific settings tenant has put into service environment are not valid
+	ErrInvalidServiceEnv = errors.New("invalid service environment")
+	errNoGroupForLease   = errors.New("group not found")
+	errManifestRejected  = errors.New("manifest rejected")
 )
 
 func newManager(h *service, daddr dtypes.DeploymentID) *manager {
@@ -436,6 +438,14 @@ func (m *manager) validateRequest(req manifestRequest) error {
 		return err
 	}
 
+	if err = checkServiceEnv(req.value.Manifest); err != nil {
+		return err
+	}
+
+	if err = m.checkManifestPolicy(req.value.Manifest); err != nil {
+		return err
+	}
+
 	groupNames := make([]string, 0)
 
 	for _, lease := range m.localLeases {
@@ -450,6 +460,29 @@ func (m *manager) validateRequest(req manifestRequest) error {
 	return nil
 }
 
+// checkManifestPolicy applies image rules of the bid policy.
+// orders do not carry images so those rules can be checked only once manifest is submitted
+func (m *manager) checkManifestPolicy(requestManifest maniv2beta2.Manifest) error {
+	if m.config.BidPolicy == nil {
+		return nil
+	}
+
+	images := make([]string, 0)
+	for _, group := range requestManifest.GetGroups() {
+		for _, svc := range group.Services {
+			images = append(images, svc.Image)
+		}
+	}
+
+	if decline := m.config.BidPolicy.Policy().EvaluateImages(images); decline != nil {
+		decline.Record()
+		m.log.Info("manifest declined by bid policy", "rule", decline.Rule, "reason", decline.Reason)
+		return decline.Err()
+	}
+
+	return nil
+}
+
 func (m *manager) checkHostnamesForManifest(requestManifest maniv2beta2.Manifest, groupNames []string) error {
 	// Check if the hostnames are available. Do not block forever
 	ownerAddr, err := m.data.GetDeployment().DeploymentID.GetOwnerAddress()
@@ -9,8 +9,14 @@ import (
 
 	deploymentv1beta3 "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
 
+	journal "github.com/akash-network/provider/bidengine/journal"
+
+	ledger "github.com/akash-network/provider/ledger"
+
 	manifest "github.com/akash-network/provider/manifest"
 
+	marketv1beta4 "github.com/akash-network/akash-api/go/node/market/v1beta4"
+
 	mock "github.com/stretchr/testify/mock"
 
 	provider "github.com/akash-network/provider"
@@ -35,6 +41,66 @@ func (_m *Client) EXPECT() *Client_Expecter {
 	return &Client_Expecter{mock: &_m.Mock}
 }
 
+// BidHistory provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) BidHistory(_a0 context.Context, _a1 types.Address, _a2 journal.ListOptions) ([]journal.Entry, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BidHistory")
+	}
+
+	var r0 []journal.Entry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.Address, journal.ListOptions) ([]journal.Entry, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.Address, journal.ListOptions) []journal.Entry); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]journal.Entry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.Address, journal.ListOptions) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_BidHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BidHistory'
+type Client_BidHistory_Call struct {
+	*mock.Call
+}
+
+// BidHistory is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 types.Address
+//   - _a2 journal.ListOptions
+func (_e *Client_Expecter) BidHistory(_a0 interface{}, _a1 interface{}, _a2 interface{}) *Client_BidHistory_Call {
+	return &Client_BidHistory_Call{Call: _e.mock.On("BidHistory", _a0, _a1, _a2)}
+}
+
+func (_c *Client_BidHistory_Call) Run(run func(_a0 context.Context, _a1 types.Address, _a2 journal.ListOptions)) *Client_BidHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(types.Address), args[2].(journal.ListOptions))
+	})
+	return _c
+}
+
+func (_c *Client_BidHistory_Call) Return(_a0 []journal.Entry, _a1 error) *Client_BidHistory_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_BidHistory_Call) RunAndReturn(run func(context.Context, types.Address, journal.ListOptions) ([]journal.Entry, error)) *Client_BidHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Cluster provides a mock function with given fields:
 func (_m *Client) Cluster() cluster.Client {
 	ret := _m.Called()
@@ -129,6 +195,64 @@ func (_c *Client_ClusterService_Call) RunAndReturn(run func() cluster.Service) *
 	return _c
 }
 
+// Earnings provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Client) Earnings(_a0 context.Context, _a1 types.Address, _a2 ledger.Query) (ledger.Report, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Earnings")
+	}
+
+	var r0 ledger.Report
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, types.Address, ledger.Query) (ledger.Report, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, types.Address, ledger.Query) ledger.Report); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(ledger.Report)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, types.Address, ledger.Query) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_Earnings_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Earnings'
+type Client_Earnings_Call struct {
+	*mock.Call
+}
+
+// Earnings is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 types.Address
+//   - _a2 ledger.Query
+func (_e *Client_Expecter) Earnings(_a0 interface{}, _a1 interface{}, _a2 interface{}) *Client_Earnings_Call {
+	return &Client_Earnings_Call{Call: _e.mock.On("Earnings", _a0, _a1, _a2)}
+}
+
+func (_c *Client_Earnings_Call) Run(run func(_a0 context.Context, _a1 types.Address, _a2 ledger.Query)) *Client_Earnings_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(types.Address), args[2].(ledger.Query))
+	})
+	return _c
+}
+
+func (_c *Client_Earnings_Call) Return(_a0 ledger.Report, _a1 error) *Client_Earnings_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_Earnings_Call) RunAndReturn(run func(context.Context, types.Address, ledger.Query) (ledger.Report, error)) *Client_Earnings_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Hostname provides a mock function with given fields:
 func (_m *Client) Hostname() v1beta3.HostnameServiceClient {
 	ret := _m.Called()
@@ -176,6 +300,65 @@ func (_c *Client_Hostname_Call) RunAndReturn(run func() v1beta3.HostnameServiceC
 	return _c
 }
 
+// LeaseRunway provides a mock function with given fields: _a0, _a1
+func (_m *Client) LeaseRunway(_a0 context.Context, _a1 marketv1beta4.LeaseID) (*provider.LeaseRunway, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LeaseRunway")
+	}
+
+	var r0 *provider.LeaseRunway
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta4.LeaseID) (*provider.LeaseRunway, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, marketv1beta4.LeaseID) *provider.LeaseRunway); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*provider.LeaseRunway)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, marketv1beta4.LeaseID) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Client_LeaseRunway_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LeaseRunway'
+type Client_LeaseRunway_Call struct {
+	*mock.Call
+}
+
+// LeaseRunway is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 marketv1beta4.LeaseID
+func (_e *Client_Expecter) LeaseRunway(_a0 interface{}, _a1 interface{}) *Client_LeaseRunway_Call {
+	return &Client_LeaseRunway_Call{Call: _e.mock.On("LeaseRunway", _a0, _a1)}
+}
+
+func (_c *Client_LeaseRunway_Call) Run(run func(_a0 context.Context, _a1 marketv1beta4.LeaseID)) *Client_LeaseRunway_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(marketv1beta4.LeaseID))
+	})
+	return _c
+}
+
+func (_c *Client_LeaseRunway_Call) Return(_a0 *provider.LeaseRunway, _a1 error) *Client_LeaseRunway_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Client_LeaseRunway_Call) RunAndReturn(run func(context.Context, marketv1beta4.LeaseID) (*provider.LeaseRunway, error)) *Client_LeaseRunway_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Manifest provides a mock function with given fields:
 func (_m *Client) Manifest() manifest.Client {
 	ret := _m.Called()
@@ -151,6 +151,39 @@ func (dp *nodeDiscovery) queryGPU(ctx context.Context) (*gpu.Info, error) {
 	}
 }
 
+func (dp *nodeDiscovery) queryTEE(ctx context.Context) (*ctypes.TEEInfo, error) {
+	respch := make(chan dpReadResp, 1)
+
+	rctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-dp.ctx.Done():
+		return nil, dp.ctx.Err()
+	case <-rctx.Done():
+		return nil, rctx.Err()
+	case dp.readch <- dpReadReq{
+		ctx:  rctx,
+		op:   dpReqTEE,
+		resp: respch,
+	}:
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-dp.ctx.Done():
+		return nil, dp.ctx.Err()
+	case resp := <-respch:
+		if resp.data == nil {
+			return nil, resp.err
+		}
+		return resp.data.(*ctypes.TEEInfo), resp.err
+	}
+}
+
 func (dp *nodeDiscovery) apiConnector() error {
 	ctx := dp.ctx
 
@@ -306,6 +339,8 @@ initloop:
 				res = "gpu"
 			case dpReqMem:
 				res = "memory"
+			case dpReqTEE:
+				res = "tee"
 			}
 
 			result := kc.CoreV1().RESTClient().Get().
@@ -335,6 +370,10 @@ initloop:
 						var res memory.Info
 						resp.err = json.Unmarshal(data, &res)
 						resp.data = &res
+					case dpReqTEE:
+						var res ctypes.TEEInfo
+						resp.err = json.Unmarshal(data, &res)
+						resp.data = &res
 					}
 				}
 			}
@@ -424,6 +463,8 @@ func (dp *nodeDiscovery) monitor() error {
 		return err
 	}
 
+	tee := dp.parseTEEInfo(ctx)
+
 	restartPodsWatcher := func() error {
 		if podsWatch != nil {
 			select {
@@ -590,7 +631,7 @@ func (dp *nodeDiscovery) monitor() error {
 				lastPubState = nodeStateRemoved
 			}
 		case <-labelch:
-			labels, nNode := generateLabels(cfg, knode, node.Dup(), sc)
+			labels, nNode := generateLabels(cfg, knode, node.Dup(), sc, tee)
 			if !reflect.DeepEqual(&nNode, &node) {
 				node = nNode
 				signalState()
@@ -819,7 +860,7 @@ func isNodeReady(conditions []corev1.NodeCondition) bool {
 	return false
 }
 
-func generateLabels(cfg Config, knode *corev1.Node, node v1.Node, sc storageClasses) (map[string]string, v1.Node) {
+func generateLabels(cfg Config, knode *corev1.Node, node v1.Node, sc storageClasses, tee ctypes.TEEInfo) (map[string]string, v1.Node) {
 	res := make(map[string]string)
 
 	presentSc := make([]string, 0, len(sc))
@@ -887,6 +928,16 @@ func generateLabels(cfg Config, knode *corev1.Node, node v1.Node, sc storageClas
 		}
 	}
 
+	// TEE capable nodes advertise total amount of devices as well as each device
+	// so workloads can be pinned to nodes having devices TEE sidecar profile requires
+	if len(tee.Devices) > 0 {
+		res[builder.AkashServiceCapabilityTEE] = strconv.Itoa(len(tee.Devices))
+
+		for _, dev := range tee.Devices {
+			res[builder.TEEDeviceLabel(dev.Path)] = "1"
+		}
+	}
+
 	return res, node
 }
 
@@ -913,6 +964,22 @@ func (dp *nodeDiscovery) parseCPUInfo(ctx context.Context) v1.CPUInfoS {
 	return res
 }
 
+func (dp *nodeDiscovery) parseTEEInfo(ctx context.Context) ctypes.TEEInfo {
+	log := fromctx.LogrFromCtx(ctx).WithName("node.monitor")
+
+	tee, err := dp.queryTEE(ctx)
+	if err != nil {
+		log.Error(err, "unable to query tee")
+		return ctypes.TEEInfo{}
+	}
+
+	if tee == nil {
+		return ctypes.TEEInfo{}
+	}
+
+	return *tee
+}
+
 func (dp *nodeDiscovery) parseGPUInfo(ctx context.Context, info RegistryGPUVendors) v1.GPUInfoS {
 	res := make(v1.GPUInfoS, 0)
 
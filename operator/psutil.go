@@ -5,8 +5,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/fs"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -17,18 +21,39 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"golang.org/x/sync/errgroup"
+
+	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
 )
 
 const (
 	flagAPIPort = "api-port"
 )
 
+const (
+	sysfsRoot = "/sys"
+)
+
+var (
+	// teeDeviceClasses are sysfs classes which every device represents TPM
+	teeDeviceClasses = []string{"tpmrm", "tpm"}
+
+	// teeMiscDevices are misc devices exposed by confidential computing drivers
+	teeMiscDevices = map[string]string{
+		"sev":           ctypes.TEETypeSEV,
+		"sev-guest":     ctypes.TEETypeSEVSNP,
+		"tdx_guest":     ctypes.TEETypeTDX,
+		"sgx_enclave":   ctypes.TEETypeSGX,
+		"sgx_provision": ctypes.TEETypeSGX,
+	}
+)
+
 type hwInfo struct {
-	Errors []string     `json:"errors"`
-	CPU    *cpu.Info    `json:"cpu,omitempty"`
-	Memory *memory.Info `json:"memory,omitempty"`
-	GPU    *gpu.Info    `json:"gpu,omitempty"`
-	PCI    *pci.Info    `json:"pci,omitempty"`
+	Errors []string        `json:"errors"`
+	CPU    *cpu.Info       `json:"cpu,omitempty"`
+	Memory *memory.Info    `json:"memory,omitempty"`
+	GPU    *gpu.Info       `json:"gpu,omitempty"`
+	PCI    *pci.Info       `json:"pci,omitempty"`
+	TEE    *ctypes.TEEInfo `json:"tee,omitempty"`
 }
 
 func cmdPsutil() *cobra.Command {
@@ -62,6 +87,7 @@ func cmdPsutilServe() *cobra.Command {
 			router.HandleFunc("/gpu", gpuHandler).Methods(http.MethodGet)
 			router.HandleFunc("/memory", memoryHandler).Methods(http.MethodGet)
 			router.HandleFunc("/pci", pciHandler).Methods(http.MethodGet)
+			router.HandleFunc("/tee", teeHandler).Methods(http.MethodGet)
 
 			port := viper.GetUint16(flagAPIPort)
 
@@ -128,6 +154,8 @@ func cmdPsutilList() *cobra.Command {
 				res, err = memory.New()
 			case "pci":
 				res, err = pci.New()
+			case "tee":
+				res, err = teeInfo(sysfsRoot)
 			default:
 				return fmt.Errorf("invalid command \"%s\"", args[0]) // nolint: err113
 			}
@@ -174,6 +202,11 @@ func infoHandler(w http.ResponseWriter, _ *http.Request) {
 		res.Errors = append(res.Errors, err.Error())
 	}
 
+	res.TEE, err = teeInfo(sysfsRoot)
+	if err != nil {
+		res.Errors = append(res.Errors, err.Error())
+	}
+
 	writeJSON(w, res)
 }
 
@@ -217,6 +250,65 @@ func pciHandler(w http.ResponseWriter, _ *http.Request) {
 	writeJSON(w, res)
 }
 
+func teeHandler(w http.ResponseWriter, _ *http.Request) {
+	res, err := teeInfo(sysfsRoot)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, res)
+}
+
+// teeInfo looks up trusted execution devices using sysfs.
+// sysfs is shared with the host, thus devices are discovered without mounting host's /dev into the pod
+func teeInfo(sysfs string) (*ctypes.TEEInfo, error) {
+	res := &ctypes.TEEInfo{
+		Devices: make([]ctypes.TEEDevice, 0),
+	}
+
+	for _, class := range teeDeviceClasses {
+		entries, err := os.ReadDir(filepath.Join(sysfs, "class", class))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			res.Devices = append(res.Devices, ctypes.TEEDevice{
+				Name: entry.Name(),
+				Path: filepath.Join("/dev", entry.Name()),
+				Type: ctypes.TEETypeTPM,
+			})
+		}
+	}
+
+	for name, teeType := range teeMiscDevices {
+		if _, err := os.Stat(filepath.Join(sysfs, "class", "misc", name)); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		res.Devices = append(res.Devices, ctypes.TEEDevice{
+			Name: name,
+			Path: filepath.Join("/dev", name),
+			Type: teeType,
+		})
+	}
+
+	sort.Slice(res.Devices, func(i, j int) bool {
+		return res.Devices[i].Name < res.Devices[j].Name
+	})
+
+	return res, nil
+}
+
 func writeJSON(w http.ResponseWriter, obj interface{}) {
 	bytes, err := json.Marshal(obj)
 	if err != nil {
@@ -10,6 +10,7 @@ import (
 	tpubsub "github.com/troian/pubsub"
 
 	dtypes "github.com/akash-network/akash-api/go/node/deployment/v1beta3"
+	mtypes "github.com/akash-network/akash-api/go/node/market/v1beta4"
 	provider "github.com/akash-network/akash-api/go/provider/v1"
 	"github.com/cosmos/cosmos-sdk/client"
 	sdktypes "github.com/cosmos/cosmos-sdk/types"
@@ -17,9 +18,11 @@ import (
 	"github.com/akash-network/node/pubsub"
 
 	"github.com/akash-network/provider/bidengine"
+	"github.com/akash-network/provider/bidengine/journal"
 	aclient "github.com/akash-network/provider/client"
 	"github.com/akash-network/provider/cluster"
 	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
+	"github.com/akash-network/provider/ledger"
 	"github.com/akash-network/provider/manifest"
 	"github.com/akash-network/provider/operator/waiter"
 	"github.com/akash-network/provider/session"
@@ -32,6 +35,21 @@ type ValidateClient interface {
 	Validate(context.Context, sdktypes.Address, dtypes.GroupSpec) (ValidateGroupSpecResult, error)
 }
 
+// BidHistoryClient is the interface to query orders provider has reserved resources and bid for
+type BidHistoryClient interface {
+	BidHistory(context.Context, sdktypes.Address, journal.ListOptions) ([]journal.Entry, error)
+}
+
+// LeaseRunwayClient is the interface to query how long deployment escrow funds pay for the lease
+type LeaseRunwayClient interface {
+	LeaseRunway(context.Context, mtypes.LeaseID) (*LeaseRunway, error)
+}
+
+// EarningsClient is the interface to query amounts provider has withdrawn from leases and leases have accrued
+type EarningsClient interface {
+	Earnings(context.Context, sdktypes.Address, ledger.Query) (ledger.Report, error)
+}
+
 // StatusClient is the interface which includes status of service
 //
 //go:generate mockery --name StatusClient
@@ -44,6 +62,9 @@ type StatusClient interface {
 type Client interface {
 	StatusClient
 	ValidateClient
+	BidHistoryClient
+	LeaseRunwayClient
+	EarningsClient
 	Manifest() manifest.Client
 	Cluster() cluster.Client
 	Hostname() ctypes.HostnameServiceClient
@@ -79,7 +100,7 @@ func NewService(ctx context.Context,
 		return nil, err
 	}
 
-	bcSvc, err := newBalanceChecker(ctx, cl, accAddr, session, bus, cfg.BalanceCheckerCfg)
+	bcSvc, err := newBalanceChecker(ctx, cl, accAddr, session, bus, cfg.BalanceCheckerCfg, cfg.EarningsLedger)
 	if err != nil {
 		session.Log().Error("starting balance checker", "err", err)
 		cancel()
@@ -93,12 +114,27 @@ func NewService(ctx context.Context,
 		return nil, err
 	}
 
+	pricing := cfg.BidPricingStrategy
+	if cfg.BidSurgePricing != nil {
+		pricing, err = bidengine.MakeSurgePricing(pricing, clusterSvc, *cfg.BidSurgePricing)
+		if err != nil {
+			cancel()
+			<-clusterSvc.Done()
+			<-bcSvc.lc.Done()
+			return nil, err
+		}
+	}
+
 	bidengineSvc, err := bidengine.NewService(ctx, cl, session, clusterSvc, bus, waiter, bidengine.Config{
-		PricingStrategy: cfg.BidPricingStrategy,
-		Deposit:         cfg.BidDeposit,
-		BidTimeout:      cfg.BidTimeout,
-		Attributes:      cfg.Attributes,
-		MaxGroupVolumes: cfg.MaxGroupVolumes,
+		PricingStrategy:  pricing,
+		Deposit:          cfg.BidDeposit,
+		Wallet:           cfg.BidWallet,
+		BidTimeout:       cfg.BidTimeout,
+		Attributes:       cfg.Attributes,
+		MaxGroupVolumes:  cfg.MaxGroupVolumes,
+		Policy:           cfg.BidPolicy,
+		Journal:          cfg.BidJournal,
+		JournalRetention: cfg.BidJournalRetention,
 	})
 	if err != nil {
 		errmsg := "creating bidengine service"
@@ -114,6 +150,7 @@ func NewService(ctx context.Context,
 		ManifestTimeout:                   cfg.ManifestTimeout,
 		RPCQueryTimeout:                   cfg.RPCQueryTimeout,
 		CachedResultMaxAge:                cfg.CachedResultMaxAge,
+		BidPolicy:                         cfg.BidPolicy,
 	}
 
 	manifestSvc, err := manifest.NewService(ctx, session, bus, clusterSvc.HostnameService(), manifestConfig)
@@ -234,36 +271,46 @@ func (s *service) StatusV1(ctx context.Context) (*provider.Status, error) {
 }
 
 func (s *service) Validate(ctx context.Context, owner sdktypes.Address, gspec dtypes.GroupSpec) (ValidateGroupSpecResult, error) {
-	// FUTURE - pass owner here
-	req := bidengine.Request{
-		Owner: owner.String(),
-		GSpec: &gspec,
-	}
-
-	// inv, err := s.cclient.Inventory(ctx)
-	// if err != nil {
-	// 	return ValidateGroupSpecResult{}, err
-	// }
-	//
-	// res := &reservation{
-	// 	resources:     nil,
-	// 	clusterParams: nil,
-	// }
-	//
-	// if err = inv.Adjust(res, ctypes.WithDryRun()); err != nil {
-	// 	return ValidateGroupSpecResult{}, err
-	// }
-
-	price, err := s.config.BidPricingStrategy.CalculatePrice(ctx, req)
+	res, err := s.bidengine.Validate(ctx, owner.String(), gspec)
 	if err != nil {
 		return ValidateGroupSpecResult{}, err
 	}
 
 	return ValidateGroupSpecResult{
-		MinBidPrice: price,
+		MinBidPrice:   res.Price,
+		PolicyDecline: res.PolicyDecline,
+		Declines:      res.Declines,
 	}, nil
 }
 
+// BidHistory returns bid journal entries. provider sees all orders, tenants only their own
+func (s *service) BidHistory(_ context.Context, owner sdktypes.Address, opts journal.ListOptions) ([]journal.Entry, error) {
+	if owner.String() != s.session.Provider().Owner {
+		opts.Owner = owner.String()
+	}
+
+	return s.config.BidJournal.List(opts)
+}
+
+// LeaseRunway returns funds runway of the lease as of its last balance check, nil if lease has not been checked yet
+func (s *service) LeaseRunway(_ context.Context, lid mtypes.LeaseID) (*LeaseRunway, error) {
+	runway, exists := s.bc.LeaseRunway(lid)
+	if !exists {
+		return nil, nil
+	}
+
+	return &runway, nil
+}
+
+// Earnings returns earnings report of the ledger. provider sees all leases, tenants only their own
+func (s *service) Earnings(_ context.Context, owner sdktypes.Address, query ledger.Query) (ledger.Report, error) {
+	if owner.String() != s.session.Provider().Owner {
+		query.Owner = owner.String()
+	}
+
+	return s.config.EarningsLedger.Report(query)
+}
+
 func (s *service) run() {
 	defer s.lc.ShutdownCompleted()
 
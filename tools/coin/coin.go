@@ -0,0 +1,27 @@
+package coin
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Float64 converts coin amount into float64 for metrics, zero is returned for coin without amount
+func Float64(coin sdk.Coin) float64 {
+	if coin.Amount.IsNil() {
+		return 0
+	}
+
+	val, _ := sdk.NewDecFromInt(coin.Amount).Float64()
+
+	return val
+}
+
+// DecFloat64 converts decimal coin amount into float64 for metrics, zero is returned for coin without amount
+func DecFloat64(coin sdk.DecCoin) float64 {
+	if coin.Amount.IsNil() {
+		return 0
+	}
+
+	val, _ := coin.Amount.Float64()
+
+	return val
+}
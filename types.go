@@ -1,9 +1,12 @@
 package provider
 
 import (
+	"time"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
 	"github.com/akash-network/provider/bidengine"
+	"github.com/akash-network/provider/bidengine/policy"
 	ctypes "github.com/akash-network/provider/cluster/types/v1beta3"
 	"github.com/akash-network/provider/manifest"
 )
@@ -18,4 +21,18 @@ type Status struct {
 
 type ValidateGroupSpecResult struct {
 	MinBidPrice sdk.DecCoin `json:"min_bid_price"`
+	// PolicyDecline is set when provider bid policy declines the group
+	PolicyDecline *policy.Decline `json:"policy_decline,omitempty"`
+	// Declines lists all reasons provider would not bid on the group, empty when it would
+	Declines []bidengine.DeclineReason `json:"declines,omitempty"`
+}
+
+// LeaseRunway is how long funds left in deployment escrow pay for the lease
+type LeaseRunway struct {
+	BlocksRemaining int64 `json:"blocks_remaining"`
+	// Runway is estimated time until deployment escrow runs out of funds
+	Runway string `json:"runway"`
+	// LowBalance is set when runway is below provider warning threshold
+	LowBalance bool      `json:"low_balance"`
+	CheckedAt  time.Time `json:"checked_at"`
 }